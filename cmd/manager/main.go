@@ -64,8 +64,13 @@ func init() {
 	customMetrics.Registry.MustRegister(integreatlymetrics.RHMIInfo)
 	customMetrics.Registry.MustRegister(integreatlymetrics.RHMIVersion)
 	customMetrics.Registry.MustRegister(integreatlymetrics.RHMIStatus)
+	customMetrics.Registry.MustRegister(integreatlymetrics.BYODatastoreAvailable)
+	customMetrics.Registry.MustRegister(integreatlymetrics.BYODatastoreConnection)
+	customMetrics.Registry.MustRegister(integreatlymetrics.DatastoreHealth)
 	customMetrics.Registry.MustRegister(integreatlymetrics.RHOAMVersion)
 	customMetrics.Registry.MustRegister(integreatlymetrics.RHOAMStatus)
+	customMetrics.Registry.MustRegister(integreatlymetrics.APIUsageDaily)
+	customMetrics.Registry.MustRegister(integreatlymetrics.APIUsageMonthly)
 	integreatlymetrics.OperatorVersion.Add(1)
 }
 
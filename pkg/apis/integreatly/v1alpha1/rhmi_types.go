@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -21,9 +22,11 @@ var (
 	PhaseCreatingComponents     StatusPhase = "creating components"
 	PhaseAwaitingComponents     StatusPhase = "awaiting components"
 
-	PhaseInProgress StatusPhase = "in progress"
-	PhaseCompleted  StatusPhase = "completed"
-	PhaseFailed     StatusPhase = "failed"
+	PhaseInProgress  StatusPhase = "in progress"
+	PhaseCompleted   StatusPhase = "completed"
+	PhaseFailed      StatusPhase = "failed"
+	PhaseRollingBack StatusPhase = "rolling back"
+	PhaseRolledBack  StatusPhase = "rolled back"
 
 	InstallationTypeWorkshop    InstallationType = "workshop"
 	InstallationTypeManaged     InstallationType = "managed"
@@ -116,6 +119,10 @@ var (
 	EventInstallationCompleted string = "InstallationCompleted"
 	EventPreflightCheckPassed  string = "PreflightCheckPassed"
 	EventUpgradeApproved       string = "UpgradeApproved"
+	EventUpgradeCompleted      string = "UpgradeCompleted"
+	EventUpgradeFailed         string = "UpgradeFailed"
+	EventUpgradeRollingBack    string = "UpgradeRollingBack"
+	EventUpgradeRolledBack     string = "UpgradeRolledBack"
 
 	DefaultOriginPullSecretName      = "pull-secret"
 	DefaultOriginPullSecretNamespace = "openshift-config"
@@ -170,6 +177,501 @@ type RHMISpec struct {
 	//
 	// url
 	DeadMansSnitchSecret string `json:"deadMansSnitchSecret,omitempty"`
+
+	// ThreeScale configures the sizing of the 3scale components
+	// +optional
+	ThreeScale *ThreeScaleSpec `json:"threeScale,omitempty"`
+
+	// Marin3r configures the rate limit service
+	// +optional
+	Marin3r *Marin3rSpec `json:"marin3r,omitempty"`
+
+	// Topology configures the placement of the product pods across zones
+	// and nodes
+	// +optional
+	Topology *TopologySpec `json:"topology,omitempty"`
+
+	// RHSSOUser configures the user facing RHSSO
+	// +optional
+	RHSSOUser *RHSSOUserSpec `json:"rhssoUser,omitempty"`
+
+	// Datastores configures how the Postgres databases and Redis caches of
+	// 3scale, RHSSO, CodeReady, Fuse and Apicurio Registry are provided
+	// +optional
+	Datastores *DatastoresSpec `json:"datastores,omitempty"`
+
+	// Monitoring configures the metrics exported from the middleware
+	// monitoring Prometheus
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+
+	// ApicurioRegistry configures the storage of the Apicurio Registry
+	// +optional
+	ApicurioRegistry *ApicurioRegistrySpec `json:"apicurioRegistry,omitempty"`
+
+	// AMQOnline configures the plans and infra configs of AMQ Online
+	// +optional
+	AMQOnline *AMQOnlineSpec `json:"amqOnline,omitempty"`
+
+	// CodeReady configures the workspaces of CodeReady Workspaces
+	// +optional
+	CodeReady *CodeReadySpec `json:"codeReady,omitempty"`
+}
+
+type ZoneSpreadPolicy string
+
+const (
+	// ZoneSpreadRequired only schedules the pods of a product when they can
+	// be spread across zones
+	ZoneSpreadRequired ZoneSpreadPolicy = "Required"
+	// ZoneSpreadPreferred spreads the pods of a product across zones when
+	// possible
+	ZoneSpreadPreferred ZoneSpreadPolicy = "Preferred"
+)
+
+type TopologySpec struct {
+	// Default is the placement of every product without its own policy
+	// +optional
+	Default TopologyPolicy `json:"default,omitempty"`
+
+	// Products overrides the placement of individual products. Each field
+	// set here replaces the default one
+	// +optional
+	Products map[ProductName]TopologyPolicy `json:"products,omitempty"`
+}
+
+type TopologyPolicy struct {
+	// ZoneSpread is Required or Preferred. Defaults to Preferred
+	// +optional
+	ZoneSpread ZoneSpreadPolicy `json:"zoneSpread,omitempty"`
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// NodePool dedicates a pool of nodes to the pods. The pods are only
+	// scheduled on nodes labelled integreatly.org/node-pool=<pool>, and
+	// tolerate the NoSchedule taint with the same key and value
+	// +optional
+	NodePool string `json:"nodePool,omitempty"`
+}
+
+type SizingProfile string
+
+const (
+	SizingProfileSmall  SizingProfile = "small"
+	SizingProfileMedium SizingProfile = "medium"
+	SizingProfileLarge  SizingProfile = "large"
+)
+
+type ThreeScaleSpec struct {
+	// Profile is the named sizing profile of the 3scale components: small,
+	// medium or large. The profile sets the minimum number of replicas of
+	// each component. When not set, the profile of the SKU selected in the
+	// rate limit configuration is used
+	// +optional
+	Profile SizingProfile `json:"profile,omitempty"`
+
+	// Components overrides the sizing of individual components, keyed by
+	// the name of their deployment config, such as "apicast-production".
+	// The replicas set here take precedence over the profile
+	// +optional
+	Components map[string]ComponentSizing `json:"components,omitempty"`
+}
+
+type ComponentSizing struct {
+	// +optional
+	Replicas *int64 `json:"replicas,omitempty"`
+
+	// Resources of the containers of the component, keyed by container name,
+	// such as "system-master" for the "system-app" component. Containers not
+	// listed keep the resources set by the 3scale operator
+	// +optional
+	Resources map[string]corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Autoscaling creates a HorizontalPodAutoscaler for the component. It
+	// can only be set for "apicast-production" and "backend-listener", and
+	// not together with the replicas
+	// +optional
+	Autoscaling *HorizontalAutoscaling `json:"autoscaling,omitempty"`
+}
+
+type Marin3rSpec struct {
+	// RateLimitAutoscaling creates a HorizontalPodAutoscaler for the
+	// ratelimit deployment
+	// +optional
+	RateLimitAutoscaling *HorizontalAutoscaling `json:"rateLimitAutoscaling,omitempty"`
+
+	// SKU selects the limits of the sku-limits-managed-api-service
+	// ConfigMap. The "sku" addon parameter takes precedence over it
+	// +optional
+	SKU string `json:"sku,omitempty"`
+
+	// RateLimitMode sets whether the requests over the limit are rejected.
+	// The "rate-limit-mode" addon parameter takes precedence over it
+	// +optional
+	RateLimitMode RateLimitMode `json:"rateLimitMode,omitempty"`
+
+	// ManagementRateLimits adds envoy sidecars that rate limit the requests
+	// to the 3scale management APIs. The management APIs aren't rate limited
+	// when not set
+	// +optional
+	ManagementRateLimits *ManagementRateLimits `json:"managementRateLimits,omitempty"`
+}
+
+// ManagementRateLimits are the limits of the requests to the 3scale
+// management APIs. They are enforced regardless of the rate limit mode
+type ManagementRateLimits struct {
+	// SystemProvider limits the requests to the admin portals and the
+	// account management API of the tenants
+	// +optional
+	SystemProvider *RateLimit `json:"systemProvider,omitempty"`
+
+	// SystemMaster limits the requests to the master admin portal and API
+	// +optional
+	SystemMaster *RateLimit `json:"systemMaster,omitempty"`
+
+	// BackendListener limits the requests to the 3scale backend listener
+	// +optional
+	BackendListener *RateLimit `json:"backendListener,omitempty"`
+}
+
+type RateLimit struct {
+	// +kubebuilder:validation:Enum=second;minute;hour;day
+	Unit string `json:"unit"`
+
+	RequestsPerUnit uint32 `json:"requestsPerUnit"`
+}
+
+type RateLimitMode string
+
+const (
+	// RateLimitModeEnforce rejects the requests over the limit
+	RateLimitModeEnforce RateLimitMode = "enforce"
+	// RateLimitModeShadow calls the rate limit service for every request and
+	// records the requests over the limit in its metrics, without rejecting
+	// them
+	RateLimitModeShadow RateLimitMode = "shadow"
+)
+
+type RHSSOUserSpec struct {
+	// RealmImport is the key of a realm export in the backups storage to
+	// import into the user facing RHSSO. Each export is imported once
+	// +optional
+	RealmImport string `json:"realmImport,omitempty"`
+}
+
+type DatastoreProvider string
+
+const (
+	// DatastoreProviderCRO provisions the datastores with the cloud resource
+	// operator
+	DatastoreProviderCRO DatastoreProvider = "CRO"
+	// DatastoreProviderBYO uses existing datastores, whose connection
+	// details are read from a secret
+	DatastoreProviderBYO DatastoreProvider = "BYO"
+)
+
+type DatastoresSpec struct {
+	// Provider is CRO or BYO. Defaults to CRO
+	// +optional
+	Provider DatastoreProvider `json:"provider,omitempty"`
+
+	// SecretRef is the name of a secret in the installation namespace
+	// containing the connection details of the BYO datastores. The keys
+	// are prefixed by the name of the datastore, for example:
+	//
+	// threescale-postgres.host
+	// threescale-postgres.port
+	// threescale-postgres.username
+	// threescale-postgres.password
+	// threescale-postgres.database
+	// threescale-backend-redis.host
+	// threescale-backend-redis.port
+	//
+	// Required when the provider is BYO
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+}
+
+type AMQOnlineSpec struct {
+	// ConfigMapRef is the name of a config map in the installation
+	// namespace declaring additional plans and infra configs, or overriding
+	// the default ones with the same name. The config map can contain the
+	// following keys, each a YAML list of the corresponding enmasse
+	// resources:
+	//
+	// addressPlans
+	// addressSpacePlans
+	// brokeredInfraConfigs
+	// standardInfraConfigs
+	//
+	// The overrides of a default resource only need the fields they change.
+	// The additional resources removed from the config map are deleted,
+	// unless they're still in use
+	// +optional
+	ConfigMapRef string `json:"configMapRef,omitempty"`
+}
+
+type WorkspacePVCStrategy string
+
+const (
+	// WorkspacePVCStrategyCommon stores all the workspaces of a user in the
+	// same PVC
+	WorkspacePVCStrategyCommon WorkspacePVCStrategy = "common"
+	// WorkspacePVCStrategyPerWorkspace creates a PVC for each workspace
+	WorkspacePVCStrategyPerWorkspace WorkspacePVCStrategy = "per-workspace"
+	// WorkspacePVCStrategyUnique creates a PVC for each volume of a
+	// workspace
+	WorkspacePVCStrategyUnique WorkspacePVCStrategy = "unique"
+)
+
+// CodeReadySpec configures the CheCluster. The other fields of the
+// CheCluster that aren't set by the operator are left as they are
+type CodeReadySpec struct {
+	// PVCStrategy is common, per-workspace or unique. Defaults to
+	// per-workspace
+	// +optional
+	// +kubebuilder:validation:Enum=common;per-workspace;unique
+	PVCStrategy WorkspacePVCStrategy `json:"pvcStrategy,omitempty"`
+
+	// PVCClaimSize is the size of the workspace PVCs, such as "2Gi". Only
+	// the PVCs created after a change use the new size. Defaults to 1Gi
+	// +optional
+	PVCClaimSize string `json:"pvcClaimSize,omitempty"`
+
+	// WorkspaceMemoryLimit is the memory limit of the workspace containers
+	// that don't set one in their devfile, such as "1Gi"
+	// +optional
+	WorkspaceMemoryLimit string `json:"workspaceMemoryLimit,omitempty"`
+
+	// WorkspaceMemoryRequest is the memory request of the workspace
+	// containers that don't set one in their devfile. It can't be greater
+	// than the memory limit
+	// +optional
+	WorkspaceMemoryRequest string `json:"workspaceMemoryRequest,omitempty"`
+
+	// PluginRegistryURL is the URL of an external plugin registry. The
+	// dedicated plugin registry isn't deployed when set
+	// +optional
+	PluginRegistryURL string `json:"pluginRegistryURL,omitempty"`
+
+	// DevfileRegistryURL is the URL of an external devfile registry. The
+	// dedicated devfile registry isn't deployed when set. The stacks offered
+	// on the dashboard are the devfiles of this registry, so pointing it to
+	// a registry serving only the allowed devfiles restricts the stacks
+	// +optional
+	DevfileRegistryURL string `json:"devfileRegistryURL,omitempty"`
+
+	// IdleTimeout stops the workspaces that are inactive for longer than
+	// it, such as "30m". Defaults to the CodeReady default
+	// +optional
+	IdleTimeout string `json:"idleTimeout,omitempty"`
+}
+
+type RegistryPersistence string
+
+const (
+	// RegistryPersistenceStreams stores the artifacts in Kafka topics of
+	// AMQ Streams
+	RegistryPersistenceStreams RegistryPersistence = "streams"
+	// RegistryPersistenceJPA stores the artifacts in a Postgres database
+	// provided by the datastores provider of the installation
+	RegistryPersistenceJPA RegistryPersistence = "jpa"
+	// RegistryPersistenceInMemory keeps the artifacts in memory. They are
+	// lost when the registry restarts, so it's only meant for workshops
+	RegistryPersistenceInMemory RegistryPersistence = "mem"
+)
+
+type ApicurioRegistrySpec struct {
+	// Persistence is streams, jpa or mem. Defaults to streams. The artifacts
+	// aren't migrated when the persistence of an installed registry is
+	// changed, so they're lost
+	// +optional
+	// +kubebuilder:validation:Enum=streams;jpa;mem
+	Persistence RegistryPersistence `json:"persistence,omitempty"`
+
+	// StorageTopic configures the Kafka topic of the artifacts. Only used
+	// by the streams persistence
+	// +optional
+	StorageTopic *KafkaTopicSettings `json:"storageTopic,omitempty"`
+
+	// GlobalIDTopic configures the Kafka topic of the global IDs. Only used
+	// by the streams persistence
+	// +optional
+	GlobalIDTopic *KafkaTopicSettings `json:"globalIdTopic,omitempty"`
+}
+
+// KafkaTopicSettings configures a Kafka topic. The partitions of an existing
+// topic can be increased but not decreased, and its replicas can't be changed.
+// The topics are compacted and keep their latest records forever, as the
+// artifacts stored in deleted records would be lost
+type KafkaTopicSettings struct {
+	// Partitions of the topic. Defaults to 3
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Partitions int32 `json:"partitions,omitempty"`
+
+	// Replicas of the topic. Defaults to 3
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas int32 `json:"replicas,omitempty"`
+}
+
+type MonitoringSpec struct {
+	// Federation configures the metrics federated into the cluster
+	// monitoring. Only the firing alerts are federated when not set
+	// +optional
+	Federation *FederationSpec `json:"federation,omitempty"`
+
+	// RemoteWrite sends the metrics to an external Prometheus or Thanos
+	// endpoint
+	// +optional
+	RemoteWrite *RemoteWriteSpec `json:"remoteWrite,omitempty"`
+
+	// MonitorCloning filters the ServiceMonitors and PodMonitors cloned into
+	// the monitoring namespace from the namespaces labelled
+	// monitoring-key=middleware. All the monitors are cloned when not set
+	// +optional
+	MonitorCloning *MonitorCloningSpec `json:"monitorCloning,omitempty"`
+
+	// Probes override the blackbox probe settings of the product endpoints,
+	// keyed by the service label of the probe, e.g. 3scale-admin-ui
+	// +optional
+	Probes map[string]ProbeSpec `json:"probes,omitempty"`
+}
+
+// ProbeSpec configures the blackbox probe of a product endpoint. The probes
+// without HTTP or interval settings use the default blackbox module
+type ProbeSpec struct {
+	// Method of the probe requests. Defaults to GET
+	// +optional
+	// +kubebuilder:validation:Enum=GET;HEAD;POST
+	Method string `json:"method,omitempty"`
+
+	// ValidStatusCodes of the responses. Defaults to any 2xx status code
+	// +optional
+	ValidStatusCodes []int `json:"validStatusCodes,omitempty"`
+
+	// InsecureSkipVerify disables the verification of the certificate of
+	// the endpoint
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// AuthSecretRef is the name of a secret in the installation namespace
+	// with the credentials of the probe requests, either a "username" and a
+	// "password" for basic authentication or a bearer "token"
+	// +optional
+	AuthSecretRef string `json:"authSecretRef,omitempty"`
+
+	// Interval between the probes, e.g. 30s. Defaults to the interval of the
+	// blackbox job
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// AvailabilityTarget is the availability SLO of the endpoint as a
+	// percentage, e.g. "99.5". Defaults to 99.9
+	// +optional
+	AvailabilityTarget string `json:"availabilityTarget,omitempty"`
+}
+
+// MonitorCloningSpec selects the monitors that are cloned. A namespace or a
+// monitor can also be excluded with the annotation
+// "monitoring.integreatly.org/clone: false", and the scrape interval and
+// sample limit of the clones can be overridden with the annotations
+// "monitoring.integreatly.org/scrape-interval" and
+// "monitoring.integreatly.org/sample-limit" of the monitor or, for all the
+// monitors of a namespace, of the namespace
+type MonitorCloningSpec struct {
+	// NamespaceSelector restricts the namespaces whose monitors are cloned
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// MonitorSelector restricts the monitors that are cloned
+	// +optional
+	MonitorSelector *metav1.LabelSelector `json:"monitorSelector,omitempty"`
+}
+
+type MetricsSeries string
+
+const (
+	// MetricsSeriesRecordingRules only exports the series of the recording
+	// rules, whose names contain a colon, and the alerts
+	MetricsSeriesRecordingRules MetricsSeries = "recording-rules"
+	// MetricsSeriesRaw exports all the selected series
+	MetricsSeriesRaw MetricsSeries = "raw"
+)
+
+type FederationSpec struct {
+	// Matches are the series selectors of the federated metrics. Defaults
+	// to the firing alerts
+	// +optional
+	Matches []string `json:"matches,omitempty"`
+
+	// Series is recording-rules or raw. Defaults to raw
+	// +optional
+	// +kubebuilder:validation:Enum=recording-rules;raw
+	Series MetricsSeries `json:"series,omitempty"`
+
+	// LabelRewrites are applied to the federated series
+	// +optional
+	LabelRewrites []LabelRewrite `json:"labelRewrites,omitempty"`
+}
+
+type RemoteWriteSpec struct {
+	// URL of the remote write endpoint
+	URL string `json:"url"`
+
+	// SecretRef is the name of a secret in the installation namespace with
+	// the credentials of the endpoint, either in the "username" and
+	// "password" keys or in the "token" key. The CA of the endpoint can be
+	// set in the "ca.crt" key
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+
+	// Series is recording-rules or raw. Defaults to recording-rules
+	// +optional
+	// +kubebuilder:validation:Enum=recording-rules;raw
+	Series MetricsSeries `json:"series,omitempty"`
+
+	// LabelRewrites are applied to the series sent
+	// +optional
+	LabelRewrites []LabelRewrite `json:"labelRewrites,omitempty"`
+}
+
+// LabelRewrite is a Prometheus relabel config
+type LabelRewrite struct {
+	// +optional
+	SourceLabels []string `json:"sourceLabels,omitempty"`
+
+	// +optional
+	Regex string `json:"regex,omitempty"`
+
+	// +optional
+	TargetLabel string `json:"targetLabel,omitempty"`
+
+	// +optional
+	Replacement string `json:"replacement,omitempty"`
+
+	// Action is replace, keep, drop, labeldrop or labelkeep. Defaults to
+	// replace
+	// +optional
+	// +kubebuilder:validation:Enum=replace;keep;drop;labeldrop;labelkeep
+	Action string `json:"action,omitempty"`
+}
+
+type HorizontalAutoscaling struct {
+	// Minimum number of replicas. Defaults to 2
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// Maximum number of replicas
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Target average CPU utilization, as a percentage of the requested CPU.
+	// Defaults to 80
+	// +optional
+	TargetCPUUtilization *int32 `json:"targetCPUUtilization,omitempty"`
 }
 
 type PullSecretSpec struct {
@@ -197,6 +699,69 @@ type RHMIStatus struct {
 	SMTPEnabled        bool                          `json:"smtpEnabled,omitempty"`
 	Version            string                        `json:"version,omitempty"`
 	ToVersion          string                        `json:"toVersion,omitempty"`
+	LastUpgrade        *RHMIUpgradeStatus            `json:"lastUpgrade,omitempty"`
+	Topology           *RHMITopologyStatus           `json:"topology,omitempty"`
+	Monitoring         *RHMIMonitoringStatus         `json:"monitoring,omitempty"`
+}
+
+// RHMIMonitoringStatus lists the monitors cloned into the monitoring
+// namespace
+type RHMIMonitoringStatus struct {
+	ClonedMonitors []ClonedMonitor `json:"clonedMonitors,omitempty"`
+}
+
+type ClonedMonitor struct {
+	// Kind is ServiceMonitor or PodMonitor
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Source is the namespace/name of the monitor that is cloned
+	Source string `json:"source"`
+}
+
+// RHMITopologyStatus is the effective placement policy of the product pods
+type RHMITopologyStatus struct {
+	// MultiAZ is true when the nodes of the cluster run in more than one
+	// zone. Required zone spreading is only applied in multi AZ clusters
+	MultiAZ bool `json:"multiAZ"`
+
+	Default  TopologyPolicy                 `json:"default"`
+	Products map[ProductName]TopologyPolicy `json:"products,omitempty"`
+}
+
+// RHMIUpgradeStatus tracks the health of the latest approved upgrade of the
+// RHMI operator. If the upgrade did not complete in time, the operator reports
+// the guidance to roll it back when requested, and tracks the rollback done
+// by the cluster administrator. The operator doesn't roll back the CSV or
+// restore the snapshots itself
+type RHMIUpgradeStatus struct {
+	FromVersion string `json:"fromVersion,omitempty"`
+	ToVersion   string `json:"toVersion"`
+	PreviousCSV string `json:"previousCSV,omitempty"`
+	TargetCSV   string `json:"targetCSV"`
+
+	ApprovedAt metav1.Time `json:"approvedAt"`
+	// Deadline is the time by which the installation must be completed in
+	// the target version for the upgrade to be considered successful
+	Deadline metav1.Time `json:"deadline"`
+
+	// Phase is "rolling back" once the rollback guidance is reported, until
+	// the previous CSV is installed again
+	Phase StatusPhase `json:"phase"`
+	// Message contains the rollback guidance of a failed upgrade
+	Message string `json:"message,omitempty"`
+
+	// RestorePoints are the pre-upgrade snapshots of the product datastores
+	// that the cluster administrator must restore when rolling back the
+	// upgrade
+	RestorePoints []UpgradeRestorePoint `json:"restorePoints,omitempty"`
+}
+
+type UpgradeRestorePoint struct {
+	Kind         string `json:"kind"`
+	Namespace    string `json:"namespace"`
+	Name         string `json:"name"`
+	ResourceName string `json:"resourceName"`
+	SnapshotID   string `json:"snapshotID,omitempty"`
 }
 
 type RHMIStageStatus struct {
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"reflect"
 	"strings"
@@ -38,6 +39,12 @@ const (
 
 	DefaultNotBeforeDays      = 7
 	DefaultWaitForMaintenance = true
+	DefaultUpgradeReminders   = "24h"
+
+	// Prefix length limits of the network CIDR. CRO splits the CIDR in /27
+	// subnets, one per availability zone
+	MinNetworkPrefixLength = 16
+	MaxNetworkPrefixLength = 26
 
 	// Maximum allowed number of days to schedule an upgrade via `NotBeforeDays`
 	// MaxUpgradeDays = 14
@@ -48,6 +55,8 @@ type RHMIConfigSpec struct {
 	Upgrade     Upgrade     `json:"upgrade,omitempty"`
 	Maintenance Maintenance `json:"maintenance,omitempty"`
 	Backup      Backup      `json:"backup,omitempty"`
+	Network     Network     `json:"network,omitempty"`
+	UserSSO     UserSSO     `json:"userSSO,omitempty"`
 }
 
 // RHMIConfigStatus defines the observed state of RHMIConfig
@@ -63,6 +72,62 @@ type RHMIConfigStatus struct {
 	Maintenance      RHMIConfigStatusMaintenance `json:"maintenance,omitempty"`
 	Upgrade          RHMIConfigStatusUpgrade     `json:"upgrade,omitempty"`
 	UpgradeAvailable *UpgradeAvailable           `json:"upgradeAvailable,omitempty"`
+
+	// UpgradeNotifications keeps track of the notices sent by email and
+	// webhook for the latest upgrade
+	UpgradeNotifications *UpgradeNotificationsStatus `json:"upgradeNotifications,omitempty"`
+
+	// Network reflects the network configuration applied to the cloud
+	// resource strategies
+	Network *RHMIConfigStatusNetwork `json:"network,omitempty"`
+
+	// UserSSO reflects the realm configuration applied to the user facing SSO
+	UserSSO *RHMIConfigStatusUserSSO `json:"userSSO,omitempty"`
+}
+
+type RHMIConfigStatusUserSSO struct {
+	// Resource version of the realm config map last applied
+	AppliedVersion string `json:"appliedVersion,omitempty"`
+
+	// Differences found between the realm and the applied realm config, such
+	// as changes made in the Keycloak console. They are corrected on the next
+	// reconcile, except for roles added to declared groups, which are kept.
+	// Clients are corrected by the Keycloak operator
+	Drift []string `json:"drift,omitempty"`
+
+	// Time the drift was detected
+	DriftDetectedAt *metav1.Time `json:"driftDetectedAt,omitempty"`
+
+	// Error found validating or applying the realm config
+	Error string `json:"error,omitempty"`
+}
+
+type RHMIConfigStatusNetwork struct {
+	// CIDR applied to the cloud resource strategies. Empty when the datastores
+	// are created in the cluster network, as the CIDR doesn't apply to them
+	CIDR string `json:"cidr,omitempty"`
+
+	// Platform of the cluster the CIDR was applied for, such as "AWS"
+	Platform string `json:"platform,omitempty"`
+
+	// If this value is true, cloud resources have been created in the CIDR,
+	// and it can no longer be changed
+	Locked bool `json:"locked,omitempty"`
+
+	// Error found validating or applying the network configuration
+	Error string `json:"error,omitempty"`
+}
+
+type UpgradeNotificationsStatus struct {
+	// Version of the upgrade the notices were sent for
+	Version string `json:"version"`
+
+	// ScheduledFor is the upgrade schedule the notices were sent for, in format "2 Jan 2006 15:04"
+	ScheduledFor string `json:"scheduledFor,omitempty"`
+
+	// Sent lists the notices already sent: "scheduled", "upcoming-<lead time>" and "completed".
+	// A notice sent to some of its channels only is listed as "<notice>/email" or "<notice>/webhook"
+	Sent []string `json:"sent,omitempty"`
 }
 
 type RHMIConfigStatusMaintenance struct {
@@ -106,6 +171,18 @@ type Upgrade struct {
 	NotBeforeDays *int `json:"notBeforeDays,omitempty"`
 
 	Schedule *bool `json:"schedule,omitempty"`
+
+	// reminders: lead times before the scheduled upgrade at which a reminder
+	// is sent to the contacts, which are comma separated. Defaults to "24h"
+	// Format: Go duration "72h,24h,1h"
+	Reminders string `json:"reminders,omitempty"`
+
+	// Name of a secret in the RHMI operator namespace containing the URL
+	// upgrade notices are posted to. The secret must contain the following
+	// fields:
+	//
+	// url
+	WebhookSecret string `json:"webhookSecret,omitempty"`
 }
 
 type Maintenance struct {
@@ -120,6 +197,89 @@ type Backup struct {
 	ApplyOn string `json:"applyOn,omitempty"`
 }
 
+type Network struct {
+	// cidr: string, CIDR block of the network the cloud resources (Postgres
+	// and Redis) are created in. It can't overlap with the cluster and service
+	// networks, and it can't be changed once cloud resources are created.
+	// Format: "10.1.0.0/16", prefix length between /16 and /26
+	CIDR string `json:"cidr,omitempty"`
+}
+
+type UserSSO struct {
+	// identityProviders: list of identity providers federated into the user
+	// facing SSO realm, in addition to the OpenShift identity provider. When
+	// set, users choose the identity provider in the login page
+	IdentityProviders []UserSSOIdentityProvider `json:"identityProviders,omitempty"`
+
+	// realmConfig: string, name of a config map in the RHMI operator
+	// namespace declaring the clients, roles, groups, password policy and
+	// token lifetimes of the user facing SSO realm, under the "realm.yaml" key
+	RealmConfig string `json:"realmConfig,omitempty"`
+}
+
+type UserSSOIdentityProviderType string
+
+// UserSSOGroups are the groups of the user facing SSO realm that identity
+// providers can map onto
+var UserSSOGroups = []string{"rhmi-developers", "dedicated-admins"}
+
+// UserSSOReservedAliases are the aliases of the identity providers created by
+// the operator in the user facing SSO realm
+var UserSSOReservedAliases = []string{"openshift-v4"}
+
+const (
+	UserSSOIdentityProviderOIDC UserSSOIdentityProviderType = "oidc"
+	UserSSOIdentityProviderSAML UserSSOIdentityProviderType = "saml"
+	UserSSOIdentityProviderLDAP UserSSOIdentityProviderType = "ldap"
+)
+
+type UserSSOIdentityProvider struct {
+	// alias: string, unique name of the identity provider in the realm.
+	// "openshift-v4" is reserved for the cluster identity provider
+	Alias string `json:"alias"`
+
+	// type: string, one of "oidc", "saml" or "ldap". LDAP providers are added
+	// as user federation
+	Type UserSSOIdentityProviderType `json:"type"`
+
+	// displayName: string, name of the identity provider in the login page
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// config: Keycloak configuration of the identity provider, such as
+	// "authorizationUrl" and "tokenUrl" for OIDC, "singleSignOnServiceUrl"
+	// for SAML, or "connectionUrl" and "usersDn" for LDAP
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+
+	// secretRef: string, name of a secret in the RHMI operator namespace
+	// whose keys are added to the config, such as "clientSecret" for OIDC or
+	// "bindCredential" for LDAP
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+
+	// groupsAttribute: string, OIDC claim or SAML attribute holding the
+	// groups of the user. Defaults to "groups"
+	// +optional
+	GroupsAttribute string `json:"groupsAttribute,omitempty"`
+
+	// groupMappings: adds the users of the identity provider to the
+	// developers or dedicated admins groups of the realm
+	// +optional
+	GroupMappings []UserSSOGroupMapping `json:"groupMappings,omitempty"`
+}
+
+type UserSSOGroupMapping struct {
+	// externalGroup: string, group of the user in the identity provider. If
+	// not set, all the users of the identity provider are added to the group.
+	// Not supported for LDAP providers
+	// +optional
+	ExternalGroup string `json:"externalGroup,omitempty"`
+
+	// group: string, either "rhmi-developers" or "dedicated-admins"
+	Group string `json:"group"`
+}
+
 type UpgradeAvailable struct {
 	// Time of new update becoming available
 	// Format: "DDD hh:mm" > "sun 23:00". UTC time
@@ -127,6 +287,32 @@ type UpgradeAvailable struct {
 
 	// target-version: string, version of incoming RHMI Operator
 	TargetVersion string `json:"targetVersion,omitempty"`
+
+	// Details of the incoming upgrade, read from the annotations of its CSV
+	// +optional
+	Metadata *UpgradeMetadata `json:"metadata,omitempty"`
+}
+
+type UpgradeMetadata struct {
+	// URL of the release notes for the incoming version
+	ReleaseNotes string `json:"releaseNotes,omitempty"`
+
+	// List of product operators whose version changes with the upgrade
+	ProductVersionChanges []ProductVersionChange `json:"productVersionChanges,omitempty"`
+
+	// If this value is true, the upgrade involves a migration of one or more
+	// product databases
+	DatabaseMigration bool `json:"databaseMigration,omitempty"`
+
+	// Expected downtime of the affected services during the upgrade.
+	// Format: Go duration string, "30m"
+	ExpectedDowntime string `json:"expectedDowntime,omitempty"`
+}
+
+type ProductVersionChange struct {
+	Product     ProductName     `json:"product"`
+	FromVersion OperatorVersion `json:"fromVersion,omitempty"`
+	ToVersion   OperatorVersion `json:"toVersion"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -170,6 +356,27 @@ func (c *RHMIConfig) ValidateUpdate(old runtime.Object) error {
 		}
 	}
 
+	if _, err := c.Spec.Upgrade.GetReminders(); err != nil {
+		return err
+	}
+
+	if c.Spec.Network.CIDR != "" {
+		if _, err := ValidateNetworkCIDR(c.Spec.Network.CIDR); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Spec.UserSSO.Validate(); err != nil {
+		return err
+	}
+
+	// The CIDR can't be changed once cloud resources have been created in it
+	if oldConfig, ok := old.(*RHMIConfig); ok && oldConfig.Status.Network != nil && oldConfig.Status.Network.Locked {
+		if c.Spec.Network.CIDR != oldConfig.Status.Network.CIDR {
+			return fmt.Errorf("Value of spec.Network.CIDR can't be changed from %s as cloud resources have already been created in it", oldConfig.Status.Network.CIDR)
+		}
+	}
+
 	return nil
 }
 
@@ -254,6 +461,29 @@ func (u *Upgrade) DefaultIfEmpty() {
 	u.Schedule = either(u.Schedule, false).(*bool)
 }
 
+// GetReminders parses the comma separated lead times of the upgrade reminders.
+// If no reminders are set, the default reminders are returned
+func (u *Upgrade) GetReminders() ([]time.Duration, error) {
+	reminders := u.Reminders
+	if reminders == "" {
+		reminders = DefaultUpgradeReminders
+	}
+
+	leadTimes := []time.Duration{}
+	for _, reminder := range strings.Split(reminders, ",") {
+		leadTime, err := time.ParseDuration(strings.TrimSpace(reminder))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse spec.Upgrade.Reminders value %s : expected comma separated durations such as 24h : %v", reminder, err)
+		}
+		if leadTime <= 0 {
+			return nil, fmt.Errorf("Value of spec.Upgrade.Reminders must be greater than zero, found %s", reminder)
+		}
+		leadTimes = append(leadTimes, leadTime)
+	}
+
+	return leadTimes, nil
+}
+
 func init() {
 	SchemeBuilder.Register(&RHMIConfig{}, &RHMIConfigList{})
 }
@@ -332,6 +562,63 @@ func ValidateBackupAndMaintenance(backupApplyOn, maintenanceApplyFrom string) (s
 	return backupApplyOn, maintenanceApplyFrom, nil
 }
 
+// ValidateNetworkCIDR ensures that the network CIDR is a correctly formatted
+// IPv4 CIDR block with a prefix length between MinNetworkPrefixLength and
+// MaxNetworkPrefixLength
+func ValidateNetworkCIDR(cidr string) (*net.IPNet, error) {
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse network CIDR value : expected format 10.1.0.0/16 : %v", err)
+	}
+	if ip.To4() == nil {
+		return nil, fmt.Errorf("network CIDR %s must be an IPv4 CIDR block", cidr)
+	}
+	if !ip.Equal(ipNet.IP) {
+		return nil, fmt.Errorf("network CIDR %s must be the network address, %s", cidr, ipNet.String())
+	}
+
+	prefixLength, _ := ipNet.Mask.Size()
+	if prefixLength < MinNetworkPrefixLength || prefixLength > MaxNetworkPrefixLength {
+		return nil, fmt.Errorf("network CIDR %s prefix length must be between /%d and /%d", cidr, MinNetworkPrefixLength, MaxNetworkPrefixLength)
+	}
+
+	return ipNet, nil
+}
+
+// Validate checks the identity providers have a unique alias, a known type,
+// and map onto the developers or dedicated admins groups
+func (u *UserSSO) Validate() error {
+	aliases := map[string]bool{}
+	for _, provider := range u.IdentityProviders {
+		if provider.Alias == "" {
+			return errors.New("Value of spec.UserSSO.IdentityProviders.Alias is required")
+		}
+		if contains(UserSSOReservedAliases, provider.Alias) {
+			return fmt.Errorf("identity provider alias %s is reserved", provider.Alias)
+		}
+		if aliases[provider.Alias] {
+			return fmt.Errorf("identity provider alias %s is used more than once", provider.Alias)
+		}
+		aliases[provider.Alias] = true
+
+		switch provider.Type {
+		case UserSSOIdentityProviderOIDC, UserSSOIdentityProviderSAML, UserSSOIdentityProviderLDAP:
+		default:
+			return fmt.Errorf("identity provider %s has unknown type %s, expected one of %s, %s or %s", provider.Alias, provider.Type, UserSSOIdentityProviderOIDC, UserSSOIdentityProviderSAML, UserSSOIdentityProviderLDAP)
+		}
+
+		for _, mapping := range provider.GroupMappings {
+			if !contains(UserSSOGroups, mapping.Group) {
+				return fmt.Errorf("identity provider %s maps onto unknown group %s, expected one of %v", provider.Alias, mapping.Group, UserSSOGroups)
+			}
+			if provider.Type == UserSSOIdentityProviderLDAP && mapping.ExternalGroup != "" {
+				return fmt.Errorf("identity provider %s can't map external group %s, LDAP providers only support mapping all their users", provider.Alias, mapping.ExternalGroup)
+			}
+		}
+	}
+	return nil
+}
+
 // timeBlockOverlaps checks if two time ranges overlap and returns true
 // if they do
 func timeBlockOverlaps(startA, endA, startB, endB time.Time) bool {
@@ -1,6 +1,10 @@
 package v1alpha1
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+	"time"
+)
 
 func TestValidateBackupAndMaintenance(t *testing.T) {
 	type args struct {
@@ -65,3 +69,189 @@ func TestValidateBackupAndMaintenance(t *testing.T) {
 		})
 	}
 }
+
+func TestGetReminders(t *testing.T) {
+	tests := []struct {
+		name      string
+		reminders string
+		want      []time.Duration
+		wantErr   bool
+	}{
+		{
+			name:      "test empty reminders returns default",
+			reminders: "",
+			want:      []time.Duration{time.Hour * 24},
+		},
+		{
+			name:      "test comma separated reminders",
+			reminders: "72h, 24h,30m",
+			want:      []time.Duration{time.Hour * 72, time.Hour * 24, time.Minute * 30},
+		},
+		{
+			name:      "test invalid reminder fails",
+			reminders: "24h,1d",
+			wantErr:   true,
+		},
+		{
+			name:      "test negative reminder fails",
+			reminders: "-1h",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upgrade := &Upgrade{Reminders: tt.reminders}
+			got, err := upgrade.GetReminders()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetReminders() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetReminders() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNetworkCIDR(t *testing.T) {
+	tests := []struct {
+		name    string
+		cidr    string
+		wantErr bool
+	}{
+		{
+			name: "test valid cidr succeeds",
+			cidr: "10.1.0.0/16",
+		},
+		{
+			name: "test smallest allowed cidr succeeds",
+			cidr: "10.1.0.0/26",
+		},
+		{
+			name:    "test invalid cidr fails",
+			cidr:    "10.1.0.0-16",
+			wantErr: true,
+		},
+		{
+			name:    "test ipv6 cidr fails",
+			cidr:    "fd00::/48",
+			wantErr: true,
+		},
+		{
+			name:    "test host address fails",
+			cidr:    "10.1.2.3/16",
+			wantErr: true,
+		},
+		{
+			name:    "test too large cidr fails",
+			cidr:    "10.0.0.0/8",
+			wantErr: true,
+		},
+		{
+			name:    "test too small cidr fails",
+			cidr:    "10.1.0.0/28",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ValidateNetworkCIDR(tt.cidr); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateNetworkCIDR() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateNetworkLocked(t *testing.T) {
+	old := &RHMIConfig{
+		Spec: RHMIConfigSpec{
+			Network: Network{CIDR: "10.1.0.0/16"},
+		},
+		Status: RHMIConfigStatus{
+			Network: &RHMIConfigStatusNetwork{CIDR: "10.1.0.0/16", Locked: true},
+		},
+	}
+
+	updated := old.DeepCopy()
+	updated.Spec.Network.CIDR = "10.2.0.0/16"
+	if err := updated.ValidateUpdate(old); err == nil {
+		t.Error("ValidateUpdate() expected error changing a locked network CIDR")
+	}
+
+	updated.Spec.Network.CIDR = old.Spec.Network.CIDR
+	if err := updated.ValidateUpdate(old); err != nil {
+		t.Errorf("ValidateUpdate() unexpected error = %v", err)
+	}
+}
+
+func TestUserSSOValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []UserSSOIdentityProvider
+		wantErr   bool
+	}{
+		{
+			name: "test valid identity providers succeed",
+			providers: []UserSSOIdentityProvider{
+				{
+					Alias:         "corporate",
+					Type:          UserSSOIdentityProviderSAML,
+					GroupMappings: []UserSSOGroupMapping{{ExternalGroup: "admins", Group: "dedicated-admins"}},
+				},
+				{
+					Alias:         "directory",
+					Type:          UserSSOIdentityProviderLDAP,
+					GroupMappings: []UserSSOGroupMapping{{Group: "rhmi-developers"}},
+				},
+			},
+		},
+		{
+			name: "test duplicated alias fails",
+			providers: []UserSSOIdentityProvider{
+				{Alias: "corporate", Type: UserSSOIdentityProviderOIDC},
+				{Alias: "corporate", Type: UserSSOIdentityProviderSAML},
+			},
+			wantErr: true,
+		},
+		{
+			name:      "test reserved alias fails",
+			providers: []UserSSOIdentityProvider{{Alias: "openshift-v4", Type: UserSSOIdentityProviderOIDC}},
+			wantErr:   true,
+		},
+		{
+			name:      "test unknown type fails",
+			providers: []UserSSOIdentityProvider{{Alias: "corporate", Type: "kerberos"}},
+			wantErr:   true,
+		},
+		{
+			name: "test unknown group fails",
+			providers: []UserSSOIdentityProvider{
+				{
+					Alias:         "corporate",
+					Type:          UserSSOIdentityProviderOIDC,
+					GroupMappings: []UserSSOGroupMapping{{Group: "cluster-admins"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "test ldap external group fails",
+			providers: []UserSSOIdentityProvider{
+				{
+					Alias:         "directory",
+					Type:          UserSSOIdentityProviderLDAP,
+					GroupMappings: []UserSSOGroupMapping{{ExternalGroup: "admins", Group: "dedicated-admins"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userSSO := &UserSSO{IdentityProviders: tt.providers}
+			if err := userSSO.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
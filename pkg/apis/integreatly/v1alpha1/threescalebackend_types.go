@@ -0,0 +1,108 @@
+/*
+Copyright YEAR Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ThreeScaleBackendSpec defines the desired state of ThreeScaleBackend
+type ThreeScaleBackendSpec struct {
+	// TenantRef is the name of the ThreeScaleTenant the backend belongs to,
+	// in the namespace of the ThreeScaleBackend. The backend belongs to the
+	// default 3scale tenant when it's not set
+	TenantRef string `json:"tenantRef,omitempty"`
+
+	Name        string `json:"name"`
+	SystemName  string `json:"systemName"`
+	Description string `json:"description,omitempty"`
+	// PrivateBaseURL is the URL of the API the backend proxies to
+	PrivateBaseURL string `json:"privateBaseURL"`
+
+	MappingRules []ThreeScaleMappingRule `json:"mappingRules,omitempty"`
+
+	// Adopt allows the operator to manage an existing backend with the same
+	// system name. Without it, the sync fails when the backend already
+	// exists in 3scale. See ThreeScaleSyncStatus.Adopted
+	Adopt bool `json:"adopt,omitempty"`
+}
+
+// ThreeScaleMappingRule maps the requests matching HTTPMethod and Pattern to
+// the hits metric
+type ThreeScaleMappingRule struct {
+	HTTPMethod string `json:"httpMethod"`
+	Pattern    string `json:"pattern"`
+	// Increment of the hits metric, 1 by default
+	Increment int `json:"increment,omitempty"`
+	// Last stops the evaluation of the mapping rules after this one
+	Last bool `json:"last,omitempty"`
+}
+
+// ThreeScaleSyncStatus is the result of the latest sync of a 3scale object
+// through the account management API
+type ThreeScaleSyncStatus struct {
+	Phase StatusPhase `json:"phase,omitempty"`
+	// ID of the object in 3scale. It's set once the object is created
+	ID int `json:"id,omitempty"`
+	// SyncError is the error of the latest sync, empty if it succeeded
+	SyncError string `json:"syncError,omitempty"`
+	// Drift lists the differences found between the spec and 3scale by the
+	// latest sync, which corrected them. Changes made in the admin portal
+	// to the objects managed by a CR are reported here
+	Drift        []string     `json:"drift,omitempty"`
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Adopted is set when the object existed in 3scale before it was
+	// synced. An adopted object isn't deleted from 3scale with its CR, and
+	// only the objects created in it by the operator are deleted when they
+	// are no longer declared
+	Adopted bool `json:"adopted,omitempty"`
+	// ManagedMappingRules are the mapping rules created by the operator in
+	// an adopted object, as "METHOD pattern"
+	ManagedMappingRules []string `json:"managedMappingRules,omitempty"`
+}
+
+// ThreeScaleBackendStatus defines the observed state of ThreeScaleBackend
+type ThreeScaleBackendStatus struct {
+	ThreeScaleSyncStatus `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ThreeScaleBackend is a 3scale backend API synced by the operator
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=threescalebackends,scope=Namespaced
+type ThreeScaleBackend struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ThreeScaleBackendSpec   `json:"spec,omitempty"`
+	Status ThreeScaleBackendStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ThreeScaleBackendList contains a list of ThreeScaleBackend
+type ThreeScaleBackendList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ThreeScaleBackend `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ThreeScaleBackend{}, &ThreeScaleBackendList{})
+}
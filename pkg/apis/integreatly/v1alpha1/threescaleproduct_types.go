@@ -0,0 +1,120 @@
+/*
+Copyright YEAR Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ThreeScaleProductSpec defines the desired state of ThreeScaleProduct
+type ThreeScaleProductSpec struct {
+	// TenantRef is the name of the ThreeScaleTenant the product belongs to,
+	// in the namespace of the ThreeScaleProduct. The product belongs to the
+	// default 3scale tenant when it's not set
+	TenantRef string `json:"tenantRef,omitempty"`
+
+	Name        string `json:"name"`
+	SystemName  string `json:"systemName"`
+	Description string `json:"description,omitempty"`
+
+	BackendUsages    []ThreeScaleBackendUsage    `json:"backendUsages,omitempty"`
+	MappingRules     []ThreeScaleMappingRule     `json:"mappingRules,omitempty"`
+	ApplicationPlans []ThreeScaleApplicationPlan `json:"applicationPlans,omitempty"`
+	// Policies is the APIcast policy chain of the product, in order. The
+	// policy chain is left as is when no policies are declared, otherwise it
+	// must include the apicast policy
+	Policies []ThreeScalePolicy `json:"policies,omitempty"`
+
+	// PromoteToProduction promotes the configuration deployed to the staging
+	// APIcast to the production APIcast after each sync. Without it, the
+	// configuration is only deployed to the staging APIcast
+	PromoteToProduction bool `json:"promoteToProduction,omitempty"`
+
+	// Adopt allows the operator to manage an existing product with the same
+	// system name. Without it, the sync fails when the product already
+	// exists in 3scale. See ThreeScaleSyncStatus.Adopted
+	Adopt bool `json:"adopt,omitempty"`
+}
+
+// ThreeScaleBackendUsage routes the requests to Path to a backend
+type ThreeScaleBackendUsage struct {
+	// BackendRef is the name of the ThreeScaleBackend, in the namespace of
+	// the ThreeScaleProduct
+	BackendRef string `json:"backendRef"`
+	Path       string `json:"path"`
+}
+
+// ThreeScaleApplicationPlan is an application plan of the product
+type ThreeScaleApplicationPlan struct {
+	Name       string `json:"name"`
+	SystemName string `json:"systemName"`
+	// Published plans can be subscribed to from the developer portal
+	Published bool `json:"published,omitempty"`
+}
+
+// ThreeScalePolicy is a policy of the APIcast policy chain
+type ThreeScalePolicy struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Enabled bool   `json:"enabled"`
+	// Configuration of the policy, in the JSON schema of the policy
+	Configuration *apiextensionsv1beta1.JSON `json:"configuration,omitempty"`
+}
+
+// ThreeScaleProductStatus defines the observed state of ThreeScaleProduct
+type ThreeScaleProductStatus struct {
+	ThreeScaleSyncStatus `json:",inline"`
+
+	// StagingVersion and ProductionVersion are the versions of the product
+	// configuration deployed to the staging and production APIcasts
+	StagingVersion    int `json:"stagingVersion,omitempty"`
+	ProductionVersion int `json:"productionVersion,omitempty"`
+
+	// ManagedApplicationPlans are the system names of the application plans
+	// created by the operator in an adopted product
+	ManagedApplicationPlans []string `json:"managedApplicationPlans,omitempty"`
+	// ManagedBackendUsages are the IDs of the backends whose usages were
+	// created by the operator in an adopted product
+	ManagedBackendUsages []int `json:"managedBackendUsages,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ThreeScaleProduct is a 3scale product synced by the operator
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=threescaleproducts,scope=Namespaced
+type ThreeScaleProduct struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ThreeScaleProductSpec   `json:"spec,omitempty"`
+	Status ThreeScaleProductStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ThreeScaleProductList contains a list of ThreeScaleProduct
+type ThreeScaleProductList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ThreeScaleProduct `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ThreeScaleProduct{}, &ThreeScaleProductList{})
+}
@@ -0,0 +1,77 @@
+/*
+Copyright YEAR Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ThreeScaleTenantSpec defines the desired state of ThreeScaleTenant
+type ThreeScaleTenantSpec struct {
+	// OrganizationName is the name of the tenant account. The subdomain of
+	// the tenant admin portal is derived from it by 3scale
+	OrganizationName string `json:"organizationName"`
+
+	// Username and Email of the tenant admin user. The admin user logs in to
+	// the admin portal with the user SSO, or with the password in the
+	// tenant secret
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// ThreeScaleTenantStatus defines the observed state of ThreeScaleTenant
+type ThreeScaleTenantStatus struct {
+	Phase   StatusPhase `json:"phase,omitempty"`
+	Message string      `json:"message,omitempty"`
+
+	// TenantID is the ID of the tenant account in 3scale. It's set once the
+	// tenant is created
+	TenantID int `json:"tenantId,omitempty"`
+	// AdminURL is the URL of the tenant admin portal
+	AdminURL string `json:"adminUrl,omitempty"`
+	// SecretRef is the name of the secret with the admin URL, the admin
+	// credentials and the access token of the tenant, in the namespace of
+	// the ThreeScaleTenant
+	SecretRef string `json:"secretRef,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ThreeScaleTenant is a 3scale tenant provisioned by the operator, with its
+// admin user signing in with the user SSO
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=threescaletenants,scope=Namespaced
+type ThreeScaleTenant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ThreeScaleTenantSpec   `json:"spec,omitempty"`
+	Status ThreeScaleTenantStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ThreeScaleTenantList contains a list of ThreeScaleTenant
+type ThreeScaleTenantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ThreeScaleTenant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ThreeScaleTenant{}, &ThreeScaleTenantList{})
+}
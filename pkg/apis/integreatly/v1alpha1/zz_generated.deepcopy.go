@@ -21,103 +21,1156 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/api/core/v1"
+	v1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMQOnlineSpec) DeepCopyInto(out *AMQOnlineSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMQOnlineSpec.
+func (in *AMQOnlineSpec) DeepCopy() *AMQOnlineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AMQOnlineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertingEmailAddresses) DeepCopyInto(out *AlertingEmailAddresses) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertingEmailAddresses.
-func (in *AlertingEmailAddresses) DeepCopy() *AlertingEmailAddresses {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertingEmailAddresses.
+func (in *AlertingEmailAddresses) DeepCopy() *AlertingEmailAddresses {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertingEmailAddresses)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApicurioRegistrySpec) DeepCopyInto(out *ApicurioRegistrySpec) {
+	*out = *in
+	if in.StorageTopic != nil {
+		in, out := &in.StorageTopic, &out.StorageTopic
+		*out = new(KafkaTopicSettings)
+		**out = **in
+	}
+	if in.GlobalIDTopic != nil {
+		in, out := &in.GlobalIDTopic, &out.GlobalIDTopic
+		*out = new(KafkaTopicSettings)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApicurioRegistrySpec.
+func (in *ApicurioRegistrySpec) DeepCopy() *ApicurioRegistrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApicurioRegistrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Backup) DeepCopyInto(out *Backup) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backup.
+func (in *Backup) DeepCopy() *Backup {
+	if in == nil {
+		return nil
+	}
+	out := new(Backup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClonedMonitor) DeepCopyInto(out *ClonedMonitor) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClonedMonitor.
+func (in *ClonedMonitor) DeepCopy() *ClonedMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(ClonedMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CodeReadySpec) DeepCopyInto(out *CodeReadySpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CodeReadySpec.
+func (in *CodeReadySpec) DeepCopy() *CodeReadySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CodeReadySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentSizing) DeepCopyInto(out *ComponentSizing) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(map[string]v1.ResourceRequirements, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(HorizontalAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentSizing.
+func (in *ComponentSizing) DeepCopy() *ComponentSizing {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentSizing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatastoresSpec) DeepCopyInto(out *DatastoresSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatastoresSpec.
+func (in *DatastoresSpec) DeepCopy() *DatastoresSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatastoresSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederationSpec) DeepCopyInto(out *FederationSpec) {
+	*out = *in
+	if in.Matches != nil {
+		in, out := &in.Matches, &out.Matches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelRewrites != nil {
+		in, out := &in.LabelRewrites, &out.LabelRewrites
+		*out = make([]LabelRewrite, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederationSpec.
+func (in *FederationSpec) DeepCopy() *FederationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FederationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HorizontalAutoscaling) DeepCopyInto(out *HorizontalAutoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilization != nil {
+		in, out := &in.TargetCPUUtilization, &out.TargetCPUUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HorizontalAutoscaling.
+func (in *HorizontalAutoscaling) DeepCopy() *HorizontalAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(HorizontalAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaTopicSettings) DeepCopyInto(out *KafkaTopicSettings) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaTopicSettings.
+func (in *KafkaTopicSettings) DeepCopy() *KafkaTopicSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaTopicSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelRewrite) DeepCopyInto(out *LabelRewrite) {
+	*out = *in
+	if in.SourceLabels != nil {
+		in, out := &in.SourceLabels, &out.SourceLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelRewrite.
+func (in *LabelRewrite) DeepCopy() *LabelRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Maintenance) DeepCopyInto(out *Maintenance) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Maintenance.
+func (in *Maintenance) DeepCopy() *Maintenance {
+	if in == nil {
+		return nil
+	}
+	out := new(Maintenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementRateLimits) DeepCopyInto(out *ManagementRateLimits) {
+	*out = *in
+	if in.SystemProvider != nil {
+		in, out := &in.SystemProvider, &out.SystemProvider
+		*out = new(RateLimit)
+		**out = **in
+	}
+	if in.SystemMaster != nil {
+		in, out := &in.SystemMaster, &out.SystemMaster
+		*out = new(RateLimit)
+		**out = **in
+	}
+	if in.BackendListener != nil {
+		in, out := &in.BackendListener, &out.BackendListener
+		*out = new(RateLimit)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementRateLimits.
+func (in *ManagementRateLimits) DeepCopy() *ManagementRateLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementRateLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Marin3rSpec) DeepCopyInto(out *Marin3rSpec) {
+	*out = *in
+	if in.RateLimitAutoscaling != nil {
+		in, out := &in.RateLimitAutoscaling, &out.RateLimitAutoscaling
+		*out = new(HorizontalAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagementRateLimits != nil {
+		in, out := &in.ManagementRateLimits, &out.ManagementRateLimits
+		*out = new(ManagementRateLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Marin3rSpec.
+func (in *Marin3rSpec) DeepCopy() *Marin3rSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(Marin3rSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorCloningSpec) DeepCopyInto(out *MonitorCloningSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MonitorSelector != nil {
+		in, out := &in.MonitorSelector, &out.MonitorSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorCloningSpec.
+func (in *MonitorCloningSpec) DeepCopy() *MonitorCloningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorCloningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+	*out = *in
+	if in.Federation != nil {
+		in, out := &in.Federation, &out.Federation
+		*out = new(FederationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteWrite != nil {
+		in, out := &in.RemoteWrite, &out.RemoteWrite
+		*out = new(RemoteWriteSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MonitorCloning != nil {
+		in, out := &in.MonitorCloning, &out.MonitorCloning
+		*out = new(MonitorCloningSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make(map[string]ProbeSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
+func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Network) DeepCopyInto(out *Network) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Network.
+func (in *Network) DeepCopy() *Network {
+	if in == nil {
+		return nil
+	}
+	out := new(Network)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeSpec) DeepCopyInto(out *ProbeSpec) {
+	*out = *in
+	if in.ValidStatusCodes != nil {
+		in, out := &in.ValidStatusCodes, &out.ValidStatusCodes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeSpec.
+func (in *ProbeSpec) DeepCopy() *ProbeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProductVersionChange) DeepCopyInto(out *ProductVersionChange) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProductVersionChange.
+func (in *ProductVersionChange) DeepCopy() *ProductVersionChange {
+	if in == nil {
+		return nil
+	}
+	out := new(ProductVersionChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PullSecretSpec) DeepCopyInto(out *PullSecretSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullSecretSpec.
+func (in *PullSecretSpec) DeepCopy() *PullSecretSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PullSecretSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMI) DeepCopyInto(out *RHMI) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMI.
+func (in *RHMI) DeepCopy() *RHMI {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RHMI) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIConfig) DeepCopyInto(out *RHMIConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfig.
+func (in *RHMIConfig) DeepCopy() *RHMIConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RHMIConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIConfigList) DeepCopyInto(out *RHMIConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RHMIConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfigList.
+func (in *RHMIConfigList) DeepCopy() *RHMIConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RHMIConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIConfigSpec) DeepCopyInto(out *RHMIConfigSpec) {
+	*out = *in
+	in.Upgrade.DeepCopyInto(&out.Upgrade)
+	out.Maintenance = in.Maintenance
+	out.Backup = in.Backup
+	out.Network = in.Network
+	in.UserSSO.DeepCopyInto(&out.UserSSO)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfigSpec.
+func (in *RHMIConfigSpec) DeepCopy() *RHMIConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIConfigStatus) DeepCopyInto(out *RHMIConfigStatus) {
+	*out = *in
+	out.Maintenance = in.Maintenance
+	in.Upgrade.DeepCopyInto(&out.Upgrade)
+	if in.UpgradeAvailable != nil {
+		in, out := &in.UpgradeAvailable, &out.UpgradeAvailable
+		*out = new(UpgradeAvailable)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradeNotifications != nil {
+		in, out := &in.UpgradeNotifications, &out.UpgradeNotifications
+		*out = new(UpgradeNotificationsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(RHMIConfigStatusNetwork)
+		**out = **in
+	}
+	if in.UserSSO != nil {
+		in, out := &in.UserSSO, &out.UserSSO
+		*out = new(RHMIConfigStatusUserSSO)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfigStatus.
+func (in *RHMIConfigStatus) DeepCopy() *RHMIConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIConfigStatusMaintenance) DeepCopyInto(out *RHMIConfigStatusMaintenance) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfigStatusMaintenance.
+func (in *RHMIConfigStatusMaintenance) DeepCopy() *RHMIConfigStatusMaintenance {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIConfigStatusMaintenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIConfigStatusNetwork) DeepCopyInto(out *RHMIConfigStatusNetwork) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfigStatusNetwork.
+func (in *RHMIConfigStatusNetwork) DeepCopy() *RHMIConfigStatusNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIConfigStatusNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIConfigStatusUpgrade) DeepCopyInto(out *RHMIConfigStatusUpgrade) {
+	*out = *in
+	if in.Scheduled != nil {
+		in, out := &in.Scheduled, &out.Scheduled
+		*out = new(UpgradeSchedule)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfigStatusUpgrade.
+func (in *RHMIConfigStatusUpgrade) DeepCopy() *RHMIConfigStatusUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIConfigStatusUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIConfigStatusUserSSO) DeepCopyInto(out *RHMIConfigStatusUserSSO) {
+	*out = *in
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DriftDetectedAt != nil {
+		in, out := &in.DriftDetectedAt, &out.DriftDetectedAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfigStatusUserSSO.
+func (in *RHMIConfigStatusUserSSO) DeepCopy() *RHMIConfigStatusUserSSO {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIConfigStatusUserSSO)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIList) DeepCopyInto(out *RHMIList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RHMI, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIList.
+func (in *RHMIList) DeepCopy() *RHMIList {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RHMIList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIMonitoringStatus) DeepCopyInto(out *RHMIMonitoringStatus) {
+	*out = *in
+	if in.ClonedMonitors != nil {
+		in, out := &in.ClonedMonitors, &out.ClonedMonitors
+		*out = make([]ClonedMonitor, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIMonitoringStatus.
+func (in *RHMIMonitoringStatus) DeepCopy() *RHMIMonitoringStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIMonitoringStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIProductStatus) DeepCopyInto(out *RHMIProductStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIProductStatus.
+func (in *RHMIProductStatus) DeepCopy() *RHMIProductStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIProductStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMISpec) DeepCopyInto(out *RHMISpec) {
+	*out = *in
+	out.PullSecret = in.PullSecret
+	out.AlertingEmailAddresses = in.AlertingEmailAddresses
+	if in.ThreeScale != nil {
+		in, out := &in.ThreeScale, &out.ThreeScale
+		*out = new(ThreeScaleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Marin3r != nil {
+		in, out := &in.Marin3r, &out.Marin3r
+		*out = new(Marin3rSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Topology != nil {
+		in, out := &in.Topology, &out.Topology
+		*out = new(TopologySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RHSSOUser != nil {
+		in, out := &in.RHSSOUser, &out.RHSSOUser
+		*out = new(RHSSOUserSpec)
+		**out = **in
+	}
+	if in.Datastores != nil {
+		in, out := &in.Datastores, &out.Datastores
+		*out = new(DatastoresSpec)
+		**out = **in
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(MonitoringSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ApicurioRegistry != nil {
+		in, out := &in.ApicurioRegistry, &out.ApicurioRegistry
+		*out = new(ApicurioRegistrySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AMQOnline != nil {
+		in, out := &in.AMQOnline, &out.AMQOnline
+		*out = new(AMQOnlineSpec)
+		**out = **in
+	}
+	if in.CodeReady != nil {
+		in, out := &in.CodeReady, &out.CodeReady
+		*out = new(CodeReadySpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMISpec.
+func (in *RHMISpec) DeepCopy() *RHMISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIStageStatus) DeepCopyInto(out *RHMIStageStatus) {
+	*out = *in
+	if in.Products != nil {
+		in, out := &in.Products, &out.Products
+		*out = make(map[ProductName]RHMIProductStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIStageStatus.
+func (in *RHMIStageStatus) DeepCopy() *RHMIStageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIStageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIStatus) DeepCopyInto(out *RHMIStatus) {
+	*out = *in
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make(map[StageName]RHMIStageStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.LastUpgrade != nil {
+		in, out := &in.LastUpgrade, &out.LastUpgrade
+		*out = new(RHMIUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Topology != nil {
+		in, out := &in.Topology, &out.Topology
+		*out = new(RHMITopologyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(RHMIMonitoringStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIStatus.
+func (in *RHMIStatus) DeepCopy() *RHMIStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMITopologyStatus) DeepCopyInto(out *RHMITopologyStatus) {
+	*out = *in
+	in.Default.DeepCopyInto(&out.Default)
+	if in.Products != nil {
+		in, out := &in.Products, &out.Products
+		*out = make(map[ProductName]TopologyPolicy, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMITopologyStatus.
+func (in *RHMITopologyStatus) DeepCopy() *RHMITopologyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMITopologyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIUpgradeStatus) DeepCopyInto(out *RHMIUpgradeStatus) {
+	*out = *in
+	in.ApprovedAt.DeepCopyInto(&out.ApprovedAt)
+	in.Deadline.DeepCopyInto(&out.Deadline)
+	if in.RestorePoints != nil {
+		in, out := &in.RestorePoints, &out.RestorePoints
+		*out = make([]UpgradeRestorePoint, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIUpgradeStatus.
+func (in *RHMIUpgradeStatus) DeepCopy() *RHMIUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHSSOUserSpec) DeepCopyInto(out *RHSSOUserSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHSSOUserSpec.
+func (in *RHSSOUserSpec) DeepCopy() *RHSSOUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RHSSOUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimit) DeepCopyInto(out *RateLimit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimit.
+func (in *RateLimit) DeepCopy() *RateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteWriteSpec) DeepCopyInto(out *RemoteWriteSpec) {
+	*out = *in
+	if in.LabelRewrites != nil {
+		in, out := &in.LabelRewrites, &out.LabelRewrites
+		*out = make([]LabelRewrite, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteWriteSpec.
+func (in *RemoteWriteSpec) DeepCopy() *RemoteWriteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteWriteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreeScaleApplicationPlan) DeepCopyInto(out *ThreeScaleApplicationPlan) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleApplicationPlan.
+func (in *ThreeScaleApplicationPlan) DeepCopy() *ThreeScaleApplicationPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ThreeScaleApplicationPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreeScaleBackend) DeepCopyInto(out *ThreeScaleBackend) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleBackend.
+func (in *ThreeScaleBackend) DeepCopy() *ThreeScaleBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(ThreeScaleBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThreeScaleBackend) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreeScaleBackendList) DeepCopyInto(out *ThreeScaleBackendList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ThreeScaleBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleBackendList.
+func (in *ThreeScaleBackendList) DeepCopy() *ThreeScaleBackendList {
 	if in == nil {
 		return nil
 	}
-	out := new(AlertingEmailAddresses)
+	out := new(ThreeScaleBackendList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThreeScaleBackendList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Backup) DeepCopyInto(out *Backup) {
+func (in *ThreeScaleBackendSpec) DeepCopyInto(out *ThreeScaleBackendSpec) {
 	*out = *in
+	if in.MappingRules != nil {
+		in, out := &in.MappingRules, &out.MappingRules
+		*out = make([]ThreeScaleMappingRule, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backup.
-func (in *Backup) DeepCopy() *Backup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleBackendSpec.
+func (in *ThreeScaleBackendSpec) DeepCopy() *ThreeScaleBackendSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Backup)
+	out := new(ThreeScaleBackendSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Maintenance) DeepCopyInto(out *Maintenance) {
+func (in *ThreeScaleBackendStatus) DeepCopyInto(out *ThreeScaleBackendStatus) {
 	*out = *in
+	in.ThreeScaleSyncStatus.DeepCopyInto(&out.ThreeScaleSyncStatus)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Maintenance.
-func (in *Maintenance) DeepCopy() *Maintenance {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleBackendStatus.
+func (in *ThreeScaleBackendStatus) DeepCopy() *ThreeScaleBackendStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Maintenance)
+	out := new(ThreeScaleBackendStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PullSecretSpec) DeepCopyInto(out *PullSecretSpec) {
+func (in *ThreeScaleBackendUsage) DeepCopyInto(out *ThreeScaleBackendUsage) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullSecretSpec.
-func (in *PullSecretSpec) DeepCopy() *PullSecretSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleBackendUsage.
+func (in *ThreeScaleBackendUsage) DeepCopy() *ThreeScaleBackendUsage {
 	if in == nil {
 		return nil
 	}
-	out := new(PullSecretSpec)
+	out := new(ThreeScaleBackendUsage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RHMI) DeepCopyInto(out *RHMI) {
+func (in *ThreeScaleMappingRule) DeepCopyInto(out *ThreeScaleMappingRule) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMI.
-func (in *RHMI) DeepCopy() *RHMI {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleMappingRule.
+func (in *ThreeScaleMappingRule) DeepCopy() *ThreeScaleMappingRule {
 	if in == nil {
 		return nil
 	}
-	out := new(RHMI)
+	out := new(ThreeScaleMappingRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RHMI) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreeScalePolicy) DeepCopyInto(out *ThreeScalePolicy) {
+	*out = *in
+	if in.Configuration != nil {
+		in, out := &in.Configuration, &out.Configuration
+		*out = new(v1beta1.JSON)
+		(*in).DeepCopyInto(*out)
 	}
-	return nil
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScalePolicy.
+func (in *ThreeScalePolicy) DeepCopy() *ThreeScalePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ThreeScalePolicy)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RHMIConfig) DeepCopyInto(out *RHMIConfig) {
+func (in *ThreeScaleProduct) DeepCopyInto(out *ThreeScaleProduct) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -126,18 +1179,18 @@ func (in *RHMIConfig) DeepCopyInto(out *RHMIConfig) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfig.
-func (in *RHMIConfig) DeepCopy() *RHMIConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleProduct.
+func (in *ThreeScaleProduct) DeepCopy() *ThreeScaleProduct {
 	if in == nil {
 		return nil
 	}
-	out := new(RHMIConfig)
+	out := new(ThreeScaleProduct)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RHMIConfig) DeepCopyObject() runtime.Object {
+func (in *ThreeScaleProduct) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -145,13 +1198,13 @@ func (in *RHMIConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RHMIConfigList) DeepCopyInto(out *RHMIConfigList) {
+func (in *ThreeScaleProductList) DeepCopyInto(out *ThreeScaleProductList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]RHMIConfig, len(*in))
+		*out = make([]ThreeScaleProduct, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -159,18 +1212,18 @@ func (in *RHMIConfigList) DeepCopyInto(out *RHMIConfigList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfigList.
-func (in *RHMIConfigList) DeepCopy() *RHMIConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleProductList.
+func (in *ThreeScaleProductList) DeepCopy() *ThreeScaleProductList {
 	if in == nil {
 		return nil
 	}
-	out := new(RHMIConfigList)
+	out := new(ThreeScaleProductList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RHMIConfigList) DeepCopyObject() runtime.Object {
+func (in *ThreeScaleProductList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -178,92 +1231,159 @@ func (in *RHMIConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RHMIConfigSpec) DeepCopyInto(out *RHMIConfigSpec) {
+func (in *ThreeScaleProductSpec) DeepCopyInto(out *ThreeScaleProductSpec) {
 	*out = *in
-	in.Upgrade.DeepCopyInto(&out.Upgrade)
-	out.Maintenance = in.Maintenance
-	out.Backup = in.Backup
+	if in.BackendUsages != nil {
+		in, out := &in.BackendUsages, &out.BackendUsages
+		*out = make([]ThreeScaleBackendUsage, len(*in))
+		copy(*out, *in)
+	}
+	if in.MappingRules != nil {
+		in, out := &in.MappingRules, &out.MappingRules
+		*out = make([]ThreeScaleMappingRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.ApplicationPlans != nil {
+		in, out := &in.ApplicationPlans, &out.ApplicationPlans
+		*out = make([]ThreeScaleApplicationPlan, len(*in))
+		copy(*out, *in)
+	}
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]ThreeScalePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfigSpec.
-func (in *RHMIConfigSpec) DeepCopy() *RHMIConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleProductSpec.
+func (in *ThreeScaleProductSpec) DeepCopy() *ThreeScaleProductSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RHMIConfigSpec)
+	out := new(ThreeScaleProductSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RHMIConfigStatus) DeepCopyInto(out *RHMIConfigStatus) {
+func (in *ThreeScaleProductStatus) DeepCopyInto(out *ThreeScaleProductStatus) {
 	*out = *in
-	out.Maintenance = in.Maintenance
-	in.Upgrade.DeepCopyInto(&out.Upgrade)
-	if in.UpgradeAvailable != nil {
-		in, out := &in.UpgradeAvailable, &out.UpgradeAvailable
-		*out = new(UpgradeAvailable)
-		(*in).DeepCopyInto(*out)
+	in.ThreeScaleSyncStatus.DeepCopyInto(&out.ThreeScaleSyncStatus)
+	if in.ManagedApplicationPlans != nil {
+		in, out := &in.ManagedApplicationPlans, &out.ManagedApplicationPlans
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedBackendUsages != nil {
+		in, out := &in.ManagedBackendUsages, &out.ManagedBackendUsages
+		*out = make([]int, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfigStatus.
-func (in *RHMIConfigStatus) DeepCopy() *RHMIConfigStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleProductStatus.
+func (in *ThreeScaleProductStatus) DeepCopy() *ThreeScaleProductStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RHMIConfigStatus)
+	out := new(ThreeScaleProductStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RHMIConfigStatusMaintenance) DeepCopyInto(out *RHMIConfigStatusMaintenance) {
+func (in *ThreeScaleSpec) DeepCopyInto(out *ThreeScaleSpec) {
 	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make(map[string]ComponentSizing, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfigStatusMaintenance.
-func (in *RHMIConfigStatusMaintenance) DeepCopy() *RHMIConfigStatusMaintenance {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleSpec.
+func (in *ThreeScaleSpec) DeepCopy() *ThreeScaleSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RHMIConfigStatusMaintenance)
+	out := new(ThreeScaleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RHMIConfigStatusUpgrade) DeepCopyInto(out *RHMIConfigStatusUpgrade) {
+func (in *ThreeScaleSyncStatus) DeepCopyInto(out *ThreeScaleSyncStatus) {
 	*out = *in
-	if in.Scheduled != nil {
-		in, out := &in.Scheduled, &out.Scheduled
-		*out = new(UpgradeSchedule)
-		**out = **in
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ManagedMappingRules != nil {
+		in, out := &in.ManagedMappingRules, &out.ManagedMappingRules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfigStatusUpgrade.
-func (in *RHMIConfigStatusUpgrade) DeepCopy() *RHMIConfigStatusUpgrade {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleSyncStatus.
+func (in *ThreeScaleSyncStatus) DeepCopy() *ThreeScaleSyncStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RHMIConfigStatusUpgrade)
+	out := new(ThreeScaleSyncStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RHMIList) DeepCopyInto(out *RHMIList) {
+func (in *ThreeScaleTenant) DeepCopyInto(out *ThreeScaleTenant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleTenant.
+func (in *ThreeScaleTenant) DeepCopy() *ThreeScaleTenant {
+	if in == nil {
+		return nil
+	}
+	out := new(ThreeScaleTenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ThreeScaleTenant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreeScaleTenantList) DeepCopyInto(out *ThreeScaleTenantList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]RHMI, len(*in))
+		*out = make([]ThreeScaleTenant, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -271,18 +1391,18 @@ func (in *RHMIList) DeepCopyInto(out *RHMIList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIList.
-func (in *RHMIList) DeepCopy() *RHMIList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleTenantList.
+func (in *ThreeScaleTenantList) DeepCopy() *ThreeScaleTenantList {
 	if in == nil {
 		return nil
 	}
-	out := new(RHMIList)
+	out := new(ThreeScaleTenantList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RHMIList) DeepCopyObject() runtime.Object {
+func (in *ThreeScaleTenantList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -290,68 +1410,74 @@ func (in *RHMIList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RHMIProductStatus) DeepCopyInto(out *RHMIProductStatus) {
+func (in *ThreeScaleTenantSpec) DeepCopyInto(out *ThreeScaleTenantSpec) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIProductStatus.
-func (in *RHMIProductStatus) DeepCopy() *RHMIProductStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleTenantSpec.
+func (in *ThreeScaleTenantSpec) DeepCopy() *ThreeScaleTenantSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RHMIProductStatus)
+	out := new(ThreeScaleTenantSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RHMISpec) DeepCopyInto(out *RHMISpec) {
+func (in *ThreeScaleTenantStatus) DeepCopyInto(out *ThreeScaleTenantStatus) {
 	*out = *in
-	out.PullSecret = in.PullSecret
-	out.AlertingEmailAddresses = in.AlertingEmailAddresses
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMISpec.
-func (in *RHMISpec) DeepCopy() *RHMISpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleTenantStatus.
+func (in *ThreeScaleTenantStatus) DeepCopy() *ThreeScaleTenantStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RHMISpec)
+	out := new(ThreeScaleTenantStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RHMIStageStatus) DeepCopyInto(out *RHMIStageStatus) {
+func (in *TopologyPolicy) DeepCopyInto(out *TopologyPolicy) {
 	*out = *in
-	if in.Products != nil {
-		in, out := &in.Products, &out.Products
-		*out = make(map[ProductName]RHMIProductStatus, len(*in))
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIStageStatus.
-func (in *RHMIStageStatus) DeepCopy() *RHMIStageStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyPolicy.
+func (in *TopologyPolicy) DeepCopy() *TopologyPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(RHMIStageStatus)
+	out := new(TopologyPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RHMIStatus) DeepCopyInto(out *RHMIStatus) {
+func (in *TopologySpec) DeepCopyInto(out *TopologySpec) {
 	*out = *in
-	if in.Stages != nil {
-		in, out := &in.Stages, &out.Stages
-		*out = make(map[StageName]RHMIStageStatus, len(*in))
+	in.Default.DeepCopyInto(&out.Default)
+	if in.Products != nil {
+		in, out := &in.Products, &out.Products
+		*out = make(map[ProductName]TopologyPolicy, len(*in))
 		for key, val := range *in {
 			(*out)[key] = *val.DeepCopy()
 		}
@@ -359,12 +1485,12 @@ func (in *RHMIStatus) DeepCopyInto(out *RHMIStatus) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIStatus.
-func (in *RHMIStatus) DeepCopy() *RHMIStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologySpec.
+func (in *TopologySpec) DeepCopy() *TopologySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RHMIStatus)
+	out := new(TopologySpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -404,6 +1530,11 @@ func (in *Upgrade) DeepCopy() *Upgrade {
 func (in *UpgradeAvailable) DeepCopyInto(out *UpgradeAvailable) {
 	*out = *in
 	in.AvailableAt.DeepCopyInto(&out.AvailableAt)
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(UpgradeMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -417,6 +1548,64 @@ func (in *UpgradeAvailable) DeepCopy() *UpgradeAvailable {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeMetadata) DeepCopyInto(out *UpgradeMetadata) {
+	*out = *in
+	if in.ProductVersionChanges != nil {
+		in, out := &in.ProductVersionChanges, &out.ProductVersionChanges
+		*out = make([]ProductVersionChange, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeMetadata.
+func (in *UpgradeMetadata) DeepCopy() *UpgradeMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeNotificationsStatus) DeepCopyInto(out *UpgradeNotificationsStatus) {
+	*out = *in
+	if in.Sent != nil {
+		in, out := &in.Sent, &out.Sent
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeNotificationsStatus.
+func (in *UpgradeNotificationsStatus) DeepCopy() *UpgradeNotificationsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeNotificationsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeRestorePoint) DeepCopyInto(out *UpgradeRestorePoint) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeRestorePoint.
+func (in *UpgradeRestorePoint) DeepCopy() *UpgradeRestorePoint {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeRestorePoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpgradeSchedule) DeepCopyInto(out *UpgradeSchedule) {
 	*out = *in
@@ -432,3 +1621,70 @@ func (in *UpgradeSchedule) DeepCopy() *UpgradeSchedule {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserSSO) DeepCopyInto(out *UserSSO) {
+	*out = *in
+	if in.IdentityProviders != nil {
+		in, out := &in.IdentityProviders, &out.IdentityProviders
+		*out = make([]UserSSOIdentityProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserSSO.
+func (in *UserSSO) DeepCopy() *UserSSO {
+	if in == nil {
+		return nil
+	}
+	out := new(UserSSO)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserSSOGroupMapping) DeepCopyInto(out *UserSSOGroupMapping) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserSSOGroupMapping.
+func (in *UserSSOGroupMapping) DeepCopy() *UserSSOGroupMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(UserSSOGroupMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserSSOIdentityProvider) DeepCopyInto(out *UserSSOIdentityProvider) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.GroupMappings != nil {
+		in, out := &in.GroupMappings, &out.GroupMappings
+		*out = make([]UserSSOGroupMapping, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserSSOIdentityProvider.
+func (in *UserSSOIdentityProvider) DeepCopy() *UserSSOIdentityProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(UserSSOIdentityProvider)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -173,12 +173,66 @@ func schema_apis_integreatly_v1alpha1__RHMISpec(ref common.ReferenceCallback) co
 							Format:      "",
 						},
 					},
+					"threeScale": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ThreeScale configures the sizing of the 3scale components",
+							Ref:         ref("./pkg/apis/integreatly/v1alpha1/.ThreeScaleSpec"),
+						},
+					},
+					"marin3r": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Marin3r configures the rate limit service",
+							Ref:         ref("./pkg/apis/integreatly/v1alpha1/.Marin3rSpec"),
+						},
+					},
+					"topology": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Topology configures the placement of the product pods across zones and nodes",
+							Ref:         ref("./pkg/apis/integreatly/v1alpha1/.TopologySpec"),
+						},
+					},
+					"rhssoUser": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RHSSOUser configures the user facing RHSSO",
+							Ref:         ref("./pkg/apis/integreatly/v1alpha1/.RHSSOUserSpec"),
+						},
+					},
+					"datastores": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Datastores configures how the Postgres databases and Redis caches of 3scale, RHSSO, CodeReady, Fuse and Apicurio Registry are provided",
+							Ref:         ref("./pkg/apis/integreatly/v1alpha1/.DatastoresSpec"),
+						},
+					},
+					"monitoring": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Monitoring configures the metrics exported from the middleware monitoring Prometheus",
+							Ref:         ref("./pkg/apis/integreatly/v1alpha1/.MonitoringSpec"),
+						},
+					},
+					"apicurioRegistry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ApicurioRegistry configures the storage of the Apicurio Registry",
+							Ref:         ref("./pkg/apis/integreatly/v1alpha1/.ApicurioRegistrySpec"),
+						},
+					},
+					"amqOnline": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AMQOnline configures the plans and infra configs of AMQ Online",
+							Ref:         ref("./pkg/apis/integreatly/v1alpha1/.AMQOnlineSpec"),
+						},
+					},
+					"codeReady": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CodeReady configures the workspaces of CodeReady Workspaces",
+							Ref:         ref("./pkg/apis/integreatly/v1alpha1/.CodeReadySpec"),
+						},
+					},
 				},
 				Required: []string{"type", "namespacePrefix"},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/integreatly/v1alpha1/.AlertingEmailAddresses", "./pkg/apis/integreatly/v1alpha1/.PullSecretSpec"},
+			"./pkg/apis/integreatly/v1alpha1/.AMQOnlineSpec", "./pkg/apis/integreatly/v1alpha1/.AlertingEmailAddresses", "./pkg/apis/integreatly/v1alpha1/.ApicurioRegistrySpec", "./pkg/apis/integreatly/v1alpha1/.CodeReadySpec", "./pkg/apis/integreatly/v1alpha1/.DatastoresSpec", "./pkg/apis/integreatly/v1alpha1/.Marin3rSpec", "./pkg/apis/integreatly/v1alpha1/.MonitoringSpec", "./pkg/apis/integreatly/v1alpha1/.PullSecretSpec", "./pkg/apis/integreatly/v1alpha1/.RHSSOUserSpec", "./pkg/apis/integreatly/v1alpha1/.ThreeScaleSpec", "./pkg/apis/integreatly/v1alpha1/.TopologySpec"},
 	}
 }
 
@@ -251,11 +305,26 @@ func schema_apis_integreatly_v1alpha1__RHMIStatus(ref common.ReferenceCallback)
 							Format: "",
 						},
 					},
+					"lastUpgrade": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/integreatly/v1alpha1/.RHMIUpgradeStatus"),
+						},
+					},
+					"topology": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/integreatly/v1alpha1/.RHMITopologyStatus"),
+						},
+					},
+					"monitoring": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/integreatly/v1alpha1/.RHMIMonitoringStatus"),
+						},
+					},
 				},
 				Required: []string{"stages", "stage", "lastError"},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/integreatly/v1alpha1/.RHMIStageStatus"},
+			"./pkg/apis/integreatly/v1alpha1/.RHMIMonitoringStatus", "./pkg/apis/integreatly/v1alpha1/.RHMIStageStatus", "./pkg/apis/integreatly/v1alpha1/.RHMITopologyStatus", "./pkg/apis/integreatly/v1alpha1/.RHMIUpgradeStatus"},
 	}
 }
@@ -35,6 +35,12 @@ func (f *DataSync) GetHost() string {
 	return f.config["HOST"]
 }
 
+// GetTemplatesURL returns the URL the templates are downloaded from instead
+// of being read from the operator image. It's empty unless overridden
+func (f *DataSync) GetTemplatesURL() string {
+	return f.config["TEMPLATES_URL"]
+}
+
 func (f *DataSync) GetProductName() integreatlyv1alpha1.ProductName {
 	return integreatlyv1alpha1.ProductDataSync
 }
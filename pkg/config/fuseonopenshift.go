@@ -34,6 +34,12 @@ func (f *FuseOnOpenshift) GetHost() string {
 	return f.config["HOST"]
 }
 
+// GetTemplatesURL returns the URL the templates are downloaded from instead
+// of being read from the operator image. It's empty unless overridden
+func (f *FuseOnOpenshift) GetTemplatesURL() string {
+	return f.config["TEMPLATES_URL"]
+}
+
 func (f *FuseOnOpenshift) GetProductName() integreatlyv1alpha1.ProductName {
 	return integreatlyv1alpha1.ProductFuseOnOpenshift
 }
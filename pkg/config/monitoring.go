@@ -135,6 +135,22 @@ func (m *Monitoring) GetAdditionalScrapeConfigSecretKey() string {
 	return "integreatly-additional.yaml"
 }
 
+// GetBlackboxExporterConfigMapName returns the config map with the modules of
+// the blackbox exporter deployed by the application monitoring operator
+func (m *Monitoring) GetBlackboxExporterConfigMapName() string {
+	return "blackbox-exporter-config"
+}
+
+func (m *Monitoring) GetBlackboxExporterConfigMapKey() string {
+	return "blackbox.yml"
+}
+
+// GetBlackboxExporterAddress returns the address of the blackbox exporter,
+// which runs in the Prometheus pod
+func (m *Monitoring) GetBlackboxExporterAddress() string {
+	return "127.0.0.1:9115"
+}
+
 func (m *Monitoring) GetPrometheusRetention() string {
 	return "45d"
 }
@@ -2,6 +2,9 @@ package config
 
 import (
 	"errors"
+	"strings"
+	"time"
+
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
 	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -91,6 +94,53 @@ func (r *RHSSOCommon) SetHost(newHost string) {
 	r.Config["HOST"] = newHost
 }
 
+// GetRealmExportTime returns when the realm was last exported to the backup
+// storage, or the zero time if it never was
+func (r *RHSSOCommon) GetRealmExportTime() time.Time {
+	exportTime, err := time.Parse(time.RFC3339, r.Config["REALM_EXPORT_TIME"])
+	if err != nil {
+		return time.Time{}
+	}
+	return exportTime
+}
+
+func (r *RHSSOCommon) SetRealmExportTime(exportTime time.Time) {
+	r.Config["REALM_EXPORT_TIME"] = exportTime.UTC().Format(time.RFC3339)
+}
+
+// GetRealmExportKey returns the key of the last realm export in the backup
+// storage
+func (r *RHSSOCommon) GetRealmExportKey() string {
+	return r.Config["REALM_EXPORT_KEY"]
+}
+
+func (r *RHSSOCommon) SetRealmExportKey(key string) {
+	r.Config["REALM_EXPORT_KEY"] = key
+}
+
+// GetRealmImportedKey returns the key of the last realm export imported from
+// the backup storage
+func (r *RHSSOCommon) GetRealmImportedKey() string {
+	return r.Config["REALM_IMPORTED_KEY"]
+}
+
+func (r *RHSSOCommon) SetRealmImportedKey(key string) {
+	r.Config["REALM_IMPORTED_KEY"] = key
+}
+
+// GetManagedIdentityProviders returns the aliases of the identity providers
+// federated into the realm from the RHMIConfig
+func (r *RHSSOCommon) GetManagedIdentityProviders() []string {
+	if r.Config["MANAGED_IDENTITY_PROVIDERS"] == "" {
+		return nil
+	}
+	return strings.Split(r.Config["MANAGED_IDENTITY_PROVIDERS"], ",")
+}
+
+func (r *RHSSOCommon) SetManagedIdentityProviders(aliases []string) {
+	r.Config["MANAGED_IDENTITY_PROVIDERS"] = strings.Join(aliases, ",")
+}
+
 func (r *RHSSOCommon) Read() ProductConfig {
 	return r.Config
 }
@@ -2,6 +2,10 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	threescaleapps "github.com/3scale/3scale-operator/pkg/apis/apps"
 	threescalev1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
@@ -45,6 +49,37 @@ func (t *ThreeScale) SetBlackboxTargetPathForAdminUI(newBlackboxTargetPath strin
 	t.config["BLACKBOX_TARGET_PATH_ADMIN_UI"] = newBlackboxTargetPath
 }
 
+// GetPostgresRotationVersions returns the latest versions of the deployment
+// configs rolled out after the postgres credentials were rotated, while the
+// rotation is being verified
+func (t *ThreeScale) GetPostgresRotationVersions() map[string]int64 {
+	versions := map[string]int64{}
+	if t.config["POSTGRES_ROTATION_VERSIONS"] == "" {
+		return versions
+	}
+	for _, version := range strings.Split(t.config["POSTGRES_ROTATION_VERSIONS"], ",") {
+		parts := strings.SplitN(version, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		latest, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		versions[parts[0]] = latest
+	}
+	return versions
+}
+
+func (t *ThreeScale) SetPostgresRotationVersions(versions map[string]int64) {
+	var entries []string
+	for name, version := range versions {
+		entries = append(entries, fmt.Sprintf("%s=%d", name, version))
+	}
+	sort.Strings(entries)
+	t.config["POSTGRES_ROTATION_VERSIONS"] = strings.Join(entries, ",")
+}
+
 func (t *ThreeScale) GetNamespace() string {
 	return t.config["NAMESPACE"]
 }
@@ -206,6 +206,7 @@ func (r *Reconciler) checkRateLimitsConfig(ctx context.Context, serverClient k8s
 					marin3rconfig.DailySoftLimitTier2,
 					marin3rconfig.DailySoftLimitTier3,
 				},
+				ThreeScaleProfile: string(integreatlyv1alpha1.SizingProfileSmall),
 			},
 		}
 
@@ -2,11 +2,9 @@ package installation
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os"
 	"reflect"
-	"strconv"
 	"strings"
 	"time"
 
@@ -373,6 +371,14 @@ func (r *ReconcileInstallation) Reconcile(request reconcile.Request) (reconcile.
 		return reconcile.Result{}, err
 	}
 
+	if err := r.reconcileTopology(installation); err != nil {
+		installation.Status.LastError = err.Error()
+		if updateErr := r.updateStatusAndObject(originalInstallation, installation); updateErr != nil {
+			return reconcile.Result{}, updateErr
+		}
+		return retryRequeue, err
+	}
+
 	for _, stage := range installType.GetInstallStages() {
 		var err error
 		var stagePhase integreatlyv1alpha1.StatusPhase
@@ -603,6 +609,23 @@ func upgradeFirstReconcile(installation *integreatlyv1alpha1.RHMI) bool {
 	return status.Version != "" && status.ToVersion == "" && status.Version != version.GetVersionByType(installation.Spec.Type)
 }
 
+// reconcileTopology validates the topology of the installation and sets its
+// effective placement policy in the status, so the products read it from there
+func (r *ReconcileInstallation) reconcileTopology(installation *integreatlyv1alpha1.RHMI) error {
+	if err := resources.ValidateTopology(installation.Spec.Topology); err != nil {
+		return fmt.Errorf("invalid topology: %w", err)
+	}
+
+	multiAZ, err := resources.IsMultiAZCluster(context.TODO(), r.client)
+	if err != nil {
+		logrus.Errorf("Error checking if the cluster is multi AZ: %v", err)
+		multiAZ = installation.Status.Topology != nil && installation.Status.Topology.MultiAZ
+	}
+
+	installation.Status.Topology = resources.ResolveTopology(installation, multiAZ)
+	return nil
+}
+
 func (r *ReconcileInstallation) preflightChecks(installation *integreatlyv1alpha1.RHMI, installationType *Type, configManager *config.Manager) (reconcile.Result, error) {
 	logrus.Info("Running preflight checks..")
 	installation.Status.Stage = integreatlyv1alpha1.StageName("Preflight Checks")
@@ -613,14 +636,8 @@ func (r *ReconcileInstallation) preflightChecks(installation *integreatlyv1alpha
 
 	eventRecorder := r.mgr.GetEventRecorderFor("Preflight Checks")
 
-	// Validate the env vars used by the operator
-	if err := checkEnvVars(map[string]func(string, bool) error{
-		resources.AntiAffinityRequiredEnvVar: optionalEnvVar(func(s string) error {
-			_, err := strconv.ParseBool(s)
-			return err
-		}),
-	}); err != nil {
-		return result, err
+	if _, ok := os.LookupEnv(resources.ForceZoneDistributionEnvVar); ok {
+		logrus.Warnf("The %s env var is deprecated, set the zone spread of the RHMI topology instead", resources.ForceZoneDistributionEnvVar)
 	}
 
 	if strings.ToLower(installation.Spec.UseClusterStorage) != "true" && strings.ToLower(installation.Spec.UseClusterStorage) != "false" {
@@ -883,37 +900,6 @@ func (r *ReconcileInstallation) addCustomInformer(crd runtime.Object, namespace
 	return nil
 }
 
-func checkEnvVars(checks map[string]func(string, bool) error) error {
-	for env, check := range checks {
-		value, exists := os.LookupEnv(env)
-		if err := check(value, exists); err != nil {
-			return fmt.Errorf("validation failure for env var %s: %w", env, err)
-		}
-	}
-
-	return nil
-}
-
-func optionalEnvVar(check func(string) error) func(string, bool) error {
-	return func(value string, ok bool) error {
-		if !ok {
-			return nil
-		}
-
-		return check(value)
-	}
-}
-
-func requiredEnvVar(check func(string) error) func(string, bool) error {
-	return func(value string, ok bool) error {
-		if !ok {
-			return errors.New("required env var not present")
-		}
-
-		return check(value)
-	}
-}
-
 type multiErr struct {
 	errors []string
 }
@@ -0,0 +1,284 @@
+package namespacelabel
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// LabelActionsConfigMapName is the name of the ConfigMap in the operator
+	// namespace where the label actions are recorded, one key per action
+	LabelActionsConfigMapName = "namespace-label-actions"
+
+	// confirmationAnnotationPrefix is followed by the action name to build the
+	// annotation that must be set to the value of the label on the namespace
+	// to confirm a destructive action
+	confirmationAnnotationPrefix = "integreatly.org/confirm-"
+)
+
+type LabelActionResult string
+
+const (
+	LabelActionAwaitingConfirmation LabelActionResult = "AwaitingConfirmation"
+	LabelActionSucceeded            LabelActionResult = "Succeeded"
+	LabelActionFailed               LabelActionResult = "Failed"
+)
+
+// LabelAction is an action performed when its label is set on the operator
+// namespace
+type LabelAction struct {
+	// Name of the action, used as the key of its record and to build its
+	// confirmation annotation
+	Name string
+
+	// Label that requests the action
+	Label string
+
+	// If this value is true, the action is only performed once the
+	// confirmation annotation is set to the value of the label on the
+	// namespace. The annotation is removed once the action succeeded, so
+	// every request must be confirmed
+	Destructive bool
+
+	// Run receives the value of the label, the reconcile request and the
+	// reconciler instance
+	Run func(string, reconcile.Request, *ReconcileNamespaceLabel) error
+}
+
+// ConfirmationAnnotation returns the annotation that confirms the action
+func (a *LabelAction) ConfirmationAnnotation() string {
+	return confirmationAnnotationPrefix + a.Name
+}
+
+// LabelActionRecord is the audit record of the last request of an action
+type LabelActionRecord struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+
+	// Requester is the field manager that last set the label, as found in
+	// the managed fields of the namespace
+	Requester   string            `json:"requester,omitempty"`
+	RequestedAt string            `json:"requestedAt,omitempty"`
+	Result      LabelActionResult `json:"result"`
+	Message     string            `json:"message,omitempty"`
+	UpdatedAt   string            `json:"updatedAt"`
+}
+
+// labelActions associates the labels in the namespace to the actions to perform
+var labelActions = map[string]*LabelAction{}
+
+// RegisterLabelAction registers a new action to be performed when its label is
+// set on the operator namespace. It's expected to be called from init functions
+func RegisterLabelAction(action LabelAction) {
+	if errs := validation.IsConfigMapKey(action.Name); len(errs) > 0 {
+		panic(fmt.Sprintf("invalid label action name %s: %v", action.Name, errs))
+	}
+	if _, ok := labelActions[action.Label]; ok {
+		panic(fmt.Sprintf("label action already registered for label %s", action.Label))
+	}
+	labelActions[action.Label] = &action
+}
+
+func init() {
+	// The uninstall labels are set by OCM when the addon is uninstalled, which
+	// can't confirm the action, so they don't require a confirmation
+
+	// Uninstall RHMI
+	RegisterLabelAction(LabelAction{
+		Name:  "rhmi-uninstall",
+		Label: "api.openshift.com/addon-rhmi-operator-delete",
+		Run:   Uninstall,
+	})
+	// Uninstall MAO
+	RegisterLabelAction(LabelAction{
+		Name:  "managed-api-service-uninstall",
+		Label: "api.openshift.com/addon-managed-api-service-delete",
+		Run:   Uninstall,
+	})
+	// Update CIDR value
+	RegisterLabelAction(LabelAction{
+		Name:  "cidr",
+		Label: "cidr",
+		Run:   CheckCidrValueAndUpdate,
+	})
+}
+
+// runLabelActions performs the actions requested by the labels of the
+// namespace, and records each of them with its requester and result. The
+// actions are performed even if they can't be recorded
+func (r *ReconcileNamespaceLabel) runLabelActions(ns *corev1.Namespace, request reconcile.Request) error {
+	labels := []string{}
+	for label := range ns.GetLabels() {
+		if _, ok := labelActions[label]; ok {
+			labels = append(labels, label)
+		}
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	sort.Strings(labels)
+
+	auditConfigMap, err := r.getLabelActionsConfigMap()
+	if err != nil {
+		logrus.Errorf("Failed to get the label actions records: %v", err)
+	}
+	updated := false
+
+	var actionErr error
+	for _, label := range labels {
+		action := labelActions[label]
+		value := ns.GetLabels()[label]
+		requester, requestedAt := getLabelRequester(ns, label)
+
+		record := LabelActionRecord{
+			Label:       label,
+			Value:       value,
+			Requester:   requester,
+			RequestedAt: requestedAt,
+		}
+
+		confirmed := !action.Destructive || ns.GetAnnotations()[action.ConfirmationAnnotation()] == value
+		if !confirmed {
+			record.Result = LabelActionAwaitingConfirmation
+			record.Message = fmt.Sprintf("set the %s annotation to %q on the namespace to confirm the action", action.ConfirmationAnnotation(), value)
+		} else if err := action.Run(value, request, r); err != nil {
+			logrus.Errorf("Label action %s failed: %v", action.Name, err)
+			record.Result = LabelActionFailed
+			record.Message = err.Error()
+			if actionErr == nil {
+				actionErr = err
+			}
+		} else {
+			record.Result = LabelActionSucceeded
+			if action.Destructive {
+				r.clearConfirmation(ns, action)
+			}
+		}
+
+		recorded := auditConfigMap != nil && recordLabelAction(auditConfigMap, action.Name, record)
+		if recorded {
+			updated = true
+		}
+		// the pending state is only logged when it's first recorded
+		if record.Result == LabelActionAwaitingConfirmation && (recorded || auditConfigMap == nil) {
+			logrus.Warnf("Label action %s requested by %s is awaiting confirmation", action.Name, requester)
+		}
+	}
+
+	if updated {
+		if err := r.client.Update(r.context, auditConfigMap); err != nil {
+			logrus.Errorf("Failed to record label actions: %v", err)
+		}
+	}
+
+	return actionErr
+}
+
+// clearConfirmation removes the confirmation annotation of the action from
+// the namespace, so that the next request of the action must be confirmed
+// again
+func (r *ReconcileNamespaceLabel) clearConfirmation(ns *corev1.Namespace, action *LabelAction) {
+	annotations := ns.GetAnnotations()
+	delete(annotations, action.ConfirmationAnnotation())
+	ns.SetAnnotations(annotations)
+	if err := r.client.Update(r.context, ns); err != nil {
+		logrus.Errorf("Failed to remove the confirmation of label action %s: %v", action.Name, err)
+	}
+}
+
+func (r *ReconcileNamespaceLabel) getLabelActionsConfigMap() (*corev1.ConfigMap, error) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      LabelActionsConfigMapName,
+			Namespace: r.operatorNamespace,
+		},
+	}
+
+	err := r.client.Get(r.context, k8sclient.ObjectKey{Name: configMap.Name, Namespace: configMap.Namespace}, configMap)
+	if k8serr.IsNotFound(err) {
+		configMap.Data = map[string]string{}
+		if err := r.client.Create(r.context, configMap); err != nil {
+			return nil, fmt.Errorf("failed to create label actions config map: %w", err)
+		}
+		return configMap, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get label actions config map: %w", err)
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	return configMap, nil
+}
+
+// recordLabelAction stores the record of the action in the config map. The
+// record is only updated when the request or its result change, so repeated
+// reconciles of the same request don't rewrite it. Returns whether the config
+// map was modified
+func recordLabelAction(configMap *corev1.ConfigMap, name string, record LabelActionRecord) bool {
+	if data, ok := configMap.Data[name]; ok {
+		previous := LabelActionRecord{}
+		if err := json.Unmarshal([]byte(data), &previous); err == nil {
+			record.UpdatedAt = previous.UpdatedAt
+			if previous == record {
+				return false
+			}
+		}
+	}
+
+	record.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		logrus.Errorf("failed to marshal label action record: %v", err)
+		return false
+	}
+
+	configMap.Data[name] = string(recordJSON)
+	return true
+}
+
+// getLabelRequester returns the field manager that last set the label on the
+// namespace, and when it did so, from the managed fields of the namespace
+func getLabelRequester(ns *corev1.Namespace, label string) (string, string) {
+	var requester string
+	var requestedAt *metav1.Time
+
+	for _, entry := range ns.GetManagedFields() {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+
+		fields := struct {
+			Metadata struct {
+				Labels map[string]interface{} `json:"f:labels"`
+			} `json:"f:metadata"`
+		}{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		if _, ok := fields.Metadata.Labels["f:"+label]; !ok {
+			continue
+		}
+
+		if requestedAt == nil || (entry.Time != nil && requestedAt.Before(entry.Time)) {
+			requester = entry.Manager
+			requestedAt = entry.Time
+		}
+	}
+
+	if requestedAt == nil {
+		return requester, ""
+	}
+	return requester, requestedAt.UTC().Format(time.RFC3339)
+}
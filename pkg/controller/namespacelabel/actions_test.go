@@ -0,0 +1,174 @@
+package namespacelabel
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	defaultNamespace = "testing-namespaces-operator"
+	uninstallLabel   = "api.openshift.com/addon-rhmi-operator-delete"
+)
+
+func buildScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	integreatlyv1alpha1.SchemeBuilder.AddToScheme(scheme)
+	corev1.SchemeBuilder.AddToScheme(scheme)
+	return scheme
+}
+
+func buildNamespace(annotations map[string]string) *corev1.Namespace {
+	labelTime := metav1.NewTime(time.Date(2020, time.October, 1, 12, 0, 0, 0, time.UTC))
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        defaultNamespace,
+			Labels:      map[string]string{uninstallLabel: "true"},
+			Annotations: annotations,
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:  "kube-controller-manager",
+					FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:phase":{}}}`)},
+				},
+				{
+					Manager:  "ocm-agent",
+					Time:     &labelTime,
+					FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{".":{},"f:` + uninstallLabel + `":{}}}}`)},
+				},
+			},
+		},
+	}
+}
+
+func getRecord(t *testing.T, client k8sclient.Client, name string) LabelActionRecord {
+	configMap := &corev1.ConfigMap{}
+	if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: LabelActionsConfigMapName, Namespace: defaultNamespace}, configMap); err != nil {
+		t.Fatalf("Unexpected error getting label actions config map: %v", err)
+	}
+
+	record := LabelActionRecord{}
+	if err := json.Unmarshal([]byte(configMap.Data[name]), &record); err != nil {
+		t.Fatalf("Unexpected error reading record of %s: %v", name, err)
+	}
+	return record
+}
+
+func TestRunLabelActions(t *testing.T) {
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rhmi",
+			Namespace: defaultNamespace,
+		},
+	}
+	client := fake.NewFakeClientWithScheme(buildScheme(), installation)
+	r := &ReconcileNamespaceLabel{
+		client:            client,
+		operatorNamespace: defaultNamespace,
+		context:           context.TODO(),
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: defaultNamespace, Namespace: defaultNamespace}}
+
+	// The uninstall requested by OCM is performed without confirmation
+	if err := r.runLabelActions(buildNamespace(nil), request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	record := getRecord(t, client, "rhmi-uninstall")
+	if record.Result != LabelActionSucceeded {
+		t.Fatalf("Expected result %s, got %s", LabelActionSucceeded, record.Result)
+	}
+	if record.Requester != "ocm-agent" || record.RequestedAt != "2020-10-01T12:00:00Z" {
+		t.Fatalf("Unexpected requester %s at %s", record.Requester, record.RequestedAt)
+	}
+	if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: installation.Name, Namespace: installation.Namespace}, &integreatlyv1alpha1.RHMI{}); err == nil {
+		t.Fatal("Expected installation to be deleted")
+	}
+}
+
+func TestRunLabelActions_Destructive(t *testing.T) {
+	const label = "integreatly.org/test-destructive"
+	runs := 0
+	RegisterLabelAction(LabelAction{
+		Name:        "test-destructive",
+		Label:       label,
+		Destructive: true,
+		Run: func(string, reconcile.Request, *ReconcileNamespaceLabel) error {
+			runs++
+			return nil
+		},
+	})
+	defer delete(labelActions, label)
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   defaultNamespace,
+			Labels: map[string]string{label: "2020-10-01"},
+		},
+	}
+	client := fake.NewFakeClientWithScheme(buildScheme(), ns)
+	r := &ReconcileNamespaceLabel{
+		client:            client,
+		operatorNamespace: defaultNamespace,
+		context:           context.TODO(),
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: defaultNamespace, Namespace: defaultNamespace}}
+
+	// The action waits for a confirmation of the requested value
+	for _, confirmation := range []string{"", "true"} {
+		ns := ns.DeepCopy()
+		ns.Annotations = map[string]string{"integreatly.org/confirm-test-destructive": confirmation}
+		if err := r.runLabelActions(ns, request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if record := getRecord(t, client, "test-destructive"); record.Result != LabelActionAwaitingConfirmation {
+			t.Fatalf("Expected result %s with confirmation %q, got %s", LabelActionAwaitingConfirmation, confirmation, record.Result)
+		}
+	}
+	if runs != 0 {
+		t.Fatal("Expected action not to be performed before confirmation")
+	}
+
+	// The confirmed action is performed and its confirmation removed
+	if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: defaultNamespace}, ns); err != nil {
+		t.Fatal(err)
+	}
+	ns.Annotations = map[string]string{"integreatly.org/confirm-test-destructive": "2020-10-01"}
+	if err := r.runLabelActions(ns, request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if record := getRecord(t, client, "test-destructive"); record.Result != LabelActionSucceeded || runs != 1 {
+		t.Fatalf("Expected action to be performed once, got result %s after %d runs", record.Result, runs)
+	}
+	updated := &corev1.Namespace{}
+	if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: defaultNamespace}, updated); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := updated.Annotations["integreatly.org/confirm-test-destructive"]; ok {
+		t.Fatal("Expected confirmation to be removed after the action")
+	}
+}
+
+func TestRegisterLabelAction(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected registering a second action for the same label to panic")
+		}
+	}()
+
+	RegisterLabelAction(LabelAction{
+		Name:  "another-cidr",
+		Label: "cidr",
+		Run:   CheckCidrValueAndUpdate,
+	})
+}
@@ -18,7 +18,6 @@ package namespacelabel
 
 import (
 	"context"
-	"encoding/json"
 	"strings"
 	"time"
 
@@ -26,19 +25,18 @@ import (
 
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
 
 	"github.com/sirupsen/logrus"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -48,25 +46,10 @@ import (
 )
 
 var (
-	log           = logf.Log.WithName("controller_namespace_label")
-	configMapName = "cloud-resources-aws-strategies"
+	log            = logf.Log.WithName("controller_namespace_label")
+	rhmiConfigName = "rhmi-config"
 )
 
-//  patchStringValue specifies a patch operation for a string.
-type patchStringValue struct {
-	Op    string `json:"op"`
-	Path  string `json:"path"`
-	Value string `json:"value"`
-}
-
-type network struct {
-	Production struct {
-		CreateStrategy struct {
-			CidrBlock string `json:"CidrBlock"`
-		} `json:"createStrategy"`
-	} `json:"production"`
-}
-
 // Add creates a new namespacelabel Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
@@ -77,21 +60,6 @@ func Add(mgr manager.Manager) error {
 	return add(mgr, reconcile)
 }
 
-var (
-	// Map that associates the labels in the namespace to actions to perform
-	// - Keys are labels that might be set to the namespace object
-	// - Values are functions that receive the value of the label, the reconcile request,
-	//   and the reconciler instance.
-	namespaceLabelBasedActions = map[string]func(string, reconcile.Request, *ReconcileNamespaceLabel) error{
-		// Uninstall RHMI
-		"api.openshift.com/addon-rhmi-operator-delete": Uninstall,
-		// Uninstall MAO
-		"api.openshift.com/addon-managed-api-service-delete": Uninstall,
-		// Update CIDR value
-		"cidr": CheckCidrValueAndUpdate,
-	}
-)
-
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -185,20 +153,9 @@ func GetNS(ctx context.Context, namespace string, client k8sclient.Client) (*cor
 	return ns, err
 }
 
-// CheckLabel Checks namespace for labels and
-func (r *ReconcileNamespaceLabel) CheckLabel(o metav1.Object, request reconcile.Request) error {
-	for k, v := range o.GetLabels() {
-		action, ok := namespaceLabelBasedActions[k]
-		if !ok {
-			continue
-		}
-
-		if err := action(v, request, r); err != nil {
-			return err
-		}
-	}
-
-	return nil
+// CheckLabel performs and records the actions requested by the labels of the namespace
+func (r *ReconcileNamespaceLabel) CheckLabel(ns *corev1.Namespace, request reconcile.Request) error {
+	return r.runLabelActions(ns, request)
 }
 
 // Uninstall deletes rhmi cr when uninstall label is set
@@ -231,76 +188,31 @@ func Uninstall(v string, request reconcile.Request, r *ReconcileNamespaceLabel)
 	return nil
 }
 
-// CheckCidrValueAndUpdate Checks cidr value and updates it in the configmap if the config map value is ""
+// CheckCidrValueAndUpdate sets the cidr value as the network CIDR of the
+// RHMIConfig if no CIDR has been set yet. The RHMIConfig controller validates
+// it and applies it to the cloud resource strategies
 func CheckCidrValueAndUpdate(value string, request reconcile.Request, r *ReconcileNamespaceLabel) error {
 	logrus.Infof("Cidr value : %v, passed in as a namespace label", value)
-	cfgMap := &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      configMapName,
-			Namespace: request.NamespacedName.Name,
-		},
-	}
 
-	err := r.client.Get(context.TODO(), k8sclient.ObjectKey{Name: configMapName, Namespace: request.NamespacedName.Name}, cfgMap)
+	rhmiConfig := &integreatlyv1alpha1.RHMIConfig{}
+	err := r.client.Get(r.context, k8sclient.ObjectKey{Name: rhmiConfigName, Namespace: request.NamespacedName.Name}, rhmiConfig)
 	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
 		return err
 	}
-	data := []byte(cfgMap.Data["_network"])
-
-	var cfgMapData network
-
-	// Unmarshal or Decode the JSON to the interface.
-	err = json.Unmarshal([]byte(data), &cfgMapData)
-	if err != nil {
-		logrus.Error(err)
-	}
-
-	cidr := cfgMapData.Production.CreateStrategy.CidrBlock
 
-	if cidr != "" {
-		logrus.Infof("Cidr value is already set to : %v , not updating", cidr)
+	if rhmiConfig.Spec.Network.CIDR != "" {
+		logrus.Infof("Cidr value is already set to : %v , not updating", rhmiConfig.Spec.Network.CIDR)
 		return nil
 	}
 
 	// replace - character from label with / so that the cidr value is set correctly.
 	// / is not a valid character in namespace label values.
 	newCidr := strings.Replace(value, "-", "/", -1)
-	logrus.Infof("No cidr has been set in configmap yet, Setting cidr from namespace label : %v", newCidr)
-
-	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		clientcmd.NewDefaultClientConfigLoadingRules(),
-		&clientcmd.ConfigOverrides{CurrentContext: ""}).ClientConfig()
-	if err != nil {
-		return err
-	}
-
-	// Creates the clientset
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return err
-	}
-
-	cfgMapData.Production.CreateStrategy.CidrBlock = newCidr
-	dataValue, err := json.Marshal(cfgMapData)
+	logrus.Infof("No cidr has been set in rhmi config yet, Setting cidr from namespace label : %v", newCidr)
 
-	if err != nil {
-		return err
-	}
-
-	payload := []patchStringValue{{
-		Op:    "add",
-		Path:  "/data/_network",
-		Value: string(dataValue),
-	}}
-
-	payloadBytes, _ := json.Marshal(payload)
-	_, err = clientset.
-		CoreV1().
-		ConfigMaps(request.NamespacedName.Name).
-		Patch(configMapName, types.JSONPatchType, payloadBytes)
-
-	if err != nil {
-		return err
-	}
-	return nil
+	rhmiConfig.Spec.Network.CIDR = newCidr
+	return r.client.Update(r.context, rhmiConfig)
 }
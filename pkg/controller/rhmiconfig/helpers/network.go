@@ -0,0 +1,317 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+
+	crov1alpha1 "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croUtil "github.com/integr8ly/cloud-resource-operator/pkg/client"
+	croProviders "github.com/integr8ly/cloud-resource-operator/pkg/providers"
+	croAWS "github.com/integr8ly/cloud-resource-operator/pkg/providers/aws"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NetworkStrategyKey is the key of the network strategy in the CRO strategy
+// config maps
+const NetworkStrategyKey = "_network"
+
+// networkStrategyMaps associates the CRO providers that create the datastores
+// in a cloud network to the strategy config map the network CIDR is applied
+// to. The in-cluster providers create them in the cluster network instead, so
+// the CIDR doesn't apply to them
+var networkStrategyMaps = map[string]string{
+	croProviders.AWSDeploymentStrategy: croAWS.DefaultConfigMapName,
+}
+
+var inClusterProviders = []string{croProviders.OpenShiftDeploymentStrategy}
+
+// cloudResourceConfigName is the config map of the CRO providers used for
+// each installation type
+const cloudResourceConfigName = "cloud-resource-config"
+
+// ReconcileNetwork validates the network CIDR of the RHMIConfig and applies it
+// to the strategy config maps of the CRO providers creating the datastores. Once cloud resources
+// have been created the CIDR is locked and can no longer be changed. Invalid
+// configurations are reported in the network status rather than returned as
+// errors, as retrying won't fix them
+func ReconcileNetwork(ctx context.Context, client k8sclient.Client, config *integreatlyv1alpha1.RHMIConfig) error {
+	cidr := config.Spec.Network.CIDR
+	if cidr == "" {
+		return nil
+	}
+
+	status := &integreatlyv1alpha1.RHMIConfigStatusNetwork{}
+	if config.Status.Network != nil {
+		*status = *config.Status.Network
+	}
+
+	if err := applyNetwork(ctx, client, config.Namespace, cidr, status); err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(status, config.Status.Network) {
+		return nil
+	}
+
+	if status.Error != "" {
+		logrus.Errorf("failed to apply network configuration : %s", status.Error)
+	}
+	config.Status.Network = status
+	return client.Status().Update(ctx, config)
+}
+
+func applyNetwork(ctx context.Context, client k8sclient.Client, namespace, cidr string, status *integreatlyv1alpha1.RHMIConfigStatusNetwork) error {
+	status.Error = ""
+
+	if status.Locked && status.CIDR != cidr {
+		status.Error = fmt.Sprintf("network CIDR can't be changed from %s as cloud resources have already been created in it", status.CIDR)
+		return nil
+	}
+
+	ipNet, err := integreatlyv1alpha1.ValidateNetworkCIDR(cidr)
+	if err != nil {
+		status.Error = err.Error()
+		return nil
+	}
+
+	clusterNetworks, err := getClusterNetworks(ctx, client)
+	if err != nil {
+		return err
+	}
+	for _, clusterNetwork := range clusterNetworks {
+		if networksOverlap(ipNet, clusterNetwork) {
+			status.Error = fmt.Sprintf("network CIDR %s overlaps with cluster network %s", cidr, clusterNetwork.String())
+			return nil
+		}
+	}
+
+	infra := &configv1.Infrastructure{}
+	if err := client.Get(ctx, k8sclient.ObjectKey{Name: "cluster"}, infra); err != nil {
+		return fmt.Errorf("failed to get cluster infrastructure : %w", err)
+	}
+	platform := infra.Status.Platform
+	if infra.Status.PlatformStatus != nil {
+		platform = infra.Status.PlatformStatus.Type
+	}
+	status.Platform = string(platform)
+
+	configMapNames, unsupportedProvider, err := getNetworkStrategyMaps(ctx, client, namespace)
+	if err != nil {
+		return err
+	}
+	if unsupportedProvider != "" {
+		status.Error = fmt.Sprintf("network configuration is not supported by cloud resource provider %s", unsupportedProvider)
+		return nil
+	}
+	if len(configMapNames) == 0 {
+		logrus.Infof("Network CIDR %s not applied, the datastores are created in the cluster", cidr)
+		status.CIDR = ""
+		status.Locked = false
+		return nil
+	}
+
+	cloudResourcesCreated, err := hasCloudResources(ctx, client, namespace)
+	if err != nil {
+		return err
+	}
+
+	for _, configMapName := range configMapNames {
+		strategyMap := &corev1.ConfigMap{}
+		if err := client.Get(ctx, k8sclient.ObjectKey{Name: configMapName, Namespace: namespace}, strategyMap); err != nil {
+			return fmt.Errorf("failed to get strategy config map %s : %w", configMapName, err)
+		}
+
+		currentCIDR, err := getStrategyCIDR(strategyMap)
+		if err != nil {
+			return err
+		}
+		if currentCIDR == cidr {
+			continue
+		}
+
+		if currentCIDR != "" && cloudResourcesCreated {
+			status.CIDR = currentCIDR
+			status.Locked = true
+			status.Error = fmt.Sprintf("network CIDR can't be changed from %s as cloud resources have already been created in it", currentCIDR)
+			return nil
+		}
+
+		logrus.Infof("Setting network CIDR %s in strategy config map %s", cidr, configMapName)
+		if err := setStrategyCIDR(strategyMap, cidr); err != nil {
+			return err
+		}
+		if err := client.Update(ctx, strategyMap); err != nil {
+			return fmt.Errorf("failed to update strategy config map %s : %w", configMapName, err)
+		}
+	}
+
+	status.CIDR = cidr
+	status.Locked = cloudResourcesCreated
+	return nil
+}
+
+// getNetworkStrategyMaps returns the strategy config maps of the CRO
+// providers creating the Postgres and Redis instances of the installation.
+// The list is empty when they're all created in the cluster. A provider that
+// doesn't support the network configuration is returned instead
+func getNetworkStrategyMaps(ctx context.Context, client k8sclient.Client, namespace string) ([]string, string, error) {
+	installation, err := resources.GetRhmiCr(client, ctx, namespace)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get the installation : %w", err)
+	}
+	if installation == nil {
+		return nil, "", fmt.Errorf("no installation found in namespace %s", namespace)
+	}
+
+	strategies, err := croProviders.NewConfigManager(cloudResourceConfigName, namespace, client).GetStrategyMappingForDeploymentType(ctx, installation.Spec.Type)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get the cloud resource providers : %w", err)
+	}
+
+	configMapNames := []string{}
+	for _, provider := range []string{strategies.Postgres, strategies.Redis} {
+		if resources.Contains(inClusterProviders, provider) {
+			continue
+		}
+		configMapName, ok := networkStrategyMaps[provider]
+		if !ok {
+			return nil, provider, nil
+		}
+		if !resources.Contains(configMapNames, configMapName) {
+			configMapNames = append(configMapNames, configMapName)
+		}
+	}
+	return configMapNames, "", nil
+}
+
+// getClusterNetworks returns the cluster and service networks of the cluster
+func getClusterNetworks(ctx context.Context, client k8sclient.Client) ([]*net.IPNet, error) {
+	clusterNetworkConfig := &configv1.Network{}
+	if err := client.Get(ctx, k8sclient.ObjectKey{Name: "cluster"}, clusterNetworkConfig); err != nil {
+		return nil, fmt.Errorf("failed to get cluster network configuration : %w", err)
+	}
+
+	cidrs := clusterNetworkConfig.Status.ServiceNetwork
+	for _, entry := range clusterNetworkConfig.Status.ClusterNetwork {
+		cidrs = append(cidrs, entry.CIDR)
+	}
+
+	networks := []*net.IPNet{}
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cluster network %s : %w", cidr, err)
+		}
+		networks = append(networks, ipNet)
+	}
+
+	return networks, nil
+}
+
+// hasCloudResources returns whether CRO has been asked to create any Postgres
+// or Redis instance, which are created in the network CIDR
+func hasCloudResources(ctx context.Context, client k8sclient.Client, namespace string) (bool, error) {
+	postgresList := &crov1alpha1.PostgresList{}
+	if err := client.List(ctx, postgresList, k8sclient.InNamespace(namespace)); err != nil {
+		return false, fmt.Errorf("failed to list postgres instances : %w", err)
+	}
+	if len(postgresList.Items) > 0 {
+		return true, nil
+	}
+
+	redisList := &crov1alpha1.RedisList{}
+	if err := client.List(ctx, redisList, k8sclient.InNamespace(namespace)); err != nil {
+		return false, fmt.Errorf("failed to list redis instances : %w", err)
+	}
+	return len(redisList.Items) > 0, nil
+}
+
+func getStrategyCIDR(strategyMap *corev1.ConfigMap) (string, error) {
+	strategies, err := getNetworkStrategies(strategyMap)
+	if err != nil {
+		return "", err
+	}
+
+	strategy, ok := strategies[croUtil.TierProduction]
+	if !ok || len(strategy.CreateStrategy) == 0 {
+		return "", nil
+	}
+
+	createStrategy := struct {
+		CidrBlock string `json:"CidrBlock"`
+	}{}
+	if err := json.Unmarshal(strategy.CreateStrategy, &createStrategy); err != nil {
+		return "", fmt.Errorf("failed to unmarshal network create strategy : %w", err)
+	}
+
+	return createStrategy.CidrBlock, nil
+}
+
+func setStrategyCIDR(strategyMap *corev1.ConfigMap, cidr string) error {
+	strategies, err := getNetworkStrategies(strategyMap)
+	if err != nil {
+		return err
+	}
+
+	strategy, ok := strategies[croUtil.TierProduction]
+	if !ok {
+		strategy = &croAWS.StrategyConfig{}
+		strategies[croUtil.TierProduction] = strategy
+	}
+
+	// keep any other field of the create strategy
+	createStrategy := map[string]interface{}{}
+	if len(strategy.CreateStrategy) > 0 {
+		if err := json.Unmarshal(strategy.CreateStrategy, &createStrategy); err != nil {
+			return fmt.Errorf("failed to unmarshal network create strategy : %w", err)
+		}
+	}
+	createStrategy["CidrBlock"] = cidr
+
+	createStrategyJSON, err := json.Marshal(createStrategy)
+	if err != nil {
+		return err
+	}
+	strategy.CreateStrategy = createStrategyJSON
+	if len(strategy.DeleteStrategy) == 0 {
+		strategy.DeleteStrategy = json.RawMessage("{}")
+	}
+
+	strategiesJSON, err := json.Marshal(strategies)
+	if err != nil {
+		return err
+	}
+
+	if strategyMap.Data == nil {
+		strategyMap.Data = map[string]string{}
+	}
+	strategyMap.Data[NetworkStrategyKey] = string(strategiesJSON)
+	return nil
+}
+
+func getNetworkStrategies(strategyMap *corev1.ConfigMap) (map[string]*croAWS.StrategyConfig, error) {
+	strategies := map[string]*croAWS.StrategyConfig{}
+
+	data, ok := strategyMap.Data[NetworkStrategyKey]
+	if !ok || data == "" {
+		return strategies, nil
+	}
+
+	if err := json.Unmarshal([]byte(data), &strategies); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal network strategy : %w", err)
+	}
+	return strategies, nil
+}
+
+func networksOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
@@ -0,0 +1,176 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	crov1alpha1 "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croAWS "github.com/integr8ly/cloud-resource-operator/pkg/providers/aws"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const networkTestNamespace = "testing-namespaces-operator"
+
+func buildNetworkScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	integreatlyv1alpha1.SchemeBuilder.AddToScheme(scheme)
+	crov1alpha1.SchemeBuilder.AddToScheme(scheme)
+	configv1.AddToScheme(scheme)
+	corev1.AddToScheme(scheme)
+	return scheme
+}
+
+func buildNetworkObjects(platform configv1.PlatformType, provider, networkStrategy string) []runtime.Object {
+	return []runtime.Object{
+		&integreatlyv1alpha1.RHMI{
+			ObjectMeta: metav1.ObjectMeta{Name: "rhmi", Namespace: networkTestNamespace},
+			Spec:       integreatlyv1alpha1.RHMISpec{Type: string(integreatlyv1alpha1.InstallationTypeManaged)},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: cloudResourceConfigName, Namespace: networkTestNamespace},
+			Data: map[string]string{
+				string(integreatlyv1alpha1.InstallationTypeManaged): fmt.Sprintf(`{"blobstorage":"%[1]s","redis":"%[1]s","postgres":"%[1]s"}`, provider),
+			},
+		},
+		&configv1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Status: configv1.InfrastructureStatus{
+				PlatformStatus: &configv1.PlatformStatus{Type: platform},
+			},
+		},
+		&configv1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Status: configv1.NetworkStatus{
+				ClusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/14"}},
+				ServiceNetwork: []string{"172.30.0.0/16"},
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: croAWS.DefaultConfigMapName, Namespace: networkTestNamespace},
+			Data: map[string]string{
+				NetworkStrategyKey: networkStrategy,
+			},
+		},
+	}
+}
+
+func TestReconcileNetwork(t *testing.T) {
+	postgres := &crov1alpha1.Postgres{
+		ObjectMeta: metav1.ObjectMeta{Name: "threescale-postgres", Namespace: networkTestNamespace},
+	}
+
+	scenarios := []struct {
+		Name                 string
+		CIDR                 string
+		Platform             configv1.PlatformType
+		Provider             string
+		NetworkStrategy      string
+		CloudResources       []runtime.Object
+		ExpectedStatus       integreatlyv1alpha1.RHMIConfigStatusNetwork
+		ExpectedStrategyCIDR string
+	}{
+		{
+			Name:                 "cidr applied to the aws strategy map",
+			CIDR:                 "10.1.0.0/16",
+			Platform:             configv1.AWSPlatformType,
+			Provider:             "aws",
+			NetworkStrategy:      `{"production": {"region": "", "createStrategy": {"CidrBlock": ""}, "deleteStrategy": {}}}`,
+			ExpectedStatus:       integreatlyv1alpha1.RHMIConfigStatusNetwork{CIDR: "10.1.0.0/16", Platform: "AWS"},
+			ExpectedStrategyCIDR: "10.1.0.0/16",
+		},
+		{
+			Name:                 "default cidr replaced before cloud resources are created",
+			CIDR:                 "10.2.0.0/20",
+			Platform:             configv1.AWSPlatformType,
+			Provider:             "aws",
+			NetworkStrategy:      `{"production": {"region": "", "createStrategy": {"CidrBlock": "10.1.0.0/16"}, "deleteStrategy": {}}}`,
+			ExpectedStatus:       integreatlyv1alpha1.RHMIConfigStatusNetwork{CIDR: "10.2.0.0/20", Platform: "AWS"},
+			ExpectedStrategyCIDR: "10.2.0.0/20",
+		},
+		{
+			Name:            "cidr locked after cloud resources are created",
+			CIDR:            "10.2.0.0/20",
+			Platform:        configv1.AWSPlatformType,
+			Provider:        "aws",
+			NetworkStrategy: `{"production": {"region": "", "createStrategy": {"CidrBlock": "10.1.0.0/16"}, "deleteStrategy": {}}}`,
+			CloudResources:  []runtime.Object{postgres},
+			ExpectedStatus: integreatlyv1alpha1.RHMIConfigStatusNetwork{
+				CIDR:     "10.1.0.0/16",
+				Platform: "AWS",
+				Locked:   true,
+				Error:    "network CIDR can't be changed from 10.1.0.0/16 as cloud resources have already been created in it",
+			},
+			ExpectedStrategyCIDR: "10.1.0.0/16",
+		},
+		{
+			Name:            "cidr overlapping with the cluster network rejected",
+			CIDR:            "10.128.0.0/16",
+			Platform:        configv1.AWSPlatformType,
+			Provider:        "aws",
+			NetworkStrategy: `{"production": {"region": "", "createStrategy": {"CidrBlock": ""}, "deleteStrategy": {}}}`,
+			ExpectedStatus: integreatlyv1alpha1.RHMIConfigStatusNetwork{
+				Error: "network CIDR 10.128.0.0/16 overlaps with cluster network 10.128.0.0/14",
+			},
+		},
+		{
+			Name:            "cidr not applied to in-cluster datastores",
+			CIDR:            "10.1.0.0/16",
+			Platform:        configv1.GCPPlatformType,
+			Provider:        "openshift",
+			NetworkStrategy: "",
+			ExpectedStatus:  integreatlyv1alpha1.RHMIConfigStatusNetwork{Platform: "GCP"},
+		},
+		{
+			Name:            "unsupported provider reported",
+			CIDR:            "10.1.0.0/16",
+			Platform:        configv1.GCPPlatformType,
+			Provider:        "gcp",
+			NetworkStrategy: "",
+			ExpectedStatus: integreatlyv1alpha1.RHMIConfigStatusNetwork{
+				Platform: "GCP",
+				Error:    "network configuration is not supported by cloud resource provider gcp",
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			config := &integreatlyv1alpha1.RHMIConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "rhmi-config", Namespace: networkTestNamespace},
+				Spec: integreatlyv1alpha1.RHMIConfigSpec{
+					Network: integreatlyv1alpha1.Network{CIDR: scenario.CIDR},
+				},
+			}
+			objects := append(buildNetworkObjects(scenario.Platform, scenario.Provider, scenario.NetworkStrategy), config)
+			client := fake.NewFakeClientWithScheme(buildNetworkScheme(), append(objects, scenario.CloudResources...)...)
+
+			if err := ReconcileNetwork(context.TODO(), client, config); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if config.Status.Network == nil || *config.Status.Network != scenario.ExpectedStatus {
+				t.Fatalf("Expected network status %+v, got %+v", scenario.ExpectedStatus, config.Status.Network)
+			}
+
+			strategyMap := &corev1.ConfigMap{}
+			if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: croAWS.DefaultConfigMapName, Namespace: networkTestNamespace}, strategyMap); err != nil {
+				t.Fatalf("Unexpected error getting strategy map: %v", err)
+			}
+			cidr, err := getStrategyCIDR(strategyMap)
+			if err != nil {
+				t.Fatalf("Unexpected error reading strategy map: %v", err)
+			}
+			if cidr != scenario.ExpectedStrategyCIDR {
+				t.Fatalf("Expected strategy cidr %s, got %s", scenario.ExpectedStrategyCIDR, cidr)
+			}
+		})
+	}
+}
@@ -150,6 +150,12 @@ func (r *ReconcileRHMIConfig) Reconcile(request reconcile.Request) (reconcile.Re
 		return retryRequeue, err
 	}
 
+	// apply the network configuration to the cloud resource strategies
+	if err := helpers.ReconcileNetwork(r.context, r.client, rhmiConfig); err != nil {
+		logrus.Errorf("rhmi config failure while reconciling network configuration : %v", err)
+		return retryRequeue, err
+	}
+
 	logrus.Infof("rhmi config reconciled successfully")
 	return reconcile.Result{Requeue: true, RequeueAfter: 5 * time.Minute}, nil
 }
@@ -0,0 +1,328 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"sort"
+	"strings"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/controller/subscription/webapp"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type NoticeType string
+
+const (
+	NoticeScheduled NoticeType = "scheduled"
+	NoticeUpcoming  NoticeType = "upcoming"
+	NoticeCompleted NoticeType = "completed"
+
+	rhmiConfigName = "rhmi-config"
+
+	channelEmail   = "email"
+	channelWebhook = "webhook"
+)
+
+// UpgradeNotice is the content of an upgrade notice, sent by email and
+// posted as JSON to the webhook
+type UpgradeNotice struct {
+	Type               NoticeType                           `json:"type"`
+	Version            string                               `json:"version"`
+	ScheduledFor       string                               `json:"scheduledFor,omitempty"`
+	IsServiceAffecting bool                                 `json:"isServiceAffecting"`
+	Metadata           *integreatlyv1alpha1.UpgradeMetadata `json:"metadata,omitempty"`
+}
+
+// UpgradeNotifierImpl sends upgrade scheduled, upcoming and completed notices
+// to the contacts of the RHMIConfig by email, using the SMTP secret of the
+// installation, and to the webhook referenced by the RHMIConfig. The notices
+// sent are recorded in the RHMIConfig status so each of them is only sent once
+// to each channel
+type UpgradeNotifierImpl struct {
+	client     k8sclient.Client
+	ctx        context.Context
+	httpClient *http.Client
+	sendMail   func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	now        func() time.Time
+}
+
+var _ webapp.UpgradeNotifier = &UpgradeNotifierImpl{}
+
+func NewUpgradeNotifier(ctx context.Context, client k8sclient.Client) *UpgradeNotifierImpl {
+	return &UpgradeNotifierImpl{
+		client:     client,
+		ctx:        ctx,
+		httpClient: &http.Client{Timeout: time.Second * 10},
+		sendMail:   smtp.SendMail,
+		now:        time.Now,
+	}
+}
+
+func (notifier *UpgradeNotifierImpl) NotifyUpgrade(config *integreatlyv1alpha1.RHMIConfig, version string, isServiceAffecting bool, metadata *integreatlyv1alpha1.UpgradeMetadata) (integreatlyv1alpha1.StatusPhase, error) {
+	if config.Status.Upgrade.Scheduled == nil || config.Status.Upgrade.Scheduled.For == "" {
+		return integreatlyv1alpha1.PhaseInProgress, nil
+	}
+	scheduledFor := config.Status.Upgrade.Scheduled.For
+
+	upgradeTime, err := time.Parse(integreatlyv1alpha1.DateFormat, scheduledFor)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
+	reminders, err := config.Spec.Upgrade.GetReminders()
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
+	// Start over when a new upgrade is scheduled, or when the upgrade is rescheduled
+	status := config.Status.UpgradeNotifications
+	if status == nil || status.Version != version || status.ScheduledFor != scheduledFor {
+		status = &integreatlyv1alpha1.UpgradeNotificationsStatus{
+			Version:      version,
+			ScheduledFor: scheduledFor,
+		}
+	}
+
+	notice := &UpgradeNotice{
+		Version:            version,
+		ScheduledFor:       scheduledFor,
+		IsServiceAffecting: isServiceAffecting,
+		Metadata:           metadata,
+	}
+
+	if !isSent(status, string(NoticeScheduled)) {
+		notice.Type = NoticeScheduled
+		if err := notifier.deliver(config, status, notice, string(NoticeScheduled)); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+	}
+
+	// Send a single reminder for all the lead times that are due, so no
+	// more than one reminder is sent when the upgrade is scheduled shortly
+	// after it becomes available
+	now := notifier.now().UTC()
+	dueReminders := []string{}
+	for _, leadTime := range reminders {
+		key := fmt.Sprintf("%s-%s", NoticeUpcoming, leadTime)
+		if isSent(status, key) {
+			continue
+		}
+		if now.After(upgradeTime.Add(-leadTime)) && now.Before(upgradeTime) {
+			dueReminders = append(dueReminders, key)
+		}
+	}
+	if len(dueReminders) > 0 {
+		notice.Type = NoticeUpcoming
+		if err := notifier.deliver(config, status, notice, dueReminders...); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+	}
+
+	if config.Status.UpgradeNotifications != status {
+		if err := notifier.record(config, status); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+// ClearNotification sends the completed notice for the last notified upgrade
+// once the installation has been upgraded to its version
+func (notifier *UpgradeNotifierImpl) ClearNotification(namespacePrefix string) error {
+	config := &integreatlyv1alpha1.RHMIConfig{}
+	if err := notifier.client.Get(notifier.ctx, k8sclient.ObjectKey{Name: rhmiConfigName, Namespace: namespacePrefix + "operator"}, config); err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	status := config.Status.UpgradeNotifications
+	if status == nil || isSent(status, string(NoticeCompleted)) {
+		return nil
+	}
+
+	installation, err := resources.GetRhmiCr(notifier.client, notifier.ctx, config.Namespace)
+	if err != nil {
+		return err
+	}
+	if installation == nil || installation.Status.Version != status.Version || installation.Status.ToVersion != "" {
+		return nil
+	}
+
+	return notifier.deliver(config, status, &UpgradeNotice{
+		Type:    NoticeCompleted,
+		Version: status.Version,
+	}, string(NoticeCompleted))
+}
+
+// deliver sends the notice to the channels it hasn't been sent to yet, and
+// records each channel in the status as soon as the notice is sent to it, so
+// a failure of one channel doesn't resend the notice to the others. Once all
+// the channels are done the channel entries are replaced by the keys of the
+// notice
+func (notifier *UpgradeNotifierImpl) deliver(config *integreatlyv1alpha1.RHMIConfig, status *integreatlyv1alpha1.UpgradeNotificationsStatus, notice *UpgradeNotice, keys ...string) error {
+	logrus.Infof("Sending upgrade %s notice for version %s", notice.Type, notice.Version)
+
+	channels := []struct {
+		name string
+		send func(*integreatlyv1alpha1.RHMIConfig, *UpgradeNotice) error
+	}{
+		{name: channelEmail, send: notifier.sendEmail},
+		{name: channelWebhook, send: notifier.postWebhook},
+	}
+	for _, channel := range channels {
+		channelKey := keys[0] + "/" + channel.name
+		if isSent(status, channelKey) {
+			continue
+		}
+		if err := channel.send(config, notice); err != nil {
+			return fmt.Errorf("failed to send upgrade %s notice to %s: %w", notice.Type, channel.name, err)
+		}
+		status.Sent = append(status.Sent, channelKey)
+		if err := notifier.record(config, status); err != nil {
+			return err
+		}
+	}
+
+	sent := []string{}
+	for _, key := range status.Sent {
+		if !strings.HasPrefix(key, keys[0]+"/") {
+			sent = append(sent, key)
+		}
+	}
+	status.Sent = append(sent, keys...)
+	return notifier.record(config, status)
+}
+
+func (notifier *UpgradeNotifierImpl) record(config *integreatlyv1alpha1.RHMIConfig, status *integreatlyv1alpha1.UpgradeNotificationsStatus) error {
+	sort.Strings(status.Sent)
+	config.Status.UpgradeNotifications = status
+	if err := notifier.client.Status().Update(notifier.ctx, config); err != nil {
+		return fmt.Errorf("failed to record the upgrade notices sent: %w", err)
+	}
+	return nil
+}
+
+func (notifier *UpgradeNotifierImpl) sendEmail(config *integreatlyv1alpha1.RHMIConfig, notice *UpgradeNotice) error {
+	to := []string{}
+	for _, contact := range strings.Split(config.Spec.Upgrade.Contacts, ",") {
+		if contact = strings.TrimSpace(contact); contact != "" {
+			to = append(to, contact)
+		}
+	}
+	if len(to) == 0 {
+		return nil
+	}
+
+	installation, err := resources.GetRhmiCr(notifier.client, notifier.ctx, config.Namespace)
+	if err != nil {
+		return err
+	}
+	if installation == nil || installation.Spec.SMTPSecret == "" {
+		logrus.Warnf("No SMTP secret set for the installation, skipping upgrade %s email", notice.Type)
+		return nil
+	}
+
+	smtpSecret := &corev1.Secret{}
+	if err := notifier.client.Get(notifier.ctx, k8sclient.ObjectKey{Name: installation.Spec.SMTPSecret, Namespace: installation.Namespace}, smtpSecret); err != nil {
+		return fmt.Errorf("could not obtain smtp credentials secret: %w", err)
+	}
+
+	host := string(smtpSecret.Data["host"])
+	from := fmt.Sprintf("noreply@%s", installation.Spec.RoutingSubdomain)
+	auth := smtp.PlainAuth("", string(smtpSecret.Data["username"]), string(smtpSecret.Data["password"]), host)
+
+	return notifier.sendMail(net.JoinHostPort(host, string(smtpSecret.Data["port"])), auth, from, to, buildEmail(from, to, notice))
+}
+
+func (notifier *UpgradeNotifierImpl) postWebhook(config *integreatlyv1alpha1.RHMIConfig, notice *UpgradeNotice) error {
+	if config.Spec.Upgrade.WebhookSecret == "" {
+		return nil
+	}
+
+	webhookSecret := &corev1.Secret{}
+	if err := notifier.client.Get(notifier.ctx, k8sclient.ObjectKey{Name: config.Spec.Upgrade.WebhookSecret, Namespace: config.Namespace}, webhookSecret); err != nil {
+		return fmt.Errorf("could not obtain webhook secret: %w", err)
+	}
+
+	body, err := json.Marshal(notice)
+	if err != nil {
+		return err
+	}
+
+	resp, err := notifier.httpClient.Post(string(webhookSecret.Data["url"]), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	return nil
+}
+
+func buildEmail(from string, to []string, notice *UpgradeNotice) []byte {
+	var subject string
+	body := &strings.Builder{}
+
+	switch notice.Type {
+	case NoticeScheduled:
+		subject = fmt.Sprintf("RHMI upgrade to %s scheduled for %s UTC", notice.Version, notice.ScheduledFor)
+		fmt.Fprintf(body, "An upgrade to version %s has been scheduled for %s UTC.\r\n", notice.Version, notice.ScheduledFor)
+	case NoticeUpcoming:
+		subject = fmt.Sprintf("Reminder: RHMI upgrade to %s on %s UTC", notice.Version, notice.ScheduledFor)
+		fmt.Fprintf(body, "The upgrade to version %s will start on %s UTC.\r\n", notice.Version, notice.ScheduledFor)
+	case NoticeCompleted:
+		subject = fmt.Sprintf("RHMI upgrade to %s completed", notice.Version)
+		fmt.Fprintf(body, "The upgrade to version %s has completed.\r\n", notice.Version)
+	}
+
+	if notice.Type != NoticeCompleted && notice.IsServiceAffecting {
+		fmt.Fprint(body, "\r\nThis upgrade is service affecting.\r\n")
+	}
+
+	if metadata := notice.Metadata; metadata != nil {
+		if metadata.ExpectedDowntime != "" {
+			fmt.Fprintf(body, "Expected downtime: %s\r\n", metadata.ExpectedDowntime)
+		}
+		if metadata.DatabaseMigration {
+			fmt.Fprint(body, "This upgrade includes a database migration.\r\n")
+		}
+		if len(metadata.ProductVersionChanges) > 0 {
+			fmt.Fprint(body, "\r\nProduct changes:\r\n")
+			for _, change := range metadata.ProductVersionChanges {
+				fmt.Fprintf(body, "  %s: %s -> %s\r\n", change.Product, change.FromVersion, change.ToVersion)
+			}
+		}
+		if metadata.ReleaseNotes != "" {
+			fmt.Fprintf(body, "\r\nRelease notes: %s\r\n", metadata.ReleaseNotes)
+		}
+	}
+
+	return []byte(fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", from, strings.Join(to, ","), subject, body.String()))
+}
+
+func isSent(status *integreatlyv1alpha1.UpgradeNotificationsStatus, notice string) bool {
+	for _, sent := range status.Sent {
+		if sent == notice {
+			return true
+		}
+	}
+	return false
+}
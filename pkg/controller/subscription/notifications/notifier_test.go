@@ -0,0 +1,303 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"reflect"
+	"testing"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	defaultNamespace = "test-namespaces-operator"
+)
+
+func buildScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	integreatlyv1alpha1.SchemeBuilder.AddToScheme(scheme)
+	corev1.SchemeBuilder.AddToScheme(scheme)
+	return scheme
+}
+
+type sentEmail struct {
+	addr string
+	from string
+	to   []string
+}
+
+func buildNotifier(client k8sclient.Client, now time.Time, emails *[]sentEmail) *UpgradeNotifierImpl {
+	notifier := NewUpgradeNotifier(context.TODO(), client)
+	notifier.now = func() time.Time { return now }
+	notifier.sendMail = func(addr string, _ smtp.Auth, from string, to []string, _ []byte) error {
+		*emails = append(*emails, sentEmail{addr: addr, from: from, to: to})
+		return nil
+	}
+	return notifier
+}
+
+func buildObjects(webhookURL string, scheduledFor time.Time, notifications *integreatlyv1alpha1.UpgradeNotificationsStatus) (*integreatlyv1alpha1.RHMIConfig, []runtime.Object) {
+	config := &integreatlyv1alpha1.RHMIConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rhmiConfigName,
+			Namespace: defaultNamespace,
+		},
+		Spec: integreatlyv1alpha1.RHMIConfigSpec{
+			Upgrade: integreatlyv1alpha1.Upgrade{
+				Contacts:      "user1@example.com, user2@example.com",
+				Reminders:     "72h,24h",
+				WebhookSecret: "upgrade-webhook",
+			},
+		},
+		Status: integreatlyv1alpha1.RHMIConfigStatus{
+			Upgrade: integreatlyv1alpha1.RHMIConfigStatusUpgrade{
+				Scheduled: &integreatlyv1alpha1.UpgradeSchedule{
+					For: scheduledFor.Format(integreatlyv1alpha1.DateFormat),
+				},
+			},
+			UpgradeNotifications: notifications,
+		},
+	}
+
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rhmi",
+			Namespace: defaultNamespace,
+		},
+		Spec: integreatlyv1alpha1.RHMISpec{
+			RoutingSubdomain: "apps.example.com",
+			SMTPSecret:       "smtp",
+		},
+		Status: integreatlyv1alpha1.RHMIStatus{
+			Version: "2.7.0",
+		},
+	}
+
+	smtpSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "smtp",
+			Namespace: defaultNamespace,
+		},
+		Data: map[string][]byte{
+			"host": []byte("smtp.example.com"),
+			"port": []byte("587"),
+		},
+	}
+
+	webhookSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "upgrade-webhook",
+			Namespace: defaultNamespace,
+		},
+		Data: map[string][]byte{
+			"url": []byte(webhookURL),
+		},
+	}
+
+	return config, []runtime.Object{config, installation, smtpSecret, webhookSecret}
+}
+
+func TestNotifyUpgrade(t *testing.T) {
+	now := time.Date(2020, time.July, 10, 12, 0, 0, 0, time.UTC)
+
+	scenarios := []struct {
+		name           string
+		scheduledFor   time.Time
+		notifications  *integreatlyv1alpha1.UpgradeNotificationsStatus
+		expectedNotice []NoticeType
+		expectedSent   []string
+	}{
+		{
+			name:           "scheduled notice sent for new upgrade",
+			scheduledFor:   now.Add(time.Hour * 96),
+			expectedNotice: []NoticeType{NoticeScheduled},
+			expectedSent:   []string{"scheduled"},
+		},
+		{
+			name:         "reminder sent when lead time is reached",
+			scheduledFor: now.Add(time.Hour * 48),
+			notifications: &integreatlyv1alpha1.UpgradeNotificationsStatus{
+				Version:      "2.8.0",
+				ScheduledFor: now.Add(time.Hour * 48).Format(integreatlyv1alpha1.DateFormat),
+				Sent:         []string{"scheduled"},
+			},
+			expectedNotice: []NoticeType{NoticeUpcoming},
+			expectedSent:   []string{"scheduled", "upcoming-72h0m0s"},
+		},
+		{
+			name:         "no notice sent twice",
+			scheduledFor: now.Add(time.Hour * 48),
+			notifications: &integreatlyv1alpha1.UpgradeNotificationsStatus{
+				Version:      "2.8.0",
+				ScheduledFor: now.Add(time.Hour * 48).Format(integreatlyv1alpha1.DateFormat),
+				Sent:         []string{"scheduled", "upcoming-72h0m0s"},
+			},
+			expectedNotice: []NoticeType{},
+			expectedSent:   []string{"scheduled", "upcoming-72h0m0s"},
+		},
+		{
+			name:         "single reminder sent for all lead times due after reschedule",
+			scheduledFor: now.Add(time.Hour * 12),
+			notifications: &integreatlyv1alpha1.UpgradeNotificationsStatus{
+				Version:      "2.8.0",
+				ScheduledFor: now.Add(time.Hour * 48).Format(integreatlyv1alpha1.DateFormat),
+				Sent:         []string{"scheduled", "upcoming-72h0m0s"},
+			},
+			expectedNotice: []NoticeType{NoticeScheduled, NoticeUpcoming},
+			expectedSent:   []string{"scheduled", "upcoming-24h0m0s", "upcoming-72h0m0s"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			notices := []NoticeType{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				notice := &UpgradeNotice{}
+				if err := json.NewDecoder(r.Body).Decode(notice); err != nil {
+					t.Fatalf("Failed to decode webhook notice: %v", err)
+				}
+				notices = append(notices, notice.Type)
+			}))
+			defer server.Close()
+
+			config, objects := buildObjects(server.URL, scenario.scheduledFor, scenario.notifications)
+			client := fake.NewFakeClientWithScheme(buildScheme(), objects...)
+			emails := []sentEmail{}
+			notifier := buildNotifier(client, now, &emails)
+
+			phase, err := notifier.NotifyUpgrade(config, "2.8.0", true, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if phase != integreatlyv1alpha1.PhaseCompleted {
+				t.Fatalf("Expected phase %s, got %s", integreatlyv1alpha1.PhaseCompleted, phase)
+			}
+
+			if !reflect.DeepEqual(notices, scenario.expectedNotice) {
+				t.Fatalf("Expected webhook notices %v, got %v", scenario.expectedNotice, notices)
+			}
+			if len(emails) != len(scenario.expectedNotice) {
+				t.Fatalf("Expected %d emails, got %d", len(scenario.expectedNotice), len(emails))
+			}
+			for _, email := range emails {
+				if email.addr != "smtp.example.com:587" || email.from != "noreply@apps.example.com" {
+					t.Fatalf("Unexpected email sender %s through %s", email.from, email.addr)
+				}
+				if !reflect.DeepEqual(email.to, []string{"user1@example.com", "user2@example.com"}) {
+					t.Fatalf("Unexpected email recipients %v", email.to)
+				}
+			}
+
+			updated := &integreatlyv1alpha1.RHMIConfig{}
+			if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: config.Name, Namespace: config.Namespace}, updated); err != nil {
+				t.Fatalf("Unexpected error getting rhmi config: %v", err)
+			}
+			if !reflect.DeepEqual(updated.Status.UpgradeNotifications.Sent, scenario.expectedSent) {
+				t.Fatalf("Expected sent notices %v, got %v", scenario.expectedSent, updated.Status.UpgradeNotifications.Sent)
+			}
+		})
+	}
+}
+
+func TestClearNotification(t *testing.T) {
+	now := time.Date(2020, time.July, 10, 12, 0, 0, 0, time.UTC)
+	notices := []NoticeType{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notice := &UpgradeNotice{}
+		if err := json.NewDecoder(r.Body).Decode(notice); err != nil {
+			t.Fatalf("Failed to decode webhook notice: %v", err)
+		}
+		notices = append(notices, notice.Type)
+	}))
+	defer server.Close()
+
+	config, objects := buildObjects(server.URL, now, &integreatlyv1alpha1.UpgradeNotificationsStatus{
+		Version: "2.7.0",
+		Sent:    []string{"scheduled", "upcoming-24h0m0s"},
+	})
+	client := fake.NewFakeClientWithScheme(buildScheme(), objects...)
+	emails := []sentEmail{}
+	notifier := buildNotifier(client, now, &emails)
+
+	for i := 0; i < 2; i++ {
+		if err := notifier.ClearNotification("test-namespaces-"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if !reflect.DeepEqual(notices, []NoticeType{NoticeCompleted}) {
+		t.Fatalf("Expected a single completed notice, got %v", notices)
+	}
+	if len(emails) != 1 {
+		t.Fatalf("Expected a single completed email, got %d", len(emails))
+	}
+
+	updated := &integreatlyv1alpha1.RHMIConfig{}
+	if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: config.Name, Namespace: config.Namespace}, updated); err != nil {
+		t.Fatalf("Unexpected error getting rhmi config: %v", err)
+	}
+	if !isSent(updated.Status.UpgradeNotifications, string(NoticeCompleted)) {
+		t.Fatalf("Expected completed notice to be recorded, got %v", updated.Status.UpgradeNotifications.Sent)
+	}
+}
+
+func TestNotifyUpgradePartialFailure(t *testing.T) {
+	now := time.Date(2020, time.July, 10, 12, 0, 0, 0, time.UTC)
+	webhookFailing := true
+	notices := []NoticeType{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if webhookFailing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		notice := &UpgradeNotice{}
+		if err := json.NewDecoder(r.Body).Decode(notice); err != nil {
+			t.Fatalf("Failed to decode webhook notice: %v", err)
+		}
+		notices = append(notices, notice.Type)
+	}))
+	defer server.Close()
+
+	config, objects := buildObjects(server.URL, now.Add(time.Hour*24*7), nil)
+	client := fake.NewFakeClientWithScheme(buildScheme(), objects...)
+	emails := []sentEmail{}
+	notifier := buildNotifier(client, now, &emails)
+
+	if _, err := notifier.NotifyUpgrade(config, "2.8.0", true, nil); err == nil {
+		t.Fatal("Expected error when the webhook fails")
+	}
+	updated := &integreatlyv1alpha1.RHMIConfig{}
+	if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: config.Name, Namespace: config.Namespace}, updated); err != nil {
+		t.Fatalf("Unexpected error getting rhmi config: %v", err)
+	}
+	if !reflect.DeepEqual(updated.Status.UpgradeNotifications.Sent, []string{"scheduled/email"}) {
+		t.Fatalf("Expected the email to be recorded, got %v", updated.Status.UpgradeNotifications.Sent)
+	}
+
+	webhookFailing = false
+	if _, err := notifier.NotifyUpgrade(updated, "2.8.0", true, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(emails) != 1 {
+		t.Fatalf("Expected the email not to be resent, got %d emails", len(emails))
+	}
+	if !reflect.DeepEqual(notices, []NoticeType{NoticeScheduled}) {
+		t.Fatalf("Expected the webhook notice to be sent, got %v", notices)
+	}
+	if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: config.Name, Namespace: config.Namespace}, updated); err != nil {
+		t.Fatalf("Unexpected error getting rhmi config: %v", err)
+	}
+	if !reflect.DeepEqual(updated.Status.UpgradeNotifications.Sent, []string{"scheduled"}) {
+		t.Fatalf("Expected the notice to be recorded, got %v", updated.Status.UpgradeNotifications.Sent)
+	}
+}
@@ -2,7 +2,9 @@ package rhmiConfigs
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -19,6 +21,12 @@ import (
 const (
 	WINDOW        = 6
 	WINDOW_MARGIN = 1
+
+	// Annotations on the RHMI CSV describing the upgrade it introduces
+	ReleaseNotesAnnotation            = "releaseNotes"
+	ProductOperatorVersionsAnnotation = "productOperatorVersions"
+	DatabaseMigrationAnnotation       = "databaseMigration"
+	ExpectedDowntimeAnnotation        = "expectedDowntime"
 )
 
 func IsUpgradeAvailable(subscription *olmv1alpha1.Subscription) bool {
@@ -121,6 +129,71 @@ func IsUpgradeServiceAffecting(csv *olmv1alpha1.ClusterServiceVersion) bool {
 	return serviceAffectingUpgrade
 }
 
+// GetUpgradeMetadata reads the details of the upgrade introduced by csv from
+// its annotations. The product operator versions annotation is a JSON object
+// mapping product names to their operator version in the incoming release, and
+// is compared against the versions currently installed to only list the products
+// that change. Invalid annotations are skipped and reported in the returned
+// error, along with the metadata read from the valid ones
+func GetUpgradeMetadata(csv *olmv1alpha1.ClusterServiceVersion, installation *integreatlyv1alpha1.RHMI) (*integreatlyv1alpha1.UpgradeMetadata, error) {
+	metadata := &integreatlyv1alpha1.UpgradeMetadata{}
+	if csv == nil {
+		return metadata, nil
+	}
+	annotations := csv.ObjectMeta.Annotations
+	var invalid []string
+
+	metadata.ReleaseNotes = annotations[ReleaseNotesAnnotation]
+
+	if val, ok := annotations[DatabaseMigrationAnnotation]; ok {
+		databaseMigration, err := strconv.ParseBool(val)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", DatabaseMigrationAnnotation, err))
+		}
+		metadata.DatabaseMigration = databaseMigration
+	}
+
+	if val, ok := annotations[ExpectedDowntimeAnnotation]; ok {
+		if _, err := time.ParseDuration(val); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", ExpectedDowntimeAnnotation, err))
+		} else {
+			metadata.ExpectedDowntime = val
+		}
+	}
+
+	if val, ok := annotations[ProductOperatorVersionsAnnotation]; ok {
+		targetVersions := map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.OperatorVersion{}
+		if err := json.Unmarshal([]byte(val), &targetVersions); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", ProductOperatorVersionsAnnotation, err))
+			targetVersions = nil
+		}
+
+		for product, toVersion := range targetVersions {
+			var fromVersion integreatlyv1alpha1.OperatorVersion
+			if installation != nil {
+				fromVersion = installation.GetProductStatusObject(product).OperatorVersion
+			}
+			if fromVersion == toVersion {
+				continue
+			}
+			metadata.ProductVersionChanges = append(metadata.ProductVersionChanges, integreatlyv1alpha1.ProductVersionChange{
+				Product:     product,
+				FromVersion: fromVersion,
+				ToVersion:   toVersion,
+			})
+		}
+
+		sort.Slice(metadata.ProductVersionChanges, func(i, j int) bool {
+			return metadata.ProductVersionChanges[i].Product < metadata.ProductVersionChanges[j].Product
+		})
+	}
+
+	if len(invalid) > 0 {
+		return metadata, fmt.Errorf("invalid upgrade annotations on csv %s: %s", csv.Name, strings.Join(invalid, "; "))
+	}
+	return metadata, nil
+}
+
 func ApproveUpgrade(ctx context.Context, client k8sclient.Client, installation *integreatlyv1alpha1.RHMI, installPlan *olmv1alpha1.InstallPlan, eventRecorder record.EventRecorder) error {
 
 	if installPlan.Status.Phase == olmv1alpha1.InstallPlanPhaseInstalling {
@@ -3,6 +3,7 @@ package rhmiConfigs
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -340,6 +341,114 @@ func TestIsUpgradeServiceAffecting(t *testing.T) {
 	}
 }
 
+func TestGetUpgradeMetadata(t *testing.T) {
+	installation := &integreatlyv1alpha1.RHMI{
+		Status: integreatlyv1alpha1.RHMIStatus{
+			Stages: map[integreatlyv1alpha1.StageName]integreatlyv1alpha1.RHMIStageStatus{
+				integreatlyv1alpha1.ProductsStage: {
+					Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
+						integreatlyv1alpha1.Product3Scale: {
+							Name:            integreatlyv1alpha1.Product3Scale,
+							OperatorVersion: "0.5.0",
+						},
+						integreatlyv1alpha1.ProductRHSSOUser: {
+							Name:            integreatlyv1alpha1.ProductRHSSOUser,
+							OperatorVersion: "10.0.1",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	scenarios := []struct {
+		Name             string
+		RhmiCSV          *olmv1alpha1.ClusterServiceVersion
+		ExpectError      bool
+		ExpectedMetadata *integreatlyv1alpha1.UpgradeMetadata
+	}{
+		{
+			Name:             "Test no CSV",
+			RhmiCSV:          nil,
+			ExpectedMetadata: &integreatlyv1alpha1.UpgradeMetadata{},
+		},
+		{
+			Name: "Test CSV with all upgrade annotations",
+			RhmiCSV: &olmv1alpha1.ClusterServiceVersion{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						ReleaseNotesAnnotation:            "https://example.com/release-notes/2.4.0",
+						DatabaseMigrationAnnotation:       "true",
+						ExpectedDowntimeAnnotation:        "30m",
+						ProductOperatorVersionsAnnotation: `{"3scale": "0.6.1", "rhssouser": "10.0.1", "amqonline": "1.4"}`,
+					},
+				},
+			},
+			ExpectedMetadata: &integreatlyv1alpha1.UpgradeMetadata{
+				ReleaseNotes:      "https://example.com/release-notes/2.4.0",
+				DatabaseMigration: true,
+				ExpectedDowntime:  "30m",
+				ProductVersionChanges: []integreatlyv1alpha1.ProductVersionChange{
+					{
+						Product:     integreatlyv1alpha1.Product3Scale,
+						FromVersion: "0.5.0",
+						ToVersion:   "0.6.1",
+					},
+					{
+						Product:   integreatlyv1alpha1.ProductAMQOnline,
+						ToVersion: "1.4",
+					},
+				},
+			},
+		},
+		{
+			Name: "Test CSV with invalid expected downtime annotation",
+			RhmiCSV: &olmv1alpha1.ClusterServiceVersion{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						ReleaseNotesAnnotation:     "https://example.com/release-notes/2.4.0",
+						ExpectedDowntimeAnnotation: "half an hour",
+					},
+				},
+			},
+			ExpectError: true,
+			ExpectedMetadata: &integreatlyv1alpha1.UpgradeMetadata{
+				ReleaseNotes: "https://example.com/release-notes/2.4.0",
+			},
+		},
+		{
+			Name: "Test CSV with invalid product operator versions annotation",
+			RhmiCSV: &olmv1alpha1.ClusterServiceVersion{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						ProductOperatorVersionsAnnotation: "3scale=0.6.1",
+						ExpectedDowntimeAnnotation:        "30m",
+					},
+				},
+			},
+			ExpectError: true,
+			ExpectedMetadata: &integreatlyv1alpha1.UpgradeMetadata{
+				ExpectedDowntime: "30m",
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			metadata, err := GetUpgradeMetadata(scenario.RhmiCSV, installation)
+			if scenario.ExpectError && err == nil {
+				t.Fatal("Expected error but got none")
+			}
+			if !scenario.ExpectError && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(metadata, scenario.ExpectedMetadata) {
+				t.Fatalf("Expected metadata %+v but got %+v", scenario.ExpectedMetadata, metadata)
+			}
+		})
+	}
+}
+
 func TestApproveUpgrade(t *testing.T) {
 	installPlanObjectMeta := metav1.ObjectMeta{
 		Name:      "rhmi-ip",
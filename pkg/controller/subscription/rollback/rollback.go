@@ -0,0 +1,216 @@
+package rollback
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	crov1alpha1 "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/backup"
+
+	olmv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// UpgradeDeadline is the time given to the installation to complete in
+	// the target version after an upgrade is approved
+	UpgradeDeadline = time.Hour * 2
+
+	// RollbackAnnotation must be set to "true" on the RHMI CR to request the
+	// guidance to roll back an upgrade that missed its deadline
+	RollbackAnnotation = "integreatly.org/rollback-upgrade"
+)
+
+// TrackUpgrade starts tracking the health of the upgrade to the CSV of
+// installPlan. It does nothing if the upgrade is already being tracked
+func TrackUpgrade(installation *integreatlyv1alpha1.RHMI, subscription *olmv1alpha1.Subscription, installPlan *olmv1alpha1.InstallPlan, toVersion string) bool {
+	if len(installPlan.Spec.ClusterServiceVersionNames) == 0 {
+		return false
+	}
+	targetCSV := installPlan.Spec.ClusterServiceVersionNames[0]
+
+	if installation.Status.LastUpgrade != nil && installation.Status.LastUpgrade.TargetCSV == targetCSV {
+		return false
+	}
+
+	now := time.Now()
+	installation.Status.LastUpgrade = &integreatlyv1alpha1.RHMIUpgradeStatus{
+		FromVersion: installation.Status.Version,
+		ToVersion:   toVersion,
+		PreviousCSV: subscription.Status.InstalledCSV,
+		TargetCSV:   targetCSV,
+		ApprovedAt:  metav1.NewTime(now),
+		Deadline:    metav1.NewTime(now.Add(UpgradeDeadline)),
+		Phase:       integreatlyv1alpha1.PhaseInProgress,
+	}
+
+	return true
+}
+
+// CheckUpgradeHealth updates the status of the tracked upgrade. An upgrade in
+// progress succeeds once the installation is completed in the target version,
+// and fails if that doesn't happen before the deadline. A failed upgrade still
+// succeeds if the installation completes later, as long as its rollback hasn't
+// been requested. A manual rollback in progress completes once the previous
+// CSV is installed again. Returns whether the status was modified
+func CheckUpgradeHealth(installation *integreatlyv1alpha1.RHMI, subscription *olmv1alpha1.Subscription, eventRecorder record.EventRecorder) bool {
+	upgrade := installation.Status.LastUpgrade
+	if upgrade == nil {
+		return false
+	}
+
+	switch upgrade.Phase {
+	case integreatlyv1alpha1.PhaseInProgress, integreatlyv1alpha1.PhaseFailed:
+		if installation.Status.Version == upgrade.ToVersion && installation.Status.ToVersion == "" {
+			if upgrade.Phase == integreatlyv1alpha1.PhaseFailed {
+				eventRecorder.Eventf(installation, "Normal", integreatlyv1alpha1.EventUpgradeCompleted,
+					"Upgrade to %s completed after its deadline", upgrade.ToVersion)
+			} else {
+				eventRecorder.Eventf(installation, "Normal", integreatlyv1alpha1.EventUpgradeCompleted,
+					"Upgrade to %s completed", upgrade.ToVersion)
+			}
+			upgrade.Phase = integreatlyv1alpha1.PhaseCompleted
+			upgrade.Message = ""
+			return true
+		}
+
+		if upgrade.Phase == integreatlyv1alpha1.PhaseInProgress && time.Now().After(upgrade.Deadline.Time) {
+			upgrade.Phase = integreatlyv1alpha1.PhaseFailed
+			upgrade.Message = fmt.Sprintf("installation did not complete in version %s before %s. Set the %s annotation to \"true\" on the RHMI CR for the guidance to roll back to %s",
+				upgrade.ToVersion, upgrade.Deadline.UTC().Format(integreatlyv1alpha1.DateFormat), RollbackAnnotation, upgrade.PreviousCSV)
+			eventRecorder.Event(installation, "Warning", integreatlyv1alpha1.EventUpgradeFailed, upgrade.Message)
+			return true
+		}
+	case integreatlyv1alpha1.PhaseRollingBack:
+		if subscription.Status.InstalledCSV == upgrade.PreviousCSV {
+			upgrade.Phase = integreatlyv1alpha1.PhaseRolledBack
+			upgrade.Message = fmt.Sprintf("rolled back to %s", upgrade.PreviousCSV)
+			eventRecorder.Eventf(installation, "Normal", integreatlyv1alpha1.EventUpgradeRolledBack,
+				"Upgrade to %s rolled back to %s", upgrade.ToVersion, upgrade.PreviousCSV)
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsSettled returns whether the outcome of the tracked upgrade is known, so
+// its health no longer needs to be checked
+func IsSettled(installation *integreatlyv1alpha1.RHMI) bool {
+	upgrade := installation.Status.LastUpgrade
+	return upgrade == nil ||
+		upgrade.Phase == integreatlyv1alpha1.PhaseCompleted ||
+		upgrade.Phase == integreatlyv1alpha1.PhaseRolledBack
+}
+
+// IsRollbackRequested returns whether the rollback guidance of a failed
+// upgrade has been requested through the RollbackAnnotation
+func IsRollbackRequested(installation *integreatlyv1alpha1.RHMI) bool {
+	upgrade := installation.Status.LastUpgrade
+	if upgrade == nil || upgrade.Phase != integreatlyv1alpha1.PhaseFailed {
+		return false
+	}
+
+	return installation.GetAnnotations()[RollbackAnnotation] == "true"
+}
+
+// IsTargetBlocked returns whether installPlan installs the CSV of an upgrade
+// that has been rolled back, or is being rolled back, and therefore must not
+// be approved
+func IsTargetBlocked(installation *integreatlyv1alpha1.RHMI, installPlan *olmv1alpha1.InstallPlan) bool {
+	upgrade := installation.Status.LastUpgrade
+	if upgrade == nil {
+		return false
+	}
+	if upgrade.Phase != integreatlyv1alpha1.PhaseRollingBack && upgrade.Phase != integreatlyv1alpha1.PhaseRolledBack {
+		return false
+	}
+
+	for _, csvName := range installPlan.Spec.ClusterServiceVersionNames {
+		if csvName == upgrade.TargetCSV {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReportRollbackGuidance blocks the approval of the failed upgrade, and
+// reports in the status message and events how to roll it back, with the
+// pre-upgrade snapshots taken for it as restore points. OLM can't downgrade
+// an installed operator and the cloud resource operator can't restore the
+// snapshots, so the rollback itself is left to the cluster administrator.
+// It completes once the previous CSV is installed again
+func ReportRollbackGuidance(ctx context.Context, client k8sclient.Client, installation *integreatlyv1alpha1.RHMI, eventRecorder record.EventRecorder) error {
+	upgrade := installation.Status.LastUpgrade
+	if upgrade.PreviousCSV == "" {
+		return fmt.Errorf("unable to roll back upgrade to %s: previous csv is unknown", upgrade.TargetCSV)
+	}
+
+	restorePoints, err := GetRestorePoints(ctx, client, upgrade.ApprovedAt)
+	if err != nil {
+		return fmt.Errorf("error getting pre-upgrade snapshots: %w", err)
+	}
+
+	snapshots := make([]string, 0, len(restorePoints))
+	for _, restorePoint := range restorePoints {
+		snapshots = append(snapshots, fmt.Sprintf("%s %s/%s (%s)", restorePoint.Kind, restorePoint.Namespace, restorePoint.Name, restorePoint.SnapshotID))
+	}
+
+	upgrade.Phase = integreatlyv1alpha1.PhaseRollingBack
+	upgrade.RestorePoints = restorePoints
+	upgrade.Message = fmt.Sprintf("the upgrade must be rolled back to %s manually: delete csv %s, recreate the subscription with starting csv %s and restore the pre-upgrade snapshots [%s]",
+		upgrade.PreviousCSV, upgrade.TargetCSV, upgrade.PreviousCSV, strings.Join(snapshots, ", "))
+	eventRecorder.Event(installation, "Warning", integreatlyv1alpha1.EventUpgradeRollingBack, upgrade.Message)
+
+	return nil
+}
+
+// GetRestorePoints returns the completed pre-upgrade Postgres and Redis
+// snapshots created since the given time
+func GetRestorePoints(ctx context.Context, client k8sclient.Client, since metav1.Time) ([]integreatlyv1alpha1.UpgradeRestorePoint, error) {
+	restorePoints := []integreatlyv1alpha1.UpgradeRestorePoint{}
+
+	postgresSnapshots := &crov1alpha1.PostgresSnapshotList{}
+	if err := client.List(ctx, postgresSnapshots); err != nil {
+		return nil, err
+	}
+	for _, snapshot := range postgresSnapshots.Items {
+		if isPreUpgradeSnapshot(snapshot.ObjectMeta, since) && snapshot.Status.SnapshotID != "" {
+			restorePoints = append(restorePoints, integreatlyv1alpha1.UpgradeRestorePoint{
+				Kind:         string(backup.PostgresSnapshotType),
+				Namespace:    snapshot.Namespace,
+				Name:         snapshot.Name,
+				ResourceName: snapshot.Spec.ResourceName,
+				SnapshotID:   snapshot.Status.SnapshotID,
+			})
+		}
+	}
+
+	redisSnapshots := &crov1alpha1.RedisSnapshotList{}
+	if err := client.List(ctx, redisSnapshots); err != nil {
+		return nil, err
+	}
+	for _, snapshot := range redisSnapshots.Items {
+		if isPreUpgradeSnapshot(snapshot.ObjectMeta, since) && snapshot.Status.SnapshotID != "" {
+			restorePoints = append(restorePoints, integreatlyv1alpha1.UpgradeRestorePoint{
+				Kind:         string(backup.RedisSnapshotType),
+				Namespace:    snapshot.Namespace,
+				Name:         snapshot.Name,
+				ResourceName: snapshot.Spec.ResourceName,
+				SnapshotID:   snapshot.Status.SnapshotID,
+			})
+		}
+	}
+
+	return restorePoints, nil
+}
+
+func isPreUpgradeSnapshot(meta metav1.ObjectMeta, since metav1.Time) bool {
+	return strings.Contains(meta.Name, backup.PreUpgradeSnapshotInfix) && !meta.CreationTimestamp.Before(&since)
+}
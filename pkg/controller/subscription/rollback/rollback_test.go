@@ -0,0 +1,287 @@
+package rollback
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	crov1alpha1 "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	crotypes "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+
+	olmv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	defaultNamespace = "testing-namespaces-operator"
+	previousCSV      = "integreatly-operator.v2.7.0"
+	targetCSV        = "integreatly-operator.v2.8.0"
+)
+
+func buildScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	integreatlyv1alpha1.SchemeBuilder.AddToScheme(scheme)
+	crov1alpha1.SchemeBuilder.AddToScheme(scheme)
+	olmv1alpha1.AddToScheme(scheme)
+	return scheme
+}
+
+func buildUpgradeStatus(phase integreatlyv1alpha1.StatusPhase, deadline time.Time) *integreatlyv1alpha1.RHMIUpgradeStatus {
+	return &integreatlyv1alpha1.RHMIUpgradeStatus{
+		FromVersion: "2.7.0",
+		ToVersion:   "2.8.0",
+		PreviousCSV: previousCSV,
+		TargetCSV:   targetCSV,
+		ApprovedAt:  metav1.NewTime(deadline.Add(-UpgradeDeadline)),
+		Deadline:    metav1.NewTime(deadline),
+		Phase:       phase,
+	}
+}
+
+func TestTrackUpgrade(t *testing.T) {
+	installation := &integreatlyv1alpha1.RHMI{
+		Status: integreatlyv1alpha1.RHMIStatus{
+			Version: "2.7.0",
+		},
+	}
+	subscription := &olmv1alpha1.Subscription{
+		Status: olmv1alpha1.SubscriptionStatus{
+			InstalledCSV: previousCSV,
+			CurrentCSV:   targetCSV,
+		},
+	}
+	installPlan := &olmv1alpha1.InstallPlan{
+		Spec: olmv1alpha1.InstallPlanSpec{
+			ClusterServiceVersionNames: []string{targetCSV},
+		},
+	}
+
+	if !TrackUpgrade(installation, subscription, installPlan, "2.8.0") {
+		t.Fatal("Expected upgrade to be tracked")
+	}
+
+	upgrade := installation.Status.LastUpgrade
+	if upgrade.Phase != integreatlyv1alpha1.PhaseInProgress {
+		t.Fatalf("Expected phase %s, got %s", integreatlyv1alpha1.PhaseInProgress, upgrade.Phase)
+	}
+	if upgrade.PreviousCSV != previousCSV || upgrade.TargetCSV != targetCSV {
+		t.Fatalf("Unexpected csvs tracked, previous: %s, target: %s", upgrade.PreviousCSV, upgrade.TargetCSV)
+	}
+	if upgrade.FromVersion != "2.7.0" || upgrade.ToVersion != "2.8.0" {
+		t.Fatalf("Unexpected versions tracked, from: %s, to: %s", upgrade.FromVersion, upgrade.ToVersion)
+	}
+	if !upgrade.Deadline.Time.Equal(upgrade.ApprovedAt.Add(UpgradeDeadline)) {
+		t.Fatalf("Expected deadline %v after approval, got %v", UpgradeDeadline, upgrade.Deadline.Sub(upgrade.ApprovedAt.Time))
+	}
+
+	if TrackUpgrade(installation, subscription, installPlan, "2.8.0") {
+		t.Fatal("Expected upgrade already tracked to be ignored")
+	}
+}
+
+func TestCheckUpgradeHealth(t *testing.T) {
+	scenarios := []struct {
+		Name            string
+		Installation    *integreatlyv1alpha1.RHMI
+		InstalledCSV    string
+		ExpectedUpdated bool
+		ExpectedPhase   integreatlyv1alpha1.StatusPhase
+	}{
+		{
+			Name: "upgrade in progress before the deadline",
+			Installation: &integreatlyv1alpha1.RHMI{
+				Status: integreatlyv1alpha1.RHMIStatus{
+					Version:     "2.7.0",
+					ToVersion:   "2.8.0",
+					LastUpgrade: buildUpgradeStatus(integreatlyv1alpha1.PhaseInProgress, time.Now().Add(time.Hour)),
+				},
+			},
+			InstalledCSV:    targetCSV,
+			ExpectedUpdated: false,
+			ExpectedPhase:   integreatlyv1alpha1.PhaseInProgress,
+		},
+		{
+			Name: "upgrade completed",
+			Installation: &integreatlyv1alpha1.RHMI{
+				Status: integreatlyv1alpha1.RHMIStatus{
+					Version:     "2.8.0",
+					LastUpgrade: buildUpgradeStatus(integreatlyv1alpha1.PhaseInProgress, time.Now().Add(time.Hour)),
+				},
+			},
+			InstalledCSV:    targetCSV,
+			ExpectedUpdated: true,
+			ExpectedPhase:   integreatlyv1alpha1.PhaseCompleted,
+		},
+		{
+			Name: "upgrade missed the deadline",
+			Installation: &integreatlyv1alpha1.RHMI{
+				Status: integreatlyv1alpha1.RHMIStatus{
+					Version:     "2.7.0",
+					ToVersion:   "2.8.0",
+					LastUpgrade: buildUpgradeStatus(integreatlyv1alpha1.PhaseInProgress, time.Now().Add(-time.Minute)),
+				},
+			},
+			InstalledCSV:    targetCSV,
+			ExpectedUpdated: true,
+			ExpectedPhase:   integreatlyv1alpha1.PhaseFailed,
+		},
+		{
+			Name: "failed upgrade completed after the deadline",
+			Installation: &integreatlyv1alpha1.RHMI{
+				Status: integreatlyv1alpha1.RHMIStatus{
+					Version:     "2.8.0",
+					LastUpgrade: buildUpgradeStatus(integreatlyv1alpha1.PhaseFailed, time.Now().Add(-time.Minute)),
+				},
+			},
+			InstalledCSV:    targetCSV,
+			ExpectedUpdated: true,
+			ExpectedPhase:   integreatlyv1alpha1.PhaseCompleted,
+		},
+		{
+			Name: "failed upgrade still not completed",
+			Installation: &integreatlyv1alpha1.RHMI{
+				Status: integreatlyv1alpha1.RHMIStatus{
+					Version:     "2.7.0",
+					ToVersion:   "2.8.0",
+					LastUpgrade: buildUpgradeStatus(integreatlyv1alpha1.PhaseFailed, time.Now().Add(-time.Minute)),
+				},
+			},
+			InstalledCSV:    targetCSV,
+			ExpectedUpdated: false,
+			ExpectedPhase:   integreatlyv1alpha1.PhaseFailed,
+		},
+		{
+			Name: "rollback waiting for the previous csv",
+			Installation: &integreatlyv1alpha1.RHMI{
+				Status: integreatlyv1alpha1.RHMIStatus{
+					LastUpgrade: buildUpgradeStatus(integreatlyv1alpha1.PhaseRollingBack, time.Now().Add(-time.Minute)),
+				},
+			},
+			InstalledCSV:    targetCSV,
+			ExpectedUpdated: false,
+			ExpectedPhase:   integreatlyv1alpha1.PhaseRollingBack,
+		},
+		{
+			Name: "rollback completed",
+			Installation: &integreatlyv1alpha1.RHMI{
+				Status: integreatlyv1alpha1.RHMIStatus{
+					LastUpgrade: buildUpgradeStatus(integreatlyv1alpha1.PhaseRollingBack, time.Now().Add(-time.Minute)),
+				},
+			},
+			InstalledCSV:    previousCSV,
+			ExpectedUpdated: true,
+			ExpectedPhase:   integreatlyv1alpha1.PhaseRolledBack,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			subscription := &olmv1alpha1.Subscription{
+				Status: olmv1alpha1.SubscriptionStatus{
+					InstalledCSV: scenario.InstalledCSV,
+				},
+			}
+
+			updated := CheckUpgradeHealth(scenario.Installation, subscription, record.NewFakeRecorder(10))
+			if updated != scenario.ExpectedUpdated {
+				t.Fatalf("Expected updated to be %v, got %v", scenario.ExpectedUpdated, updated)
+			}
+			if phase := scenario.Installation.Status.LastUpgrade.Phase; phase != scenario.ExpectedPhase {
+				t.Fatalf("Expected phase %s, got %s", scenario.ExpectedPhase, phase)
+			}
+		})
+	}
+}
+
+func TestReportRollbackGuidance(t *testing.T) {
+	deadline := time.Now().Add(-time.Minute)
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rhmi",
+			Namespace: defaultNamespace,
+			Annotations: map[string]string{
+				RollbackAnnotation: "true",
+			},
+		},
+		Status: integreatlyv1alpha1.RHMIStatus{
+			LastUpgrade: buildUpgradeStatus(integreatlyv1alpha1.PhaseFailed, deadline),
+		},
+	}
+	subscription := &olmv1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "integreatly",
+			Namespace: defaultNamespace,
+		},
+		Spec: &olmv1alpha1.SubscriptionSpec{
+			InstallPlanApproval: olmv1alpha1.ApprovalManual,
+		},
+	}
+	preUpgradeSnapshot := &crov1alpha1.PostgresSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "threescale-postgres-preupgrade-snapshot-2020-10-01-120000",
+			Namespace:         "testing-namespaces-operator",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+		Spec: crov1alpha1.PostgresSnapshotSpec{
+			ResourceName: "threescale-postgres",
+		},
+		Status: crov1alpha1.PostgresSnapshotStatus{
+			SnapshotID: "rds:snapshot-1",
+			Phase:      crotypes.PhaseComplete,
+		},
+	}
+	oldSnapshot := &crov1alpha1.RedisSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "threescale-redis-preupgrade-snapshot-2020-09-01-120000",
+			Namespace:         "testing-namespaces-operator",
+			CreationTimestamp: metav1.NewTime(deadline.Add(-UpgradeDeadline - time.Hour)),
+		},
+		Spec: crov1alpha1.RedisSnapshotSpec{
+			ResourceName: "threescale-redis",
+		},
+		Status: crov1alpha1.RedisSnapshotStatus{
+			SnapshotID: "redis:snapshot-0",
+			Phase:      crotypes.PhaseComplete,
+		},
+	}
+
+	client := fake.NewFakeClientWithScheme(buildScheme(), installation, subscription, preUpgradeSnapshot, oldSnapshot)
+
+	if !IsRollbackRequested(installation) {
+		t.Fatal("Expected rollback to be requested")
+	}
+
+	if err := ReportRollbackGuidance(context.TODO(), client, installation, record.NewFakeRecorder(10)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	upgrade := installation.Status.LastUpgrade
+	if upgrade.Phase != integreatlyv1alpha1.PhaseRollingBack {
+		t.Fatalf("Expected phase %s, got %s", integreatlyv1alpha1.PhaseRollingBack, upgrade.Phase)
+	}
+	if len(upgrade.RestorePoints) != 1 || upgrade.RestorePoints[0].SnapshotID != "rds:snapshot-1" {
+		t.Fatalf("Expected only the pre-upgrade snapshot as restore point, got %+v", upgrade.RestorePoints)
+	}
+
+	unchangedSubscription := &olmv1alpha1.Subscription{}
+	if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: subscription.Name, Namespace: subscription.Namespace}, unchangedSubscription); err != nil {
+		t.Fatalf("Unexpected error getting subscription: %v", err)
+	}
+	if unchangedSubscription.Spec.StartingCSV != "" {
+		t.Fatalf("Expected subscription to be left to the manual rollback, got starting csv %s", unchangedSubscription.Spec.StartingCSV)
+	}
+
+	installPlan := &olmv1alpha1.InstallPlan{
+		Spec: olmv1alpha1.InstallPlanSpec{
+			ClusterServiceVersionNames: []string{targetCSV},
+		},
+	}
+	if !IsTargetBlocked(installation, installPlan) {
+		t.Fatal("Expected installplan for the rolled back csv to be blocked")
+	}
+}
@@ -12,7 +12,9 @@ import (
 
 	"github.com/blang/semver"
 	"github.com/integr8ly/integreatly-operator/pkg/controller/subscription/csvlocator"
+	"github.com/integr8ly/integreatly-operator/pkg/controller/subscription/notifications"
 	"github.com/integr8ly/integreatly-operator/pkg/controller/subscription/rhmiConfigs"
+	"github.com/integr8ly/integreatly-operator/pkg/controller/subscription/rollback"
 	"github.com/integr8ly/integreatly-operator/pkg/controller/subscription/webapp"
 	"github.com/integr8ly/integreatly-operator/version"
 
@@ -29,11 +31,14 @@ import (
 	pkgerr "github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -89,6 +94,11 @@ func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 		return k8sclient.New(restConfig, k8sclient.Options{})
 	})
 
+	upgradeNotifier := webapp.NewMultiUpgradeNotifier(
+		webappNotifierClient,
+		notifications.NewUpgradeNotifier(context.TODO(), client),
+	)
+
 	csvLocator := csvlocator.NewCachedCSVLocator(csvlocator.NewConditionalCSVLocator(
 		csvlocator.SwitchLocators(
 			csvlocator.ForReference,
@@ -102,7 +112,7 @@ func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 		scheme:              mgr.GetScheme(),
 		operatorNamespace:   operatorNs,
 		catalogSourceClient: catalogSourceClient,
-		webbappNotifier:     webappNotifierClient,
+		webbappNotifier:     upgradeNotifier,
 		csvLocator:          csvLocator,
 	}, nil
 }
@@ -118,6 +128,40 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Changes to the RHMI CR can settle the last upgrade, or request its
+	// rollback
+	err = c.Watch(&source.Kind{Type: &integreatlyv1alpha1.RHMI{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+			requests := []reconcile.Request{}
+			for _, name := range subscriptionsToReconcile {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: name, Namespace: obj.Meta.GetNamespace()},
+				})
+			}
+			return requests
+		}),
+	}, predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldInstallation, ok := e.ObjectOld.(*integreatlyv1alpha1.RHMI)
+			if !ok {
+				return false
+			}
+			newInstallation, ok := e.ObjectNew.(*integreatlyv1alpha1.RHMI)
+			if !ok || rollback.IsSettled(newInstallation) {
+				return false
+			}
+			return oldInstallation.Status.Version != newInstallation.Status.Version ||
+				oldInstallation.Status.ToVersion != newInstallation.Status.ToVersion ||
+				oldInstallation.GetAnnotations()[rollback.RollbackAnnotation] != newInstallation.GetAnnotations()[rollback.RollbackAnnotation]
+		},
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -170,7 +214,50 @@ func (r *ReconcileSubscription) Reconcile(request reconcile.Request) (reconcile.
 		return reconcile.Result{}, nil
 	}
 
-	return r.HandleUpgrades(context.TODO(), subscription, rhmiCr)
+	if err := r.reconcileUpgradeHealth(context.TODO(), subscription, rhmiCr); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	result, err := r.HandleUpgrades(context.TODO(), subscription, rhmiCr)
+	if err != nil {
+		return result, err
+	}
+
+	// Check the last upgrade again when it reaches its deadline. Its health
+	// is otherwise checked again when the RHMI CR or the subscription change
+	if !result.Requeue && rhmiCr.Status.LastUpgrade != nil && rhmiCr.Status.LastUpgrade.Phase == integreatlyv1alpha1.PhaseInProgress {
+		result = reconcile.Result{
+			Requeue:      true,
+			RequeueAfter: time.Until(rhmiCr.Status.LastUpgrade.Deadline.Time) + time.Second,
+		}
+	}
+
+	return result, nil
+}
+
+// reconcileUpgradeHealth checks whether the last approved upgrade completed
+// before its deadline, and reports the guidance to roll it back when requested
+func (r *ReconcileSubscription) reconcileUpgradeHealth(ctx context.Context, subscription *operatorsv1alpha1.Subscription, installation *integreatlyv1alpha1.RHMI) error {
+	if installation.Status.LastUpgrade == nil {
+		return nil
+	}
+
+	eventRecorder := r.mgr.GetEventRecorderFor("RHMI Upgrade")
+
+	updated := rollback.CheckUpgradeHealth(installation, subscription, eventRecorder)
+
+	if rollback.IsRollbackRequested(installation) {
+		if err := rollback.ReportRollbackGuidance(ctx, r.client, installation, eventRecorder); err != nil {
+			return err
+		}
+		updated = true
+	}
+
+	if !updated {
+		return nil
+	}
+
+	return r.client.Status().Update(ctx, installation)
 }
 
 func (r *ReconcileSubscription) shouldReconcileSubscription(request reconcile.Request) bool {
@@ -295,10 +382,18 @@ func (r *ReconcileSubscription) HandleUpgrades(ctx context.Context, rhmiSubscrip
 
 	isServiceAffecting := rhmiConfigs.IsUpgradeServiceAffecting(latestRHMICSV)
 
+	// a malformed annotation mustn't block the upgrade, the metadata read
+	// from the valid ones is published
+	upgradeMetadata, err := rhmiConfigs.GetUpgradeMetadata(latestRHMICSV, installation)
+	if err != nil {
+		logrus.Warnf("Error reading the upgrade metadata: %v", err)
+	}
+
 	if isServiceAffecting && !latestRHMIInstallPlan.Spec.Approved && config.Status.UpgradeAvailable == nil {
 		newUpgradeAvailable := &integreatlyv1alpha1.UpgradeAvailable{
 			TargetVersion: rhmiSubscription.Status.CurrentCSV,
 			AvailableAt:   latestRHMIInstallPlan.CreationTimestamp,
+			Metadata:      upgradeMetadata,
 		}
 
 		config.Status.UpgradeAvailable = newUpgradeAvailable
@@ -311,17 +406,26 @@ func (r *ReconcileSubscription) HandleUpgrades(ctx context.Context, rhmiSubscrip
 		}, nil
 	}
 
+	if rollback.IsTargetBlocked(installation, latestRHMIInstallPlan) {
+		logrus.Infof("Upgrade to %s has been rolled back, skipping approval", installation.Status.LastUpgrade.TargetCSV)
+		return reconcile.Result{
+			Requeue:      true,
+			RequeueAfter: time.Minute,
+		}, nil
+	}
+
 	canUpgradeNow, err := rhmiConfigs.CanUpgradeNow(config, installation)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	phase, err := r.webbappNotifier.NotifyUpgrade(config, latestRHMICSV.Spec.Version.String(), isServiceAffecting)
+	// a notification outage mustn't block the upgrade, the notices that
+	// failed are sent again on the next reconcile
+	phase, err := r.webbappNotifier.NotifyUpgrade(config, latestRHMICSV.Spec.Version.String(), isServiceAffecting, upgradeMetadata)
 	if err != nil {
-		return reconcile.Result{}, err
-	}
-	if phase == integreatlyv1alpha1.PhaseInProgress {
-		logrus.Infof("WebApp instance not found yet, skipping upgrade addition")
+		logrus.Errorf("Error sending the upgrade notifications: %v", err)
+	} else if phase == integreatlyv1alpha1.PhaseInProgress {
+		logrus.Infof("Upgrade notification not completed yet, it's sent again on the next reconcile")
 	}
 
 	if !isServiceAffecting || canUpgradeNow {
@@ -339,6 +443,12 @@ func (r *ReconcileSubscription) HandleUpgrades(ctx context.Context, rhmiSubscrip
 			return reconcile.Result{}, err
 		}
 
+		if rollback.TrackUpgrade(installation, rhmiSubscription, latestRHMIInstallPlan, latestRHMICSV.Spec.Version.String()) {
+			if err := r.client.Status().Update(ctx, installation); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+
 		// Requeue the reconciler until the RHMI subscription upgrade is complete
 		return reconcile.Result{
 			Requeue:      true,
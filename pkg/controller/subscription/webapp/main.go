@@ -11,12 +11,13 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/rest"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type UpgradeNotifier interface {
-	NotifyUpgrade(config *integreatlyv1alpha1.RHMIConfig, version string, isServiceAffecting bool) (integreatlyv1alpha1.StatusPhase, error)
+	NotifyUpgrade(config *integreatlyv1alpha1.RHMIConfig, version string, isServiceAffecting bool, metadata *integreatlyv1alpha1.UpgradeMetadata) (integreatlyv1alpha1.StatusPhase, error)
 	ClearNotification(namespacePrefix string) error
 }
 
@@ -53,9 +54,13 @@ func NewUpgradeNotifierWithClient(ctx context.Context, client k8sclient.Client)
 }
 
 type upgradeData struct {
-	ScheduledFor       string `json:"scheduledFor"`
-	Version            string `json:"version"`
-	IsServiceAffecting bool   `json:"isServiceAffecting"`
+	ScheduledFor          string                                     `json:"scheduledFor"`
+	Version               string                                     `json:"version"`
+	IsServiceAffecting    bool                                       `json:"isServiceAffecting"`
+	ReleaseNotes          string                                     `json:"releaseNotes,omitempty"`
+	ProductVersionChanges []integreatlyv1alpha1.ProductVersionChange `json:"productVersionChanges,omitempty"`
+	DatabaseMigration     bool                                       `json:"databaseMigration,omitempty"`
+	ExpectedDowntime      string                                     `json:"expectedDowntime,omitempty"`
 }
 
 func (lazyNotifier *LazyUpgradeNotifier) GetNotifier() (UpgradeNotifier, error) {
@@ -71,13 +76,13 @@ func (lazyNotifier *LazyUpgradeNotifier) GetNotifier() (UpgradeNotifier, error)
 	return lazyNotifier.Notifier, nil
 }
 
-func (lazyNotifier *LazyUpgradeNotifier) NotifyUpgrade(config *integreatlyv1alpha1.RHMIConfig, version string, isServiceAffecting bool) (integreatlyv1alpha1.StatusPhase, error) {
+func (lazyNotifier *LazyUpgradeNotifier) NotifyUpgrade(config *integreatlyv1alpha1.RHMIConfig, version string, isServiceAffecting bool, metadata *integreatlyv1alpha1.UpgradeMetadata) (integreatlyv1alpha1.StatusPhase, error) {
 	notifier, err := lazyNotifier.GetNotifier()
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, err
 	}
 
-	return notifier.NotifyUpgrade(config, version, isServiceAffecting)
+	return notifier.NotifyUpgrade(config, version, isServiceAffecting, metadata)
 }
 
 func (lazyNotifier *LazyUpgradeNotifier) ClearNotification(nsPrefix string) error {
@@ -89,7 +94,7 @@ func (lazyNotifier *LazyUpgradeNotifier) ClearNotification(nsPrefix string) erro
 	return notifier.ClearNotification(nsPrefix)
 }
 
-func (notifier *UpgradeNotifierImpl) NotifyUpgrade(config *integreatlyv1alpha1.RHMIConfig, version string, isServiceAffecting bool) (integreatlyv1alpha1.StatusPhase, error) {
+func (notifier *UpgradeNotifierImpl) NotifyUpgrade(config *integreatlyv1alpha1.RHMIConfig, version string, isServiceAffecting bool, metadata *integreatlyv1alpha1.UpgradeMetadata) (integreatlyv1alpha1.StatusPhase, error) {
 	namespaceSegments := strings.Split(config.Namespace, "-")
 	namespacePrefix := strings.Join(namespaceSegments[0:2], "-") + "-"
 	webapp := &solutionExplorerv1alpha1.WebApp{
@@ -109,7 +114,7 @@ func (notifier *UpgradeNotifierImpl) NotifyUpgrade(config *integreatlyv1alpha1.R
 	}
 
 	// Get the upgrade data
-	upgrade := makeUpgradeData(config, version, isServiceAffecting)
+	upgrade := makeUpgradeData(config, version, isServiceAffecting, metadata)
 	encoded, err := json.Marshal(upgrade)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, err
@@ -147,23 +152,74 @@ func (notifier *UpgradeNotifierImpl) ClearNotification(namespacePrefix string) e
 	return notifier.client.Update(notifier.ctx, webapp)
 }
 
-func makeUpgradeData(rhmiConfig *integreatlyv1alpha1.RHMIConfig, version string, isServiceAffecting bool) *upgradeData {
+func makeUpgradeData(rhmiConfig *integreatlyv1alpha1.RHMIConfig, version string, isServiceAffecting bool, metadata *integreatlyv1alpha1.UpgradeMetadata) *upgradeData {
 	var scheduledFor string
 	if rhmiConfig.Status.Upgrade.Scheduled != nil {
 		scheduledFor = rhmiConfig.Status.Upgrade.Scheduled.For
 	}
 
-	return &upgradeData{
+	data := &upgradeData{
 		ScheduledFor:       scheduledFor,
 		Version:            version,
 		IsServiceAffecting: isServiceAffecting,
 	}
+
+	if metadata != nil {
+		data.ReleaseNotes = metadata.ReleaseNotes
+		data.ProductVersionChanges = metadata.ProductVersionChanges
+		data.DatabaseMigration = metadata.DatabaseMigration
+		data.ExpectedDowntime = metadata.ExpectedDowntime
+	}
+
+	return data
+}
+
+// MultiUpgradeNotifier delegates the notifications to a list of
+// `UpgradeNotifier`. Every notifier is tried, the lowest phase of its
+// notifiers is returned along with the errors of the ones that failed
+type MultiUpgradeNotifier struct {
+	Notifiers []UpgradeNotifier
+}
+
+func NewMultiUpgradeNotifier(notifiers ...UpgradeNotifier) UpgradeNotifier {
+	return &MultiUpgradeNotifier{
+		Notifiers: notifiers,
+	}
+}
+
+func (multiNotifier *MultiUpgradeNotifier) NotifyUpgrade(config *integreatlyv1alpha1.RHMIConfig, version string, isServiceAffecting bool, metadata *integreatlyv1alpha1.UpgradeMetadata) (integreatlyv1alpha1.StatusPhase, error) {
+	result := integreatlyv1alpha1.PhaseCompleted
+	var errs []error
+	for _, notifier := range multiNotifier.Notifiers {
+		phase, err := notifier.NotifyUpgrade(config, version, isServiceAffecting, metadata)
+		if err != nil {
+			errs = append(errs, err)
+			result = integreatlyv1alpha1.PhaseFailed
+			continue
+		}
+		if phase != integreatlyv1alpha1.PhaseCompleted && result != integreatlyv1alpha1.PhaseFailed {
+			result = phase
+		}
+	}
+
+	return result, utilerrors.NewAggregate(errs)
+}
+
+func (multiNotifier *MultiUpgradeNotifier) ClearNotification(namespacePrefix string) error {
+	var errs []error
+	for _, notifier := range multiNotifier.Notifiers {
+		if err := notifier.ClearNotification(namespacePrefix); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
 }
 
 type NoOp struct {
 }
 
-func (noop *NoOp) NotifyUpgrade(config *integreatlyv1alpha1.RHMIConfig, version string, isServiceAffecting bool) (integreatlyv1alpha1.StatusPhase, error) {
+func (noop *NoOp) NotifyUpgrade(config *integreatlyv1alpha1.RHMIConfig, version string, isServiceAffecting bool, metadata *integreatlyv1alpha1.UpgradeMetadata) (integreatlyv1alpha1.StatusPhase, error) {
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
@@ -31,6 +31,7 @@ func TestNotifyUpgrade(t *testing.T) {
 		config             *integreatlyv1alpha1.RHMIConfig
 		version            string
 		isServiceAffecting bool
+		metadata           *integreatlyv1alpha1.UpgradeMetadata
 		webapp             *solutionExplorerv1alpha1.WebApp
 		assertion          func(integreatlyv1alpha1.StatusPhase, error, *solutionExplorerv1alpha1.WebApp) error
 	}
@@ -131,6 +132,78 @@ func TestNotifyUpgrade(t *testing.T) {
 					return fmt.Errorf("Unexpected value for upgrade data. Expected %v, got %v", expectedUpgradeData, upgradeDataValue)
 				}
 
+				return nil
+			},
+		},
+		{
+			name: "Upgrade metadata added",
+			config: &integreatlyv1alpha1.RHMIConfig{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "test-namespaces-webapp",
+				},
+				Status: integreatlyv1alpha1.RHMIConfigStatus{
+					Upgrade: integreatlyv1alpha1.RHMIConfigStatusUpgrade{
+						Scheduled: &integreatlyv1alpha1.UpgradeSchedule{
+							For: "13 Jul 2020 00:00",
+						},
+					},
+				},
+			},
+			isServiceAffecting: true,
+			version:            "2.4.0",
+			metadata: &integreatlyv1alpha1.UpgradeMetadata{
+				ReleaseNotes: "https://example.com/release-notes/2.4.0",
+				ProductVersionChanges: []integreatlyv1alpha1.ProductVersionChange{
+					{
+						Product:     integreatlyv1alpha1.Product3Scale,
+						FromVersion: "0.5.0",
+						ToVersion:   "0.6.1",
+					},
+				},
+				DatabaseMigration: true,
+				ExpectedDowntime:  "30m",
+			},
+			webapp: &solutionExplorerv1alpha1.WebApp{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      solutionexplorer.DefaultName,
+					Namespace: "test-namespaces-solution-explorer",
+				},
+				Spec: solutionExplorerv1alpha1.WebAppSpec{
+					Template: solutionExplorerv1alpha1.WebAppTemplate{
+						Parameters: map[string]string{},
+					},
+				},
+			},
+			assertion: func(phase integreatlyv1alpha1.StatusPhase, err error, webapp *solutionExplorerv1alpha1.WebApp) error {
+				if err != nil {
+					return err
+				}
+
+				upgradeDataValue := &upgradeData{}
+				if err := json.Unmarshal([]byte(webapp.Spec.Template.Parameters[solutionexplorer.ParamUpgradeData]), upgradeDataValue); err != nil {
+					return fmt.Errorf("Failed to unmarshall upgrade data parameter value: %v", err)
+				}
+
+				expectedUpgradeData := &upgradeData{
+					ScheduledFor:       "13 Jul 2020 00:00",
+					Version:            "2.4.0",
+					IsServiceAffecting: true,
+					ReleaseNotes:       "https://example.com/release-notes/2.4.0",
+					ProductVersionChanges: []integreatlyv1alpha1.ProductVersionChange{
+						{
+							Product:     integreatlyv1alpha1.Product3Scale,
+							FromVersion: "0.5.0",
+							ToVersion:   "0.6.1",
+						},
+					},
+					DatabaseMigration: true,
+					ExpectedDowntime:  "30m",
+				}
+
+				if !reflect.DeepEqual(upgradeDataValue, expectedUpgradeData) {
+					return fmt.Errorf("Unexpected value for upgrade data. Expected %v, got %v", expectedUpgradeData, upgradeDataValue)
+				}
+
 				return nil
 			},
 		},
@@ -150,7 +223,7 @@ func TestNotifyUpgrade(t *testing.T) {
 		client := fake.NewFakeClientWithScheme(scheme, objects...)
 		notifier := NewUpgradeNotifierWithClient(context.TODO(), client)
 
-		phase, err := notifier.NotifyUpgrade(scenario.config, scenario.version, scenario.isServiceAffecting)
+		phase, err := notifier.NotifyUpgrade(scenario.config, scenario.version, scenario.isServiceAffecting, scenario.metadata)
 
 		var webapp *solutionExplorerv1alpha1.WebApp
 		if scenario.webapp != nil {
@@ -260,3 +333,48 @@ func TestClearNotification(t *testing.T) {
 		}
 	}
 }
+
+type testUpgradeNotifier struct {
+	phase    integreatlyv1alpha1.StatusPhase
+	err      error
+	notified bool
+	cleared  bool
+}
+
+func (notifier *testUpgradeNotifier) NotifyUpgrade(_ *integreatlyv1alpha1.RHMIConfig, _ string, _ bool, _ *integreatlyv1alpha1.UpgradeMetadata) (integreatlyv1alpha1.StatusPhase, error) {
+	notifier.notified = true
+	return notifier.phase, notifier.err
+}
+
+func (notifier *testUpgradeNotifier) ClearNotification(_ string) error {
+	notifier.cleared = true
+	return notifier.err
+}
+
+func TestMultiUpgradeNotifier(t *testing.T) {
+	failing := &testUpgradeNotifier{phase: integreatlyv1alpha1.PhaseFailed, err: fmt.Errorf("notifier unavailable")}
+	inProgress := &testUpgradeNotifier{phase: integreatlyv1alpha1.PhaseInProgress}
+	completed := &testUpgradeNotifier{phase: integreatlyv1alpha1.PhaseCompleted}
+	notifier := NewMultiUpgradeNotifier(failing, inProgress, completed)
+
+	phase, err := notifier.NotifyUpgrade(&integreatlyv1alpha1.RHMIConfig{}, "2.3.0", true, nil)
+	if err == nil || phase != integreatlyv1alpha1.PhaseFailed {
+		t.Errorf("Expected the failure of the first notifier, got %s, %v", phase, err)
+	}
+	if !inProgress.notified || !completed.notified {
+		t.Errorf("Expected every notifier to be tried after a failure")
+	}
+
+	err = notifier.ClearNotification("testing-namespaces-")
+	if err == nil {
+		t.Errorf("Expected the failure of the first notifier to be returned")
+	}
+	if !inProgress.cleared || !completed.cleared {
+		t.Errorf("Expected every notification to be cleared after a failure")
+	}
+
+	phase, err = NewMultiUpgradeNotifier(inProgress, completed).NotifyUpgrade(&integreatlyv1alpha1.RHMIConfig{}, "2.3.0", true, nil)
+	if err != nil || phase != integreatlyv1alpha1.PhaseInProgress {
+		t.Errorf("Expected phase to be %s, got %s, %v", integreatlyv1alpha1.PhaseInProgress, phase, err)
+	}
+}
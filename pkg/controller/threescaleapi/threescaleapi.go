@@ -0,0 +1,258 @@
+/*
+Copyright 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package threescaleapi syncs the 3scale backends and products declared
+// with ThreeScaleBackend and ThreeScaleProduct CRs through the account
+// management API of their tenant
+package threescaleapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/controller/threescaletenant"
+	"github.com/integr8ly/integreatly-operator/pkg/products/threescale"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	defaultInstallationConfigMapName = "installation-config"
+	systemSeedSecretName             = "system-seed"
+
+	// The proxy configuration environments of APIcast
+	stagingEnvironment    = "sandbox"
+	productionEnvironment = "production"
+
+	pendingRequeue = 30 * time.Second
+	// syncRequeue is the interval between syncs, which detect and correct
+	// the changes made in the admin portal
+	syncRequeue = 5 * time.Minute
+)
+
+// errTenantNotFound is returned when the ThreeScaleTenant referenced by a CR
+// doesn't exist
+var errTenantNotFound = errors.New("3scale tenant not found")
+
+type tsClientFactory func(installation *integreatlyv1alpha1.RHMI, adminDomain string) threescale.ThreeScaleInterface
+
+// Add creates the ThreeScaleBackend and ThreeScaleProduct Controllers and adds them to the Manager. The Manager will
+// set fields on the Controllers and Start them when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	// The 3scale secrets are in the product namespaces, which are not in the
+	// cache of the manager
+	client, err := k8sclient.New(mgr.GetConfig(), k8sclient.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return fmt.Errorf("could not create client for threescaleapi controllers: %w", err)
+	}
+
+	backendReconciler := &ReconcileThreeScaleBackend{
+		client:          client,
+		tsClientFactory: threescale.NewThreeScaleInstallationClient,
+	}
+	if err := addBackend(mgr, backendReconciler); err != nil {
+		return err
+	}
+
+	productReconciler := &ReconcileThreeScaleProduct{
+		client:          client,
+		tsClientFactory: threescale.NewThreeScaleInstallationClient,
+	}
+	return addProduct(mgr, productReconciler)
+}
+
+// account is the 3scale tenant account the objects of a CR are synced to
+type account struct {
+	tsClient    threescale.ThreeScaleInterface
+	accessToken string
+}
+
+// getAccount returns the account of the tenant referenced by tenantRef, or
+// of the default tenant if tenantRef is empty. It returns nil if 3scale or
+// the tenant are not ready
+func getAccount(ctx context.Context, serverClient k8sclient.Client, newClient tsClientFactory, namespace, tenantRef string) (*account, error) {
+	installation, err := resources.GetRhmiCr(serverClient, ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RHMI CR: %w", err)
+	}
+	if installation == nil {
+		return nil, nil
+	}
+
+	if tenantRef != "" {
+		tenant := &integreatlyv1alpha1.ThreeScaleTenant{}
+		err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: tenantRef, Namespace: namespace}, tenant)
+		if k8serr.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", errTenantNotFound, tenantRef)
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get 3scale tenant %s: %w", tenantRef, err)
+		}
+		if tenant.Status.TenantID == 0 {
+			return nil, nil
+		}
+		adminDomain, accessToken, err := threescaletenant.GetTenantAccess(ctx, serverClient, tenant)
+		if err != nil {
+			return nil, err
+		}
+		return &account{tsClient: newClient(installation, adminDomain), accessToken: accessToken}, nil
+	}
+
+	installationCfgMap := os.Getenv("INSTALLATION_CONFIG_MAP")
+	if installationCfgMap == "" {
+		installationCfgMap = installation.Spec.NamespacePrefix + defaultInstallationConfigMapName
+	}
+	configManager, err := config.NewManager(ctx, serverClient, installation.Namespace, installationCfgMap, installation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config manager: %w", err)
+	}
+	tsConfig, err := configManager.ReadThreeScale()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read 3scale config: %w", err)
+	}
+	if tsConfig.GetHost() == "" {
+		return nil, nil
+	}
+
+	seed := &corev1.Secret{}
+	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: systemSeedSecretName, Namespace: tsConfig.GetNamespace()}, seed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s secret: %w", systemSeedSecretName, err)
+	}
+	adminDomain := strings.TrimPrefix(tsConfig.GetHost(), "https://")
+	return &account{tsClient: newClient(installation, adminDomain), accessToken: string(seed.Data["ADMIN_ACCESS_TOKEN"])}, nil
+}
+
+// getHitsMetricID returns the ID of the hits metric, which the mapping rules
+// are mapped to. The system name of the hits metric of a backend is suffixed
+// with its ID
+func getHitsMetricID(metrics *threescale.Metrics) (int, error) {
+	for _, metric := range metrics.Metrics {
+		if metric.MetricDetails.SystemName == "hits" || strings.HasPrefix(metric.MetricDetails.SystemName, "hits.") {
+			return metric.MetricDetails.Id, nil
+		}
+	}
+	return 0, fmt.Errorf("hits metric not found")
+}
+
+func mappingRuleKey(httpMethod, pattern string) string {
+	return strings.ToUpper(httpMethod) + " " + pattern
+}
+
+// isManaged returns whether the operator manages the object with key in a
+// 3scale object: all of them when the operator created the 3scale object,
+// only the ones it created when the 3scale object was adopted
+func isManaged(adopted bool, managed []string, key string) bool {
+	return !adopted || resources.Contains(managed, key)
+}
+
+// addManaged records that the operator created the object with key in an
+// adopted 3scale object
+func addManaged(adopted bool, managed []string, key string) []string {
+	if !adopted || resources.Contains(managed, key) {
+		return managed
+	}
+	return append(managed, key)
+}
+
+// syncMappingRules creates the declared mapping rules that are missing or
+// differ, and deletes the managed mapping rules that aren't declared. The
+// mapping rules created in an adopted object are added to managed. It
+// returns the differences found
+func syncMappingRules(declared []integreatlyv1alpha1.ThreeScaleMappingRule, existing *threescale.MappingRules, hitsID int, adopted bool, managed *[]string,
+	create func(data map[string]string) error, remove func(ruleID int) error) ([]string, error) {
+	var drift []string
+
+	existingRules := map[string]threescale.MappingRuleDetails{}
+	for _, rule := range existing.MappingRules {
+		existingRules[mappingRuleKey(rule.MappingRuleDetails.HTTPMethod, rule.MappingRuleDetails.Pattern)] = rule.MappingRuleDetails
+	}
+
+	declaredRules := map[string]bool{}
+	for _, rule := range declared {
+		key := mappingRuleKey(rule.HTTPMethod, rule.Pattern)
+		declaredRules[key] = true
+		increment := rule.Increment
+		if increment == 0 {
+			increment = 1
+		}
+
+		if current, ok := existingRules[key]; ok {
+			if current.Delta == increment && current.Last == rule.Last && current.MetricId == hitsID {
+				continue
+			}
+			drift = append(drift, fmt.Sprintf("mapping rule %s differs", key))
+			if err := remove(current.Id); err != nil {
+				return drift, fmt.Errorf("failed to delete mapping rule %s: %w", key, err)
+			}
+		} else {
+			drift = append(drift, fmt.Sprintf("mapping rule %s is missing", key))
+		}
+
+		err := create(map[string]string{
+			"http_method": strings.ToUpper(rule.HTTPMethod),
+			"pattern":     rule.Pattern,
+			"delta":       fmt.Sprintf("%d", increment),
+			"metric_id":   fmt.Sprintf("%d", hitsID),
+			"last":        fmt.Sprintf("%t", rule.Last),
+		})
+		if err != nil {
+			return drift, fmt.Errorf("failed to create mapping rule %s: %w", key, err)
+		}
+		*managed = addManaged(adopted, *managed, key)
+	}
+
+	var undeclared []string
+	for key := range existingRules {
+		if !declaredRules[key] && isManaged(adopted, *managed, key) {
+			undeclared = append(undeclared, key)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, key := range undeclared {
+		rule := existingRules[key]
+		drift = append(drift, fmt.Sprintf("mapping rule %s is not declared", key))
+		if err := remove(rule.Id); err != nil {
+			return drift, fmt.Errorf("failed to delete mapping rule %s: %w", key, err)
+		}
+		*managed = resources.Remove(*managed, key)
+	}
+
+	return drift, nil
+}
+
+// syncStatus returns the sync status for the result of a sync
+func syncStatus(status integreatlyv1alpha1.ThreeScaleSyncStatus, phase integreatlyv1alpha1.StatusPhase, drift []string, err error) integreatlyv1alpha1.ThreeScaleSyncStatus {
+	status.Phase = phase
+	status.Drift = drift
+	status.SyncError = ""
+	if err != nil {
+		status.SyncError = err.Error()
+	}
+	now := metav1.Now()
+	status.LastSyncTime = &now
+	return status
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package threescaleapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/products/threescale"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	"github.com/sirupsen/logrus"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const backendFinalizer = "finalizer.backend.3scale.integreatly.org"
+
+// addBackend adds a new Controller to mgr with r as the reconcile.Reconciler
+func addBackend(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("threescalebackend-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource ThreeScaleBackend
+	return c.Watch(&source.Kind{Type: &integreatlyv1alpha1.ThreeScaleBackend{}}, &handler.EnqueueRequestForObject{})
+}
+
+// blank assignment to verify that ReconcileThreeScaleBackend implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileThreeScaleBackend{}
+
+// ReconcileThreeScaleBackend reconciles a ThreeScaleBackend object
+type ReconcileThreeScaleBackend struct {
+	client          k8sclient.Client
+	tsClientFactory tsClientFactory
+}
+
+// Reconcile syncs the backend of a ThreeScaleBackend and its mapping rules
+// to 3scale, and deletes the backend from 3scale when the ThreeScaleBackend
+// is deleted, unless it was adopted
+func (r *ReconcileThreeScaleBackend) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx := context.TODO()
+
+	backend := &integreatlyv1alpha1.ThreeScaleBackend{}
+	err := r.client.Get(ctx, request.NamespacedName, backend)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	acc, err := getAccount(ctx, r.client, r.tsClientFactory, backend.Namespace, backend.Spec.TenantRef)
+
+	if backend.DeletionTimestamp != nil {
+		if !resources.Contains(backend.Finalizers, backendFinalizer) {
+			return reconcile.Result{}, nil
+		}
+		// The backends of a deleted tenant are deleted with it
+		if err != nil && !errors.Is(err, errTenantNotFound) {
+			return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, backend, integreatlyv1alpha1.PhaseFailed, nil, err)
+		}
+		if backend.Status.Adopted {
+			logrus.Infof("Leaving adopted 3scale backend %s in 3scale", backend.Spec.SystemName)
+		} else if acc != nil && backend.Status.ID != 0 {
+			_, err := acc.tsClient.DeleteBackendApi(backend.Status.ID, acc.accessToken)
+			if err != nil && !threescale.IsNotFoundError(err) {
+				err = fmt.Errorf("failed to delete backend %s: %w", backend.Spec.SystemName, err)
+				return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, backend, integreatlyv1alpha1.PhaseFailed, nil, err)
+			}
+			logrus.Infof("Deleted 3scale backend %s", backend.Spec.SystemName)
+		}
+		backend.Finalizers = resources.Remove(backend.Finalizers, backendFinalizer)
+		return reconcile.Result{}, r.client.Update(ctx, backend)
+	}
+
+	if !resources.Contains(backend.Finalizers, backendFinalizer) {
+		backend.Finalizers = append(backend.Finalizers, backendFinalizer)
+		if err := r.client.Update(ctx, backend); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if err != nil {
+		return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, backend, integreatlyv1alpha1.PhaseFailed, nil, err)
+	}
+	if acc == nil {
+		err := fmt.Errorf("waiting for the 3scale tenant to be ready")
+		return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, backend, integreatlyv1alpha1.PhaseInProgress, nil, err)
+	}
+
+	drift, err := r.syncBackend(acc, backend)
+	if err != nil {
+		logrus.Errorf("Failed to sync 3scale backend %s: %v", backend.Spec.SystemName, err)
+		return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, backend, integreatlyv1alpha1.PhaseFailed, drift, err)
+	}
+	if len(drift) > 0 {
+		logrus.Warnf("Corrected drift of 3scale backend %s: %v", backend.Spec.SystemName, drift)
+	}
+
+	return reconcile.Result{RequeueAfter: syncRequeue}, r.updateStatus(ctx, backend, integreatlyv1alpha1.PhaseCompleted, drift, nil)
+}
+
+// syncBackend creates or updates the backend and its mapping rules. It
+// returns the differences found with an existing backend
+func (r *ReconcileThreeScaleBackend) syncBackend(acc *account, backend *integreatlyv1alpha1.ThreeScaleBackend) ([]string, error) {
+	spec := backend.Spec
+	var drift []string
+
+	backends, err := acc.tsClient.GetBackendApis(acc.accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backends: %w", err)
+	}
+	var current *threescale.BackendApiDetails
+	for _, b := range backends.BackendApis {
+		if (backend.Status.ID != 0 && b.BackendApiDetails.Id == backend.Status.ID) ||
+			(backend.Status.ID == 0 && b.BackendApiDetails.SystemName == spec.SystemName) {
+			current = &b.BackendApiDetails
+			break
+		}
+	}
+	if current != nil && backend.Status.ID == 0 {
+		if !spec.Adopt {
+			return nil, fmt.Errorf("backend %s already exists in 3scale, set adopt to manage it", spec.SystemName)
+		}
+		backend.Status.Adopted = true
+		logrus.Infof("Adopted 3scale backend %s", spec.SystemName)
+	}
+
+	data := map[string]string{
+		"name":             spec.Name,
+		"description":      spec.Description,
+		"private_endpoint": spec.PrivateBaseURL,
+	}
+	created := current == nil
+	if created {
+		if backend.Status.ID != 0 {
+			drift = append(drift, fmt.Sprintf("backend %d was deleted", backend.Status.ID))
+		}
+		data["system_name"] = spec.SystemName
+		createdBackend, err := acc.tsClient.CreateBackendApi(data, acc.accessToken)
+		if err != nil {
+			return drift, fmt.Errorf("failed to create backend: %w", err)
+		}
+		current = &createdBackend.BackendApiDetails
+		backend.Status.Adopted = false
+		backend.Status.ManagedMappingRules = nil
+		logrus.Infof("Created 3scale backend %s", spec.SystemName)
+	} else {
+		var diff []string
+		if current.Name != spec.Name {
+			diff = append(diff, fmt.Sprintf("name is %q instead of %q", current.Name, spec.Name))
+		}
+		if current.Description != spec.Description {
+			diff = append(diff, fmt.Sprintf("description is %q instead of %q", current.Description, spec.Description))
+		}
+		if current.PrivateEndpoint != spec.PrivateBaseURL {
+			diff = append(diff, fmt.Sprintf("private base URL is %q instead of %q", current.PrivateEndpoint, spec.PrivateBaseURL))
+		}
+		if len(diff) > 0 {
+			drift = append(drift, diff...)
+			if _, err := acc.tsClient.UpdateBackendApi(current.Id, data, acc.accessToken); err != nil {
+				return drift, fmt.Errorf("failed to update backend: %w", err)
+			}
+		}
+	}
+	backend.Status.ID = current.Id
+
+	metrics, err := acc.tsClient.GetBackendApiMetrics(current.Id, acc.accessToken)
+	if err != nil {
+		return drift, fmt.Errorf("failed to get backend metrics: %w", err)
+	}
+	hitsID, err := getHitsMetricID(metrics)
+	if err != nil {
+		return drift, err
+	}
+	rules, err := acc.tsClient.GetBackendApiMappingRules(current.Id, acc.accessToken)
+	if err != nil {
+		return drift, fmt.Errorf("failed to get backend mapping rules: %w", err)
+	}
+	rulesDrift, err := syncMappingRules(spec.MappingRules, rules, hitsID, backend.Status.Adopted, &backend.Status.ManagedMappingRules, func(data map[string]string) error {
+		_, err := acc.tsClient.CreateBackendApiMappingRule(current.Id, data, acc.accessToken)
+		return err
+	}, func(ruleID int) error {
+		_, err := acc.tsClient.DeleteBackendApiMappingRule(current.Id, ruleID, acc.accessToken)
+		return err
+	})
+	if !created {
+		drift = append(drift, rulesDrift...)
+	}
+
+	return drift, err
+}
+
+func (r *ReconcileThreeScaleBackend) updateStatus(ctx context.Context, backend *integreatlyv1alpha1.ThreeScaleBackend, phase integreatlyv1alpha1.StatusPhase, drift []string, syncErr error) error {
+	backend.Status.ThreeScaleSyncStatus = syncStatus(backend.Status.ThreeScaleSyncStatus, phase, drift, syncErr)
+	if err := r.client.Status().Update(ctx, backend); err != nil && !k8serr.IsNotFound(err) {
+		return fmt.Errorf("failed to update status of 3scale backend %s: %w", backend.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package threescaleapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/products/threescale"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	operatorNamespace = "redhat-rhmi-operator"
+	tsNamespace       = "redhat-rhmi-3scale"
+	hitsMetricID      = 11
+)
+
+func getBuildScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	if err := integreatlyv1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}
+
+func getTestObjects() []runtime.Object {
+	return []runtime.Object{
+		&integreatlyv1alpha1.RHMI{
+			ObjectMeta: metav1.ObjectMeta{Name: "rhmi", Namespace: operatorNamespace},
+			Spec:       integreatlyv1alpha1.RHMISpec{RoutingSubdomain: "apps.example.com"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultInstallationConfigMapName, Namespace: operatorNamespace},
+			Data: map[string]string{
+				"3scale": "NAMESPACE: " + tsNamespace + "\nHOST: https://3scale-admin.apps.example.com\n",
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: systemSeedSecretName, Namespace: tsNamespace},
+			Data:       map[string][]byte{"ADMIN_ACCESS_TOKEN": []byte("admin-token")},
+		},
+	}
+}
+
+func getHitsMetrics() *threescale.Metrics {
+	return &threescale.Metrics{Metrics: []*threescale.Metric{
+		{MetricDetails: threescale.MetricDetails{Id: hitsMetricID, SystemName: "hits.3"}},
+	}}
+}
+
+func getBackend() *integreatlyv1alpha1.ThreeScaleBackend {
+	return &integreatlyv1alpha1.ThreeScaleBackend{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments-backend", Namespace: operatorNamespace},
+		Spec: integreatlyv1alpha1.ThreeScaleBackendSpec{
+			Name:           "Payments Backend",
+			SystemName:     "payments-backend",
+			PrivateBaseURL: "https://payments.example.com:443",
+			MappingRules: []integreatlyv1alpha1.ThreeScaleMappingRule{
+				{HTTPMethod: "GET", Pattern: "/payments"},
+			},
+		},
+	}
+}
+
+func newTestClientFactory(t *testing.T, tsClient threescale.ThreeScaleInterface) tsClientFactory {
+	return func(installation *integreatlyv1alpha1.RHMI, adminDomain string) threescale.ThreeScaleInterface {
+		if adminDomain != "3scale-admin.apps.example.com" {
+			t.Errorf("unexpected admin domain %s", adminDomain)
+		}
+		return tsClient
+	}
+}
+
+func TestReconcileThreeScaleBackend(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "payments-backend", Namespace: operatorNamespace}}
+
+	t.Run("Backend is created with its mapping rules", func(t *testing.T) {
+		client := fake.NewFakeClientWithScheme(scheme, append(getTestObjects(), getBackend())...)
+		var createdRules []map[string]string
+		tsClient := &threescale.ThreeScaleInterfaceMock{
+			GetBackendApisFunc: func(accessToken string) (*threescale.BackendApis, error) {
+				return &threescale.BackendApis{}, nil
+			},
+			CreateBackendApiFunc: func(data map[string]string, accessToken string) (*threescale.BackendApi, error) {
+				if accessToken != "admin-token" {
+					t.Errorf("unexpected access token %q", accessToken)
+				}
+				return &threescale.BackendApi{BackendApiDetails: threescale.BackendApiDetails{
+					Id: 3, Name: data["name"], SystemName: data["system_name"], PrivateEndpoint: data["private_endpoint"],
+				}}, nil
+			},
+			GetBackendApiMetricsFunc: func(backendID int, accessToken string) (*threescale.Metrics, error) {
+				return getHitsMetrics(), nil
+			},
+			GetBackendApiMappingRulesFunc: func(backendID int, accessToken string) (*threescale.MappingRules, error) {
+				return &threescale.MappingRules{}, nil
+			},
+			CreateBackendApiMappingRuleFunc: func(backendID int, data map[string]string, accessToken string) (*threescale.MappingRule, error) {
+				createdRules = append(createdRules, data)
+				return &threescale.MappingRule{}, nil
+			},
+		}
+		r := &ReconcileThreeScaleBackend{client: client, tsClientFactory: newTestClientFactory(t, tsClient)}
+
+		if _, err := r.Reconcile(request); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if calls := tsClient.CreateBackendApiCalls(); len(calls) != 1 || calls[0].Data["system_name"] != "payments-backend" {
+			t.Errorf("expected backend to be created once, got calls %v", calls)
+		}
+		if len(createdRules) != 1 || createdRules[0]["metric_id"] != "11" || createdRules[0]["delta"] != "1" {
+			t.Errorf("unexpected mapping rules created %v", createdRules)
+		}
+
+		backend := &integreatlyv1alpha1.ThreeScaleBackend{}
+		if err := client.Get(context.TODO(), request.NamespacedName, backend); err != nil {
+			t.Fatal(err)
+		}
+		if backend.Status.Phase != integreatlyv1alpha1.PhaseCompleted || backend.Status.ID != 3 || len(backend.Status.Drift) != 0 {
+			t.Errorf("unexpected status %+v", backend.Status)
+		}
+		if !resources.Contains(backend.Finalizers, backendFinalizer) {
+			t.Errorf("expected finalizer to be added")
+		}
+	})
+
+	t.Run("Drift of an existing backend is reported and corrected", func(t *testing.T) {
+		backend := getBackend()
+		backend.Status.ID = 3
+		client := fake.NewFakeClientWithScheme(scheme, append(getTestObjects(), backend)...)
+		tsClient := &threescale.ThreeScaleInterfaceMock{
+			GetBackendApisFunc: func(accessToken string) (*threescale.BackendApis, error) {
+				return &threescale.BackendApis{BackendApis: []*threescale.BackendApi{
+					{BackendApiDetails: threescale.BackendApiDetails{
+						Id: 3, Name: "Payments Backend", SystemName: "payments-backend", PrivateEndpoint: "https://other.example.com:443",
+					}},
+				}}, nil
+			},
+			UpdateBackendApiFunc: func(backendID int, data map[string]string, accessToken string) (*threescale.BackendApi, error) {
+				return &threescale.BackendApi{}, nil
+			},
+			GetBackendApiMetricsFunc: func(backendID int, accessToken string) (*threescale.Metrics, error) {
+				return getHitsMetrics(), nil
+			},
+			GetBackendApiMappingRulesFunc: func(backendID int, accessToken string) (*threescale.MappingRules, error) {
+				return &threescale.MappingRules{MappingRules: []*threescale.MappingRule{
+					{MappingRuleDetails: threescale.MappingRuleDetails{Id: 1, HTTPMethod: "GET", Pattern: "/payments", Delta: 1, MetricId: hitsMetricID}},
+					{MappingRuleDetails: threescale.MappingRuleDetails{Id: 2, HTTPMethod: "DELETE", Pattern: "/payments", Delta: 1, MetricId: hitsMetricID}},
+				}}, nil
+			},
+			DeleteBackendApiMappingRuleFunc: func(backendID int, ruleID int, accessToken string) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK}, nil
+			},
+		}
+		r := &ReconcileThreeScaleBackend{client: client, tsClientFactory: newTestClientFactory(t, tsClient)}
+
+		if _, err := r.Reconcile(request); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if calls := tsClient.UpdateBackendApiCalls(); len(calls) != 1 || calls[0].Data["private_endpoint"] != "https://payments.example.com:443" {
+			t.Errorf("expected private endpoint to be updated, got calls %v", calls)
+		}
+		if calls := tsClient.DeleteBackendApiMappingRuleCalls(); len(calls) != 1 || calls[0].RuleID != 2 {
+			t.Errorf("expected undeclared mapping rule to be deleted, got calls %v", calls)
+		}
+
+		updated := &integreatlyv1alpha1.ThreeScaleBackend{}
+		if err := client.Get(context.TODO(), request.NamespacedName, updated); err != nil {
+			t.Fatal(err)
+		}
+		if updated.Status.Phase != integreatlyv1alpha1.PhaseCompleted || len(updated.Status.Drift) != 2 {
+			t.Errorf("expected 2 differences to be reported, got status %+v", updated.Status)
+		}
+	})
+
+	t.Run("Backend is deleted with the ThreeScaleBackend", func(t *testing.T) {
+		backend := getBackend()
+		now := metav1.Now()
+		backend.DeletionTimestamp = &now
+		backend.Finalizers = []string{backendFinalizer}
+		backend.Status.ID = 3
+		client := fake.NewFakeClientWithScheme(scheme, append(getTestObjects(), backend)...)
+		tsClient := &threescale.ThreeScaleInterfaceMock{
+			DeleteBackendApiFunc: func(backendID int, accessToken string) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK}, nil
+			},
+		}
+		r := &ReconcileThreeScaleBackend{client: client, tsClientFactory: newTestClientFactory(t, tsClient)}
+
+		if _, err := r.Reconcile(request); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if calls := tsClient.DeleteBackendApiCalls(); len(calls) != 1 || calls[0].BackendID != 3 {
+			t.Errorf("expected backend 3 to be deleted, got calls %v", calls)
+		}
+		updated := &integreatlyv1alpha1.ThreeScaleBackend{}
+		if err := client.Get(context.TODO(), request.NamespacedName, updated); err != nil {
+			t.Fatal(err)
+		}
+		if resources.Contains(updated.Finalizers, backendFinalizer) {
+			t.Errorf("expected finalizer to be removed")
+		}
+	})
+	t.Run("Existing backend is not adopted without adopt", func(t *testing.T) {
+		client := fake.NewFakeClientWithScheme(scheme, append(getTestObjects(), getBackend())...)
+		tsClient := &threescale.ThreeScaleInterfaceMock{
+			GetBackendApisFunc: func(accessToken string) (*threescale.BackendApis, error) {
+				return &threescale.BackendApis{BackendApis: []*threescale.BackendApi{
+					{BackendApiDetails: threescale.BackendApiDetails{Id: 3, SystemName: "payments-backend"}},
+				}}, nil
+			},
+		}
+		r := &ReconcileThreeScaleBackend{client: client, tsClientFactory: newTestClientFactory(t, tsClient)}
+
+		if _, err := r.Reconcile(request); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		updated := &integreatlyv1alpha1.ThreeScaleBackend{}
+		if err := client.Get(context.TODO(), request.NamespacedName, updated); err != nil {
+			t.Fatal(err)
+		}
+		if updated.Status.Phase != integreatlyv1alpha1.PhaseFailed || updated.Status.ID != 0 || updated.Status.Adopted {
+			t.Errorf("expected the existing backend not to be adopted, got status %+v", updated.Status)
+		}
+	})
+}
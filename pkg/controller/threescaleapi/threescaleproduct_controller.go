@@ -0,0 +1,594 @@
+/*
+Copyright 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package threescaleapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/products/threescale"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	"github.com/sirupsen/logrus"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	productFinalizer = "finalizer.product.3scale.integreatly.org"
+
+	publishedPlanState = "published"
+)
+
+// errBackendNotSynced is returned when a backend used by a product hasn't
+// been created in 3scale yet
+var errBackendNotSynced = errors.New("backend not synced yet")
+
+// addProduct adds a new Controller to mgr with r as the reconcile.Reconciler
+func addProduct(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("threescaleproduct-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource ThreeScaleProduct
+	err = c.Watch(&source.Kind{Type: &integreatlyv1alpha1.ThreeScaleProduct{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the backends used by the products
+	return c.Watch(&source.Kind{Type: &integreatlyv1alpha1.ThreeScaleBackend{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: backendProductsMapper{context: context.TODO(), client: mgr.GetClient()},
+	})
+}
+
+// backendProductsMapper maps a ThreeScaleBackend to the ThreeScaleProducts
+// that use it
+type backendProductsMapper struct {
+	context context.Context
+	client  k8sclient.Client
+}
+
+func (m backendProductsMapper) Map(mo handler.MapObject) []reconcile.Request {
+	products := &integreatlyv1alpha1.ThreeScaleProductList{}
+	if err := m.client.List(m.context, products, k8sclient.InNamespace(mo.Meta.GetNamespace())); err != nil {
+		logrus.Errorf("Failed to list 3scale products: %v", err)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, product := range products.Items {
+		for _, usage := range product.Spec.BackendUsages {
+			if usage.BackendRef == mo.Meta.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: product.Name, Namespace: product.Namespace},
+				})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// blank assignment to verify that ReconcileThreeScaleProduct implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileThreeScaleProduct{}
+
+// ReconcileThreeScaleProduct reconciles a ThreeScaleProduct object
+type ReconcileThreeScaleProduct struct {
+	client          k8sclient.Client
+	tsClientFactory tsClientFactory
+}
+
+// Reconcile syncs the product of a ThreeScaleProduct to 3scale, deploys its
+// configuration to the staging APIcast and optionally promotes it to the
+// production APIcast. The product is deleted from 3scale when the
+// ThreeScaleProduct is deleted, unless it was adopted
+func (r *ReconcileThreeScaleProduct) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx := context.TODO()
+
+	product := &integreatlyv1alpha1.ThreeScaleProduct{}
+	err := r.client.Get(ctx, request.NamespacedName, product)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	acc, err := getAccount(ctx, r.client, r.tsClientFactory, product.Namespace, product.Spec.TenantRef)
+
+	if product.DeletionTimestamp != nil {
+		if !resources.Contains(product.Finalizers, productFinalizer) {
+			return reconcile.Result{}, nil
+		}
+		// The products of a deleted tenant are deleted with it
+		if err != nil && !errors.Is(err, errTenantNotFound) {
+			return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, product, integreatlyv1alpha1.PhaseFailed, nil, err)
+		}
+		if product.Status.Adopted {
+			logrus.Infof("Leaving adopted 3scale product %s in 3scale", product.Spec.SystemName)
+		} else if acc != nil && product.Status.ID != 0 {
+			_, err := acc.tsClient.DeleteService(product.Status.ID, acc.accessToken)
+			if err != nil && !threescale.IsNotFoundError(err) {
+				err = fmt.Errorf("failed to delete product %s: %w", product.Spec.SystemName, err)
+				return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, product, integreatlyv1alpha1.PhaseFailed, nil, err)
+			}
+			logrus.Infof("Deleted 3scale product %s", product.Spec.SystemName)
+		}
+		product.Finalizers = resources.Remove(product.Finalizers, productFinalizer)
+		return reconcile.Result{}, r.client.Update(ctx, product)
+	}
+
+	if !resources.Contains(product.Finalizers, productFinalizer) {
+		product.Finalizers = append(product.Finalizers, productFinalizer)
+		if err := r.client.Update(ctx, product); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if err != nil {
+		return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, product, integreatlyv1alpha1.PhaseFailed, nil, err)
+	}
+	if acc == nil {
+		err := fmt.Errorf("waiting for the 3scale tenant to be ready")
+		return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, product, integreatlyv1alpha1.PhaseInProgress, nil, err)
+	}
+
+	drift, err := r.syncProduct(ctx, acc, product)
+	if errors.Is(err, errBackendNotSynced) {
+		return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, product, integreatlyv1alpha1.PhaseInProgress, drift, err)
+	}
+	if err != nil {
+		logrus.Errorf("Failed to sync 3scale product %s: %v", product.Spec.SystemName, err)
+		return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, product, integreatlyv1alpha1.PhaseFailed, drift, err)
+	}
+	if len(drift) > 0 {
+		logrus.Warnf("Corrected drift of 3scale product %s: %v", product.Spec.SystemName, drift)
+	}
+
+	return reconcile.Result{RequeueAfter: syncRequeue}, r.updateStatus(ctx, product, integreatlyv1alpha1.PhaseCompleted, drift, nil)
+}
+
+// syncProduct creates or updates the product with its backend usages,
+// mapping rules, application plans and policies, and deploys it when it
+// changed. It returns the differences found with an existing product
+func (r *ReconcileThreeScaleProduct) syncProduct(ctx context.Context, acc *account, product *integreatlyv1alpha1.ThreeScaleProduct) ([]string, error) {
+	spec := product.Spec
+	var drift []string
+
+	services, err := acc.tsClient.GetServices(acc.accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	var current *threescale.ServiceDetails
+	for _, s := range services.Services {
+		if (product.Status.ID != 0 && s.ServiceDetails.Id == product.Status.ID) ||
+			(product.Status.ID == 0 && s.ServiceDetails.SystemName == spec.SystemName) {
+			current = &s.ServiceDetails
+			break
+		}
+	}
+	if current != nil && product.Status.ID == 0 {
+		if !spec.Adopt {
+			return nil, fmt.Errorf("product %s already exists in 3scale, set adopt to manage it", spec.SystemName)
+		}
+		product.Status.Adopted = true
+		logrus.Infof("Adopted 3scale product %s", spec.SystemName)
+	}
+
+	data := map[string]string{
+		"name":        spec.Name,
+		"description": spec.Description,
+	}
+	created := current == nil
+	if created {
+		if product.Status.ID != 0 {
+			drift = append(drift, fmt.Sprintf("product %d was deleted", product.Status.ID))
+		}
+		data["system_name"] = spec.SystemName
+		service, err := acc.tsClient.CreateService(data, acc.accessToken)
+		if err != nil {
+			return drift, fmt.Errorf("failed to create product: %w", err)
+		}
+		current = &service.ServiceDetails
+		product.Status.StagingVersion = 0
+		product.Status.ProductionVersion = 0
+		product.Status.Adopted = false
+		product.Status.ManagedMappingRules = nil
+		product.Status.ManagedApplicationPlans = nil
+		product.Status.ManagedBackendUsages = nil
+		logrus.Infof("Created 3scale product %s", spec.SystemName)
+	} else {
+		var diff []string
+		if current.Name != spec.Name {
+			diff = append(diff, fmt.Sprintf("name is %q instead of %q", current.Name, spec.Name))
+		}
+		if current.Description != spec.Description {
+			diff = append(diff, fmt.Sprintf("description is %q instead of %q", current.Description, spec.Description))
+		}
+		if len(diff) > 0 {
+			drift = append(drift, diff...)
+			if _, err := acc.tsClient.UpdateService(current.Id, data, acc.accessToken); err != nil {
+				return drift, fmt.Errorf("failed to update product: %w", err)
+			}
+		}
+	}
+	product.Status.ID = current.Id
+
+	syncs := []func() ([]string, error){
+		func() ([]string, error) { return r.syncBackendUsages(ctx, acc, product) },
+		func() ([]string, error) { return syncServiceMappingRules(acc, product) },
+		func() ([]string, error) { return syncApplicationPlans(acc, product) },
+		func() ([]string, error) { return syncPolicies(acc, current.Id, spec.Policies) },
+	}
+	for _, sync := range syncs {
+		syncDrift, err := sync()
+		drift = append(drift, syncDrift...)
+		if err != nil {
+			return reportedDrift(created, drift), err
+		}
+	}
+
+	err = deployProduct(acc, product, created || len(drift) > 0)
+	return reportedDrift(created, drift), err
+}
+
+// reportedDrift returns the drift to report. The differences found while
+// setting up a product that was just created aren't drift
+func reportedDrift(created bool, drift []string) []string {
+	if created {
+		return nil
+	}
+	return drift
+}
+
+// syncBackendUsages creates the declared backend usages that are missing or
+// differ, and deletes the managed backend usages that aren't declared
+func (r *ReconcileThreeScaleProduct) syncBackendUsages(ctx context.Context, acc *account, product *integreatlyv1alpha1.ThreeScaleProduct) ([]string, error) {
+	var drift []string
+
+	declared := map[int]string{}
+	for _, usage := range product.Spec.BackendUsages {
+		backend := &integreatlyv1alpha1.ThreeScaleBackend{}
+		err := r.client.Get(ctx, k8sclient.ObjectKey{Name: usage.BackendRef, Namespace: product.Namespace}, backend)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get 3scale backend %s: %w", usage.BackendRef, err)
+		}
+		if backend.Spec.TenantRef != product.Spec.TenantRef {
+			return nil, fmt.Errorf("3scale backend %s belongs to another tenant", usage.BackendRef)
+		}
+		if backend.Status.ID == 0 {
+			return nil, fmt.Errorf("%w: %s", errBackendNotSynced, usage.BackendRef)
+		}
+		declared[backend.Status.ID] = usage.Path
+	}
+
+	usages, err := acc.tsClient.GetBackendUsages(product.Status.ID, acc.accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backend usages: %w", err)
+	}
+	adopted := product.Status.Adopted
+	existing := map[int]bool{}
+	for _, usage := range usages {
+		details := usage.BackendUsageDetails
+		path, ok := declared[details.BackendId]
+		if ok && path == details.Path {
+			existing[details.BackendId] = true
+			continue
+		}
+		if !ok && adopted && !containsID(product.Status.ManagedBackendUsages, details.BackendId) {
+			continue
+		}
+		if ok {
+			drift = append(drift, fmt.Sprintf("backend %d is used on path %q instead of %q", details.BackendId, details.Path, path))
+		} else {
+			drift = append(drift, fmt.Sprintf("backend %d usage is not declared", details.BackendId))
+		}
+		if _, err := acc.tsClient.DeleteBackendUsage(product.Status.ID, details.Id, acc.accessToken); err != nil {
+			return drift, fmt.Errorf("failed to delete usage of backend %d: %w", details.BackendId, err)
+		}
+		if !ok {
+			product.Status.ManagedBackendUsages = removeID(product.Status.ManagedBackendUsages, details.BackendId)
+		}
+	}
+
+	var missing []int
+	for backendID := range declared {
+		if !existing[backendID] {
+			missing = append(missing, backendID)
+		}
+	}
+	sort.Ints(missing)
+	for _, backendID := range missing {
+		drift = append(drift, fmt.Sprintf("backend %d usage is missing", backendID))
+		_, err := acc.tsClient.CreateBackendUsage(product.Status.ID, map[string]string{
+			"backend_api_id": fmt.Sprintf("%d", backendID),
+			"path":           declared[backendID],
+		}, acc.accessToken)
+		if err != nil {
+			return drift, fmt.Errorf("failed to create usage of backend %d: %w", backendID, err)
+		}
+		if adopted && !containsID(product.Status.ManagedBackendUsages, backendID) {
+			product.Status.ManagedBackendUsages = append(product.Status.ManagedBackendUsages, backendID)
+		}
+	}
+
+	return drift, nil
+}
+
+func containsID(ids []int, id int) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func removeID(ids []int, id int) []int {
+	for i, v := range ids {
+		if v == id {
+			return append(ids[:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
+
+func syncServiceMappingRules(acc *account, product *integreatlyv1alpha1.ThreeScaleProduct) ([]string, error) {
+	serviceID := product.Status.ID
+	metrics, err := acc.tsClient.GetServiceMetrics(serviceID, acc.accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product metrics: %w", err)
+	}
+	hitsID, err := getHitsMetricID(metrics)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := acc.tsClient.GetServiceMappingRules(serviceID, acc.accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product mapping rules: %w", err)
+	}
+	return syncMappingRules(product.Spec.MappingRules, rules, hitsID, product.Status.Adopted, &product.Status.ManagedMappingRules, func(data map[string]string) error {
+		_, err := acc.tsClient.CreateServiceMappingRule(serviceID, data, acc.accessToken)
+		return err
+	}, func(ruleID int) error {
+		_, err := acc.tsClient.DeleteServiceMappingRule(serviceID, ruleID, acc.accessToken)
+		return err
+	})
+}
+
+// syncApplicationPlans creates the declared application plans that are
+// missing, updates the ones that differ and deletes the managed ones that
+// aren't declared
+func syncApplicationPlans(acc *account, product *integreatlyv1alpha1.ThreeScaleProduct) ([]string, error) {
+	serviceID := product.Status.ID
+	declared := product.Spec.ApplicationPlans
+	adopted := product.Status.Adopted
+	var drift []string
+
+	plans, err := acc.tsClient.GetApplicationPlans(serviceID, acc.accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application plans: %w", err)
+	}
+	existing := map[string]threescale.ApplicationPlanDetails{}
+	for _, plan := range plans.Plans {
+		existing[plan.ApplicationPlanDetails.SystemName] = plan.ApplicationPlanDetails
+	}
+
+	declaredPlans := map[string]bool{}
+	for _, plan := range declared {
+		declaredPlans[plan.SystemName] = true
+		stateEvent := "hide"
+		if plan.Published {
+			stateEvent = "publish"
+		}
+
+		current, ok := existing[plan.SystemName]
+		if !ok {
+			drift = append(drift, fmt.Sprintf("application plan %s is missing", plan.SystemName))
+			data := map[string]string{"name": plan.Name, "system_name": plan.SystemName}
+			if plan.Published {
+				data["state_event"] = stateEvent
+			}
+			if _, err := acc.tsClient.CreateApplicationPlan(serviceID, data, acc.accessToken); err != nil {
+				return drift, fmt.Errorf("failed to create application plan %s: %w", plan.SystemName, err)
+			}
+			product.Status.ManagedApplicationPlans = addManaged(adopted, product.Status.ManagedApplicationPlans, plan.SystemName)
+			continue
+		}
+
+		if current.Name == plan.Name && (current.State == publishedPlanState) == plan.Published {
+			continue
+		}
+		drift = append(drift, fmt.Sprintf("application plan %s differs", plan.SystemName))
+		data := map[string]string{"name": plan.Name, "state_event": stateEvent}
+		if _, err := acc.tsClient.UpdateApplicationPlan(serviceID, current.Id, data, acc.accessToken); err != nil {
+			return drift, fmt.Errorf("failed to update application plan %s: %w", plan.SystemName, err)
+		}
+	}
+
+	var undeclared []string
+	for systemName := range existing {
+		if !declaredPlans[systemName] && isManaged(adopted, product.Status.ManagedApplicationPlans, systemName) {
+			undeclared = append(undeclared, systemName)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, systemName := range undeclared {
+		drift = append(drift, fmt.Sprintf("application plan %s is not declared", systemName))
+		if _, err := acc.tsClient.DeleteApplicationPlan(serviceID, existing[systemName].Id, acc.accessToken); err != nil {
+			return drift, fmt.Errorf("failed to delete application plan %s: %w", systemName, err)
+		}
+		product.Status.ManagedApplicationPlans = resources.Remove(product.Status.ManagedApplicationPlans, systemName)
+	}
+
+	return drift, nil
+}
+
+// syncPolicies replaces the policy chain of the product when it differs
+// from the declared one. The policy chain isn't managed when no policies are
+// declared
+func syncPolicies(acc *account, serviceID int, declared []integreatlyv1alpha1.ThreeScalePolicy) ([]string, error) {
+	if len(declared) == 0 {
+		return nil, nil
+	}
+
+	policies := make([]threescale.PolicyConfig, 0, len(declared))
+	for _, policy := range declared {
+		configuration := json.RawMessage("{}")
+		if policy.Configuration != nil && len(policy.Configuration.Raw) > 0 {
+			configuration = json.RawMessage(policy.Configuration.Raw)
+		}
+		policies = append(policies, threescale.PolicyConfig{
+			Name:          policy.Name,
+			Version:       policy.Version,
+			Configuration: configuration,
+			Enabled:       policy.Enabled,
+		})
+	}
+
+	current, err := acc.tsClient.GetPolicies(serviceID, acc.accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policy chain: %w", err)
+	}
+	equal, err := policiesEqual(current.Policies, policies)
+	if err != nil {
+		return nil, err
+	}
+	if equal {
+		return nil, nil
+	}
+
+	drift := []string{"policy chain differs"}
+	if _, err := acc.tsClient.UpdatePolicies(serviceID, policies, acc.accessToken); err != nil {
+		return drift, fmt.Errorf("failed to update policy chain: %w", err)
+	}
+	return drift, nil
+}
+
+// policiesEqual compares two policy chains, ignoring the formatting of the
+// policy configurations
+func policiesEqual(a, b []threescale.PolicyConfig) (bool, error) {
+	if len(a) != len(b) {
+		return false, nil
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Version != b[i].Version || a[i].Enabled != b[i].Enabled {
+			return false, nil
+		}
+		var configA, configB interface{}
+		if len(a[i].Configuration) > 0 {
+			if err := json.Unmarshal(a[i].Configuration, &configA); err != nil {
+				return false, fmt.Errorf("failed to parse configuration of policy %s: %w", a[i].Name, err)
+			}
+		}
+		if len(b[i].Configuration) > 0 {
+			if err := json.Unmarshal(b[i].Configuration, &configB); err != nil {
+				return false, fmt.Errorf("failed to parse configuration of policy %s: %w", b[i].Name, err)
+			}
+		}
+		if configA == nil {
+			configA = map[string]interface{}{}
+		}
+		if configB == nil {
+			configB = map[string]interface{}{}
+		}
+		if !reflect.DeepEqual(configA, configB) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// deployProduct deploys the product configuration to the staging APIcast
+// when it changed or was never deployed, and promotes the latest staging
+// configuration to the production APIcast if requested
+func deployProduct(acc *account, product *integreatlyv1alpha1.ThreeScaleProduct, changed bool) error {
+	serviceID := product.Status.ID
+
+	staging, err := getLatestProxyConfig(acc, serviceID, stagingEnvironment)
+	if err != nil {
+		return err
+	}
+	if changed || staging == nil {
+		if _, err := acc.tsClient.DeployProxy(serviceID, acc.accessToken); err != nil {
+			return fmt.Errorf("failed to deploy product to staging: %w", err)
+		}
+		staging, err = getLatestProxyConfig(acc, serviceID, stagingEnvironment)
+		if err != nil {
+			return err
+		}
+		if staging == nil {
+			return fmt.Errorf("staging configuration not found after deploying")
+		}
+		logrus.Infof("Deployed 3scale product %s to staging version %d", product.Spec.SystemName, staging.Version)
+	}
+	product.Status.StagingVersion = staging.Version
+
+	production, err := getLatestProxyConfig(acc, serviceID, productionEnvironment)
+	if err != nil {
+		return err
+	}
+	productionVersion := 0
+	if production != nil {
+		productionVersion = production.Version
+	}
+	if product.Spec.PromoteToProduction && productionVersion < staging.Version {
+		_, err := acc.tsClient.PromoteProxyConfig(serviceID, stagingEnvironment, staging.Version, productionEnvironment, acc.accessToken)
+		if err != nil {
+			return fmt.Errorf("failed to promote staging version %d to production: %w", staging.Version, err)
+		}
+		productionVersion = staging.Version
+		logrus.Infof("Promoted 3scale product %s to production version %d", product.Spec.SystemName, productionVersion)
+	}
+	product.Status.ProductionVersion = productionVersion
+
+	return nil
+}
+
+// getLatestProxyConfig returns the latest proxy configuration of the
+// environment, or nil if none was deployed
+func getLatestProxyConfig(acc *account, serviceID int, environment string) (*threescale.ProxyConfig, error) {
+	config, err := acc.tsClient.GetLatestProxyConfig(serviceID, environment, acc.accessToken)
+	if threescale.IsNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest %s configuration: %w", environment, err)
+	}
+	return config, nil
+}
+
+func (r *ReconcileThreeScaleProduct) updateStatus(ctx context.Context, product *integreatlyv1alpha1.ThreeScaleProduct, phase integreatlyv1alpha1.StatusPhase, drift []string, syncErr error) error {
+	product.Status.ThreeScaleSyncStatus = syncStatus(product.Status.ThreeScaleSyncStatus, phase, drift, syncErr)
+	if err := r.client.Status().Update(ctx, product); err != nil && !k8serr.IsNotFound(err) {
+		return fmt.Errorf("failed to update status of 3scale product %s: %w", product.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+package threescaleapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/products/threescale"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func getProduct() *integreatlyv1alpha1.ThreeScaleProduct {
+	return &integreatlyv1alpha1.ThreeScaleProduct{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: operatorNamespace},
+		Spec: integreatlyv1alpha1.ThreeScaleProductSpec{
+			Name:       "Payments",
+			SystemName: "payments",
+			BackendUsages: []integreatlyv1alpha1.ThreeScaleBackendUsage{
+				{BackendRef: "payments-backend", Path: "/"},
+			},
+			ApplicationPlans: []integreatlyv1alpha1.ThreeScaleApplicationPlan{
+				{Name: "Basic", SystemName: "basic", Published: true},
+			},
+			Policies: []integreatlyv1alpha1.ThreeScalePolicy{
+				{Name: "apicast", Version: "builtin", Enabled: true},
+			},
+			PromoteToProduction: true,
+		},
+	}
+}
+
+// getProductTestClient returns a client for a 3scale account without
+// products, which keeps track of the deployed configuration versions. No
+// configuration is returned for the environments that weren't deployed to
+func getProductTestClient(proxyVersions map[string]int) *threescale.ThreeScaleInterfaceMock {
+	return &threescale.ThreeScaleInterfaceMock{
+		GetServicesFunc: func(accessToken string) (*threescale.Services, error) {
+			return &threescale.Services{}, nil
+		},
+		CreateServiceFunc: func(data map[string]string, accessToken string) (*threescale.Service, error) {
+			return &threescale.Service{ServiceDetails: threescale.ServiceDetails{Id: 8, Name: data["name"], SystemName: data["system_name"]}}, nil
+		},
+		GetBackendUsagesFunc: func(serviceID int, accessToken string) ([]*threescale.BackendUsage, error) {
+			return nil, nil
+		},
+		CreateBackendUsageFunc: func(serviceID int, data map[string]string, accessToken string) (*threescale.BackendUsage, error) {
+			return &threescale.BackendUsage{}, nil
+		},
+		GetServiceMetricsFunc: func(serviceID int, accessToken string) (*threescale.Metrics, error) {
+			return getHitsMetrics(), nil
+		},
+		GetServiceMappingRulesFunc: func(serviceID int, accessToken string) (*threescale.MappingRules, error) {
+			return &threescale.MappingRules{}, nil
+		},
+		GetApplicationPlansFunc: func(serviceID int, accessToken string) (*threescale.ApplicationPlans, error) {
+			return &threescale.ApplicationPlans{}, nil
+		},
+		CreateApplicationPlanFunc: func(serviceID int, data map[string]string, accessToken string) (*threescale.ApplicationPlan, error) {
+			return &threescale.ApplicationPlan{}, nil
+		},
+		GetPoliciesFunc: func(serviceID int, accessToken string) (*threescale.PoliciesConfig, error) {
+			return &threescale.PoliciesConfig{Policies: []threescale.PolicyConfig{
+				{Name: "apicast", Version: "builtin", Enabled: true, Configuration: json.RawMessage("{}")},
+			}}, nil
+		},
+		DeployProxyFunc: func(serviceID int, accessToken string) (*http.Response, error) {
+			proxyVersions[stagingEnvironment]++
+			return &http.Response{StatusCode: http.StatusCreated}, nil
+		},
+		GetLatestProxyConfigFunc: func(serviceID int, environment string, accessToken string) (*threescale.ProxyConfig, error) {
+			version, ok := proxyVersions[environment]
+			if !ok {
+				return nil, nil
+			}
+			return &threescale.ProxyConfig{Version: version, Environment: environment}, nil
+		},
+		PromoteProxyConfigFunc: func(serviceID int, environment string, version int, toEnvironment string, accessToken string) (*http.Response, error) {
+			proxyVersions[toEnvironment] = version
+			return &http.Response{StatusCode: http.StatusCreated}, nil
+		},
+	}
+}
+
+func TestReconcileThreeScaleProduct(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "payments", Namespace: operatorNamespace}}
+
+	t.Run("Product is created, deployed to staging and promoted to production", func(t *testing.T) {
+		backend := getBackend()
+		backend.Status.ID = 3
+		client := fake.NewFakeClientWithScheme(scheme, append(getTestObjects(), backend, getProduct())...)
+		proxyVersions := map[string]int{}
+		tsClient := getProductTestClient(proxyVersions)
+		r := &ReconcileThreeScaleProduct{client: client, tsClientFactory: newTestClientFactory(t, tsClient)}
+
+		if _, err := r.Reconcile(request); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if calls := tsClient.CreateBackendUsageCalls(); len(calls) != 1 || calls[0].Data["backend_api_id"] != "3" || calls[0].Data["path"] != "/" {
+			t.Errorf("expected usage of backend 3 to be created, got calls %v", calls)
+		}
+		if calls := tsClient.CreateApplicationPlanCalls(); len(calls) != 1 || calls[0].Data["state_event"] != "publish" {
+			t.Errorf("expected published application plan to be created, got calls %v", calls)
+		}
+		if len(tsClient.UpdatePoliciesCalls()) != 0 {
+			t.Errorf("expected the matching policy chain to be left as is")
+		}
+		if len(tsClient.DeployProxyCalls()) != 1 || len(tsClient.PromoteProxyConfigCalls()) != 1 {
+			t.Errorf("expected product to be deployed and promoted once")
+		}
+
+		product := &integreatlyv1alpha1.ThreeScaleProduct{}
+		if err := client.Get(context.TODO(), request.NamespacedName, product); err != nil {
+			t.Fatal(err)
+		}
+		if product.Status.Phase != integreatlyv1alpha1.PhaseCompleted || product.Status.ID != 8 ||
+			product.Status.StagingVersion != 1 || product.Status.ProductionVersion != 1 || len(product.Status.Drift) != 0 {
+			t.Errorf("unexpected status %+v", product.Status)
+		}
+	})
+
+	t.Run("Product waits for its backends to be synced", func(t *testing.T) {
+		client := fake.NewFakeClientWithScheme(scheme, append(getTestObjects(), getBackend(), getProduct())...)
+		tsClient := getProductTestClient(map[string]int{})
+		r := &ReconcileThreeScaleProduct{client: client, tsClientFactory: newTestClientFactory(t, tsClient)}
+
+		if _, err := r.Reconcile(request); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(tsClient.CreateBackendUsageCalls()) != 0 || len(tsClient.DeployProxyCalls()) != 0 {
+			t.Errorf("expected product not to be deployed before its backend is synced")
+		}
+		product := &integreatlyv1alpha1.ThreeScaleProduct{}
+		if err := client.Get(context.TODO(), request.NamespacedName, product); err != nil {
+			t.Fatal(err)
+		}
+		if product.Status.Phase != integreatlyv1alpha1.PhaseInProgress {
+			t.Errorf("expected phase %s, got %s", integreatlyv1alpha1.PhaseInProgress, product.Status.Phase)
+		}
+	})
+
+	t.Run("Policy chain drift is corrected and redeployed without promotion", func(t *testing.T) {
+		backend := getBackend()
+		backend.Status.ID = 3
+		product := getProduct()
+		product.Spec.PromoteToProduction = false
+		product.Spec.Policies = []integreatlyv1alpha1.ThreeScalePolicy{
+			{Name: "headers", Version: "builtin", Enabled: true, Configuration: &apiextensionsv1beta1.JSON{Raw: []byte(`{"request": []}`)}},
+			{Name: "apicast", Version: "builtin", Enabled: true},
+		}
+		product.Status.ID = 8
+		client := fake.NewFakeClientWithScheme(scheme, append(getTestObjects(), backend, product)...)
+		proxyVersions := map[string]int{stagingEnvironment: 4, productionEnvironment: 4}
+		tsClient := getProductTestClient(proxyVersions)
+		tsClient.GetServicesFunc = func(accessToken string) (*threescale.Services, error) {
+			return &threescale.Services{Services: []*threescale.Service{
+				{ServiceDetails: threescale.ServiceDetails{Id: 8, Name: "Payments", SystemName: "payments"}},
+			}}, nil
+		}
+		tsClient.GetBackendUsagesFunc = func(serviceID int, accessToken string) ([]*threescale.BackendUsage, error) {
+			return []*threescale.BackendUsage{
+				{BackendUsageDetails: threescale.BackendUsageDetails{Id: 1, Path: "/", ServiceId: 8, BackendId: 3}},
+			}, nil
+		}
+		tsClient.GetApplicationPlansFunc = func(serviceID int, accessToken string) (*threescale.ApplicationPlans, error) {
+			return &threescale.ApplicationPlans{Plans: []*threescale.ApplicationPlan{
+				{ApplicationPlanDetails: threescale.ApplicationPlanDetails{Id: 2, Name: "Basic", SystemName: "basic", State: publishedPlanState}},
+			}}, nil
+		}
+		tsClient.UpdatePoliciesFunc = func(serviceID int, policies []threescale.PolicyConfig, accessToken string) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}
+		r := &ReconcileThreeScaleProduct{client: client, tsClientFactory: newTestClientFactory(t, tsClient)}
+
+		if _, err := r.Reconcile(request); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if calls := tsClient.UpdatePoliciesCalls(); len(calls) != 1 || len(calls[0].Policies) != 2 || calls[0].Policies[0].Name != "headers" {
+			t.Errorf("expected the declared policy chain to be applied, got calls %v", calls)
+		}
+		if len(tsClient.DeployProxyCalls()) != 1 || len(tsClient.PromoteProxyConfigCalls()) != 0 {
+			t.Errorf("expected product to be deployed to staging only")
+		}
+
+		updated := &integreatlyv1alpha1.ThreeScaleProduct{}
+		if err := client.Get(context.TODO(), request.NamespacedName, updated); err != nil {
+			t.Fatal(err)
+		}
+		if len(updated.Status.Drift) != 1 || updated.Status.StagingVersion != 5 || updated.Status.ProductionVersion != 4 {
+			t.Errorf("unexpected status %+v", updated.Status)
+		}
+	})
+
+	t.Run("Existing product is only adopted when requested", func(t *testing.T) {
+		scenarios := []struct {
+			Name          string
+			Adopt         bool
+			ExpectedPhase integreatlyv1alpha1.StatusPhase
+		}{
+			{
+				Name:          "product without adopt",
+				ExpectedPhase: integreatlyv1alpha1.PhaseFailed,
+			},
+			{
+				Name:          "product with adopt",
+				Adopt:         true,
+				ExpectedPhase: integreatlyv1alpha1.PhaseCompleted,
+			},
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario.Name, func(t *testing.T) {
+				backend := getBackend()
+				backend.Status.ID = 3
+				product := getProduct()
+				product.Spec.Adopt = scenario.Adopt
+				client := fake.NewFakeClientWithScheme(scheme, append(getTestObjects(), backend, product)...)
+				tsClient := getProductTestClient(map[string]int{stagingEnvironment: 1, productionEnvironment: 1})
+				tsClient.GetServicesFunc = func(accessToken string) (*threescale.Services, error) {
+					return &threescale.Services{Services: []*threescale.Service{
+						{ServiceDetails: threescale.ServiceDetails{Id: 8, Name: "Payments", SystemName: "payments"}},
+					}}, nil
+				}
+				tsClient.GetBackendUsagesFunc = func(serviceID int, accessToken string) ([]*threescale.BackendUsage, error) {
+					return []*threescale.BackendUsage{
+						{BackendUsageDetails: threescale.BackendUsageDetails{Id: 1, Path: "/other", ServiceId: 8, BackendId: 4}},
+					}, nil
+				}
+				tsClient.GetServiceMappingRulesFunc = func(serviceID int, accessToken string) (*threescale.MappingRules, error) {
+					return &threescale.MappingRules{MappingRules: []*threescale.MappingRule{
+						{MappingRuleDetails: threescale.MappingRuleDetails{Id: 1, HTTPMethod: "GET", Pattern: "/", Delta: 1, MetricId: hitsMetricID}},
+					}}, nil
+				}
+				tsClient.GetApplicationPlansFunc = func(serviceID int, accessToken string) (*threescale.ApplicationPlans, error) {
+					return &threescale.ApplicationPlans{Plans: []*threescale.ApplicationPlan{
+						{ApplicationPlanDetails: threescale.ApplicationPlanDetails{Id: 2, Name: "Gold", SystemName: "gold"}},
+					}}, nil
+				}
+				r := &ReconcileThreeScaleProduct{client: client, tsClientFactory: newTestClientFactory(t, tsClient)}
+
+				if _, err := r.Reconcile(request); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if len(tsClient.CreateServiceCalls()) != 0 {
+					t.Errorf("expected the existing product not to be created again")
+				}
+				if len(tsClient.DeleteBackendUsageCalls()) != 0 || len(tsClient.DeleteServiceMappingRuleCalls()) != 0 || len(tsClient.DeleteApplicationPlanCalls()) != 0 {
+					t.Errorf("expected the objects not created by the operator to be left as is")
+				}
+				updated := &integreatlyv1alpha1.ThreeScaleProduct{}
+				if err := client.Get(context.TODO(), request.NamespacedName, updated); err != nil {
+					t.Fatal(err)
+				}
+				if updated.Status.Phase != scenario.ExpectedPhase || updated.Status.Adopted != scenario.Adopt {
+					t.Errorf("expected phase %s and adopted %t, got status %+v", scenario.ExpectedPhase, scenario.Adopt, updated.Status)
+				}
+				if scenario.Adopt && (len(updated.Status.ManagedApplicationPlans) != 1 || len(updated.Status.ManagedBackendUsages) != 1) {
+					t.Errorf("expected the created application plan and backend usage to be managed, got status %+v", updated.Status)
+				}
+			})
+		}
+	})
+
+	t.Run("Adopted product is left in 3scale when deleted", func(t *testing.T) {
+		product := getProduct()
+		now := metav1.Now()
+		product.DeletionTimestamp = &now
+		product.Finalizers = []string{productFinalizer}
+		product.Status.ID = 8
+		product.Status.Adopted = true
+		client := fake.NewFakeClientWithScheme(scheme, append(getTestObjects(), product)...)
+		tsClient := &threescale.ThreeScaleInterfaceMock{}
+		r := &ReconcileThreeScaleProduct{client: client, tsClientFactory: newTestClientFactory(t, tsClient)}
+
+		if _, err := r.Reconcile(request); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(tsClient.DeleteServiceCalls()) != 0 {
+			t.Errorf("expected the adopted product not to be deleted")
+		}
+		updated := &integreatlyv1alpha1.ThreeScaleProduct{}
+		if err := client.Get(context.TODO(), request.NamespacedName, updated); err != nil {
+			t.Fatal(err)
+		}
+		if resources.Contains(updated.Finalizers, productFinalizer) {
+			t.Errorf("expected finalizer to be removed")
+		}
+	})
+}
+
+func TestPoliciesEqual(t *testing.T) {
+	current := []threescale.PolicyConfig{
+		{Name: "headers", Version: "builtin", Enabled: true, Configuration: json.RawMessage(`{"request":[],"response":[]}`)},
+	}
+	declared := []threescale.PolicyConfig{
+		{Name: "headers", Version: "builtin", Enabled: true, Configuration: json.RawMessage(`{ "response": [], "request": [] }`)},
+	}
+
+	equal, err := policiesEqual(current, declared)
+	if err != nil || !equal {
+		t.Errorf("expected policy chains with differently formatted configurations to be equal, got %t, %v", equal, err)
+	}
+
+	declared[0].Enabled = false
+	equal, err = policiesEqual(current, declared)
+	if err != nil || equal {
+		t.Errorf("expected policy chains with a disabled policy to differ, got %t, %v", equal, err)
+	}
+}
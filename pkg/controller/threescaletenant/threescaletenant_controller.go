@@ -0,0 +1,569 @@
+/*
+Copyright 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package threescaletenant
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/products/rhssouser"
+	"github.com/integr8ly/integreatly-operator/pkg/products/threescale"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+)
+
+const (
+	tenantFinalizer = "finalizer.tenant.3scale.integreatly.org"
+	// tenantLabel is set to the name of the ThreeScaleTenant on the objects
+	// created for the tenant in the product namespaces, which can't be owned
+	// by the ThreeScaleTenant
+	tenantLabel = "integreatly.org/3scale-tenant"
+
+	defaultInstallationConfigMapName = "installation-config"
+	systemSeedSecretName             = "system-seed"
+	systemProviderServiceName        = "system-provider"
+	ssoIntegrationName               = "rhssouser"
+
+	// Keys of the tenant secret
+	adminURLKey         = "ADMIN_URL"
+	adminUserKey        = "ADMIN_USER"
+	adminPasswordKey    = "ADMIN_PASSWORD"
+	adminAccessTokenKey = "ADMIN_ACCESS_TOKEN"
+	tenantIDKey         = "TENANT_ID"
+	clientSecretKey     = "CLIENT_SECRET"
+
+	pendingRequeue   = 30 * time.Second
+	completedRequeue = 10 * time.Minute
+)
+
+// Add creates a new ThreeScaleTenant Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	r, err := newReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return add(mgr, r)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	// The objects of the tenants are created in the product namespaces,
+	// which are not in the cache of the manager
+	client, err := k8sclient.New(mgr.GetConfig(), k8sclient.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return nil, fmt.Errorf("could not create client for threescaletenant controller: %w", err)
+	}
+
+	return &ReconcileThreeScaleTenant{
+		client:          client,
+		scheme:          mgr.GetScheme(),
+		tsClientFactory: threescale.NewThreeScaleInstallationClient,
+	}, nil
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("threescaletenant-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource ThreeScaleTenant
+	err = c.Watch(&source.Kind{Type: &integreatlyv1alpha1.ThreeScaleTenant{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the tenant secrets
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &integreatlyv1alpha1.ThreeScaleTenant{},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileThreeScaleTenant implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileThreeScaleTenant{}
+
+// ReconcileThreeScaleTenant reconciles a ThreeScaleTenant object
+type ReconcileThreeScaleTenant struct {
+	client          k8sclient.Client
+	scheme          *runtime.Scheme
+	tsClientFactory func(installation *integreatlyv1alpha1.RHMI, adminDomain string) threescale.ThreeScaleInterface
+}
+
+// tenantContext holds the installation details used to reconcile a tenant
+type tenantContext struct {
+	installation *integreatlyv1alpha1.RHMI
+	tsConfig     *config.ThreeScale
+	ssoConfig    *config.RHSSOUser
+	masterToken  string
+	tsClient     threescale.ThreeScaleInterface
+}
+
+// Reconcile creates the 3scale tenant of a ThreeScaleTenant with its admin
+// user, integrates its admin portal with the user SSO, and publishes its
+// admin portal route and the secret with its access token. The tenant is
+// deleted from 3scale when the ThreeScaleTenant is deleted
+func (r *ReconcileThreeScaleTenant) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx := context.TODO()
+
+	tenant := &integreatlyv1alpha1.ThreeScaleTenant{}
+	err := r.client.Get(ctx, request.NamespacedName, tenant)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	logrus.Infof("Reconciling 3scale tenant %s", tenant.Name)
+
+	tc, err := r.getTenantContext(ctx, tenant.Namespace)
+
+	if tenant.DeletionTimestamp != nil {
+		if !resources.Contains(tenant.Finalizers, tenantFinalizer) {
+			return reconcile.Result{}, nil
+		}
+		// Without 3scale installed there is nothing left to clean up, and
+		// without a working installation the tenant can't be cleaned up, so
+		// it's left in 3scale rather than blocking the deletion
+		if err != nil {
+			logrus.Warnf("Failed to get the 3scale installation, tenant %s is not deleted from 3scale: %v", tenant.Spec.OrganizationName, err)
+		} else if tc != nil {
+			if err := r.deleteTenant(ctx, tc, tenant); err != nil {
+				return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, tenant, integreatlyv1alpha1.PhaseFailed, err.Error())
+			}
+		}
+		tenant.Finalizers = resources.Remove(tenant.Finalizers, tenantFinalizer)
+		return reconcile.Result{}, r.client.Update(ctx, tenant)
+	}
+
+	if err != nil {
+		return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, tenant, integreatlyv1alpha1.PhaseFailed, err.Error())
+	}
+
+	if !resources.Contains(tenant.Finalizers, tenantFinalizer) {
+		tenant.Finalizers = append(tenant.Finalizers, tenantFinalizer)
+		if err := r.client.Update(ctx, tenant); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if tc == nil {
+		return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, tenant, integreatlyv1alpha1.PhaseInProgress, "waiting for 3scale and the user SSO to be installed")
+	}
+
+	phase, err := r.reconcileTenant(ctx, tc, tenant)
+	if err != nil {
+		logrus.Errorf("Failed to reconcile 3scale tenant %s: %v", tenant.Name, err)
+		return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, tenant, integreatlyv1alpha1.PhaseFailed, err.Error())
+	}
+	if phase != integreatlyv1alpha1.PhaseCompleted {
+		return reconcile.Result{RequeueAfter: pendingRequeue}, r.updateStatus(ctx, tenant, phase, "")
+	}
+
+	logrus.Infof("Reconciled 3scale tenant %s", tenant.Name)
+	return reconcile.Result{RequeueAfter: completedRequeue}, r.updateStatus(ctx, tenant, integreatlyv1alpha1.PhaseCompleted, "")
+}
+
+// getTenantContext returns the installation details used to reconcile the
+// tenants in namespace, or nil if 3scale or the user SSO aren't installed
+func (r *ReconcileThreeScaleTenant) getTenantContext(ctx context.Context, namespace string) (*tenantContext, error) {
+	installation, err := resources.GetRhmiCr(r.client, ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RHMI CR: %w", err)
+	}
+	if installation == nil {
+		return nil, nil
+	}
+
+	installationCfgMap := os.Getenv("INSTALLATION_CONFIG_MAP")
+	if installationCfgMap == "" {
+		installationCfgMap = installation.Spec.NamespacePrefix + defaultInstallationConfigMapName
+	}
+	configManager, err := config.NewManager(ctx, r.client, installation.Namespace, installationCfgMap, installation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config manager: %w", err)
+	}
+	tsConfig, err := configManager.ReadThreeScale()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read 3scale config: %w", err)
+	}
+	ssoConfig, err := configManager.ReadRHSSOUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user SSO config: %w", err)
+	}
+	if tsConfig.GetHost() == "" || ssoConfig.GetHost() == "" {
+		return nil, nil
+	}
+
+	seed := &corev1.Secret{}
+	err = r.client.Get(ctx, k8sclient.ObjectKey{Name: systemSeedSecretName, Namespace: tsConfig.GetNamespace()}, seed)
+	if k8serr.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get %s secret: %w", systemSeedSecretName, err)
+	}
+
+	return &tenantContext{
+		installation: installation,
+		tsConfig:     tsConfig,
+		ssoConfig:    ssoConfig,
+		masterToken:  string(seed.Data["MASTER_ACCESS_TOKEN"]),
+		tsClient:     r.tsClientFactory(installation, ""),
+	}, nil
+}
+
+func (r *ReconcileThreeScaleTenant) reconcileTenant(ctx context.Context, tc *tenantContext, tenant *integreatlyv1alpha1.ThreeScaleTenant) (integreatlyv1alpha1.StatusPhase, error) {
+	secret, err := r.reconcileTenantSecret(ctx, tenant)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
+	phase, err := r.reconcileTenantAccount(ctx, tc, tenant, secret)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		return phase, err
+	}
+
+	phase, err = r.reconcileAdminRoute(ctx, tc, tenant)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		return phase, err
+	}
+
+	return r.reconcileSSOIntegration(ctx, tc, tenant, secret)
+}
+
+// reconcileTenantSecret creates the secret of the tenant, with the generated
+// password of its admin user and the secret of its SSO client
+func (r *ReconcileThreeScaleTenant) reconcileTenantSecret(ctx context.Context, tenant *integreatlyv1alpha1.ThreeScaleTenant) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tenant.Name + "-tenant",
+			Namespace: tenant.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		for _, key := range []string{adminPasswordKey, clientSecretKey} {
+			if len(secret.Data[key]) > 0 {
+				continue
+			}
+			value, err := generateSecret(32)
+			if err != nil {
+				return err
+			}
+			secret.Data[key] = []byte(value)
+		}
+		secret.Data[adminUserKey] = []byte(tenant.Spec.Username)
+		return controllerutil.SetControllerReference(tenant, secret, r.scheme)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create or update tenant secret %s: %w", secret.Name, err)
+	}
+
+	tenant.Status.SecretRef = secret.Name
+	return secret, nil
+}
+
+// reconcileTenantAccount creates the tenant in 3scale, and activates its
+// admin user. The access token of the tenant is only returned when the tenant
+// is created, so it's stored in the tenant secret with the tenant ID before
+// anything else. An existing tenant with the same organization name that
+// wasn't created by the operator is not adopted
+func (r *ReconcileThreeScaleTenant) reconcileTenantAccount(ctx context.Context, tc *tenantContext, tenant *integreatlyv1alpha1.ThreeScaleTenant, secret *corev1.Secret) (integreatlyv1alpha1.StatusPhase, error) {
+	if tenant.Status.TenantID == 0 {
+		tenantID, err := r.getOrCreateTenant(ctx, tc, tenant, secret)
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+
+		tenant.Status.TenantID = tenantID
+		tenant.Status.AdminURL = string(secret.Data[adminURLKey])
+		if err := r.client.Status().Update(ctx, tenant); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to store ID of tenant %s: %w", tenant.Spec.OrganizationName, err)
+		}
+	}
+	if len(secret.Data[adminAccessTokenKey]) == 0 {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("tenant %s exists in 3scale without an access token in secret %s, add the %s of a tenant admin token to it", tenant.Spec.OrganizationName, secret.Name, adminAccessTokenKey)
+	}
+
+	account, err := tc.tsClient.GetTenant(tenant.Status.TenantID, tc.masterToken)
+	if threescale.IsNotFoundError(err) {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("tenant %s with ID %d no longer exists in 3scale", tenant.Spec.OrganizationName, tenant.Status.TenantID)
+	} else if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get tenant %s: %w", tenant.Spec.OrganizationName, err)
+	}
+	tenant.Status.AdminURL = "https://" + account.AdminDomain
+
+	users, err := tc.tsClient.GetTenantUsers(tenant.Status.TenantID, tc.masterToken)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get users of tenant %s: %w", tenant.Spec.OrganizationName, err)
+	}
+	for _, user := range users.Users {
+		if !strings.EqualFold(user.UserDetails.Username, tenant.Spec.Username) || user.UserDetails.State != "pending" {
+			continue
+		}
+		res, err := tc.tsClient.ActivateTenantUser(tenant.Status.TenantID, user.UserDetails.Id, tc.masterToken)
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to activate admin user of tenant %s: %w", tenant.Spec.OrganizationName, err)
+		}
+		if res.StatusCode != http.StatusOK {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to activate admin user of tenant %s: %s", tenant.Spec.OrganizationName, res.Status)
+		}
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+// getOrCreateTenant returns the ID of the tenant stored in its secret, or
+// creates the tenant. Only the tenants created by the operator are managed,
+// and deleted with their ThreeScaleTenant, so it fails if a tenant with the
+// same organization name already exists in 3scale
+func (r *ReconcileThreeScaleTenant) getOrCreateTenant(ctx context.Context, tc *tenantContext, tenant *integreatlyv1alpha1.ThreeScaleTenant, secret *corev1.Secret) (int, error) {
+	if id := string(secret.Data[tenantIDKey]); id != "" {
+		tenantID, err := strconv.Atoi(id)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ID %q of tenant %s in secret %s: %w", id, tenant.Spec.OrganizationName, secret.Name, err)
+		}
+		return tenantID, nil
+	}
+
+	existing, err := tc.tsClient.GetTenantByOrgName(tenant.Spec.OrganizationName, tc.masterToken)
+	if err == nil {
+		return 0, fmt.Errorf("tenant %s already exists in 3scale with ID %d and was not created by the operator, use another organization name", tenant.Spec.OrganizationName, existing.Id)
+	} else if !threescale.IsNotFoundError(err) {
+		return 0, fmt.Errorf("failed to find tenant %s: %w", tenant.Spec.OrganizationName, err)
+	}
+
+	created, err := tc.tsClient.CreateTenant(tenant.Spec.OrganizationName, tenant.Spec.Username, tenant.Spec.Email, string(secret.Data[adminPasswordKey]), tc.masterToken)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create tenant %s: %w", tenant.Spec.OrganizationName, err)
+	}
+	logrus.Infof("Created 3scale tenant %s with ID %d", tenant.Spec.OrganizationName, created.Signup.Account.Id)
+
+	secret.Data[tenantIDKey] = []byte(strconv.Itoa(created.Signup.Account.Id))
+	secret.Data[adminAccessTokenKey] = []byte(created.Signup.AccessToken.Value)
+	secret.Data[adminURLKey] = []byte("https://" + created.Signup.Account.AdminDomain)
+	if err := r.client.Update(ctx, secret); err != nil {
+		return 0, fmt.Errorf("failed to store access token of tenant %s: %w", tenant.Spec.OrganizationName, err)
+	}
+	return created.Signup.Account.Id, nil
+}
+
+// reconcileAdminRoute creates the route of the tenant admin portal, unless
+// zync already created one for its domain
+func (r *ReconcileThreeScaleTenant) reconcileAdminRoute(ctx context.Context, tc *tenantContext, tenant *integreatlyv1alpha1.ThreeScaleTenant) (integreatlyv1alpha1.StatusPhase, error) {
+	adminDomain := strings.TrimPrefix(tenant.Status.AdminURL, "https://")
+	route := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tenantObjectName(tenant),
+			Namespace: tc.tsConfig.GetNamespace(),
+		},
+	}
+
+	routes := &routev1.RouteList{}
+	if err := r.client.List(ctx, routes, k8sclient.InNamespace(route.Namespace)); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to list 3scale routes: %w", err)
+	}
+	for _, existing := range routes.Items {
+		if existing.Spec.Host == adminDomain && existing.Name != route.Name {
+			return integreatlyv1alpha1.PhaseCompleted, nil
+		}
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.client, route, func() error {
+		if route.Labels == nil {
+			route.Labels = map[string]string{}
+		}
+		route.Labels[tenantLabel] = tenant.Name
+		route.Spec.Host = adminDomain
+		route.Spec.To = routev1.RouteTargetReference{
+			Kind: "Service",
+			Name: systemProviderServiceName,
+		}
+		route.Spec.Port = &routev1.RoutePort{TargetPort: intstr.FromString("http")}
+		route.Spec.TLS = &routev1.TLSConfig{
+			Termination:                   routev1.TLSTerminationEdge,
+			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+		}
+		return nil
+	})
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create or update admin route of tenant %s: %w", tenant.Spec.OrganizationName, err)
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+// reconcileSSOIntegration creates a client for the tenant admin portal in the
+// user SSO realm, and adds it as authentication provider of the tenant
+func (r *ReconcileThreeScaleTenant) reconcileSSOIntegration(ctx context.Context, tc *tenantContext, tenant *integreatlyv1alpha1.ThreeScaleTenant, secret *corev1.Secret) (integreatlyv1alpha1.StatusPhase, error) {
+	clientID := tenantObjectName(tenant)
+	clientSecret := string(secret.Data[clientSecretKey])
+
+	kcClient := &keycloak.KeycloakClient{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clientID,
+			Namespace: tc.ssoConfig.GetNamespace(),
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.client, kcClient, func() error {
+		if kcClient.Labels == nil {
+			kcClient.Labels = map[string]string{}
+		}
+		kcClient.Labels[tenantLabel] = tenant.Name
+		kcClient.Spec = threescale.GetKeycloakClientSpec(clientID, clientSecret, tenant.Status.AdminURL, rhssouser.GetInstanceLabels())
+		return nil
+	})
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create or update keycloak client of tenant %s: %w", tenant.Spec.OrganizationName, err)
+	}
+
+	accessToken := string(secret.Data[adminAccessTokenKey])
+	tenantClient := r.tsClientFactory(tc.installation, strings.TrimPrefix(tenant.Status.AdminURL, "https://"))
+	authProviders, err := tenantClient.GetAuthenticationProviders(accessToken)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get authentication providers of tenant %s: %w", tenant.Spec.OrganizationName, err)
+	}
+	for _, ap := range authProviders.AuthProviders {
+		if ap.ProviderDetails.Name == ssoIntegrationName {
+			return integreatlyv1alpha1.PhaseCompleted, nil
+		}
+	}
+
+	res, err := tenantClient.AddAuthenticationProvider(map[string]string{
+		"kind":                              "keycloak",
+		"name":                              ssoIntegrationName,
+		"client_id":                         clientID,
+		"client_secret":                     clientSecret,
+		"site":                              tc.ssoConfig.GetHost() + "/auth/realms/" + tc.ssoConfig.GetRealm(),
+		"skip_ssl_certificate_verification": "true",
+		"published":                         "true",
+	}, accessToken)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to add authentication provider to tenant %s: %w", tenant.Spec.OrganizationName, err)
+	}
+	if res.StatusCode != http.StatusCreated {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to add authentication provider to tenant %s: %s", tenant.Spec.OrganizationName, res.Status)
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+// deleteTenant deletes the tenant from 3scale, and the objects created for
+// it in the product namespaces. The tenant secret is garbage collected
+func (r *ReconcileThreeScaleTenant) deleteTenant(ctx context.Context, tc *tenantContext, tenant *integreatlyv1alpha1.ThreeScaleTenant) error {
+	if tenant.Status.TenantID != 0 {
+		res, err := tc.tsClient.DeleteTenant(tenant.Status.TenantID, tc.masterToken)
+		if err != nil {
+			return fmt.Errorf("failed to delete tenant %s: %w", tenant.Spec.OrganizationName, err)
+		}
+		if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+			return fmt.Errorf("failed to delete tenant %s: %s", tenant.Spec.OrganizationName, res.Status)
+		}
+		logrus.Infof("Deleted 3scale tenant %s with ID %d", tenant.Spec.OrganizationName, tenant.Status.TenantID)
+	}
+
+	objects := []runtime.Object{
+		&keycloak.KeycloakClient{
+			ObjectMeta: metav1.ObjectMeta{Name: tenantObjectName(tenant), Namespace: tc.ssoConfig.GetNamespace()},
+		},
+		&routev1.Route{
+			ObjectMeta: metav1.ObjectMeta{Name: tenantObjectName(tenant), Namespace: tc.tsConfig.GetNamespace()},
+		},
+	}
+	for _, obj := range objects {
+		if err := r.client.Delete(ctx, obj); err != nil && !k8serr.IsNotFound(err) {
+			return fmt.Errorf("failed to delete objects of tenant %s: %w", tenant.Spec.OrganizationName, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *ReconcileThreeScaleTenant) updateStatus(ctx context.Context, tenant *integreatlyv1alpha1.ThreeScaleTenant, phase integreatlyv1alpha1.StatusPhase, message string) error {
+	tenant.Status.Phase = phase
+	tenant.Status.Message = message
+	if err := r.client.Status().Update(ctx, tenant); err != nil && !k8serr.IsNotFound(err) {
+		return fmt.Errorf("failed to update status of 3scale tenant %s: %w", tenant.Name, err)
+	}
+	return nil
+}
+
+// GetTenantAccess returns the admin domain and the access token of the 3scale
+// tenant of a ThreeScaleTenant. The tenant must have been created
+func GetTenantAccess(ctx context.Context, serverClient k8sclient.Client, tenant *integreatlyv1alpha1.ThreeScaleTenant) (string, string, error) {
+	if tenant.Status.TenantID == 0 || tenant.Status.SecretRef == "" {
+		return "", "", fmt.Errorf("3scale tenant %s has not been created", tenant.Name)
+	}
+	secret := &corev1.Secret{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: tenant.Status.SecretRef, Namespace: tenant.Namespace}, secret); err != nil {
+		return "", "", fmt.Errorf("failed to get secret of 3scale tenant %s: %w", tenant.Name, err)
+	}
+	accessToken := string(secret.Data[adminAccessTokenKey])
+	if accessToken == "" {
+		return "", "", fmt.Errorf("secret of 3scale tenant %s has no access token", tenant.Name)
+	}
+	return strings.TrimPrefix(tenant.Status.AdminURL, "https://"), accessToken, nil
+}
+
+// tenantObjectName is the name of the keycloak client and the route of the
+// tenant, which is also the ID of the keycloak client
+func tenantObjectName(tenant *integreatlyv1alpha1.ThreeScaleTenant) string {
+	return "3scale-tenant-" + tenant.Name
+}
+
+// generateSecret returns a base64 encoded securely random string of n bytes
+func generateSecret(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,358 @@
+package threescaletenant
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/products/threescale"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	operatorNamespace = "redhat-rhmi-operator"
+	tsNamespace       = "redhat-rhmi-3scale"
+	ssoNamespace      = "redhat-rhmi-user-sso"
+	tenantAdminDomain = "payments-admin.apps.example.com"
+)
+
+func getBuildScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	if err := integreatlyv1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := keycloak.SchemeBuilder.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := routev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}
+
+func getTestObjects() []runtime.Object {
+	return []runtime.Object{
+		&integreatlyv1alpha1.RHMI{
+			ObjectMeta: metav1.ObjectMeta{Name: "rhmi", Namespace: operatorNamespace},
+			Spec:       integreatlyv1alpha1.RHMISpec{RoutingSubdomain: "apps.example.com"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultInstallationConfigMapName, Namespace: operatorNamespace},
+			Data: map[string]string{
+				"3scale":    "NAMESPACE: " + tsNamespace + "\nHOST: https://3scale-admin.apps.example.com\n",
+				"rhssouser": "NAMESPACE: " + ssoNamespace + "\nHOST: https://keycloak.apps.example.com\nREALM: master\n",
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: systemSeedSecretName, Namespace: tsNamespace},
+			Data:       map[string][]byte{"MASTER_ACCESS_TOKEN": []byte("master-token")},
+		},
+	}
+}
+
+func getTenant() *integreatlyv1alpha1.ThreeScaleTenant {
+	return &integreatlyv1alpha1.ThreeScaleTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: operatorNamespace},
+		Spec: integreatlyv1alpha1.ThreeScaleTenantSpec{
+			OrganizationName: "payments",
+			Username:         "payments-admin",
+			Email:            "payments-admin@example.com",
+		},
+	}
+}
+
+func TestReconcileThreeScaleTenant(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "payments", Namespace: operatorNamespace}}
+
+	t.Run("Tenant is created and integrated with the user SSO", func(t *testing.T) {
+		client := fake.NewFakeClientWithScheme(scheme, append(getTestObjects(), getTenant())...)
+		userActivated := false
+		authProviders := map[string]map[string]string{}
+		tsClient := &threescale.ThreeScaleInterfaceMock{
+			GetTenantByOrgNameFunc: func(orgName, masterAccessToken string) (*threescale.TenantAccount, error) {
+				return nil, threescale.NewNotFoundError("tenant not found")
+			},
+			CreateTenantFunc: func(orgName, username, email, password, masterAccessToken string) (*threescale.Tenant, error) {
+				if masterAccessToken != "master-token" || password == "" {
+					t.Errorf("unexpected master token %q or empty password", masterAccessToken)
+				}
+				return &threescale.Tenant{Signup: threescale.Signup{
+					Account:     threescale.TenantAccount{Id: 5, AdminDomain: tenantAdminDomain},
+					AccessToken: threescale.AccessToken{Value: "tenant-token"},
+				}}, nil
+			},
+			GetTenantFunc: func(tenantID int, masterAccessToken string) (*threescale.TenantAccount, error) {
+				return &threescale.TenantAccount{Id: tenantID, AdminDomain: tenantAdminDomain, State: "approved"}, nil
+			},
+			GetTenantUsersFunc: func(tenantID int, masterAccessToken string) (*threescale.Users, error) {
+				state := "pending"
+				if userActivated {
+					state = "active"
+				}
+				return &threescale.Users{Users: []*threescale.User{
+					{UserDetails: threescale.UserDetails{Id: 7, Username: "payments-admin", State: state, Role: "admin"}},
+				}}, nil
+			},
+			ActivateTenantUserFunc: func(tenantID int, userID int, masterAccessToken string) (*http.Response, error) {
+				userActivated = true
+				return &http.Response{StatusCode: http.StatusOK}, nil
+			},
+			GetAuthenticationProvidersFunc: func(accessToken string) (*threescale.AuthProviders, error) {
+				providers := &threescale.AuthProviders{}
+				for name := range authProviders {
+					providers.AuthProviders = append(providers.AuthProviders, &threescale.AuthProvider{
+						ProviderDetails: threescale.AuthProviderDetails{Name: name},
+					})
+				}
+				return providers, nil
+			},
+			AddAuthenticationProviderFunc: func(data map[string]string, accessToken string) (*http.Response, error) {
+				if accessToken != "tenant-token" {
+					t.Errorf("expected authentication provider to be added with the tenant token, got %q", accessToken)
+				}
+				authProviders[data["name"]] = data
+				return &http.Response{StatusCode: http.StatusCreated}, nil
+			},
+		}
+		var adminDomains []string
+		r := &ReconcileThreeScaleTenant{
+			client: client,
+			scheme: scheme,
+			tsClientFactory: func(installation *integreatlyv1alpha1.RHMI, adminDomain string) threescale.ThreeScaleInterface {
+				adminDomains = append(adminDomains, adminDomain)
+				return tsClient
+			},
+		}
+
+		for i := 0; i < 2; i++ {
+			if _, err := r.Reconcile(request); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if len(tsClient.CreateTenantCalls()) != 1 {
+			t.Errorf("expected tenant to be created once, got %d calls", len(tsClient.CreateTenantCalls()))
+		}
+		if !userActivated || len(tsClient.ActivateTenantUserCalls()) != 1 {
+			t.Errorf("expected admin user to be activated once")
+		}
+		if !resources.Contains(adminDomains, tenantAdminDomain) {
+			t.Errorf("expected calls to the tenant admin portal, got clients for %v", adminDomains)
+		}
+
+		tenant := &integreatlyv1alpha1.ThreeScaleTenant{}
+		if err := client.Get(context.TODO(), request.NamespacedName, tenant); err != nil {
+			t.Fatal(err)
+		}
+		if tenant.Status.Phase != integreatlyv1alpha1.PhaseCompleted || tenant.Status.TenantID != 5 || tenant.Status.AdminURL != "https://"+tenantAdminDomain {
+			t.Errorf("unexpected status %+v", tenant.Status)
+		}
+		if !resources.Contains(tenant.Finalizers, tenantFinalizer) {
+			t.Errorf("expected finalizer to be added")
+		}
+
+		secret := &corev1.Secret{}
+		if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: tenant.Status.SecretRef, Namespace: operatorNamespace}, secret); err != nil {
+			t.Fatalf("expected tenant secret: %v", err)
+		}
+		if string(secret.Data[adminAccessTokenKey]) != "tenant-token" || string(secret.Data[adminURLKey]) != "https://"+tenantAdminDomain || string(secret.Data[tenantIDKey]) != "5" {
+			t.Errorf("unexpected tenant secret data %v", secret.Data)
+		}
+
+		route := &routev1.Route{}
+		if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: "3scale-tenant-payments", Namespace: tsNamespace}, route); err != nil {
+			t.Fatalf("expected admin route: %v", err)
+		}
+		if route.Spec.Host != tenantAdminDomain {
+			t.Errorf("unexpected admin route host %s", route.Spec.Host)
+		}
+
+		kcClient := &keycloak.KeycloakClient{}
+		if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: "3scale-tenant-payments", Namespace: ssoNamespace}, kcClient); err != nil {
+			t.Fatalf("expected keycloak client: %v", err)
+		}
+		if kcClient.Spec.Client.Secret != string(secret.Data[clientSecretKey]) {
+			t.Errorf("expected keycloak client secret to match the tenant secret")
+		}
+		provider, ok := authProviders[ssoIntegrationName]
+		if !ok {
+			t.Fatalf("expected authentication provider %s to be added", ssoIntegrationName)
+		}
+		if provider["site"] != "https://keycloak.apps.example.com/auth/realms/master" || provider["client_id"] != kcClient.Spec.Client.ClientID {
+			t.Errorf("unexpected authentication provider %v", provider)
+		}
+	})
+
+	t.Run("Existing tenant is not created again", func(t *testing.T) {
+		scenarios := []struct {
+			Name             string
+			SecretData       map[string][]byte
+			ExpectedTenantID int
+			ExpectedPhase    integreatlyv1alpha1.StatusPhase
+		}{
+			{
+				Name: "tenant stored in the secret",
+				SecretData: map[string][]byte{
+					tenantIDKey:         []byte("5"),
+					adminAccessTokenKey: []byte("tenant-token"),
+					adminURLKey:         []byte("https://" + tenantAdminDomain),
+				},
+				ExpectedTenantID: 5,
+				ExpectedPhase:    integreatlyv1alpha1.PhaseCompleted,
+			},
+			{
+				Name:          "tenant not created by the operator is not adopted",
+				ExpectedPhase: integreatlyv1alpha1.PhaseFailed,
+			},
+		}
+
+		for _, scenario := range scenarios {
+			t.Run(scenario.Name, func(t *testing.T) {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "payments-tenant", Namespace: operatorNamespace},
+					Data:       scenario.SecretData,
+				}
+				client := fake.NewFakeClientWithScheme(scheme, append(getTestObjects(), getTenant(), secret)...)
+				tsClient := &threescale.ThreeScaleInterfaceMock{
+					GetTenantByOrgNameFunc: func(orgName, masterAccessToken string) (*threescale.TenantAccount, error) {
+						return &threescale.TenantAccount{Id: 5, OrgName: orgName, AdminDomain: tenantAdminDomain}, nil
+					},
+					GetTenantFunc: func(tenantID int, masterAccessToken string) (*threescale.TenantAccount, error) {
+						return &threescale.TenantAccount{Id: tenantID, AdminDomain: tenantAdminDomain, State: "approved"}, nil
+					},
+					GetTenantUsersFunc: func(tenantID int, masterAccessToken string) (*threescale.Users, error) {
+						return &threescale.Users{}, nil
+					},
+					GetAuthenticationProvidersFunc: func(accessToken string) (*threescale.AuthProviders, error) {
+						return &threescale.AuthProviders{AuthProviders: []*threescale.AuthProvider{
+							{ProviderDetails: threescale.AuthProviderDetails{Name: ssoIntegrationName}},
+						}}, nil
+					},
+				}
+				r := &ReconcileThreeScaleTenant{
+					client: client,
+					scheme: scheme,
+					tsClientFactory: func(installation *integreatlyv1alpha1.RHMI, adminDomain string) threescale.ThreeScaleInterface {
+						return tsClient
+					},
+				}
+
+				if _, err := r.Reconcile(request); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if len(tsClient.CreateTenantCalls()) != 0 {
+					t.Errorf("expected the existing tenant not to be created again")
+				}
+				tenant := &integreatlyv1alpha1.ThreeScaleTenant{}
+				if err := client.Get(context.TODO(), request.NamespacedName, tenant); err != nil {
+					t.Fatal(err)
+				}
+				if tenant.Status.TenantID != scenario.ExpectedTenantID || tenant.Status.Phase != scenario.ExpectedPhase {
+					t.Errorf("expected tenant %d in phase %s, got %+v", scenario.ExpectedTenantID, scenario.ExpectedPhase, tenant.Status)
+				}
+			})
+		}
+	})
+
+	t.Run("Tenant is deleted with the ThreeScaleTenant", func(t *testing.T) {
+		tenant := getTenant()
+		now := metav1.Now()
+		tenant.DeletionTimestamp = &now
+		tenant.Finalizers = []string{tenantFinalizer}
+		tenant.Status.TenantID = 5
+		objects := append(getTestObjects(), tenant,
+			&routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: "3scale-tenant-payments", Namespace: tsNamespace}},
+			&keycloak.KeycloakClient{ObjectMeta: metav1.ObjectMeta{Name: "3scale-tenant-payments", Namespace: ssoNamespace}},
+		)
+		client := fake.NewFakeClientWithScheme(scheme, objects...)
+		tsClient := &threescale.ThreeScaleInterfaceMock{
+			DeleteTenantFunc: func(tenantID int, masterAccessToken string) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK}, nil
+			},
+		}
+		r := &ReconcileThreeScaleTenant{
+			client: client,
+			scheme: scheme,
+			tsClientFactory: func(installation *integreatlyv1alpha1.RHMI, adminDomain string) threescale.ThreeScaleInterface {
+				return tsClient
+			},
+		}
+
+		if _, err := r.Reconcile(request); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if calls := tsClient.DeleteTenantCalls(); len(calls) != 1 || calls[0].TenantID != 5 {
+			t.Errorf("expected tenant 5 to be deleted, got calls %v", calls)
+		}
+		for _, obj := range []runtime.Object{&routev1.Route{}, &keycloak.KeycloakClient{}} {
+			ns := tsNamespace
+			if _, ok := obj.(*keycloak.KeycloakClient); ok {
+				ns = ssoNamespace
+			}
+			err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: "3scale-tenant-payments", Namespace: ns}, obj)
+			if !k8serr.IsNotFound(err) {
+				t.Errorf("expected %T of the tenant to be deleted, got error %v", obj, err)
+			}
+		}
+		updated := &integreatlyv1alpha1.ThreeScaleTenant{}
+		if err := client.Get(context.TODO(), request.NamespacedName, updated); err != nil {
+			t.Fatal(err)
+		}
+		if resources.Contains(updated.Finalizers, tenantFinalizer) {
+			t.Errorf("expected finalizer to be removed")
+		}
+	})
+	t.Run("Finalizer is removed without a working installation", func(t *testing.T) {
+		tenant := getTenant()
+		now := metav1.Now()
+		tenant.DeletionTimestamp = &now
+		tenant.Finalizers = []string{tenantFinalizer}
+		tenant.Status.TenantID = 5
+		objects := getTestObjects()
+		objects[1] = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultInstallationConfigMapName, Namespace: operatorNamespace},
+			Data:       map[string]string{"3scale": "NAMESPACE: ["},
+		}
+		client := fake.NewFakeClientWithScheme(scheme, append(objects, tenant)...)
+		tsClient := &threescale.ThreeScaleInterfaceMock{}
+		r := &ReconcileThreeScaleTenant{
+			client: client,
+			scheme: scheme,
+			tsClientFactory: func(installation *integreatlyv1alpha1.RHMI, adminDomain string) threescale.ThreeScaleInterface {
+				return tsClient
+			},
+		}
+
+		if _, err := r.Reconcile(request); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		updated := &integreatlyv1alpha1.ThreeScaleTenant{}
+		if err := client.Get(context.TODO(), request.NamespacedName, updated); err != nil {
+			t.Fatal(err)
+		}
+		if resources.Contains(updated.Finalizers, tenantFinalizer) {
+			t.Errorf("expected finalizer to be removed")
+		}
+	})
+}
@@ -90,6 +90,64 @@ var (
 			"stage",
 		},
 	)
+
+	BYODatastoreAvailable = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhmi_byo_datastore_available",
+			Help: "Whether the connection details of a BYO datastore are configured",
+		},
+		[]string{
+			"resourceID",
+			"productName",
+			"type",
+		},
+	)
+
+	DatastoreHealth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhmi_datastore_health",
+			Help: "Whether the last probe of a datastore could connect and authenticate",
+		},
+		[]string{
+			"datastore",
+			"namespace",
+			"type",
+		},
+	)
+
+	BYODatastoreConnection = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhmi_byo_datastore_connection",
+			Help: "Whether the last connection to a BYO datastore succeeded",
+		},
+		[]string{
+			"resourceID",
+			"productName",
+			"type",
+		},
+	)
+
+	APIUsageDaily = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_api_usage_daily_requests",
+			Help: "API requests of the current day, in UTC",
+		},
+		[]string{
+			"tier",
+			"account",
+		},
+	)
+
+	APIUsageMonthly = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_api_usage_monthly_requests",
+			Help: "API requests of the current month, in UTC",
+		},
+		[]string{
+			"tier",
+			"account",
+		},
+	)
 )
 
 // SetRHMIInfo exposes rhmi info metrics with labels from the installation CR
@@ -126,3 +184,28 @@ func SetRhmiVersions(stage string, version string, toVersion string, firstInstal
 	RHOAMVersion.Reset()
 	RHOAMVersion.WithLabelValues(stage, version, toVersion).Set(float64(firstInstallTimestamp))
 }
+
+// APIUsage is the number of API requests of an account in a period
+type APIUsage struct {
+	Period   string
+	Tier     string
+	Account  string
+	Requests uint64
+}
+
+// SetAPIUsage exposes the API usage of the current day and month
+func SetAPIUsage(day, month string, daily, monthly []APIUsage) {
+	APIUsageDaily.Reset()
+	for _, usage := range daily {
+		if usage.Period == day {
+			APIUsageDaily.WithLabelValues(usage.Tier, usage.Account).Set(float64(usage.Requests))
+		}
+	}
+
+	APIUsageMonthly.Reset()
+	for _, usage := range monthly {
+		if usage.Period == month {
+			APIUsageMonthly.WithLabelValues(usage.Tier, usage.Account).Set(float64(usage.Requests))
+		}
+	}
+}
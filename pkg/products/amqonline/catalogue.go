@@ -0,0 +1,244 @@
+package amqonline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	enmassev1beta1 "github.com/integr8ly/integreatly-operator/pkg/apis-products/enmasse/v1beta1"
+	enmassev1beta2 "github.com/integr8ly/integreatly-operator/pkg/apis-products/enmasse/v1beta2"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	addressPlansKey         = "addressPlans"
+	addressSpacePlansKey    = "addressSpacePlans"
+	brokeredInfraConfigsKey = "brokeredInfraConfigs"
+	standardInfraConfigsKey = "standardInfraConfigs"
+
+	// customResourceLabelKey labels the plans and infra configs declared in
+	// the overrides config map that aren't part of the defaults, so they can
+	// be deleted once they're removed from it
+	customResourceLabelKey = "integreatly.org/amq-online-custom"
+)
+
+var (
+	addressTypes      = []string{"anycast", "multicast", "queue", "topic", "subscription"}
+	addressSpaceTypes = []string{"brokered", "standard"}
+	addressFullPolicy = []string{"FAIL", "BLOCK", "PAGE", "DROP"}
+)
+
+// catalogue is the set of plans and infra configs reconciled in the AMQ
+// Online namespace
+type catalogue struct {
+	addressPlans         []*enmassev1beta2.AddressPlan
+	addressSpacePlans    []*enmassev1beta2.AddressSpacePlan
+	brokeredInfraConfigs []*enmassev1beta1.BrokeredInfraConfig
+	standardInfraConfigs []*enmassev1beta1.StandardInfraConfig
+}
+
+// getCatalogue returns the default plans and infra configs merged with the
+// overrides config map of the installation
+func (r *Reconciler) getCatalogue(ctx context.Context, serverClient k8sclient.Client, ns string) (*catalogue, error) {
+	c := &catalogue{
+		addressPlans:         GetDefaultAddressPlans(ns),
+		addressSpacePlans:    GetDefaultAddressSpacePlans(ns),
+		brokeredInfraConfigs: GetDefaultBrokeredInfraConfigs(ns),
+		standardInfraConfigs: GetDefaultStandardInfraConfigs(ns),
+	}
+	if r.inst.Spec.AMQOnline == nil || r.inst.Spec.AMQOnline.ConfigMapRef == "" {
+		return c, nil
+	}
+
+	configMapName := r.inst.Spec.AMQOnline.ConfigMapRef
+	configMap := &corev1.ConfigMap{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: configMapName, Namespace: r.inst.Namespace}, configMap); err != nil {
+		return nil, fmt.Errorf("failed to get AMQ Online overrides config map %s: %w", configMapName, err)
+	}
+
+	err := mergeOverrides(configMap, addressPlansKey, func(name string) interface{} {
+		for _, plan := range c.addressPlans {
+			if plan.Name == name {
+				return plan
+			}
+		}
+		plan := &enmassev1beta2.AddressPlan{ObjectMeta: customObjectMeta(name)}
+		c.addressPlans = append(c.addressPlans, plan)
+		return plan
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = mergeOverrides(configMap, addressSpacePlansKey, func(name string) interface{} {
+		for _, plan := range c.addressSpacePlans {
+			if plan.Name == name {
+				return plan
+			}
+		}
+		plan := &enmassev1beta2.AddressSpacePlan{ObjectMeta: customObjectMeta(name)}
+		c.addressSpacePlans = append(c.addressSpacePlans, plan)
+		return plan
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = mergeOverrides(configMap, brokeredInfraConfigsKey, func(name string) interface{} {
+		for _, cfg := range c.brokeredInfraConfigs {
+			if cfg.Name == name {
+				return cfg
+			}
+		}
+		cfg := &enmassev1beta1.BrokeredInfraConfig{ObjectMeta: customObjectMeta(name)}
+		c.brokeredInfraConfigs = append(c.brokeredInfraConfigs, cfg)
+		return cfg
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = mergeOverrides(configMap, standardInfraConfigsKey, func(name string) interface{} {
+		for _, cfg := range c.standardInfraConfigs {
+			if cfg.Name == name {
+				return cfg
+			}
+		}
+		cfg := &enmassev1beta1.StandardInfraConfig{ObjectMeta: customObjectMeta(name)}
+		c.standardInfraConfigs = append(c.standardInfraConfigs, cfg)
+		return cfg
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the plans and infra configs always live in the AMQ Online namespace
+	for _, plan := range c.addressPlans {
+		plan.Namespace = ns
+	}
+	for _, plan := range c.addressSpacePlans {
+		plan.Namespace = ns
+	}
+	for _, cfg := range c.brokeredInfraConfigs {
+		cfg.Namespace = ns
+	}
+	for _, cfg := range c.standardInfraConfigs {
+		cfg.Namespace = ns
+	}
+
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("invalid AMQ Online overrides config map %s: %w", configMapName, err)
+	}
+	return c, nil
+}
+
+func customObjectMeta(name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:   name,
+		Labels: map[string]string{customResourceLabelKey: "true"},
+	}
+}
+
+// mergeOverrides decodes the YAML list of resources under key. Each resource
+// is decoded into the object returned by find for its name, which is either
+// the default with the same name, so that the override only needs the fields
+// it changes, or a new object
+func mergeOverrides(configMap *corev1.ConfigMap, key string, find func(name string) interface{}) error {
+	data, ok := configMap.Data[key]
+	if !ok {
+		return nil
+	}
+
+	jsonData, err := yaml.YAMLToJSON([]byte(data))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s of config map %s: %w", key, configMap.Name, err)
+	}
+	var overrides []json.RawMessage
+	if err := json.Unmarshal(jsonData, &overrides); err != nil {
+		return fmt.Errorf("%s of config map %s must be a list: %w", key, configMap.Name, err)
+	}
+
+	for i, override := range overrides {
+		meta := struct {
+			Metadata metav1.ObjectMeta `json:"metadata"`
+		}{}
+		if err := json.Unmarshal(override, &meta); err != nil || meta.Metadata.Name == "" {
+			return fmt.Errorf("%s[%d] of config map %s has no name", key, i, configMap.Name)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(override))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(find(meta.Metadata.Name)); err != nil {
+			return fmt.Errorf("invalid %s %s of config map %s: %w", key, meta.Metadata.Name, configMap.Name, err)
+		}
+	}
+	return nil
+}
+
+// validate checks the values the enmasse types don't constrain, and that the
+// plans only refer to plans and infra configs of the catalogue
+func (c *catalogue) validate() error {
+	addressPlans := map[string]bool{}
+	for _, plan := range c.addressPlans {
+		if !resources.Contains(addressTypes, plan.Spec.AddressType) {
+			return fmt.Errorf("address plan %s has invalid address type %q", plan.Name, plan.Spec.AddressType)
+		}
+		if plan.Spec.Resources.Broker < 0 || plan.Spec.Resources.Router < 0 {
+			return fmt.Errorf("address plan %s has negative resources", plan.Name)
+		}
+		addressPlans[plan.Name] = true
+	}
+
+	infraConfigs := map[string]map[string]bool{"brokered": {}, "standard": {}}
+	for _, cfg := range c.brokeredInfraConfigs {
+		if err := validateInfraConfig(cfg.Name, cfg.Spec.Broker, cfg.Spec.Admin.Resources); err != nil {
+			return err
+		}
+		infraConfigs["brokered"][cfg.Name] = true
+	}
+	for _, cfg := range c.standardInfraConfigs {
+		if err := validateInfraConfig(cfg.Name, cfg.Spec.Broker, cfg.Spec.Admin.Resources, cfg.Spec.Router.Resources); err != nil {
+			return err
+		}
+		infraConfigs["standard"][cfg.Name] = true
+	}
+
+	for _, plan := range c.addressSpacePlans {
+		if !resources.Contains(addressSpaceTypes, plan.Spec.AddressSpaceType) {
+			return fmt.Errorf("address space plan %s has invalid address space type %q", plan.Name, plan.Spec.AddressSpaceType)
+		}
+		if !infraConfigs[plan.Spec.AddressSpaceType][plan.Spec.InfraConfigRef] {
+			return fmt.Errorf("address space plan %s refers to unknown %s infra config %q", plan.Name, plan.Spec.AddressSpaceType, plan.Spec.InfraConfigRef)
+		}
+		limits := plan.Spec.ResourceLimits
+		if limits.Broker < 0 || limits.Router < 0 || limits.Aggregate < 0 {
+			return fmt.Errorf("address space plan %s has negative resource limits", plan.Name)
+		}
+		for _, addressPlan := range plan.Spec.AddressPlans {
+			if !addressPlans[addressPlan] {
+				return fmt.Errorf("address space plan %s refers to unknown address plan %q", plan.Name, addressPlan)
+			}
+		}
+	}
+	return nil
+}
+
+func validateInfraConfig(name string, broker enmassev1beta1.InfraConfigBroker, resourceList ...enmassev1beta1.InfraConfigResources) error {
+	if broker.AddressFullPolicy != "" && !resources.Contains(addressFullPolicy, broker.AddressFullPolicy) {
+		return fmt.Errorf("infra config %s has invalid address full policy %q", name, broker.AddressFullPolicy)
+	}
+	for _, res := range append(resourceList, broker.Resources) {
+		for _, quantity := range []string{res.Memory, res.Storage} {
+			if quantity == "" {
+				continue
+			}
+			if _, err := resource.ParseQuantity(quantity); err != nil {
+				return fmt.Errorf("infra config %s has invalid quantity %q: %w", name, quantity, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package amqonline
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	enmasse "github.com/integr8ly/integreatly-operator/pkg/apis-products/enmasse/enmasse/v1beta1"
+	enmassev1beta2 "github.com/integr8ly/integreatly-operator/pkg/apis-products/enmasse/v1beta2"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	moqclient "github.com/integr8ly/integreatly-operator/pkg/client"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const overridesConfigMapName = "amq-online-overrides"
+
+func TestReconciler_getCatalogue(t *testing.T) {
+	scenarios := []struct {
+		Name          string
+		Overrides     map[string]string
+		ExpectedError string
+		Verify        func(t *testing.T, c *catalogue)
+	}{
+		{
+			Name: "Test overrides are merged with the defaults",
+			Overrides: map[string]string{
+				brokeredInfraConfigsKey: `
+- metadata:
+    name: default
+  spec:
+    broker:
+      resources:
+        memory: 2Gi
+`,
+				addressPlansKey: `
+- metadata:
+    name: brokered-queue-large
+  spec:
+    displayName: Large Brokered Queue
+    addressType: queue
+    resources:
+      broker: 0.5
+`,
+				addressSpacePlansKey: `
+- metadata:
+    name: brokered-large
+  spec:
+    displayName: Large Broker
+    infraConfigRef: default
+    addressSpaceType: brokered
+    resourceLimits:
+      broker: 4.0
+    addressPlans:
+    - brokered-queue-large
+`,
+			},
+			Verify: func(t *testing.T, c *catalogue) {
+				broker := c.brokeredInfraConfigs[0].Spec.Broker
+				if broker.Resources.Memory != "2Gi" || broker.Resources.Storage != "5Gi" || broker.AddressFullPolicy != "FAIL" {
+					t.Errorf("expected the broker memory to be overridden and the other settings kept, got %+v", broker)
+				}
+				if len(c.addressPlans) != len(GetDefaultAddressPlans(defaultNamespace))+1 {
+					t.Fatalf("expected the custom address plan to be added, got %d address plans", len(c.addressPlans))
+				}
+				plan := c.addressSpacePlans[len(c.addressSpacePlans)-1]
+				if plan.Name != "brokered-large" || plan.Namespace != defaultNamespace || plan.Labels[customResourceLabelKey] != "true" {
+					t.Errorf("expected the custom address space plan to be labelled in the AMQ Online namespace, got %+v", plan.ObjectMeta)
+				}
+			},
+		},
+		{
+			Name: "Test error on unknown fields",
+			Overrides: map[string]string{
+				standardInfraConfigsKey: `
+- metadata:
+    name: default
+  spec:
+    broker:
+      memory: 2Gi
+`,
+			},
+			ExpectedError: "unknown field",
+		},
+		{
+			Name: "Test error on unknown address plan",
+			Overrides: map[string]string{
+				addressSpacePlansKey: `
+- metadata:
+    name: brokered-large
+  spec:
+    infraConfigRef: default
+    addressSpaceType: brokered
+    addressPlans:
+    - missing
+`,
+			},
+			ExpectedError: `unknown address plan "missing"`,
+		},
+		{
+			Name: "Test error on invalid quantity",
+			Overrides: map[string]string{
+				standardInfraConfigsKey: `
+- metadata:
+    name: default
+  spec:
+    router:
+      resources:
+        memory: lots
+`,
+			},
+			ExpectedError: `invalid quantity "lots"`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.Name, func(t *testing.T) {
+			installation := basicInstallation()
+			installation.Spec.AMQOnline = &integreatlyv1alpha1.AMQOnlineSpec{ConfigMapRef: overridesConfigMapName}
+			client := fake.NewFakeClientWithScheme(buildScheme(), &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: overridesConfigMapName, Namespace: installation.Namespace},
+				Data:       s.Overrides,
+			})
+			r, err := NewReconciler(basicConfigMock(), installation, nil, setupRecorder())
+			if err != nil {
+				t.Fatalf("could not create reconciler %v", err)
+			}
+
+			c, err := r.getCatalogue(context.TODO(), client, defaultNamespace)
+			if s.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), s.ExpectedError) {
+					t.Fatalf("expected error containing %q, got %v", s.ExpectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			s.Verify(t, c)
+		})
+	}
+}
+
+func TestReconcile_deleteStaleAddressSpacePlans(t *testing.T) {
+	customPlan := func(name string) *enmassev1beta2.AddressSpacePlan {
+		return &enmassev1beta2.AddressSpacePlan{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: defaultNamespace,
+			Labels:    map[string]string{customResourceLabelKey: "true"},
+		}}
+	}
+	client := fake.NewFakeClientWithScheme(buildScheme(),
+		customPlan("custom-used"),
+		customPlan("custom-unused"),
+		&enmasse.AddressSpace{
+			ObjectMeta: metav1.ObjectMeta{Name: "messaging", Namespace: "user-project"},
+			Spec:       enmasse.AddressSpaceSpec{Plan: "custom-used"},
+		},
+	)
+	r, err := NewReconciler(basicConfigMock(), basicInstallation(), nil, setupRecorder())
+	if err != nil {
+		t.Fatalf("could not create reconciler %v", err)
+	}
+
+	phase, err := r.reconcileAddressSpacePlans(context.TODO(), client, GetDefaultAddressSpacePlans(defaultNamespace))
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcileAddressSpacePlans() = %s, %v", phase, err)
+	}
+
+	if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: "custom-used", Namespace: defaultNamespace}, &enmassev1beta2.AddressSpacePlan{}); err != nil {
+		t.Errorf("expected the plan in use to be kept: %v", err)
+	}
+	err = client.Get(context.TODO(), k8sclient.ObjectKey{Name: "custom-unused", Namespace: defaultNamespace}, &enmassev1beta2.AddressSpacePlan{})
+	if !k8serr.IsNotFound(err) {
+		t.Errorf("expected the unused plan to be deleted, got %v", err)
+	}
+	defaultPlan := GetDefaultAddressSpacePlans(defaultNamespace)[0]
+	if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: defaultPlan.Name, Namespace: defaultNamespace}, &enmassev1beta2.AddressSpacePlan{}); err != nil {
+		t.Errorf("expected the default plan to be created: %v", err)
+	}
+}
+
+func TestReconcile_reconcileAddressSpacePlansWithoutStalePlans(t *testing.T) {
+	client := moqclient.NewSigsClientMoqWithScheme(buildScheme())
+	sigsClient := client.GetSigsClient()
+	client.ListFunc = func(ctx context.Context, list runtime.Object, opts ...k8sclient.ListOption) error {
+		if _, ok := list.(*enmasse.AddressSpaceList); ok {
+			t.Errorf("expected the address spaces not to be listed without stale plans")
+		}
+		return sigsClient.List(ctx, list, opts...)
+	}
+	r, err := NewReconciler(basicConfigMock(), basicInstallation(), nil, setupRecorder())
+	if err != nil {
+		t.Fatalf("could not create reconciler %v", err)
+	}
+
+	phase, err := r.reconcileAddressSpacePlans(context.TODO(), client, GetDefaultAddressSpacePlans(defaultNamespace))
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcileAddressSpacePlans() = %s, %v", phase, err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 	monitoringv1alpha1 "github.com/integr8ly/application-monitoring-operator/pkg/apis/applicationmonitoring/v1alpha1"
 	enmasseadminv1beta1 "github.com/integr8ly/integreatly-operator/pkg/apis-products/enmasse/admin/v1beta1"
+	enmasse "github.com/integr8ly/integreatly-operator/pkg/apis-products/enmasse/enmasse/v1beta1"
 	enmassev1beta1 "github.com/integr8ly/integreatly-operator/pkg/apis-products/enmasse/v1beta1"
 	enmassev1beta2 "github.com/integr8ly/integreatly-operator/pkg/apis-products/enmasse/v1beta2"
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
@@ -31,6 +32,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
@@ -156,19 +158,25 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
-	phase, err = r.reconcileInfraConfigs(ctx, serverClient, GetDefaultBrokeredInfraConfigs(ns), GetDefaultStandardInfraConfigs(ns))
+	plans, err := r.getCatalogue(ctx, serverClient, ns)
+	if err != nil {
+		events.HandleError(r.recorder, installation, integreatlyv1alpha1.PhaseFailed, "Failed to read plans and infra configs overrides", err)
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
+	phase, err = r.reconcileInfraConfigs(ctx, serverClient, plans.brokeredInfraConfigs, plans.standardInfraConfigs)
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.recorder, installation, phase, "Failed to reconcile broker configs", err)
 		return phase, err
 	}
 
-	phase, err = r.reconcileAddressPlans(ctx, serverClient, GetDefaultAddressPlans(ns))
+	phase, err = r.reconcileAddressPlans(ctx, serverClient, plans.addressPlans)
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.recorder, installation, phase, "Failed to reconcile address plans", err)
 		return phase, err
 	}
 
-	phase, err = r.reconcileAddressSpacePlans(ctx, serverClient, GetDefaultAddressSpacePlans(ns))
+	phase, err = r.reconcileAddressSpacePlans(ctx, serverClient, plans.addressSpacePlans)
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.recorder, installation, phase, "Failed to reconcile address space plans", err)
 		return phase, err
@@ -403,12 +411,16 @@ func (r *Reconciler) reconcileStandardAuthenticationService(ctx context.Context,
 }
 
 func (r *Reconciler) reconcileInfraConfigs(ctx context.Context, serverClient k8sclient.Client, brokeredCfgs []*enmassev1beta1.BrokeredInfraConfig, stdCfgs []*enmassev1beta1.StandardInfraConfig) (integreatlyv1alpha1.StatusPhase, error) {
-	r.logger.Info("reconciling default infra configs")
+	r.logger.Info("reconciling infra configs")
 
+	brokeredNames := map[string]bool{}
 	for _, bic := range brokeredCfgs {
-
+		desired := bic.DeepCopy()
+		brokeredNames[bic.Name] = true
 		_, err := controllerutil.CreateOrUpdate(ctx, serverClient, bic, func() error {
 			bic.Namespace = r.Config.GetNamespace()
+			bic.Spec = desired.Spec
+			setCustomResourceLabel(bic, desired)
 			owner.AddIntegreatlyOwnerAnnotations(bic, r.inst)
 			return nil
 		})
@@ -416,10 +428,15 @@ func (r *Reconciler) reconcileInfraConfigs(ctx context.Context, serverClient k8s
 			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not create brokered infra config %v: %w", bic, err)
 		}
 	}
+	standardNames := map[string]bool{}
 	for _, sic := range stdCfgs {
 		sic.Namespace = r.Config.GetNamespace()
+		desired := sic.DeepCopy()
+		standardNames[sic.Name] = true
 		_, err := controllerutil.CreateOrUpdate(ctx, serverClient, sic, func() error {
 			sic.Namespace = r.Config.GetNamespace()
+			sic.Spec = desired.Spec
+			setCustomResourceLabel(sic, desired)
 			owner.AddIntegreatlyOwnerAnnotations(sic, r.inst)
 			return nil
 		})
@@ -427,14 +444,67 @@ func (r *Reconciler) reconcileInfraConfigs(ctx context.Context, serverClient k8s
 			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not create standard infra config %v: %w", sic, err)
 		}
 	}
+
+	staleBrokeredCfgs := &enmassev1beta1.BrokeredInfraConfigList{}
+	if err := serverClient.List(ctx, staleBrokeredCfgs, customResourceListOpts(r.Config.GetNamespace())...); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not list brokered infra configs: %w", err)
+	}
+	staleStdCfgs := &enmassev1beta1.StandardInfraConfigList{}
+	if err := serverClient.List(ctx, staleStdCfgs, customResourceListOpts(r.Config.GetNamespace())...); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not list standard infra configs: %w", err)
+	}
+	var stale []runtime.Object
+	for i := range staleBrokeredCfgs.Items {
+		if !brokeredNames[staleBrokeredCfgs.Items[i].Name] {
+			stale = append(stale, &staleBrokeredCfgs.Items[i])
+		}
+	}
+	for i := range staleStdCfgs.Items {
+		if !standardNames[staleStdCfgs.Items[i].Name] {
+			stale = append(stale, &staleStdCfgs.Items[i])
+		}
+	}
+	if len(stale) == 0 {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	// infra configs are in use while an address space plan refers to them
+	addrSpacePlans := &enmassev1beta2.AddressSpacePlanList{}
+	if err := serverClient.List(ctx, addrSpacePlans, k8sclient.InNamespace(r.Config.GetNamespace())); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not list address space plans: %w", err)
+	}
+	inUse := map[string]map[string]bool{"brokered": {}, "standard": {}}
+	for _, asp := range addrSpacePlans.Items {
+		if inUse[asp.Spec.AddressSpaceType] != nil {
+			inUse[asp.Spec.AddressSpaceType][asp.Spec.InfraConfigRef] = true
+		}
+	}
+
+	for _, obj := range stale {
+		var used bool
+		switch cfg := obj.(type) {
+		case *enmassev1beta1.BrokeredInfraConfig:
+			used = inUse["brokered"][cfg.Name]
+		case *enmassev1beta1.StandardInfraConfig:
+			used = inUse["standard"][cfg.Name]
+		}
+		if err := r.deleteStaleResource(ctx, serverClient, obj, used); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+	}
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
 func (r *Reconciler) reconcileAddressPlans(ctx context.Context, serverClient k8sclient.Client, addrPlans []*enmassev1beta2.AddressPlan) (integreatlyv1alpha1.StatusPhase, error) {
-	r.logger.Info("reconciling default address plans")
+	r.logger.Info("reconciling address plans")
 
+	names := map[string]bool{}
 	for _, ap := range addrPlans {
+		desired := ap.DeepCopy()
+		names[ap.Name] = true
 		_, err := controllerutil.CreateOrUpdate(ctx, serverClient, ap, func() error {
+			ap.Spec = desired.Spec
+			setCustomResourceLabel(ap, desired)
 			owner.AddIntegreatlyOwnerAnnotations(ap, r.inst)
 			return nil
 		})
@@ -442,14 +512,59 @@ func (r *Reconciler) reconcileAddressPlans(ctx context.Context, serverClient k8s
 			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not create address plan %v: %w", ap, err)
 		}
 	}
+
+	stalePlans := &enmassev1beta2.AddressPlanList{}
+	if err := serverClient.List(ctx, stalePlans, customResourceListOpts(r.Config.GetNamespace())...); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not list address plans: %w", err)
+	}
+	var stale []*enmassev1beta2.AddressPlan
+	for i := range stalePlans.Items {
+		if !names[stalePlans.Items[i].Name] {
+			stale = append(stale, &stalePlans.Items[i])
+		}
+	}
+	if len(stale) == 0 {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	// address plans are in use while an address space plan lists them or an
+	// address uses them
+	inUse := map[string]bool{}
+	addrSpacePlans := &enmassev1beta2.AddressSpacePlanList{}
+	if err := serverClient.List(ctx, addrSpacePlans, k8sclient.InNamespace(r.Config.GetNamespace())); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not list address space plans: %w", err)
+	}
+	for _, asp := range addrSpacePlans.Items {
+		for _, plan := range asp.Spec.AddressPlans {
+			inUse[plan] = true
+		}
+	}
+	addresses := &enmasse.AddressList{}
+	if err := serverClient.List(ctx, addresses); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not list addresses: %w", err)
+	}
+	for _, address := range addresses.Items {
+		inUse[address.Spec.Plan] = true
+	}
+
+	for _, ap := range stale {
+		if err := r.deleteStaleResource(ctx, serverClient, ap, inUse[ap.Name]); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+	}
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
 func (r *Reconciler) reconcileAddressSpacePlans(ctx context.Context, serverClient k8sclient.Client, addrSpacePlans []*enmassev1beta2.AddressSpacePlan) (integreatlyv1alpha1.StatusPhase, error) {
-	r.logger.Info("reconciling default address space plans")
+	r.logger.Info("reconciling address space plans")
 
+	names := map[string]bool{}
 	for _, asp := range addrSpacePlans {
+		desired := asp.DeepCopy()
+		names[asp.Name] = true
 		_, err := controllerutil.CreateOrUpdate(ctx, serverClient, asp, func() error {
+			asp.Spec = desired.Spec
+			setCustomResourceLabel(asp, desired)
 			owner.AddIntegreatlyOwnerAnnotations(asp, r.inst)
 			return nil
 		})
@@ -457,9 +572,81 @@ func (r *Reconciler) reconcileAddressSpacePlans(ctx context.Context, serverClien
 			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not create address space plan %v: %w", asp, err)
 		}
 	}
+
+	stalePlans := &enmassev1beta2.AddressSpacePlanList{}
+	if err := serverClient.List(ctx, stalePlans, customResourceListOpts(r.Config.GetNamespace())...); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not list address space plans: %w", err)
+	}
+	var stale []*enmassev1beta2.AddressSpacePlan
+	for i := range stalePlans.Items {
+		if !names[stalePlans.Items[i].Name] {
+			stale = append(stale, &stalePlans.Items[i])
+		}
+	}
+	if len(stale) == 0 {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	// address space plans are in use while an address space uses them
+	inUse := map[string]bool{}
+	addressSpaces := &enmasse.AddressSpaceList{}
+	if err := serverClient.List(ctx, addressSpaces); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not list address spaces: %w", err)
+	}
+	for _, addressSpace := range addressSpaces.Items {
+		inUse[addressSpace.Spec.Plan] = true
+	}
+
+	for _, asp := range stale {
+		if err := r.deleteStaleResource(ctx, serverClient, asp, inUse[asp.Name]); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+	}
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
+// setCustomResourceLabel copies the label of the additional plans and infra
+// configs from desired to obj
+func setCustomResourceLabel(obj, desired metav1.Object) {
+	labels := obj.GetLabels()
+	if desired.GetLabels()[customResourceLabelKey] == "true" {
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[customResourceLabelKey] = "true"
+	} else {
+		delete(labels, customResourceLabelKey)
+	}
+	obj.SetLabels(labels)
+}
+
+func customResourceListOpts(ns string) []k8sclient.ListOption {
+	return []k8sclient.ListOption{
+		k8sclient.InNamespace(ns),
+		k8sclient.MatchingLabels{customResourceLabelKey: "true"},
+	}
+}
+
+// deleteStaleResource deletes an additional plan or infra config that was
+// removed from the overrides config map. It's kept while it's in use, as
+// deleting it would break the address spaces or addresses using it
+func (r *Reconciler) deleteStaleResource(ctx context.Context, serverClient k8sclient.Client, obj runtime.Object, inUse bool) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		r.logger.Warnf("keeping %T %s removed from the overrides config map, as it's still in use", obj, accessor.GetName())
+		return nil
+	}
+
+	r.logger.Infof("deleting %T %s removed from the overrides config map", obj, accessor.GetName())
+	if err := serverClient.Delete(ctx, obj); err != nil && !k8serr.IsNotFound(err) {
+		return fmt.Errorf("could not delete %T %s: %w", obj, accessor.GetName(), err)
+	}
+	return nil
+}
+
 func (r *Reconciler) reconcileServiceAdmin(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 	r.logger.Info("reconciling service admin role to the dedicated admins group")
 
@@ -3,6 +3,7 @@ package apicurioregistry
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	apicurioregistry "github.com/Apicurio/apicurio-registry-operator/pkg/apis/apicur/v1alpha1"
 	kafkav1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis-products/kafka.strimzi.io/v1alpha1"
@@ -18,6 +19,8 @@ import (
 	"github.com/integr8ly/integreatly-operator/version"
 	appsv1 "github.com/openshift/api/apps/v1"
 	"github.com/sirupsen/logrus"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
@@ -32,6 +35,9 @@ const (
 	amqStreamsTopicPartitions    = 3
 	amqStreamsTopicReplicas      = 3
 	amqStreamsTopicCleanupPolicy = "compact"
+	dataSourceSecretName         = "apicurio-registry-datasource"
+	dataSourcePasswordKey        = "password"
+	dataSourcePasswordEnvVar     = "QUARKUS_DATASOURCE_PASSWORD"
 )
 
 // Reconciler reconciles everything needed to install Apicurio Registry. The resources that it works
@@ -133,7 +139,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
-	phase, err = r.reconcileStorage(ctx, client)
+	phase, err = r.reconcileStorage(ctx, installation, client)
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.recorder, installation, phase, "Failed to reconcile storage", err)
 		return phase, err
@@ -145,6 +151,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
+	phase, err = r.reconcileDataSourcePassword(ctx, installation, client)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		events.HandleError(r.recorder, installation, phase, "Failed to reconcile data source password", err)
+		return phase, err
+	}
+
 	phase, err = r.handleProgressPhase(ctx, client)
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.recorder, installation, phase, "Failed to handle in progress phase", err)
@@ -167,18 +179,63 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
-func (r *Reconciler) reconcileStorage(ctx context.Context, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+func getPersistence(installation *integreatlyv1alpha1.RHMI) integreatlyv1alpha1.RegistryPersistence {
+	if installation.Spec.ApicurioRegistry == nil || installation.Spec.ApicurioRegistry.Persistence == "" {
+		return integreatlyv1alpha1.RegistryPersistenceStreams
+	}
+	return installation.Spec.ApicurioRegistry.Persistence
+}
+
+// getTopicSettings returns the settings of a topic with the defaults applied
+func getTopicSettings(settings *integreatlyv1alpha1.KafkaTopicSettings) integreatlyv1alpha1.KafkaTopicSettings {
+	result := integreatlyv1alpha1.KafkaTopicSettings{
+		Partitions: amqStreamsTopicPartitions,
+		Replicas:   amqStreamsTopicReplicas,
+	}
+	if settings == nil {
+		return result
+	}
+	if settings.Partitions > 0 {
+		result.Partitions = settings.Partitions
+	}
+	if settings.Replicas > 0 {
+		result.Replicas = settings.Replicas
+	}
+	return result
+}
+
+func (r *Reconciler) reconcileStorage(ctx context.Context, installation *integreatlyv1alpha1.RHMI, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	switch persistence := getPersistence(installation); persistence {
+	case integreatlyv1alpha1.RegistryPersistenceStreams:
+		return r.reconcileKafkaTopics(ctx, installation, client)
+	case integreatlyv1alpha1.RegistryPersistenceJPA:
+		return r.reconcilePostgres(ctx, installation, client)
+	case integreatlyv1alpha1.RegistryPersistenceInMemory:
+		r.logger.Warnf("%s artifacts are kept in memory and are lost when it restarts", r.Config.GetProductName())
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	default:
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("unsupported persistence %s", persistence)
+	}
+}
+
+func (r *Reconciler) reconcileKafkaTopics(ctx context.Context, installation *integreatlyv1alpha1.RHMI, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 	amqStreams, err := r.ConfigManager.ReadAMQStreams()
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to read AMQ Streams config: %s", err)
 	}
 
-	err = createKafkaTopic(ctx, client, "storage-topic", amqStreams.GetNamespace())
+	var storageTopic, globalIDTopic *integreatlyv1alpha1.KafkaTopicSettings
+	if spec := installation.Spec.ApicurioRegistry; spec != nil {
+		storageTopic = spec.StorageTopic
+		globalIDTopic = spec.GlobalIDTopic
+	}
+
+	err = createKafkaTopic(ctx, client, "storage-topic", amqStreams.GetNamespace(), getTopicSettings(storageTopic))
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create storage topic: %w", err)
 	}
 
-	err = createKafkaTopic(ctx, client, "global-id-topic", amqStreams.GetNamespace())
+	err = createKafkaTopic(ctx, client, "global-id-topic", amqStreams.GetNamespace(), getTopicSettings(globalIDTopic))
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create global id topic: %w", err)
 	}
@@ -186,7 +243,11 @@ func (r *Reconciler) reconcileStorage(ctx context.Context, client k8sclient.Clie
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
-func createKafkaTopic(ctx context.Context, client k8sclient.Client, name string, namespace string) error {
+// createKafkaTopic creates or updates a topic with the given settings. The
+// partitions of a topic can't be decreased without losing the order of its
+// records, and the topic operator can't change its replicas, so existing
+// topics are only updated when that's safe
+func createKafkaTopic(ctx context.Context, client k8sclient.Client, name string, namespace string, settings integreatlyv1alpha1.KafkaTopicSettings) error {
 	kafkaTopic := &kafkav1alpha1.KafkaTopic{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -198,8 +259,17 @@ func createKafkaTopic(ctx context.Context, client k8sclient.Client, name string,
 	}
 
 	_, err := controllerutil.CreateOrUpdate(ctx, client, kafkaTopic, func() error {
-		kafkaTopic.Spec.Partitions = amqStreamsTopicPartitions
-		kafkaTopic.Spec.Replicas = amqStreamsTopicReplicas
+		if kafkaTopic.Spec.Partitions > int(settings.Partitions) {
+			return fmt.Errorf("cannot decrease the partitions of topic %s from %d to %d", name, kafkaTopic.Spec.Partitions, settings.Partitions)
+		}
+		kafkaTopic.Spec.Partitions = int(settings.Partitions)
+
+		if kafkaTopic.Spec.Replicas == 0 {
+			kafkaTopic.Spec.Replicas = int(settings.Replicas)
+		} else if kafkaTopic.Spec.Replicas != int(settings.Replicas) {
+			logrus.Warnf("keeping the %d replicas of topic %s, the replicas of an existing topic can't be changed", kafkaTopic.Spec.Replicas, name)
+		}
+
 		kafkaTopic.Spec.Config = map[string]string{
 			"cleanup.policy": amqStreamsTopicCleanupPolicy,
 		}
@@ -209,11 +279,141 @@ func createKafkaTopic(ctx context.Context, client k8sclient.Client, name string,
 	return err
 }
 
+// reconcilePostgres provides the database of the jpa persistence
+func (r *Reconciler) reconcilePostgres(ctx context.Context, installation *integreatlyv1alpha1.RHMI, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	datastores := resources.NewDatastoreProvider(installation)
+	postgres, err := datastores.ReconcilePostgres(ctx, client, defaultInstallationNamespace, constants.ApicurioRegistryPostgresPrefix)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile postgres instance for apicurio registry: %w", err)
+	}
+
+	// reconcile postgres alerts
+	phase, err := datastores.ReconcileAlerts(ctx, client, postgres)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile postgres alerts for %s: %w", postgres.ProductName, err)
+	}
+	if phase != integreatlyv1alpha1.PhaseCompleted {
+		return phase, nil
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+// getDataSource returns the connection details of the jpa persistence
+// database. The password isn't part of them, it's copied into a secret in the
+// registry namespace so that it's not stored in plain text in the
+// ApicurioRegistry CR
+func (r *Reconciler) getDataSource(ctx context.Context, installation *integreatlyv1alpha1.RHMI, client k8sclient.Client) (apicurioregistry.ApicurioRegistrySpecConfigurationDataSource, error) {
+	pgName := fmt.Sprintf("%s%s", constants.ApicurioRegistryPostgresPrefix, installation.Name)
+	postgresSec := &corev1.Secret{}
+	if err := client.Get(ctx, k8sclient.ObjectKey{Name: pgName, Namespace: installation.Namespace}, postgresSec); err != nil {
+		return apicurioregistry.ApicurioRegistrySpecConfigurationDataSource{}, fmt.Errorf("failed to get postgres credential secret for apicurio registry: %w", err)
+	}
+
+	dataSourceSec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dataSourceSecretName,
+			Namespace: r.Config.GetNamespace(),
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, client, dataSourceSec, func() error {
+		dataSourceSec.Data = map[string][]byte{
+			dataSourcePasswordKey: postgresSec.Data["password"],
+		}
+		return nil
+	})
+	if err != nil {
+		return apicurioregistry.ApicurioRegistrySpecConfigurationDataSource{}, fmt.Errorf("failed to reconcile data source secret for apicurio registry: %w", err)
+	}
+
+	return apicurioregistry.ApicurioRegistrySpecConfigurationDataSource{
+		Url:      fmt.Sprintf("jdbc:postgresql://%s:%s/%s", postgresSec.Data["host"], postgresSec.Data["port"], postgresSec.Data["database"]),
+		UserName: string(postgresSec.Data["username"]),
+	}, nil
+}
+
+// reconcileDataSourcePassword sets the data source password of the registry
+// deployment from the data source secret. The ApicurioRegistry CR only takes
+// the password in plain text, so it's set on the deployment created by the
+// apicurio registry operator instead
+func (r *Reconciler) reconcileDataSourcePassword(ctx context.Context, installation *integreatlyv1alpha1.RHMI, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	if getPersistence(installation) != integreatlyv1alpha1.RegistryPersistenceJPA {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	cr := &apicurioregistry.ApicurioRegistry{}
+	err := client.Get(ctx, k8sclient.ObjectKey{Name: string(r.Config.GetProductName()), Namespace: r.Config.GetNamespace()}, cr)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get ApicurioRegistry CR: %w", err)
+	}
+	if cr.Status.DeploymentName == "" {
+		r.logger.Info("waiting for the apicurio registry deployment to be created")
+		return integreatlyv1alpha1.PhaseInProgress, nil
+	}
+
+	deployment := &k8sappsv1.Deployment{}
+	err = client.Get(ctx, k8sclient.ObjectKey{Name: cr.Status.DeploymentName, Namespace: r.Config.GetNamespace()}, deployment)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get apicurio registry deployment: %w", err)
+	}
+
+	passwordEnvVar := corev1.EnvVar{
+		Name: dataSourcePasswordEnvVar,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: dataSourceSecretName},
+				Key:                  dataSourcePasswordKey,
+			},
+		},
+	}
+	updated := false
+	for i := range deployment.Spec.Template.Spec.Containers {
+		container := &deployment.Spec.Template.Spec.Containers[i]
+		found := false
+		for j, env := range container.Env {
+			if env.Name != dataSourcePasswordEnvVar {
+				continue
+			}
+			found = true
+			if env.Value != "" || !reflect.DeepEqual(env.ValueFrom, passwordEnvVar.ValueFrom) {
+				container.Env[j] = passwordEnvVar
+				updated = true
+			}
+		}
+		if !found {
+			container.Env = append(container.Env, passwordEnvVar)
+			updated = true
+		}
+	}
+	if !updated {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	if err := client.Update(ctx, deployment); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to set the data source password of the apicurio registry deployment: %w", err)
+	}
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
 // ReconcileCustomResource creates/updates the ApicurioRegistry custom resource
 func (r *Reconciler) reconcileCustomResource(ctx context.Context, installation *integreatlyv1alpha1.RHMI, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
-	amqStreams, err := r.ConfigManager.ReadAMQStreams()
-	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, err
+	persistence := getPersistence(installation)
+
+	streams := apicurioregistry.ApicurioRegistrySpecConfigurationStreams{}
+	dataSource := apicurioregistry.ApicurioRegistrySpecConfigurationDataSource{}
+	switch persistence {
+	case integreatlyv1alpha1.RegistryPersistenceStreams:
+		amqStreams, err := r.ConfigManager.ReadAMQStreams()
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+		streams.ApplicationId = string(r.Config.GetProductName())
+		streams.BootstrapServers = amqStreams.GetHost()
+	case integreatlyv1alpha1.RegistryPersistenceJPA:
+		var err error
+		if dataSource, err = r.getDataSource(ctx, installation, client); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
 	}
 
 	apicurioRegistry := &apicurioregistry.ApicurioRegistry{
@@ -223,10 +423,11 @@ func (r *Reconciler) reconcileCustomResource(ctx context.Context, installation *
 		},
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, client, apicurioRegistry, func() error {
-		apicurioRegistry.Spec.Configuration.Persistence = "streams"
-		apicurioRegistry.Spec.Configuration.Streams.ApplicationId = string(r.Config.GetProductName())
-		apicurioRegistry.Spec.Configuration.Streams.BootstrapServers = amqStreams.GetHost()
+	_, err := controllerutil.CreateOrUpdate(ctx, client, apicurioRegistry, func() error {
+		apicurioRegistry.Spec.Configuration.Persistence = string(persistence)
+		apicurioRegistry.Spec.Configuration.Streams.ApplicationId = streams.ApplicationId
+		apicurioRegistry.Spec.Configuration.Streams.BootstrapServers = streams.BootstrapServers
+		apicurioRegistry.Spec.Configuration.DataSource = dataSource
 
 		if apicurioRegistry.Spec.Deployment.Replicas < replicas {
 			apicurioRegistry.Spec.Deployment.Replicas = replicas
@@ -294,8 +495,24 @@ func (r *Reconciler) reconcileSubscription(ctx context.Context, serverClient k8s
 		ctx,
 		target,
 		[]string{productNamespace},
-		backup.NewNoopBackupExecutor(),
+		preUpgradeBackupExecutor(inst),
 		serverClient,
 		catalogSourceReconciler,
 	)
 }
+
+// preUpgradeBackupExecutor snapshots the database of the jpa persistence
+// before the upgrades. The artifacts of the other persistences aren't
+// stored by the installation
+func preUpgradeBackupExecutor(installation *integreatlyv1alpha1.RHMI) backup.BackupExecutor {
+	if installation.Spec.UseClusterStorage != "false" || resources.IsBYODatastores(installation) ||
+		getPersistence(installation) != integreatlyv1alpha1.RegistryPersistenceJPA {
+		return backup.NewNoopBackupExecutor()
+	}
+
+	return backup.NewAWSBackupExecutor(
+		installation.Namespace,
+		constants.ApicurioRegistryPostgresPrefix+installation.Name,
+		backup.PostgresSnapshotType,
+	)
+}
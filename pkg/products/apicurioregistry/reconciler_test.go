@@ -3,6 +3,7 @@ package apicurioregistry
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 
 	apicurioregistry "github.com/Apicurio/apicurio-registry-operator/pkg/apis/apicur/v1alpha1"
@@ -11,6 +12,7 @@ import (
 	moqclient "github.com/integr8ly/integreatly-operator/pkg/client"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/backup"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
 
 	projectv1 "github.com/openshift/api/project/v1"
@@ -19,6 +21,7 @@ import (
 	operatorsv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	marketplacev1 "github.com/operator-framework/operator-marketplace/pkg/apis/operators/v1"
 
+	k8sappsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -51,6 +54,7 @@ func getBuildScheme() (*runtime.Scheme, error) {
 	err = coreosv1.SchemeBuilder.AddToScheme(scheme)
 	err = kafkav1alpha1.SchemeBuilder.AddToScheme(scheme)
 	err = apicurioregistry.SchemeBuilder.AddToScheme(scheme)
+	err = k8sappsv1.SchemeBuilder.AddToScheme(scheme)
 	projectv1.AddToScheme(scheme)
 	return scheme, err
 }
@@ -367,3 +371,262 @@ func TestReconciler_fullReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestReconciler_reconcileStorage(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existingTopic := func(partitions, replicas int) *kafkav1alpha1.KafkaTopic {
+		return &kafkav1alpha1.KafkaTopic{
+			ObjectMeta: metav1.ObjectMeta{Name: "storage-topic"},
+			Spec: kafkav1alpha1.KafkaTopicSpec{
+				Partitions: partitions,
+				Replicas:   replicas,
+			},
+		}
+	}
+
+	cases := []struct {
+		Name               string
+		Spec               *integreatlyv1alpha1.ApicurioRegistrySpec
+		ExistingTopic      *kafkav1alpha1.KafkaTopic
+		ExpectError        bool
+		ExpectedPartitions int
+		ExpectedReplicas   int
+		ExpectedConfig     map[string]string
+	}{
+		{
+			Name:               "test default topic settings",
+			ExpectedPartitions: amqStreamsTopicPartitions,
+			ExpectedReplicas:   amqStreamsTopicReplicas,
+			ExpectedConfig:     map[string]string{"cleanup.policy": "compact"},
+		},
+		{
+			Name: "test configured topic settings",
+			Spec: &integreatlyv1alpha1.ApicurioRegistrySpec{
+				StorageTopic: &integreatlyv1alpha1.KafkaTopicSettings{Partitions: 6, Replicas: 2},
+			},
+			ExpectedPartitions: 6,
+			ExpectedReplicas:   2,
+			ExpectedConfig:     map[string]string{"cleanup.policy": "compact"},
+		},
+		{
+			Name: "test partitions of an existing topic are increased and its replicas are kept",
+			Spec: &integreatlyv1alpha1.ApicurioRegistrySpec{
+				StorageTopic: &integreatlyv1alpha1.KafkaTopicSettings{Partitions: 6, Replicas: 1},
+			},
+			ExistingTopic:      existingTopic(3, 3),
+			ExpectedPartitions: 6,
+			ExpectedReplicas:   3,
+			ExpectedConfig:     map[string]string{"cleanup.policy": "compact"},
+		},
+		{
+			Name: "test partitions of an existing topic are not decreased",
+			Spec: &integreatlyv1alpha1.ApicurioRegistrySpec{
+				StorageTopic: &integreatlyv1alpha1.KafkaTopicSettings{Partitions: 1},
+			},
+			ExistingTopic:      existingTopic(3, 3),
+			ExpectError:        true,
+			ExpectedPartitions: 3,
+			ExpectedReplicas:   3,
+		},
+		{
+			Name: "test in memory persistence creates no topics",
+			Spec: &integreatlyv1alpha1.ApicurioRegistrySpec{
+				Persistence: integreatlyv1alpha1.RegistryPersistenceInMemory,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			installation := &integreatlyv1alpha1.RHMI{Spec: integreatlyv1alpha1.RHMISpec{ApicurioRegistry: tc.Spec}}
+			var objs []runtime.Object
+			if tc.ExistingTopic != nil {
+				objs = append(objs, tc.ExistingTopic)
+			}
+			serverClient := fakeclient.NewFakeClientWithScheme(scheme, objs...)
+
+			testReconciler, err := NewReconciler(basicConfigMock(), installation, nil, setupRecorder())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			phase, err := testReconciler.reconcileStorage(context.TODO(), installation, serverClient)
+			if tc.ExpectError {
+				if err == nil || phase != integreatlyv1alpha1.PhaseFailed {
+					t.Fatalf("expected failure, got %s, %v", phase, err)
+				}
+			} else if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+				t.Fatalf("reconcileStorage() = %s, %v", phase, err)
+			}
+
+			topic := &kafkav1alpha1.KafkaTopic{}
+			err = serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "storage-topic"}, topic)
+			if tc.ExpectedPartitions == 0 {
+				if err == nil {
+					t.Fatal("expected no storage topic")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if topic.Spec.Partitions != tc.ExpectedPartitions || topic.Spec.Replicas != tc.ExpectedReplicas {
+				t.Errorf("expected %d partitions and %d replicas, got %d and %d", tc.ExpectedPartitions, tc.ExpectedReplicas, topic.Spec.Partitions, topic.Spec.Replicas)
+			}
+			if tc.ExpectedConfig != nil && !reflect.DeepEqual(topic.Spec.Config, tc.ExpectedConfig) {
+				t.Errorf("expected config %v, got %v", tc.ExpectedConfig, topic.Spec.Config)
+			}
+		})
+	}
+}
+
+func TestReconciler_reconcileCustomResourceWithJPA(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{Name: "installation", Namespace: "redhat-rhmi-operator"},
+		Spec: integreatlyv1alpha1.RHMISpec{
+			ApicurioRegistry: &integreatlyv1alpha1.ApicurioRegistrySpec{Persistence: integreatlyv1alpha1.RegistryPersistenceJPA},
+		},
+	}
+	serverClient := fakeclient.NewFakeClientWithScheme(scheme, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "apicurio-registry-postgres-installation", Namespace: "redhat-rhmi-operator"},
+		Data: map[string][]byte{
+			"host":     []byte("postgres.example.com"),
+			"port":     []byte("5432"),
+			"database": []byte("registry"),
+			"username": []byte("user"),
+			"password": []byte("secret"),
+		},
+	})
+
+	testReconciler, err := NewReconciler(basicConfigMock(), installation, nil, setupRecorder())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	phase, err := testReconciler.reconcileCustomResource(context.TODO(), installation, serverClient)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcileCustomResource() = %s, %v", phase, err)
+	}
+
+	cr := &apicurioregistry.ApicurioRegistry{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: string(testReconciler.Config.GetProductName()), Namespace: testReconciler.Config.GetNamespace()}, cr); err != nil {
+		t.Fatal(err)
+	}
+	expected := apicurioregistry.ApicurioRegistrySpecConfigurationDataSource{
+		Url:      "jdbc:postgresql://postgres.example.com:5432/registry",
+		UserName: "user",
+	}
+	if cr.Spec.Configuration.Persistence != "jpa" || cr.Spec.Configuration.DataSource != expected {
+		t.Errorf("unexpected configuration %+v", cr.Spec.Configuration)
+	}
+	if cr.Spec.Configuration.Streams.BootstrapServers != "" {
+		t.Errorf("expected no streams configuration, got %+v", cr.Spec.Configuration.Streams)
+	}
+
+	dataSourceSec := &corev1.Secret{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: dataSourceSecretName, Namespace: testReconciler.Config.GetNamespace()}, dataSourceSec); err != nil {
+		t.Fatal(err)
+	}
+	if string(dataSourceSec.Data[dataSourcePasswordKey]) != "secret" {
+		t.Errorf("expected the data source password in the secret, got %q", dataSourceSec.Data[dataSourcePasswordKey])
+	}
+}
+
+func TestReconciler_reconcileDataSourcePassword(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installation := &integreatlyv1alpha1.RHMI{
+		Spec: integreatlyv1alpha1.RHMISpec{
+			ApicurioRegistry: &integreatlyv1alpha1.ApicurioRegistrySpec{Persistence: integreatlyv1alpha1.RegistryPersistenceJPA},
+		},
+	}
+	cr := newApicurioRegistry(1)
+	cr.Status.DeploymentName = "apicurio-registry-deployment"
+	deployment := &k8sappsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "apicurio-registry-deployment", Namespace: defaultInstallationNamespace},
+		Spec: k8sappsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name: "registry",
+						Env: []corev1.EnvVar{
+							{Name: "QUARKUS_DATASOURCE_URL", Value: "jdbc:postgresql://postgres.example.com:5432/registry"},
+							{Name: dataSourcePasswordEnvVar, Value: ""},
+						},
+					}},
+				},
+			},
+		},
+	}
+	serverClient := fakeclient.NewFakeClientWithScheme(scheme, cr, deployment)
+
+	testReconciler, err := NewReconciler(basicConfigMock(), installation, nil, setupRecorder())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	phase, err := testReconciler.reconcileDataSourcePassword(context.TODO(), installation, serverClient)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcileDataSourcePassword() = %s, %v", phase, err)
+	}
+
+	updated := &k8sappsv1.Deployment{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: deployment.Name, Namespace: deployment.Namespace}, updated); err != nil {
+		t.Fatal(err)
+	}
+	env := updated.Spec.Template.Spec.Containers[0].Env
+	if len(env) != 2 || env[0].Value == "" {
+		t.Fatalf("expected the other env vars to be kept, got %+v", env)
+	}
+	if env[1].Value != "" || env[1].ValueFrom == nil || env[1].ValueFrom.SecretKeyRef == nil ||
+		env[1].ValueFrom.SecretKeyRef.Name != dataSourceSecretName || env[1].ValueFrom.SecretKeyRef.Key != dataSourcePasswordKey {
+		t.Errorf("expected the password to come from the data source secret, got %+v", env[1])
+	}
+}
+
+func TestPreUpgradeBackupExecutor(t *testing.T) {
+	scenarios := []struct {
+		Name        string
+		Persistence integreatlyv1alpha1.RegistryPersistence
+		Expected    backup.BackupExecutor
+	}{
+		{
+			Name:     "test no backup with streams persistence",
+			Expected: &backup.NoopBackupExecutor{},
+		},
+		{
+			Name:        "test postgres snapshot with jpa persistence",
+			Persistence: integreatlyv1alpha1.RegistryPersistenceJPA,
+			Expected:    &backup.AWSBackupExecutor{},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			installation := &integreatlyv1alpha1.RHMI{
+				ObjectMeta: metav1.ObjectMeta{Name: "installation", Namespace: defaultInstallationNamespace},
+				Spec: integreatlyv1alpha1.RHMISpec{
+					UseClusterStorage: "false",
+					ApicurioRegistry:  &integreatlyv1alpha1.ApicurioRegistrySpec{Persistence: scenario.Persistence},
+				},
+			}
+
+			executor := preUpgradeBackupExecutor(installation)
+			if reflect.TypeOf(executor) != reflect.TypeOf(scenario.Expected) {
+				t.Errorf("expected executor %T, got %T", scenario.Expected, executor)
+			}
+		})
+	}
+}
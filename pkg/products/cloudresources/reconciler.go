@@ -349,15 +349,25 @@ func overrideStrategyConfig(resourceType string, tier string, croStrategyConfig
 	return nil
 }
 
-// reconcileCIDRValue sets the CIDR value in the ConfigMap from the addon
-// parameter. If the value has already been set, or if the secret is not found,
-// it does nothing
+// reconcileCIDRValue sets the CIDR value in the ConfigMap from the network
+// configuration of the RHMIConfig or, if not set, from the addon parameter.
+// If the value has already been set, or if the secret is not found, it does
+// nothing. Changes to the RHMIConfig network are applied by its controller
 func (r *Reconciler) reconcileCIDRValue(ctx context.Context, client k8sclient.Client) error {
-	cidrValue, ok, err := addon.GetStringParameter(ctx, client, r.installation.Namespace, "cidr-range")
-	if err != nil {
+	rhmiConfig := &integreatlyv1alpha1.RHMIConfig{}
+	if err := client.Get(ctx, k8sclient.ObjectKey{Name: "rhmi-config", Namespace: r.installation.Namespace}, rhmiConfig); err != nil && !errors.IsNotFound(err) {
 		return err
 	}
 
+	cidrValue, ok := rhmiConfig.Spec.Network.CIDR, rhmiConfig.Spec.Network.CIDR != ""
+	if !ok {
+		var err error
+		cidrValue, ok, err = addon.GetStringParameter(ctx, client, r.installation.Namespace, "cidr-range")
+		if err != nil {
+			return err
+		}
+	}
+
 	//!ok means the param wasn't found so we want to default rather than return
 	//but don't do it until the installation object is more than a minute old in case the secret is slow to create
 	if !ok || cidrValue == "" && r.installation.ObjectMeta.CreationTimestamp.Time.Before(time.Now().Add(-(1*time.Minute))) {
@@ -16,8 +16,7 @@ import (
 
 	chev1 "github.com/eclipse/che-operator/pkg/apis/org/v1"
 
-	crov1alpha1 "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
-	croUtil "github.com/integr8ly/cloud-resource-operator/pkg/client"
+	croTypes "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
 
 	monitoringv1alpha1 "github.com/integr8ly/application-monitoring-operator/pkg/apis/applicationmonitoring/v1alpha1"
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
@@ -141,13 +140,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
-	phase, err = r.reconcileExternalDatasources(ctx, serverClient)
+	postgresSecretRef, phase, err := r.reconcileExternalDatasources(ctx, serverClient)
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.recorder, installation, phase, "Failed to reconcile external data sources", err)
 		return phase, err
 	}
 
-	phase, err = r.reconcileCheCluster(ctx, serverClient)
+	phase, err = r.reconcileCheCluster(ctx, serverClient, postgresSecretRef)
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.recorder, installation, phase, "Failed to reconcile che cluster", err)
 		return phase, err
@@ -186,35 +185,32 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
-func (r *Reconciler) reconcileExternalDatasources(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+// reconcileExternalDatasources reconciles the Postgres database of CodeReady
+// and returns the secret with its connection details once it's ready
+func (r *Reconciler) reconcileExternalDatasources(ctx context.Context, serverClient k8sclient.Client) (*croTypes.SecretRef, integreatlyv1alpha1.StatusPhase, error) {
 	logrus.Infof("Reconciling external datastore")
-	ns := r.installation.Namespace
+	datastores := resources.NewDatastoreProvider(r.installation)
 
-	// setup postgres custom resource
-	postgresName := fmt.Sprintf("%s%s", constants.CodeReadyPostgresPrefix, r.installation.Name)
-	postgres, err := croUtil.ReconcilePostgres(ctx, serverClient, defaultInstallationNamespace, r.installation.Spec.Type, croUtil.TierProduction, postgresName, ns, postgresName, ns, func(cr metav1.Object) error {
-		owner.AddIntegreatlyOwnerAnnotations(cr, r.installation)
-		return nil
-	})
+	// setup postgres
+	postgres, err := datastores.ReconcilePostgres(ctx, serverClient, defaultInstallationNamespace, constants.CodeReadyPostgresPrefix)
 	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile postgres: %w", err)
+		return nil, integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile postgres: %w", err)
 	}
 
 	// reconcile postgres alerts
-	phase, err := resources.ReconcilePostgresAlerts(ctx, serverClient, r.installation, postgres)
-	productName := postgres.Labels["productName"]
+	phase, err := datastores.ReconcileAlerts(ctx, serverClient, postgres)
 	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile postgres alerts for %s: %w", productName, err)
+		return nil, integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile postgres alerts for %s: %w", postgres.ProductName, err)
 	}
 	if phase != integreatlyv1alpha1.PhaseCompleted {
-		return phase, nil
+		return nil, phase, nil
 	}
 
-	// get the secret created by the cloud resources operator
+	// get the secret containing the postgres connection details
 	croSec := &corev1.Secret{}
-	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: postgres.Status.SecretRef.Name, Namespace: postgres.Status.SecretRef.Namespace}, croSec)
+	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: postgres.SecretRef.Name, Namespace: postgres.SecretRef.Namespace}, croSec)
 	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get postgres credential secret: %w", err)
+		return nil, integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get postgres credential secret: %w", err)
 	}
 
 	// create backup secret
@@ -238,13 +234,13 @@ func (r *Reconciler) reconcileExternalDatasources(ctx context.Context, serverCli
 		return nil
 	})
 	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create or update %s connection secret: %w", r.Config.GetPostgresBackupSecretName(), err)
+		return nil, integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create or update %s connection secret: %w", r.Config.GetPostgresBackupSecretName(), err)
 	}
 
-	return integreatlyv1alpha1.PhaseCompleted, nil
+	return postgres.SecretRef, integreatlyv1alpha1.PhaseCompleted, nil
 }
 
-func (r *Reconciler) reconcileCheCluster(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+func (r *Reconciler) reconcileCheCluster(ctx context.Context, serverClient k8sclient.Client, postgresSecretRef *croTypes.SecretRef) (integreatlyv1alpha1.StatusPhase, error) {
 	kcConfig, err := r.ConfigManager.ReadRHSSO()
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not retrieve keycloak config: %w", err)
@@ -262,7 +258,7 @@ func (r *Reconciler) reconcileCheCluster(ctx context.Context, serverClient k8scl
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not retrieve: %+v: %w", key, err)
 	}
 
-	cheCluster, err := r.createCheCluster(ctx, kcConfig, kcRealm, serverClient)
+	cheCluster, err := r.createCheCluster(ctx, kcConfig, kcRealm, serverClient, postgresSecretRef)
 
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, err
@@ -411,20 +407,17 @@ func (r *Reconciler) reconcileBlackboxTargets(ctx context.Context, client k8scli
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
-func (r *Reconciler) createCheCluster(ctx context.Context, kcCfg *config.RHSSO, kr *keycloak.KeycloakRealm, serverClient k8sclient.Client) (*chev1.CheCluster, error) {
+func (r *Reconciler) createCheCluster(ctx context.Context, kcCfg *config.RHSSO, kr *keycloak.KeycloakRealm, serverClient k8sclient.Client, postgresSecretRef *croTypes.SecretRef) (*chev1.CheCluster, error) {
 	selfSignedCerts := r.installation.Spec.SelfSignedCerts
 
-	// get postgres cloud resource cr
-	pcr := &crov1alpha1.Postgres{}
-	postgresName := fmt.Sprintf("codeready-postgres-%s", r.installation.Name)
-	err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: postgresName, Namespace: r.installation.Namespace}, pcr)
+	settings, err := getWorkspaceSettings(r.installation)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find postgres custom resource: %w", err)
+		return nil, err
 	}
 
-	// get the postgres cloud resources operator cr
+	// get the postgres connection details
 	croSec := &corev1.Secret{}
-	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: pcr.Status.SecretRef.Name, Namespace: pcr.Status.SecretRef.Namespace}, croSec)
+	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: postgresSecretRef.Name, Namespace: postgresSecretRef.Namespace}, croSec)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get postgres credential secret: %w", err)
 	}
@@ -459,9 +452,8 @@ func (r *Reconciler) createCheCluster(ctx context.Context, kcCfg *config.RHSSO,
 		cheCluster.Spec.Auth.IdentityProviderURL = kcCfg.GetHost()
 		cheCluster.Spec.Auth.IdentityProviderRealm = kr.Name
 		cheCluster.Spec.Auth.IdentityProviderClientId = defaultClientName
-		cheCluster.Spec.Storage.PvcStrategy = "per-workspace"
-		cheCluster.Spec.Storage.PvcClaimSize = "1Gi"
 		cheCluster.Spec.Storage.PreCreateSubPaths = true
+		settings.apply(cheCluster)
 
 		owner.AddIntegreatlyOwnerAnnotations(cheCluster, r.installation)
 
@@ -482,7 +474,7 @@ func (r *Reconciler) preUpgradeBackupExecutor() backup.BackupExecutor {
 		"codeready-preupgrade-pv-backup",
 	)
 
-	if r.installation.Spec.UseClusterStorage != "false" {
+	if r.installation.Spec.UseClusterStorage != "false" || resources.IsBYODatastores(r.installation) {
 		return pvBackup
 	}
 
@@ -18,6 +18,7 @@ import (
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
 	moqclient "github.com/integr8ly/integreatly-operator/pkg/client"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/backup"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
 	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
 
@@ -275,7 +276,7 @@ func TestCodeready_reconcileCluster(t *testing.T) {
 				t.Fatalf("unexpected error : '%v', expected: '%v'", err, scenario.ExpectedError)
 			}
 
-			status, err := testReconciler.reconcileCheCluster(context.TODO(), scenario.FakeClient)
+			status, err := testReconciler.reconcileCheCluster(context.TODO(), scenario.FakeClient, pg.Status.SecretRef)
 			if err != nil && err.Error() != scenario.ExpectedError {
 				t.Fatalf("unexpected error: %v, expected: %v", err, scenario.ExpectedError)
 			}
@@ -715,3 +716,38 @@ func TestCodeready_fullReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestCodeready_preUpgradeBackupExecutor(t *testing.T) {
+	scenarios := []struct {
+		Name       string
+		Datastores *integreatlyv1alpha1.DatastoresSpec
+		Expected   interface{}
+	}{
+		{
+			Name:     "test postgres snapshot with cloud resource operator datastores",
+			Expected: &backup.ConcurrentBackupExecutor{},
+		},
+		{
+			Name:       "test only pv backup with existing datastores",
+			Datastores: &integreatlyv1alpha1.DatastoresSpec{Provider: integreatlyv1alpha1.DatastoreProviderBYO},
+			Expected:   &backup.CronJobBackupExecutor{},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			installation := &integreatlyv1alpha1.RHMI{
+				Spec: integreatlyv1alpha1.RHMISpec{UseClusterStorage: "false", Datastores: scenario.Datastores},
+			}
+			r, err := NewReconciler(basicConfigMock(), installation, nil, setupRecorder())
+			if err != nil {
+				t.Fatalf("could not create reconciler %v", err)
+			}
+
+			executor := r.preUpgradeBackupExecutor()
+			if fmt.Sprintf("%T", executor) != fmt.Sprintf("%T", scenario.Expected) {
+				t.Errorf("expected executor %T, got %T", scenario.Expected, executor)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package codeready
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	chev1 "github.com/eclipse/che-operator/pkg/apis/org/v1"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	defaultPVCStrategy  = integreatlyv1alpha1.WorkspacePVCStrategyPerWorkspace
+	defaultPVCClaimSize = "1Gi"
+
+	// the Che server properties set from the workspace settings. They're
+	// removed from the custom properties of the CheCluster when not set,
+	// unless they weren't set by the operator
+	idleTimeoutProperty   = "CHE_LIMITS_WORKSPACE_IDLE_TIMEOUT"
+	memoryLimitProperty   = "CHE_WORKSPACE_DEFAULT__MEMORY__LIMIT__MB"
+	memoryRequestProperty = "CHE_WORKSPACE_DEFAULT__MEMORY__REQUEST__MB"
+
+	// managedPropertiesAnnotation lists the custom properties of the
+	// CheCluster set by the operator, separated by commas
+	managedPropertiesAnnotation = "integreatly.org/managed-che-properties"
+)
+
+var workspacePVCStrategies = []integreatlyv1alpha1.WorkspacePVCStrategy{
+	integreatlyv1alpha1.WorkspacePVCStrategyCommon,
+	integreatlyv1alpha1.WorkspacePVCStrategyPerWorkspace,
+	integreatlyv1alpha1.WorkspacePVCStrategyUnique,
+}
+
+// workspaceSettings are the fields of the CheCluster configured by the
+// CodeReady spec of the installation
+type workspaceSettings struct {
+	pvcStrategy        integreatlyv1alpha1.WorkspacePVCStrategy
+	pvcClaimSize       string
+	pluginRegistryURL  string
+	devfileRegistryURL string
+	properties         map[string]string
+}
+
+// getWorkspaceSettings validates the CodeReady spec of the installation and
+// returns the settings it configures, defaulted when not set
+func getWorkspaceSettings(installation *integreatlyv1alpha1.RHMI) (*workspaceSettings, error) {
+	settings := &workspaceSettings{
+		pvcStrategy:  defaultPVCStrategy,
+		pvcClaimSize: defaultPVCClaimSize,
+		properties: map[string]string{
+			idleTimeoutProperty:   "",
+			memoryLimitProperty:   "",
+			memoryRequestProperty: "",
+		},
+	}
+	spec := installation.Spec.CodeReady
+	if spec == nil {
+		return settings, nil
+	}
+
+	if spec.PVCStrategy != "" {
+		if !containsPVCStrategy(spec.PVCStrategy) {
+			return nil, fmt.Errorf("invalid workspace pvc strategy %q", spec.PVCStrategy)
+		}
+		settings.pvcStrategy = spec.PVCStrategy
+	}
+	if spec.PVCClaimSize != "" {
+		if _, err := resource.ParseQuantity(spec.PVCClaimSize); err != nil {
+			return nil, fmt.Errorf("invalid workspace pvc claim size %q: %w", spec.PVCClaimSize, err)
+		}
+		settings.pvcClaimSize = spec.PVCClaimSize
+	}
+
+	var limit, request int64
+	if spec.WorkspaceMemoryLimit != "" {
+		quantity, err := resource.ParseQuantity(spec.WorkspaceMemoryLimit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace memory limit %q: %w", spec.WorkspaceMemoryLimit, err)
+		}
+		limit = quantity.Value()
+		settings.properties[memoryLimitProperty] = toMebibytes(limit)
+	}
+	if spec.WorkspaceMemoryRequest != "" {
+		quantity, err := resource.ParseQuantity(spec.WorkspaceMemoryRequest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace memory request %q: %w", spec.WorkspaceMemoryRequest, err)
+		}
+		request = quantity.Value()
+		settings.properties[memoryRequestProperty] = toMebibytes(request)
+	}
+	if limit != 0 && request > limit {
+		return nil, fmt.Errorf("workspace memory request %s is greater than the memory limit %s", spec.WorkspaceMemoryRequest, spec.WorkspaceMemoryLimit)
+	}
+
+	if spec.IdleTimeout != "" {
+		timeout, err := time.ParseDuration(spec.IdleTimeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid workspace idle timeout %q", spec.IdleTimeout)
+		}
+		settings.properties[idleTimeoutProperty] = strconv.FormatInt(timeout.Milliseconds(), 10)
+	}
+
+	for _, registryURL := range []string{spec.PluginRegistryURL, spec.DevfileRegistryURL} {
+		if registryURL == "" {
+			continue
+		}
+		if u, err := url.ParseRequestURI(registryURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("invalid registry url %q", registryURL)
+		}
+	}
+	settings.pluginRegistryURL = spec.PluginRegistryURL
+	settings.devfileRegistryURL = spec.DevfileRegistryURL
+
+	return settings, nil
+}
+
+// apply sets the settings on the CheCluster. The custom properties that
+// aren't set from the settings are kept, and the ones that aren't set anymore
+// are only removed when they were set by the operator
+func (s *workspaceSettings) apply(cheCluster *chev1.CheCluster) {
+	cheCluster.Spec.Storage.PvcStrategy = string(s.pvcStrategy)
+	cheCluster.Spec.Storage.PvcClaimSize = s.pvcClaimSize
+
+	// the che operator writes the URLs of the dedicated registries in the
+	// spec, so they're only cleared when switching back from an external one
+	server := &cheCluster.Spec.Server
+	if s.pluginRegistryURL != "" {
+		server.ExternalPluginRegistry = true
+		server.PluginRegistryUrl = s.pluginRegistryURL
+	} else if server.ExternalPluginRegistry {
+		server.ExternalPluginRegistry = false
+		server.PluginRegistryUrl = ""
+	}
+	if s.devfileRegistryURL != "" {
+		server.ExternalDevfileRegistry = true
+		server.DevfileRegistryUrl = s.devfileRegistryURL
+	} else if server.ExternalDevfileRegistry {
+		server.ExternalDevfileRegistry = false
+		server.DevfileRegistryUrl = ""
+	}
+
+	var managed []string
+	if annotation := cheCluster.GetAnnotations()[managedPropertiesAnnotation]; annotation != "" {
+		managed = strings.Split(annotation, ",")
+	}
+	var setProperties []string
+	for key, value := range s.properties {
+		if value == "" {
+			if resources.Contains(managed, key) {
+				delete(server.CustomCheProperties, key)
+			}
+			continue
+		}
+		if server.CustomCheProperties == nil {
+			server.CustomCheProperties = map[string]string{}
+		}
+		server.CustomCheProperties[key] = value
+		setProperties = append(setProperties, key)
+	}
+
+	annotations := cheCluster.GetAnnotations()
+	if len(setProperties) == 0 {
+		delete(annotations, managedPropertiesAnnotation)
+	} else {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		sort.Strings(setProperties)
+		annotations[managedPropertiesAnnotation] = strings.Join(setProperties, ",")
+	}
+	cheCluster.SetAnnotations(annotations)
+}
+
+func containsPVCStrategy(strategy integreatlyv1alpha1.WorkspacePVCStrategy) bool {
+	for _, s := range workspacePVCStrategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// toMebibytes rounds the bytes up to the next mebibyte, as the Che memory
+// properties are in MB
+func toMebibytes(bytes int64) string {
+	return strconv.FormatInt((bytes+1024*1024-1)/(1024*1024), 10)
+}
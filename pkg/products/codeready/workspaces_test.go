@@ -0,0 +1,169 @@
+package codeready
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	chev1 "github.com/eclipse/che-operator/pkg/apis/org/v1"
+	types2 "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetWorkspaceSettings(t *testing.T) {
+	scenarios := []struct {
+		Name          string
+		Spec          *integreatlyv1alpha1.CodeReadySpec
+		ExpectedError string
+		Verify        func(t *testing.T, s *workspaceSettings)
+	}{
+		{
+			Name: "Test defaults when not set",
+			Verify: func(t *testing.T, s *workspaceSettings) {
+				if s.pvcStrategy != defaultPVCStrategy || s.pvcClaimSize != defaultPVCClaimSize {
+					t.Errorf("expected the default storage, got %s %s", s.pvcStrategy, s.pvcClaimSize)
+				}
+				for key, value := range s.properties {
+					if value != "" {
+						t.Errorf("expected property %s not to be set, got %s", key, value)
+					}
+				}
+			},
+		},
+		{
+			Name: "Test settings are converted to the Che properties",
+			Spec: &integreatlyv1alpha1.CodeReadySpec{
+				PVCStrategy:            integreatlyv1alpha1.WorkspacePVCStrategyCommon,
+				PVCClaimSize:           "5Gi",
+				WorkspaceMemoryLimit:   "1Gi",
+				WorkspaceMemoryRequest: "500M",
+				IdleTimeout:            "30m",
+			},
+			Verify: func(t *testing.T, s *workspaceSettings) {
+				if s.pvcStrategy != integreatlyv1alpha1.WorkspacePVCStrategyCommon || s.pvcClaimSize != "5Gi" {
+					t.Errorf("unexpected storage %s %s", s.pvcStrategy, s.pvcClaimSize)
+				}
+				if s.properties[memoryLimitProperty] != "1024" || s.properties[memoryRequestProperty] != "477" || s.properties[idleTimeoutProperty] != "1800000" {
+					t.Errorf("unexpected properties %v", s.properties)
+				}
+			},
+		},
+		{
+			Name:          "Test error on invalid pvc claim size",
+			Spec:          &integreatlyv1alpha1.CodeReadySpec{PVCClaimSize: "large"},
+			ExpectedError: `invalid workspace pvc claim size "large"`,
+		},
+		{
+			Name:          "Test error on memory request greater than the limit",
+			Spec:          &integreatlyv1alpha1.CodeReadySpec{WorkspaceMemoryLimit: "512Mi", WorkspaceMemoryRequest: "1Gi"},
+			ExpectedError: "is greater than the memory limit",
+		},
+		{
+			Name:          "Test error on invalid idle timeout",
+			Spec:          &integreatlyv1alpha1.CodeReadySpec{IdleTimeout: "-5m"},
+			ExpectedError: `invalid workspace idle timeout "-5m"`,
+		},
+		{
+			Name:          "Test error on invalid registry url",
+			Spec:          &integreatlyv1alpha1.CodeReadySpec{DevfileRegistryURL: "registry.example.com"},
+			ExpectedError: `invalid registry url "registry.example.com"`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.Name, func(t *testing.T) {
+			installation := &integreatlyv1alpha1.RHMI{Spec: integreatlyv1alpha1.RHMISpec{CodeReady: s.Spec}}
+			settings, err := getWorkspaceSettings(installation)
+			if s.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), s.ExpectedError) {
+					t.Fatalf("expected error containing %q, got %v", s.ExpectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			s.Verify(t, settings)
+		})
+	}
+}
+
+func TestCodeready_reconcileClusterSettings(t *testing.T) {
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultInstallationNamespace},
+		Spec: integreatlyv1alpha1.RHMISpec{
+			CodeReady: &integreatlyv1alpha1.CodeReadySpec{
+				PVCClaimSize:      "2Gi",
+				IdleTimeout:       "1h",
+				PluginRegistryURL: "https://plugins.example.com/v3",
+			},
+		},
+	}
+	cheCluster := &chev1.CheCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        defaultCheClusterName,
+			Namespace:   defaultInstallationNamespace,
+			Annotations: map[string]string{managedPropertiesAnnotation: memoryLimitProperty},
+		},
+		Spec: chev1.CheClusterSpec{
+			Server: chev1.CheClusterSpecServer{
+				CheLogLevel:             "DEBUG",
+				ExternalDevfileRegistry: true,
+				DevfileRegistryUrl:      "https://devfiles.example.com",
+				CustomCheProperties: map[string]string{
+					"CHE_CUSTOM":          "kept",
+					memoryLimitProperty:   "2048",
+					memoryRequestProperty: "1024",
+					idleTimeoutProperty:   "60000",
+				},
+			},
+		},
+	}
+	serverClient := fakeclient.NewFakeClientWithScheme(buildScheme(), testKeycloakRealm, cheCluster,
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "codeready-postgres-", Namespace: defaultInstallationNamespace}},
+	)
+
+	r, err := NewReconciler(basicConfigMock(), installation, nil, setupRecorder())
+	if err != nil {
+		t.Fatalf("could not create reconciler %v", err)
+	}
+	secretRef := &types2.SecretRef{Name: "codeready-postgres-", Namespace: defaultInstallationNamespace}
+	phase, err := r.reconcileCheCluster(context.TODO(), serverClient, secretRef)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcileCheCluster() = %s, %v", phase, err)
+	}
+
+	cheCluster = &chev1.CheCluster{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: defaultCheClusterName, Namespace: defaultInstallationNamespace}, cheCluster); err != nil {
+		t.Fatal(err)
+	}
+	server := cheCluster.Spec.Server
+	if server.CheLogLevel != "DEBUG" || server.CustomCheProperties["CHE_CUSTOM"] != "kept" {
+		t.Errorf("expected the fields not managed by the operator to be kept, got %+v", server)
+	}
+	if _, ok := server.CustomCheProperties[memoryLimitProperty]; ok {
+		t.Errorf("expected the memory limit property set by the operator to be removed")
+	}
+	if server.CustomCheProperties[memoryRequestProperty] != "1024" {
+		t.Errorf("expected the memory request property not set by the operator to be kept, got %s", server.CustomCheProperties[memoryRequestProperty])
+	}
+	if managed := cheCluster.Annotations[managedPropertiesAnnotation]; managed != idleTimeoutProperty {
+		t.Errorf("expected only the idle timeout property to be recorded as managed, got %q", managed)
+	}
+	if server.CustomCheProperties[idleTimeoutProperty] != "3600000" {
+		t.Errorf("expected the idle timeout property to be updated, got %s", server.CustomCheProperties[idleTimeoutProperty])
+	}
+	if !server.ExternalPluginRegistry || server.PluginRegistryUrl != "https://plugins.example.com/v3" {
+		t.Errorf("expected the external plugin registry to be set, got %t %s", server.ExternalPluginRegistry, server.PluginRegistryUrl)
+	}
+	if server.ExternalDevfileRegistry || server.DevfileRegistryUrl != "" {
+		t.Errorf("expected the external devfile registry to be cleared, got %t %s", server.ExternalDevfileRegistry, server.DevfileRegistryUrl)
+	}
+	if cheCluster.Spec.Storage.PvcClaimSize != "2Gi" || cheCluster.Spec.Storage.PvcStrategy != string(defaultPVCStrategy) {
+		t.Errorf("unexpected storage %+v", cheCluster.Spec.Storage)
+	}
+}
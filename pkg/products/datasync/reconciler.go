@@ -3,8 +3,6 @@ package datasync
 import (
 	"context"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"net/http"
 	"path/filepath"
 	"time"
@@ -12,6 +10,7 @@ import (
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/assets"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/events"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
 	"github.com/integr8ly/integreatly-operator/version"
@@ -28,8 +27,11 @@ import (
 )
 
 const (
-	datasyncNs               = "openshift"
-	templatesBaseURL         = "https://raw.githubusercontent.com/aerogear/datasync-deployment/"
+	datasyncNs = "openshift"
+	// templatesBundle is vendored from
+	// https://github.com/aerogear/datasync-deployment
+	templatesBundle          = "datasync"
+	templatesUpstreamURL     = "https://raw.githubusercontent.com/aerogear/datasync-deployment/"
 	openshiftTemplatesFolder = "/openshift/"
 )
 
@@ -45,7 +47,7 @@ type Reconciler struct {
 	coreClient    kubernetes.Interface
 	Config        *config.DataSync
 	ConfigManager config.ConfigReadWriter
-	httpClient    http.Client
+	templates     *assets.Bundle
 	logger        *logrus.Entry
 	recorder      record.EventRecorder
 	installation  *integreatlyv1alpha1.RHMI
@@ -86,7 +88,7 @@ func NewReconciler(configManager config.ConfigReadWriter, installation *integrea
 		ConfigManager: configManager,
 		Config:        config,
 		logger:        logger,
-		httpClient:    httpClient,
+		templates:     assets.NewBundle(templatesBundle, config.GetTemplatesURL(), templatesUpstreamURL, &httpClient),
 		Reconciler:    resources.NewReconciler(mpm),
 		recorder:      recorder,
 		installation:  installation,
@@ -108,18 +110,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 
 func (r *Reconciler) reconcileTemplates(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 	for _, templateFn := range datasyncTemplates {
-		fileUrl := templatesBaseURL + string(r.Config.GetProductVersion()) + openshiftTemplatesFolder + templateFn
-
-		fileData, err := r.getFileContentFromURL(fileUrl)
+		content, err := r.templates.ReadFile(string(r.Config.GetProductVersion()) + openshiftTemplatesFolder + templateFn)
 		if err != nil {
 			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get file contents of %s: %w", templateFn, err)
 		}
-		defer fileData.Close()
-
-		content, err := ioutil.ReadAll(fileData)
-		if err != nil {
-			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to read contents of %s: %w", templateFn, err)
-		}
 
 		if filepath.Ext(templateFn) == ".yml" || filepath.Ext(templateFn) == ".yaml" {
 			content, err = yaml.ToJSON(content)
@@ -147,15 +141,3 @@ func (r *Reconciler) reconcileTemplates(ctx context.Context, serverClient k8scli
 	}
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
-
-func (r *Reconciler) getFileContentFromURL(url string) (io.ReadCloser, error) {
-	resp, err := r.httpClient.Get(url)
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.StatusCode == http.StatusOK {
-		return resp.Body, nil
-	}
-	return nil, fmt.Errorf("failed to get file content from %s. Status: %d", url, resp.StatusCode)
-}
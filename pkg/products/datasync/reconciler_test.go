@@ -3,11 +3,13 @@ package datasync
 import (
 	"context"
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/integr8ly/integreatly-operator/pkg/apis"
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/assets"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
 
 	templatev1 "github.com/openshift/api/template/v1"
@@ -58,6 +60,12 @@ func TestDataSync(t *testing.T) {
 		t.Fatalf("failed to initialize scheme: %s", err)
 	}
 
+	// read the templates from the test bundle
+	if err := os.Setenv(assets.AssetsDirEnvVarKey, "testtemplates"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv(assets.AssetsDirEnvVarKey)
+
 	datasyncServerAppTemplate := &templatev1.Template{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Template",
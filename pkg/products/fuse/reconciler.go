@@ -7,7 +7,6 @@ import (
 	"github.com/integr8ly/integreatly-operator/pkg/resources/events"
 
 	monitoringv1alpha1 "github.com/integr8ly/application-monitoring-operator/pkg/apis/applicationmonitoring/v1alpha1"
-	croResources "github.com/integr8ly/cloud-resource-operator/pkg/client"
 	v1 "k8s.io/api/core/v1"
 
 	"github.com/integr8ly/integreatly-operator/pkg/resources/constants"
@@ -282,21 +281,16 @@ func (r *Reconciler) reconcileViewFusePerms(ctx context.Context, client k8sclien
 func (r *Reconciler) reconcileCloudResources(ctx context.Context, rhmi *integreatlyv1alpha1.RHMI, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 	r.logger.Info("Reconciling cloud resources for Fuse")
 
-	pgName := fmt.Sprintf("%s%s", constants.FusePostgresPrefix, rhmi.Name)
-	ns := rhmi.Namespace
-	postgres, err := croResources.ReconcilePostgres(ctx, client, defaultInstallationNamespace, rhmi.Spec.Type, croResources.TierProduction, pgName, ns, pgName, ns, func(cr metav1.Object) error {
-		owner.AddIntegreatlyOwnerAnnotations(cr, rhmi)
-		return nil
-	})
+	datastores := resources.NewDatastoreProvider(rhmi)
+	postgres, err := datastores.ReconcilePostgres(ctx, client, defaultInstallationNamespace, constants.FusePostgresPrefix)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile postgres instance for fuse: %w", err)
 	}
 
 	// reconcile postgres alerts
-	phase, err := resources.ReconcilePostgresAlerts(ctx, client, rhmi, postgres)
-	productName := postgres.Labels["productName"]
+	phase, err := datastores.ReconcileAlerts(ctx, client, postgres)
 	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile postgres alerts for %s: %w", productName, err)
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile postgres alerts for %s: %w", postgres.ProductName, err)
 	}
 	if phase != integreatlyv1alpha1.PhaseCompleted {
 		return phase, nil
@@ -454,7 +448,7 @@ func (r *Reconciler) reconcileBlackboxTargets(ctx context.Context, installation
 }
 func preUpgradeBackupExecutor(rhmi *integreatlyv1alpha1.RHMI) backup.BackupExecutor {
 	pgName := fmt.Sprintf("%s%s", constants.FusePostgresPrefix, rhmi.Name)
-	if rhmi.Spec.UseClusterStorage != "false" {
+	if rhmi.Spec.UseClusterStorage != "false" || resources.IsBYODatastores(rhmi) {
 		return backup.NewNoopBackupExecutor()
 	}
 
@@ -4,8 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"net/http"
 	"path/filepath"
 	"reflect"
@@ -19,6 +17,7 @@ import (
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/assets"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/events"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
 	"github.com/integr8ly/integreatly-operator/version"
@@ -35,8 +34,11 @@ import (
 )
 
 const (
-	fuseOnOpenshiftNs      = "openshift"
-	TemplatesBaseURL       = "https://raw.githubusercontent.com/jboss-fuse/application-templates/"
+	fuseOnOpenshiftNs = "openshift"
+	// templatesBundle is vendored from
+	// https://github.com/jboss-fuse/application-templates
+	templatesBundle        = "fuse-on-openshift"
+	templatesUpstreamURL   = "https://raw.githubusercontent.com/jboss-fuse/application-templates/"
 	templatesConfigMapName = "fuse-on-openshift-templates"
 	imageStreamFileName    = "fis-image-streams.json"
 )
@@ -99,11 +101,10 @@ type Reconciler struct {
 	*resources.Reconciler
 	Config        *config.FuseOnOpenshift
 	ConfigManager config.ConfigReadWriter
-	httpClient    *http.Client
+	templates     *assets.Bundle
 	logger        *logrus.Entry
 	recorder      record.EventRecorder
 	installation  *integreatlyv1alpha1.RHMI
-	baseURL       string
 }
 
 func (r *Reconciler) GetPreflightObject(ns string) runtime.Object {
@@ -128,21 +129,20 @@ func NewReconciler(configManager config.ConfigReadWriter, installation *integrea
 	httpClient.Timeout = time.Second * 20
 	httpClient.Transport = &http.Transport{DisableKeepAlives: true, IdleConnTimeout: time.Second * 20}
 
-	url := ""
-	if baseURL == "" {
-		url = TemplatesBaseURL
-	} else {
-		url = baseURL
+	// the templates are read from the operator image unless a URL to
+	// download them from is set
+	url := baseURL
+	if url == "" {
+		url = config.GetTemplatesURL()
 	}
 	return &Reconciler{
 		ConfigManager: configManager,
 		Config:        config,
 		logger:        logger,
-		httpClient:    httpClient,
+		templates:     assets.NewBundle(templatesBundle, url, templatesUpstreamURL, httpClient),
 		Reconciler:    resources.NewReconciler(mpm),
 		recorder:      recorder,
 		installation:  installation,
-		baseURL:       url,
 	}, nil
 }
 
@@ -181,6 +181,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
+// templateFileNames returns the paths of the image streams and templates in
+// the templates bundle
+func templateFileNames() []string {
+	fileNames := []string{
+		corePrefix + imageStreamFileName,
+	}
+
+	for _, qn := range consoleTemplates {
+		fileNames = append(fileNames, corePrefix+qn)
+	}
+
+	for _, qn := range quickstartCoreTemplates {
+		fileNames = append(fileNames, corePrefix+quickStartLocation+qn)
+	}
+
+	for _, qn := range quickstartSpringBoot2Templates {
+		fileNames = append(fileNames, springBoot2Prefix+quickStartLocation+qn)
+	}
+
+	return fileNames
+}
+
 func (r *Reconciler) reconcileConfigMap(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 	logrus.Infoln("Reconciling Fuse on OpenShift templates config map")
 	cfgMap := &corev1.ConfigMap{
@@ -196,36 +218,11 @@ func (r *Reconciler) reconcileConfigMap(ctx context.Context, serverClient k8scli
 		cfgMap.Namespace = r.ConfigManager.GetOperatorNamespace()
 
 		configMapData := make(map[string]string)
-		fileNames := []string{
-			corePrefix + imageStreamFileName,
-		}
-
-		for _, qn := range consoleTemplates {
-			fileNames = append(fileNames, corePrefix+qn)
-		}
-
-		for _, qn := range quickstartCoreTemplates {
-			fileNames = append(fileNames, corePrefix+quickStartLocation+qn)
-		}
-
-		for _, qn := range quickstartSpringBoot2Templates {
-			fileNames = append(fileNames, springBoot2Prefix+quickStartLocation+qn)
-		}
-
-		for _, fn := range fileNames {
-
-			fileURL := r.baseURL + fn
-
-			content, err := r.getFileContentFromURL(fileURL)
+		for _, fn := range templateFileNames() {
+			data, err := r.templates.ReadFile(fn)
 			if err != nil {
 				return fmt.Errorf("failed to get file contents of %s: %w", fn, err)
 			}
-			defer content.Close()
-
-			data, err := ioutil.ReadAll(content)
-			if err != nil {
-				return fmt.Errorf("failed to read contents of %s: %w", fn, err)
-			}
 
 			// Remove the possible prefixes from the key as this is not a valid configmap data key
 			key := strings.TrimPrefix(fn, corePrefix)
@@ -397,19 +394,6 @@ func (r *Reconciler) createResourceIfNotExist(ctx context.Context, serverClient
 	return nil
 }
 
-func (r *Reconciler) getFileContentFromURL(url string) (io.ReadCloser, error) {
-	resp, err := r.httpClient.Get(url)
-
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.StatusCode == http.StatusOK {
-		return resp.Body, nil
-	}
-	return nil, fmt.Errorf("failed to get file content from %s. Status: %d", url, resp.StatusCode)
-}
-
 func (r *Reconciler) getResourcesFromList(listObj map[string]interface{}) []interface{} {
 	items := reflect.ValueOf(listObj["items"])
 
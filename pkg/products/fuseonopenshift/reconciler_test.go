@@ -2,6 +2,7 @@ package fuseonopenshift
 
 import (
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	moqclient "github.com/integr8ly/integreatly-operator/pkg/client"
@@ -11,6 +12,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"strings"
 	"testing"
@@ -18,6 +21,7 @@ import (
 	"github.com/integr8ly/integreatly-operator/pkg/apis"
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/assets"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
 
 	imagev1 "github.com/openshift/api/image/v1"
@@ -83,6 +87,43 @@ func getFakeServer(t *testing.T) *httptest.Server {
 	return server
 }
 
+// setupTemplatesBundle creates a bundle with the checksums of the files
+// served by the fake server, which the downloaded templates are verified
+// against. It returns the function removing the bundle
+func setupTemplatesBundle(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, templatesBundle), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var checksums strings.Builder
+	for _, fn := range templateFileNames() {
+		testFile := "./testtemplates/test_template.json"
+		if strings.Contains(fn, "fis-image-streams") {
+			testFile = "./testtemplates/fis-image-streams.json"
+		}
+		content, err := ioutil.ReadFile(testFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&checksums, "%x  %s\n", sha256.Sum256(content), fn)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, templatesBundle, assets.ChecksumsFileName), []byte(checksums.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Setenv(assets.AssetsDirEnvVarKey, dir); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		os.Unsetenv(assets.AssetsDirEnvVarKey)
+		os.RemoveAll(dir)
+	}
+}
+
 func setupRecorder() record.EventRecorder {
 	return record.NewFakeRecorder(50)
 }
@@ -135,6 +176,7 @@ func TestFuseOnOpenShift(t *testing.T) {
 	}
 
 	server := getFakeServer(t)
+	defer setupTemplatesBundle(t)()
 
 	cases := []FuseOnOpenShiftScenario{
 		{
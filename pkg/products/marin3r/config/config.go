@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/integr8ly/integreatly-operator/pkg/addon"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -14,18 +16,48 @@ const (
 	AlertConfigMapName     = "rate-limit-alerts"
 	ManagedApiServiceSKU   = "RHOAM SERVICE SKU"
 
+	// The addon parameters that select the SKU and the rate limit mode
+	SKUParameter           = "sku"
+	RateLimitModeParameter = "rate-limit-mode"
+
 	DefaultRateLimitUnit     = "minute"
 	DefaultRateLimitRequests = 13860
 
 	DailySoftLimitTier1 = 5_000_000
 	DailySoftLimitTier2 = 10_000_000
 	DailySoftLimitTier3 = 15_000_000
+
+	// The rate limit domains of the envoy sidecars of the 3scale management
+	// APIs, which are also their node IDs
+	SystemRateLimitDomain          = "system-ratelimit"
+	BackendListenerRateLimitDomain = "backend-listener-ratelimit"
+
+	// The descriptors of the requests to the 3scale management APIs
+	SystemProviderDescriptor  = "system-provider"
+	SystemMasterDescriptor    = "system-master"
+	BackendListenerDescriptor = "backend-listener"
 )
 
+// ManagementRateLimitDomains are the rate limit domains of all the 3scale
+// management APIs
+var ManagementRateLimitDomains = []string{SystemRateLimitDomain, BackendListenerRateLimitDomain}
+
 type RateLimitConfig struct {
 	Unit            string   `json:"unit"`
 	RequestsPerUnit uint32   `json:"requests_per_unit"`
 	SoftDailyLimits []uint32 `json:"soft_daily_limits,omitempty"`
+
+	// ThreeScaleProfile is the sizing profile of the 3scale components for
+	// the SKU, unless a profile is set in the RHMI spec
+	ThreeScaleProfile string `json:"threescale_profile,omitempty"`
+}
+
+// ManagementRateLimit is the limit of the requests to a 3scale management
+// API, counted with a generic key descriptor in the domain of its sidecar
+type ManagementRateLimit struct {
+	Domain     string
+	Descriptor string
+	Limit      *integreatlyv1alpha1.RateLimit
 }
 
 type AlertConfig struct {
@@ -39,17 +71,17 @@ type AlertConfig struct {
 // GetRateLimitConfig retrieves the configuration for the rate limit service,
 // taken from a ConfigMap that is expected to exist in the managed api operator
 // namespace.
-func GetRateLimitConfig(ctx context.Context, client k8sclient.Client, namespace string) (*RateLimitConfig, error) {
+func GetRateLimitConfig(ctx context.Context, client k8sclient.Client, installation *integreatlyv1alpha1.RHMI) (*RateLimitConfig, error) {
 	skuConfigs := map[string]*RateLimitConfig{}
 	if err := getFromJSONConfigMap(
 		ctx, client,
-		RateLimitConfigMapName, namespace, "rate_limit",
+		RateLimitConfigMapName, installation.Namespace, "rate_limit",
 		&skuConfigs,
 	); err != nil {
 		return nil, err
 	}
 
-	sku, err := GetSKU(ctx, client)
+	sku, err := GetSKU(ctx, client, installation)
 	if err != nil {
 		return nil, err
 	}
@@ -72,10 +104,75 @@ func GetAlertConfig(ctx context.Context, client k8sclient.Client, namespace stri
 	return alertsConfig, err
 }
 
-func GetSKU(_ context.Context, _ k8sclient.Client) (string, error) {
+// GetSKU returns the SKU whose limits are applied, taken from the addon
+// parameters or the RHMI spec. It defaults to ManagedApiServiceSKU
+func GetSKU(ctx context.Context, client k8sclient.Client, installation *integreatlyv1alpha1.RHMI) (string, error) {
+	sku, ok, err := addon.GetStringParameterByInstallType(
+		ctx, client,
+		integreatlyv1alpha1.InstallationType(installation.Spec.Type),
+		installation.Namespace, SKUParameter,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s addon parameter: %w", SKUParameter, err)
+	}
+	if ok && sku != "" {
+		return sku, nil
+	}
+
+	if installation.Spec.Marin3r != nil && installation.Spec.Marin3r.SKU != "" {
+		return installation.Spec.Marin3r.SKU, nil
+	}
+
 	return ManagedApiServiceSKU, nil
 }
 
+// GetRateLimitMode returns whether the rate limit is enforced or only
+// recorded, taken from the addon parameters or the RHMI spec. It defaults to
+// RateLimitModeEnforce
+func GetRateLimitMode(ctx context.Context, client k8sclient.Client, installation *integreatlyv1alpha1.RHMI) (integreatlyv1alpha1.RateLimitMode, error) {
+	mode, ok, err := addon.GetStringParameterByInstallType(
+		ctx, client,
+		integreatlyv1alpha1.InstallationType(installation.Spec.Type),
+		installation.Namespace, RateLimitModeParameter,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s addon parameter: %w", RateLimitModeParameter, err)
+	}
+	if (!ok || mode == "") && installation.Spec.Marin3r != nil {
+		mode = string(installation.Spec.Marin3r.RateLimitMode)
+	}
+
+	switch integreatlyv1alpha1.RateLimitMode(mode) {
+	case "", integreatlyv1alpha1.RateLimitModeEnforce:
+		return integreatlyv1alpha1.RateLimitModeEnforce, nil
+	case integreatlyv1alpha1.RateLimitModeShadow:
+		return integreatlyv1alpha1.RateLimitModeShadow, nil
+	default:
+		return "", fmt.Errorf("unknown rate limit mode %s", mode)
+	}
+}
+
+// GetManagementRateLimits returns the limits of the 3scale management APIs
+// set in the RHMI spec
+func GetManagementRateLimits(installation *integreatlyv1alpha1.RHMI) []ManagementRateLimit {
+	if installation.Spec.Marin3r == nil || installation.Spec.Marin3r.ManagementRateLimits == nil {
+		return nil
+	}
+	limits := installation.Spec.Marin3r.ManagementRateLimits
+
+	var result []ManagementRateLimit
+	for _, limit := range []ManagementRateLimit{
+		{Domain: SystemRateLimitDomain, Descriptor: SystemProviderDescriptor, Limit: limits.SystemProvider},
+		{Domain: SystemRateLimitDomain, Descriptor: SystemMasterDescriptor, Limit: limits.SystemMaster},
+		{Domain: BackendListenerRateLimitDomain, Descriptor: BackendListenerDescriptor, Limit: limits.BackendListener},
+	} {
+		if limit.Limit != nil {
+			result = append(result, limit)
+		}
+	}
+	return result
+}
+
 func getFromJSONConfigMap(ctx context.Context, client k8sclient.Client, cmName, namespace, configkey string, v interface{}) error {
 	configMap := &corev1.ConfigMap{}
 	if err := client.Get(ctx, k8sclient.ObjectKey{
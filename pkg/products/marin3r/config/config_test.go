@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"testing"
 
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -16,10 +17,43 @@ import (
 func TestGetRateLimitConfig(t *testing.T) {
 	scheme := testScheme()
 
+	tiersConfigMap := &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "sku-limits-managed-api-service",
+			Namespace: "redhat-test-operator",
+		},
+		Data: map[string]string{
+			"rate_limit": `
+			{
+				"RHOAM SERVICE SKU": {
+					"unit": "minute",
+					"requests_per_unit": 42
+				},
+				"RHOAM SKU TIER 2": {
+					"unit": "minute",
+					"requests_per_unit": 84
+				}
+			}
+			`,
+		},
+	}
+	assertRequestsPerUnit := func(requestsPerUnit uint32) func(client.Client, *RateLimitConfig, error) error {
+		return func(c client.Client, config *RateLimitConfig, err error) error {
+			if err != nil {
+				return fmt.Errorf("Unexpected error: %v", err)
+			}
+			if config.RequestsPerUnit != requestsPerUnit {
+				return fmt.Errorf("Expected %d requests per unit, but got %d", requestsPerUnit, config.RequestsPerUnit)
+			}
+			return nil
+		}
+	}
+
 	scenarios := []struct {
 		Name        string
 		InitialObjs []runtime.Object
 		Namespace   string
+		Marin3r     *integreatlyv1alpha1.Marin3rSpec
 		Assert      func(client.Client, *RateLimitConfig, error) error
 	}{
 		{
@@ -60,12 +94,49 @@ func TestGetRateLimitConfig(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			Name:        "SKU from RHMI spec",
+			Namespace:   "redhat-test-operator",
+			InitialObjs: []runtime.Object{tiersConfigMap},
+			Marin3r:     &integreatlyv1alpha1.Marin3rSpec{SKU: "RHOAM SKU TIER 2"},
+			Assert:      assertRequestsPerUnit(84),
+		},
+		{
+			Name:      "SKU from addon parameter takes precedence",
+			Namespace: "redhat-test-operator",
+			InitialObjs: []runtime.Object{
+				tiersConfigMap,
+				&corev1.Secret{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      "addon-managed-api-service-parameters",
+						Namespace: "redhat-test-operator",
+					},
+					Data: map[string][]byte{
+						SKUParameter: []byte("RHOAM SKU TIER 2"),
+					},
+				},
+			},
+			Marin3r: &integreatlyv1alpha1.Marin3rSpec{SKU: "RHOAM SERVICE SKU"},
+			Assert:  assertRequestsPerUnit(84),
+		},
+		{
+			Name:        "Unknown SKU",
+			Namespace:   "redhat-test-operator",
+			InitialObjs: []runtime.Object{tiersConfigMap},
+			Marin3r:     &integreatlyv1alpha1.Marin3rSpec{SKU: "RHOAM SKU TIER 9"},
+			Assert: func(c client.Client, config *RateLimitConfig, err error) error {
+				if err == nil {
+					return fmt.Errorf("Expected error for unknown SKU")
+				}
+				return nil
+			},
+		},
 	}
 
 	for _, scenario := range scenarios {
 		t.Run(scenario.Name, func(t *testing.T) {
 			client := fake.NewFakeClientWithScheme(scheme, scenario.InitialObjs...)
-			config, err := GetRateLimitConfig(context.TODO(), client, scenario.Namespace)
+			config, err := GetRateLimitConfig(context.TODO(), client, testInstallation(scenario.Namespace, scenario.Marin3r))
 
 			if err := scenario.Assert(client, config, err); err != nil {
 				t.Error(err)
@@ -148,6 +219,84 @@ func TestGetAlertConfig(t *testing.T) {
 	}
 }
 
+func TestGetRateLimitMode(t *testing.T) {
+	scheme := testScheme()
+
+	scenarios := []struct {
+		Name         string
+		Parameter    string
+		Marin3r      *integreatlyv1alpha1.Marin3rSpec
+		ExpectedMode integreatlyv1alpha1.RateLimitMode
+		ExpectError  bool
+	}{
+		{
+			Name:         "Enforced by default",
+			ExpectedMode: integreatlyv1alpha1.RateLimitModeEnforce,
+		},
+		{
+			Name:         "Mode from RHMI spec",
+			Marin3r:      &integreatlyv1alpha1.Marin3rSpec{RateLimitMode: integreatlyv1alpha1.RateLimitModeShadow},
+			ExpectedMode: integreatlyv1alpha1.RateLimitModeShadow,
+		},
+		{
+			Name:         "Mode from addon parameter takes precedence",
+			Parameter:    "enforce",
+			Marin3r:      &integreatlyv1alpha1.Marin3rSpec{RateLimitMode: integreatlyv1alpha1.RateLimitModeShadow},
+			ExpectedMode: integreatlyv1alpha1.RateLimitModeEnforce,
+		},
+		{
+			Name:        "Unknown mode",
+			Parameter:   "dry-run",
+			ExpectError: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			var initialObjs []runtime.Object
+			if scenario.Parameter != "" {
+				initialObjs = append(initialObjs, &corev1.Secret{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      "addon-managed-api-service-parameters",
+						Namespace: "redhat-test-operator",
+					},
+					Data: map[string][]byte{
+						RateLimitModeParameter: []byte(scenario.Parameter),
+					},
+				})
+			}
+			client := fake.NewFakeClientWithScheme(scheme, initialObjs...)
+
+			mode, err := GetRateLimitMode(context.TODO(), client, testInstallation("redhat-test-operator", scenario.Marin3r))
+			if scenario.ExpectError {
+				if err == nil {
+					t.Errorf("Expected error, but got mode %s", mode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if mode != scenario.ExpectedMode {
+				t.Errorf("Expected mode %s, but got %s", scenario.ExpectedMode, mode)
+			}
+		})
+	}
+}
+
+func testInstallation(namespace string, marin3r *integreatlyv1alpha1.Marin3rSpec) *integreatlyv1alpha1.RHMI {
+	return &integreatlyv1alpha1.RHMI{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "rhoam",
+			Namespace: namespace,
+		},
+		Spec: integreatlyv1alpha1.RHMISpec{
+			Type:    string(integreatlyv1alpha1.InstallationTypeManagedApi),
+			Marin3r: marin3r,
+		},
+	}
+}
+
 func testScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	corev1.AddToScheme(scheme)
@@ -10,6 +10,7 @@ import (
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	"gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -65,8 +66,8 @@ type yamlRoot struct {
 }
 
 // ReconcileRateLimitService creates the resources to deploy the rate limit service
-// It reconciles a ConfigMap to configure the service, a Deployment to run it, and
-// exposes it as a Service
+// It reconciles a ConfigMap to configure the service, a Deployment to run it,
+// optionally a HorizontalPodAutoscaler to scale it, and exposes it as a Service
 func (r *RateLimitServiceReconciler) ReconcileRateLimitService(ctx context.Context, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 	phase, err := r.reconcileConfigMap(ctx, client)
 	if err != nil {
@@ -78,6 +79,11 @@ func (r *RateLimitServiceReconciler) ReconcileRateLimitService(ctx context.Conte
 		return phase, err
 	}
 
+	phase, err = r.reconcileAutoscaling(ctx, client)
+	if phase != integreatlyv1alpha1.PhaseCompleted {
+		return phase, err
+	}
+
 	return r.reconcileService(ctx, client)
 }
 
@@ -123,6 +129,19 @@ func (r *RateLimitServiceReconciler) reconcileConfigMap(ctx context.Context, cli
 		}
 
 		cm.Data["apicast-ratelimiting.yaml"] = string(stagingConfigYamlMarshalled)
+
+		managementConfigs, err := r.managementRateLimitConfigs()
+		if err != nil {
+			return err
+		}
+		for _, domain := range marin3rconfig.ManagementRateLimitDomains {
+			if config, ok := managementConfigs[domain]; ok {
+				cm.Data[domain+".yaml"] = config
+			} else {
+				delete(cm.Data, domain+".yaml")
+			}
+		}
+
 		cm.Labels["app"] = "ratelimit"
 		cm.Labels["part-of"] = "3scale-saas"
 		return nil
@@ -151,6 +170,29 @@ func (r *RateLimitServiceReconciler) reconcileDeployment(ctx context.Context, cl
 		},
 	}
 
+	topologyPolicy := resources.GetTopologyPolicy(r.Installation, integreatlyv1alpha1.ProductMarin3r)
+
+	// The config files are mounted with a path that changes with their
+	// contents, so the service reloads them when the limits change
+	configItems := []corev1.KeyToPath{
+		{
+			Key:  "apicast-ratelimiting.yaml",
+			Path: fmt.Sprintf("apicast-ratelimiting-%s.yaml", uniqueKey(r.RateLimitConfig)),
+		},
+	}
+	managementConfigs, err := r.managementRateLimitConfigs()
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+	for _, domain := range marin3rconfig.ManagementRateLimitDomains {
+		if config, ok := managementConfigs[domain]; ok {
+			configItems = append(configItems, corev1.KeyToPath{
+				Key:  domain + ".yaml",
+				Path: fmt.Sprintf("%s-%x.yaml", domain, md5.Sum([]byte(config))),
+			})
+		}
+	}
+
 	_, err = controllerutil.CreateOrUpdate(ctx, client, deployment, func() error {
 		if deployment.Labels == nil {
 			deployment.Labels = map[string]string{}
@@ -260,12 +302,7 @@ func (r *RateLimitServiceReconciler) reconcileDeployment(ctx context.Context, cl
 								LocalObjectReference: corev1.LocalObjectReference{
 									Name: "ratelimit-config",
 								},
-								Items: []corev1.KeyToPath{
-									{
-										Key:  "apicast-ratelimiting.yaml",
-										Path: fmt.Sprintf("apicast-ratelimiting-%s.yaml", uniqueKey(r.RateLimitConfig)),
-									},
-								},
+								Items: configItems,
 							},
 						},
 					},
@@ -276,8 +313,9 @@ func (r *RateLimitServiceReconciler) reconcileDeployment(ctx context.Context, cl
 		if err := resources.SetPodTemplate(
 			resources.SelectFromDeployment,
 			resources.AllMutationsOf(
-				resources.MutateZoneTopologySpreadConstraints("app"),
-				resources.MutateMultiAZAntiAffinity(ctx, client, "app"),
+				resources.MutateZoneTopologySpreadConstraints(topologyPolicy, "app"),
+				resources.MutateMultiAZAntiAffinity(topologyPolicy, "app"),
+				resources.MutateNodePlacement(topologyPolicy),
 			),
 			deployment,
 		); err != nil {
@@ -294,6 +332,25 @@ func (r *RateLimitServiceReconciler) reconcileDeployment(ctx context.Context, cl
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
+// reconcileAutoscaling creates the HorizontalPodAutoscaler of the rate limit
+// Deployment when autoscaling is set in the installation, or removes it. The
+// replicas of the Deployment are only set on creation so they don't conflict
+// with the autoscaler
+func (r *RateLimitServiceReconciler) reconcileAutoscaling(ctx context.Context, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	var spec *integreatlyv1alpha1.HorizontalAutoscaling
+	if r.Installation.Spec.Marin3r != nil {
+		spec = r.Installation.Spec.Marin3r.RateLimitAutoscaling
+	}
+
+	target := autoscalingv2beta2.CrossVersionObjectReference{
+		APIVersion: appsv1.SchemeGroupVersion.String(),
+		Kind:       "Deployment",
+		Name:       "ratelimit",
+	}
+
+	return resources.ReconcileHorizontalPodAutoscaler(ctx, client, r.Namespace, target, spec)
+}
+
 func (r *RateLimitServiceReconciler) reconcileService(ctx context.Context, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 	service := &corev1.Service{
 		ObjectMeta: v1.ObjectMeta{
@@ -352,6 +409,36 @@ func (r *RateLimitServiceReconciler) getRedisSecret(ctx context.Context, client
 	return secret, err
 }
 
+// managementRateLimitConfigs returns the rate limit service configs of the
+// 3scale management APIs whose limits are set, by domain
+func (r *RateLimitServiceReconciler) managementRateLimitConfigs() (map[string]string, error) {
+	descriptors := map[string][]yamlDescriptor{}
+	for _, limit := range marin3rconfig.GetManagementRateLimits(r.Installation) {
+		descriptors[limit.Domain] = append(descriptors[limit.Domain], yamlDescriptor{
+			Key:   "generic_key",
+			Value: limit.Descriptor,
+			RateLimit: &yamlRateLimit{
+				Unit:            limit.Limit.Unit,
+				RequestsPerUnit: limit.Limit.RequestsPerUnit,
+			},
+		})
+	}
+
+	configs := map[string]string{}
+	for domain, domainDescriptors := range descriptors {
+		config, err := yaml.Marshal(yamlRoot{
+			Domain:      domain,
+			Descriptors: domainDescriptors,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshall %s rate limit config: %v", domain, err)
+		}
+		configs[domain] = string(config)
+	}
+
+	return configs, nil
+}
+
 // uniqueKey generates a unique string for each possible rate limit configuration
 // combination
 func uniqueKey(r *marin3rconfig.RateLimitConfig) string {
@@ -8,7 +8,9 @@ import (
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
 	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -208,6 +210,119 @@ func TestRateLimitService(t *testing.T) {
 				}),
 			),
 		},
+		{
+			Name: "Autoscaling set",
+			InitObjs: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      "ratelimit-redis",
+						Namespace: "redhat-test-marin3r",
+					},
+					Data: map[string][]byte{
+						"URL": []byte("test-url"),
+					},
+				},
+			},
+			Reconciler: NewRateLimitServiceReconciler(
+				&marin3rconfig.RateLimitConfig{
+					Unit:            "minute",
+					RequestsPerUnit: 1,
+				},
+				&integreatlyv1alpha1.RHMI{
+					Spec: integreatlyv1alpha1.RHMISpec{
+						Marin3r: &integreatlyv1alpha1.Marin3rSpec{
+							RateLimitAutoscaling: &integreatlyv1alpha1.HorizontalAutoscaling{
+								MaxReplicas:          6,
+								TargetCPUUtilization: &[]int32{70}[0],
+							},
+						},
+					},
+				},
+				"redhat-test-marin3r",
+				"ratelimit-redis",
+			),
+			Assert: allOf(
+				assertNoError,
+				assertPhase(integreatlyv1alpha1.PhaseCompleted),
+				func(client k8sclient.Client, _ integreatlyv1alpha1.StatusPhase, _ error) error {
+					hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+					if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: "ratelimit", Namespace: "redhat-test-marin3r"}, hpa); err != nil {
+						return fmt.Errorf("failed to get horizontal pod autoscaler: %v", err)
+					}
+					if hpa.Spec.ScaleTargetRef.Kind != "Deployment" || hpa.Spec.MaxReplicas != 6 {
+						return fmt.Errorf("unexpected horizontal pod autoscaler spec: %v", hpa.Spec)
+					}
+					if len(hpa.Spec.Metrics) != 1 || *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization != 70 {
+						return fmt.Errorf("expected cpu utilization metric, got: %v", hpa.Spec.Metrics)
+					}
+					return nil
+				},
+			),
+		},
+		{
+			Name: "Management API limits configured",
+			InitObjs: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      "ratelimit-redis",
+						Namespace: "redhat-test-marin3r",
+					},
+					Data: map[string][]byte{
+						"URL": []byte("test-url"),
+					},
+				},
+			},
+			Reconciler: NewRateLimitServiceReconciler(
+				&marin3rconfig.RateLimitConfig{
+					Unit:            "minute",
+					RequestsPerUnit: 1,
+				},
+				&integreatlyv1alpha1.RHMI{
+					Spec: integreatlyv1alpha1.RHMISpec{
+						Marin3r: &integreatlyv1alpha1.Marin3rSpec{
+							ManagementRateLimits: &integreatlyv1alpha1.ManagementRateLimits{
+								SystemMaster:    &integreatlyv1alpha1.RateLimit{Unit: "minute", RequestsPerUnit: 100},
+								BackendListener: &integreatlyv1alpha1.RateLimit{Unit: "second", RequestsPerUnit: 500},
+							},
+						},
+					},
+				},
+				"redhat-test-marin3r",
+				"ratelimit-redis",
+			),
+			Assert: allOf(
+				assertNoError,
+				assertPhase(integreatlyv1alpha1.PhaseCompleted),
+				func(client k8sclient.Client, _ integreatlyv1alpha1.StatusPhase, _ error) error {
+					configMap := &corev1.ConfigMap{}
+					if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: "ratelimit-config", Namespace: "redhat-test-marin3r"}, configMap); err != nil {
+						return fmt.Errorf("failed to obtain expected ConfigMap: %v", err)
+					}
+
+					systemConfig := yamlRoot{}
+					if err := yaml.Unmarshal([]byte(configMap.Data["system-ratelimit.yaml"]), &systemConfig); err != nil {
+						return fmt.Errorf("failed to parse system rate limit config: %v", err)
+					}
+					if systemConfig.Domain != marin3rconfig.SystemRateLimitDomain || len(systemConfig.Descriptors) != 1 ||
+						systemConfig.Descriptors[0].Value != marin3rconfig.SystemMasterDescriptor || systemConfig.Descriptors[0].RateLimit.RequestsPerUnit != 100 {
+						return fmt.Errorf("unexpected system rate limit config: %v", systemConfig)
+					}
+					if _, ok := configMap.Data["backend-listener-ratelimit.yaml"]; !ok {
+						return fmt.Errorf("expected backend listener rate limit config")
+					}
+					return nil
+				},
+				assertDeployment(func(deployment *appsv1.Deployment, e error) error {
+					if e != nil {
+						return fmt.Errorf("failed to obtain deployment: %v", e)
+					}
+					if items := deployment.Spec.Template.Spec.Volumes[0].ConfigMap.Items; len(items) != 3 {
+						return fmt.Errorf("expected the apicast and management API configs to be mounted, got %v", items)
+					}
+					return nil
+				}),
+			),
+		},
 	}
 
 	for _, scenario := range scenarios {
@@ -226,6 +341,7 @@ func newScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	corev1.AddToScheme(scheme)
 	appsv1.AddToScheme(scheme)
+	autoscalingv2beta2.AddToScheme(scheme)
 
 	return scheme
 }
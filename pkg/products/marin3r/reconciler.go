@@ -58,6 +58,8 @@ type Reconciler struct {
 	mpm             marketplace.MarketplaceInterface
 	logger          *logrus.Entry
 	recorder        record.EventRecorder
+
+	readUsageCounters usageCountersReader
 }
 
 func (r *Reconciler) GetPreflightObject(ns string) runtime.Object {
@@ -99,6 +101,8 @@ func NewReconciler(configManager config.ConfigReadWriter, installation *integrea
 		logger:        logger,
 		Reconciler:    resources.NewReconciler(mpm),
 		recorder:      recorder,
+
+		readUsageCounters: readUsageCounters,
 	}, nil
 }
 
@@ -141,7 +145,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
-	rateLimitConfig, err := marin3rconfig.GetRateLimitConfig(ctx, client, r.installation.Namespace)
+	rateLimitConfig, err := marin3rconfig.GetRateLimitConfig(ctx, client, r.installation)
 	if err != nil {
 		events.HandleError(r.recorder, installation, phase, "Failed to obtain rate limit config", err)
 		return integreatlyv1alpha1.PhaseFailed, err
@@ -239,6 +243,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
+	// The usage is recorded again on the next reconcile, so failing to read
+	// it doesn't block the installation
+	if err := r.reconcileUsage(ctx, client, productNamespace); err != nil {
+		logrus.Errorf("failed to record API usage: %v", err)
+	}
+
 	product.Host = r.Config.GetHost()
 	product.Version = r.Config.GetProductVersion()
 	product.OperatorVersion = r.Config.GetOperatorVersion()
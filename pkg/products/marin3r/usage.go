@@ -0,0 +1,287 @@
+package marin3r
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/owner"
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// UsageConfigMapName is the name of the ConfigMap in the operator
+	// namespace that stores the API usage rollups and their reports
+	UsageConfigMapName = "rate-limit-usage"
+
+	usageJSONKey       = "usage.json"
+	usageDailyCSVKey   = "daily.csv"
+	usageMonthlyCSVKey = "monthly.csv"
+
+	// The rollups kept in the ConfigMap
+	dailyUsageRetention   = 93
+	monthlyUsageRetention = 25
+
+	dailyPeriodLayout   = "2006-01-02"
+	monthlyPeriodLayout = "2006-01"
+
+	// usageCounterPrefix and usageCounterSuffix delimit the name of the total
+	// hits counters of the rate limit descriptors, as exported by the StatsD
+	// exporter
+	usageCounterPrefix = "ratelimit_service_rate_limit_apicast_ratelimit_"
+	usageCounterSuffix = "_total_hits"
+	// installationDescriptor is the descriptor that counts all the requests
+	// of the installation
+	installationDescriptor = "generic_key_slowpath"
+)
+
+// UsageRollups are the API requests counted per period, tier and account.
+// The requests are counted from the increase of the total hits counters of
+// the rate limit service between two reconciles, so they survive the
+// retention of Prometheus and the restarts of the exporter
+type UsageRollups struct {
+	Daily   []UsageRollup `json:"daily"`
+	Monthly []UsageRollup `json:"monthly"`
+
+	// LastCounters are the total hits counters read on the last update, by
+	// account
+	LastCounters map[string]float64 `json:"lastCounters,omitempty"`
+	LastUpdate   *metav1.Time       `json:"lastUpdate,omitempty"`
+}
+
+// UsageRollup is the number of requests of an account in a period. The
+// requests of the whole installation have no account
+type UsageRollup struct {
+	Period   string `json:"period"`
+	Tier     string `json:"tier"`
+	Account  string `json:"account,omitempty"`
+	Requests uint64 `json:"requests"`
+}
+
+// usageCountersReader returns the total hits counters by account
+type usageCountersReader func(ctx context.Context, url string) (map[string]float64, error)
+
+// Record adds the increase of the counters since the last update to the
+// rollups of the periods of now. The first counters read are only used as a
+// baseline, and a counter lower than on the last update was reset
+func (u *UsageRollups) Record(now time.Time, tier string, counters map[string]float64) {
+	now = now.UTC()
+	firstUpdate := u.LastUpdate == nil
+	if u.LastCounters == nil {
+		u.LastCounters = map[string]float64{}
+	}
+
+	for account, counter := range counters {
+		last, ok := u.LastCounters[account]
+		u.LastCounters[account] = counter
+		if firstUpdate {
+			continue
+		}
+
+		increase := counter
+		if ok && counter >= last {
+			increase = counter - last
+		}
+		if increase <= 0 {
+			continue
+		}
+
+		u.Daily = addUsage(u.Daily, now.Format(dailyPeriodLayout), tier, account, uint64(increase))
+		u.Monthly = addUsage(u.Monthly, now.Format(monthlyPeriodLayout), tier, account, uint64(increase))
+	}
+
+	u.Daily = pruneUsage(u.Daily, dailyUsageRetention)
+	u.Monthly = pruneUsage(u.Monthly, monthlyUsageRetention)
+	lastUpdate := metav1.NewTime(now)
+	u.LastUpdate = &lastUpdate
+}
+
+func addUsage(rollups []UsageRollup, period, tier, account string, requests uint64) []UsageRollup {
+	for i := range rollups {
+		if rollups[i].Period == period && rollups[i].Tier == tier && rollups[i].Account == account {
+			rollups[i].Requests += requests
+			return rollups
+		}
+	}
+
+	rollups = append(rollups, UsageRollup{Period: period, Tier: tier, Account: account, Requests: requests})
+	sort.SliceStable(rollups, func(i, j int) bool {
+		if rollups[i].Period != rollups[j].Period {
+			return rollups[i].Period < rollups[j].Period
+		}
+		if rollups[i].Tier != rollups[j].Tier {
+			return rollups[i].Tier < rollups[j].Tier
+		}
+		return rollups[i].Account < rollups[j].Account
+	})
+	return rollups
+}
+
+// pruneUsage removes the rollups of all but the latest retention periods
+func pruneUsage(rollups []UsageRollup, retention int) []UsageRollup {
+	var periods []string
+	for _, rollup := range rollups {
+		if len(periods) == 0 || periods[len(periods)-1] != rollup.Period {
+			periods = append(periods, rollup.Period)
+		}
+	}
+	if len(periods) <= retention {
+		return rollups
+	}
+
+	oldestPeriod := periods[len(periods)-retention]
+	for i, rollup := range rollups {
+		if rollup.Period >= oldestPeriod {
+			return rollups[i:]
+		}
+	}
+	return rollups
+}
+
+// usageCSV returns the rollups as a CSV report
+func usageCSV(rollups []UsageRollup) (string, error) {
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	if err := writer.Write([]string{"period", "tier", "account", "requests"}); err != nil {
+		return "", err
+	}
+	for _, rollup := range rollups {
+		if err := writer.Write([]string{rollup.Period, rollup.Tier, rollup.Account, strconv.FormatUint(rollup.Requests, 10)}); err != nil {
+			return "", err
+		}
+	}
+	writer.Flush()
+	return buffer.String(), writer.Error()
+}
+
+// reconcileUsage records the API usage since the last reconcile in the
+// rollups of the usage ConfigMap and exports the usage of the current day
+// and month as metrics
+func (r *Reconciler) reconcileUsage(ctx context.Context, client k8sclient.Client, productNamespace string) error {
+	tier, err := marin3rconfig.GetSKU(ctx, client, r.installation)
+	if err != nil {
+		return fmt.Errorf("failed to get SKU: %w", err)
+	}
+
+	counters, err := r.readUsageCounters(ctx, fmt.Sprintf("http://%s.%s.svc:%d/metrics", statsdHost, productNamespace, metricsPort))
+	if err != nil {
+		return fmt.Errorf("failed to read rate limit counters: %w", err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      UsageConfigMapName,
+			Namespace: r.installation.Namespace,
+		},
+	}
+
+	rollups := &UsageRollups{}
+	if _, err := controllerutil.CreateOrUpdate(ctx, client, cm, func() error {
+		owner.AddIntegreatlyOwnerAnnotations(cm, r.installation)
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+
+		if usageJSON, ok := cm.Data[usageJSONKey]; ok {
+			if err := json.Unmarshal([]byte(usageJSON), rollups); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", usageJSONKey, err)
+			}
+		}
+		rollups.Record(time.Now(), tier, counters)
+
+		usageJSON, err := json.Marshal(rollups)
+		if err != nil {
+			return fmt.Errorf("failed to marshal usage rollups: %w", err)
+		}
+		dailyCSV, err := usageCSV(rollups.Daily)
+		if err != nil {
+			return fmt.Errorf("failed to write daily usage report: %w", err)
+		}
+		monthlyCSV, err := usageCSV(rollups.Monthly)
+		if err != nil {
+			return fmt.Errorf("failed to write monthly usage report: %w", err)
+		}
+
+		cm.Data[usageJSONKey] = string(usageJSON)
+		cm.Data[usageDailyCSVKey] = dailyCSV
+		cm.Data[usageMonthlyCSVKey] = monthlyCSV
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to update %s ConfigMap: %w", UsageConfigMapName, err)
+	}
+
+	metrics.SetAPIUsage(rollups.LastUpdate.Format(dailyPeriodLayout), rollups.LastUpdate.Format(monthlyPeriodLayout), toMetricsUsage(rollups.Daily), toMetricsUsage(rollups.Monthly))
+	return nil
+}
+
+func toMetricsUsage(rollups []UsageRollup) []metrics.APIUsage {
+	result := make([]metrics.APIUsage, 0, len(rollups))
+	for _, rollup := range rollups {
+		result = append(result, metrics.APIUsage{
+			Period:   rollup.Period,
+			Tier:     rollup.Tier,
+			Account:  rollup.Account,
+			Requests: rollup.Requests,
+		})
+	}
+	return result
+}
+
+// readUsageCounters reads the total hits counters of the rate limit
+// descriptors from the metrics of the StatsD exporter. The descriptors only
+// count the requests of the whole installation until they include the
+// account of the requests
+func readUsageCounters(ctx context.Context, url string) (map[string]float64, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	res, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+
+	counters := map[string]float64{}
+	for name, family := range families {
+		if !strings.HasPrefix(name, usageCounterPrefix) || !strings.HasSuffix(name, usageCounterSuffix) {
+			continue
+		}
+		descriptor := strings.TrimSuffix(strings.TrimPrefix(name, usageCounterPrefix), usageCounterSuffix)
+		if descriptor != installationDescriptor {
+			continue
+		}
+		for _, metric := range family.Metric {
+			if metric.Counter != nil {
+				counters[""] += metric.Counter.GetValue()
+			} else if metric.Untyped != nil {
+				counters[""] += metric.Untyped.GetValue()
+			}
+		}
+	}
+
+	return counters, nil
+}
@@ -0,0 +1,138 @@
+package marin3r
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/owner"
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUsageRollups_Record(t *testing.T) {
+	day1 := time.Date(2021, time.January, 31, 22, 0, 0, 0, time.UTC)
+	day2 := day1.Add(4 * time.Hour)
+
+	rollups := &UsageRollups{}
+	rollups.Record(day1, "10M", map[string]float64{"": 100})
+	if len(rollups.Daily) != 0 || len(rollups.Monthly) != 0 {
+		t.Fatalf("expected first counters to only be used as a baseline, got %+v", rollups)
+	}
+
+	rollups.Record(day1.Add(time.Hour), "10M", map[string]float64{"": 150})
+	// The exporter restarted, so all the requests counted are new
+	rollups.Record(day2, "10M", map[string]float64{"": 30})
+
+	wantDaily := []UsageRollup{
+		{Period: "2021-01-31", Tier: "10M", Requests: 50},
+		{Period: "2021-02-01", Tier: "10M", Requests: 30},
+	}
+	wantMonthly := []UsageRollup{
+		{Period: "2021-01", Tier: "10M", Requests: 50},
+		{Period: "2021-02", Tier: "10M", Requests: 30},
+	}
+	if fmt.Sprint(rollups.Daily) != fmt.Sprint(wantDaily) {
+		t.Errorf("unexpected daily rollups %v, want %v", rollups.Daily, wantDaily)
+	}
+	if fmt.Sprint(rollups.Monthly) != fmt.Sprint(wantMonthly) {
+		t.Errorf("unexpected monthly rollups %v, want %v", rollups.Monthly, wantMonthly)
+	}
+	if rollups.LastCounters[""] != 30 || !rollups.LastUpdate.Time.Equal(day2) {
+		t.Errorf("unexpected last update %v, %v", rollups.LastCounters, rollups.LastUpdate)
+	}
+}
+
+func TestPruneUsage(t *testing.T) {
+	rollups := []UsageRollup{
+		{Period: "2021-01", Tier: "1M", Requests: 1},
+		{Period: "2021-02", Tier: "1M", Requests: 2},
+		{Period: "2021-02", Tier: "10M", Requests: 3},
+		{Period: "2021-03", Tier: "10M", Requests: 4},
+	}
+
+	got := pruneUsage(rollups, 2)
+	if len(got) != 3 || got[0].Period != "2021-02" {
+		t.Errorf("expected the rollups of the latest 2 periods to be kept, got %v", got)
+	}
+}
+
+func TestReadUsageCounters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `# HELP ratelimit_service_rate_limit_apicast_ratelimit_generic_key_slowpath_total_hits Metric autogenerated by statsd_exporter.
+# TYPE ratelimit_service_rate_limit_apicast_ratelimit_generic_key_slowpath_total_hits counter
+ratelimit_service_rate_limit_apicast_ratelimit_generic_key_slowpath_total_hits 1234
+# HELP ratelimit_service_rate_limit_apicast_ratelimit_generic_key_slowpath_over_limit Metric autogenerated by statsd_exporter.
+# TYPE ratelimit_service_rate_limit_apicast_ratelimit_generic_key_slowpath_over_limit counter
+ratelimit_service_rate_limit_apicast_ratelimit_generic_key_slowpath_over_limit 12
+`)
+	}))
+	defer server.Close()
+
+	counters, err := readUsageCounters(context.TODO(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(counters) != 1 || counters[""] != 1234 {
+		t.Errorf("unexpected counters %v", counters)
+	}
+}
+
+func TestReconcileUsage(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverClient := fakeclient.NewFakeClientWithScheme(scheme)
+
+	reconciler := getBasicReconciler()
+	reconciler.installation.Spec.Marin3r = &integreatlyv1alpha1.Marin3rSpec{SKU: "10M"}
+	counter := 100.0
+	reconciler.readUsageCounters = func(ctx context.Context, url string) (map[string]float64, error) {
+		if url != "http://prom-statsd-exporter.marin3r.svc:9102/metrics" {
+			t.Errorf("unexpected metrics URL %s", url)
+		}
+		return map[string]float64{"": counter}, nil
+	}
+
+	for _, c := range []float64{100, 175} {
+		counter = c
+		if err := reconciler.reconcileUsage(context.TODO(), serverClient, defaultInstallationNamespace); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: UsageConfigMapName, Namespace: defaultInstallationNamespace}, cm); err != nil {
+		t.Fatal(err)
+	}
+
+	rollups := &UsageRollups{}
+	if err := json.Unmarshal([]byte(cm.Data[usageJSONKey]), rollups); err != nil {
+		t.Fatal(err)
+	}
+	if len(rollups.Daily) != 1 || rollups.Daily[0].Requests != 75 || rollups.Daily[0].Tier != "10M" {
+		t.Errorf("unexpected daily rollups %v", rollups.Daily)
+	}
+	if len(rollups.Monthly) != 1 || rollups.Monthly[0].Requests != 75 {
+		t.Errorf("unexpected monthly rollups %v", rollups.Monthly)
+	}
+
+	wantCSV := fmt.Sprintf("period,tier,account,requests\n%s,10M,,75\n", rollups.Daily[0].Period)
+	if cm.Data[usageDailyCSVKey] != wantCSV {
+		t.Errorf("unexpected daily report %q, want %q", cm.Data[usageDailyCSVKey], wantCSV)
+	}
+	if !strings.HasPrefix(cm.Data[usageMonthlyCSVKey], "period,tier,account,requests\n") {
+		t.Errorf("unexpected monthly report %q", cm.Data[usageMonthlyCSVKey])
+	}
+	if cm.Annotations[owner.IntegreatlyOwnerName] != "installation" {
+		t.Errorf("expected owner annotations to be set, got %v", cm.Annotations)
+	}
+}
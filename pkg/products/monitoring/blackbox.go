@@ -0,0 +1,521 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoring "github.com/integr8ly/application-monitoring-operator/pkg/apis/applicationmonitoring/v1alpha1"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/owner"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// probeAnnotation holds the endpoint and the probe settings of a
+	// blackbox target
+	probeAnnotation = "integreatly.org/probe"
+	// customModulePrefix is the prefix of the blackbox modules of the custom
+	// probes, which are owned by the operator
+	customModulePrefix        = "rhmi_"
+	defaultAvailabilityTarget = "99.9"
+	endpointSLORuleName       = "endpoint-slo-rules"
+
+	// blackboxSecretName is the secret with the credentials of the custom
+	// probes, mounted in the blackbox exporter container of the Prometheus
+	// pod. The volumes of the secrets listed in the Prometheus spec are
+	// prefixed with secret-
+	blackboxSecretName            = "rhmi-blackbox-probes"
+	blackboxSecretVolumeName      = "secret-" + blackboxSecretName
+	blackboxSecretPath            = "/etc/prometheus/secrets/" + blackboxSecretName
+	blackboxExporterContainerName = "blackbox-exporter"
+)
+
+// sloWindows are the windows of the probe success ratios, used by the burn
+// rate alerts and the error budget
+var sloWindows = []string{"5m", "30m", "1h", "2h", "6h", "1d", "3d", "28d"}
+
+// burnRateAlerts are the multiwindow burn rate alerts of the endpoint SLOs.
+// An alert fires when the error budget is burnt faster than burnRate in both
+// windows
+var burnRateAlerts = []struct {
+	longWindow  string
+	shortWindow string
+	burnRate    string
+	severity    string
+}{
+	{longWindow: "1h", shortWindow: "5m", burnRate: "14.4", severity: "critical"},
+	{longWindow: "6h", shortWindow: "30m", burnRate: "6", severity: "critical"},
+	{longWindow: "1d", shortWindow: "2h", burnRate: "3", severity: "warning"},
+	{longWindow: "3d", shortWindow: "6h", burnRate: "1", severity: "warning"},
+}
+
+// blackboxProbe is the endpoint and the probe settings of a blackbox target
+type blackboxProbe struct {
+	URL     string                        `json:"url"`
+	Service string                        `json:"service"`
+	Module  string                        `json:"module,omitempty"`
+	Probe   integreatlyv1alpha1.ProbeSpec `json:"probe"`
+}
+
+// CreateBlackboxProbe creates or updates the blackbox target of a product
+// endpoint. The probe settings are overridden by the probe of the installation
+// with the same service. The custom probes aren't listed in the blackbox
+// target, which can't set their settings, but probed by a job of the
+// additional scrape config with their own blackbox module
+func CreateBlackboxProbe(ctx context.Context, name string, target monitoring.BlackboxtargetData, probe integreatlyv1alpha1.ProbeSpec, cfg *config.Monitoring, installation *integreatlyv1alpha1.RHMI, serverClient k8sclient.Client) error {
+	if cfg.GetOperatorNamespace() == "" {
+		// Retry later
+		return nil
+	}
+
+	if target.Url == "" {
+		// Retry later if the URL is not yet known
+		return nil
+	}
+
+	// default policy is to require a 2xx http return code
+	module := target.Module
+	if module == "" {
+		module = defaultBlackboxModule
+	}
+
+	probe = resolveProbe(probe, installation, target.Service)
+	annotation, err := json.Marshal(blackboxProbe{URL: target.Url, Service: target.Service, Module: module, Probe: probe})
+	if err != nil {
+		return fmt.Errorf("failed to marshal probe of %s: %w", name, err)
+	}
+
+	blackboxTarget := &monitoring.BlackboxTarget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cfg.GetOperatorNamespace(),
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, serverClient, blackboxTarget, func() error {
+		owner.AddIntegreatlyOwnerAnnotations(blackboxTarget, installation)
+		blackboxTarget.Annotations[probeAnnotation] = string(annotation)
+		if blackboxTarget.Labels == nil {
+			blackboxTarget.Labels = map[string]string{}
+		}
+		blackboxTarget.Labels[cfg.GetLabelSelectorKey()] = cfg.GetLabelSelector()
+
+		blackboxTarget.Spec.BlackboxTargets = nil
+		if !isCustomProbe(probe) {
+			blackboxTarget.Spec.BlackboxTargets = []monitoring.BlackboxtargetData{
+				{Service: target.Service, Url: target.Url, Module: module},
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error creating blackbox target: %w", err)
+	}
+
+	return nil
+}
+
+// resolveProbe overrides the settings of probe that are set in the probe of
+// the installation for service
+func resolveProbe(probe integreatlyv1alpha1.ProbeSpec, installation *integreatlyv1alpha1.RHMI, service string) integreatlyv1alpha1.ProbeSpec {
+	if installation.Spec.Monitoring == nil {
+		return probe
+	}
+	override, ok := installation.Spec.Monitoring.Probes[service]
+	if !ok {
+		return probe
+	}
+
+	if override.Method != "" {
+		probe.Method = override.Method
+	}
+	if len(override.ValidStatusCodes) > 0 {
+		probe.ValidStatusCodes = override.ValidStatusCodes
+	}
+	if override.InsecureSkipVerify {
+		probe.InsecureSkipVerify = true
+	}
+	if override.AuthSecretRef != "" {
+		probe.AuthSecretRef = override.AuthSecretRef
+	}
+	if override.Interval != "" {
+		probe.Interval = override.Interval
+	}
+	if override.AvailabilityTarget != "" {
+		probe.AvailabilityTarget = override.AvailabilityTarget
+	}
+	return probe
+}
+
+// isCustomProbe returns whether the probe can't use the default blackbox
+// module and job
+func isCustomProbe(probe integreatlyv1alpha1.ProbeSpec) bool {
+	return probe.Method != "" ||
+		len(probe.ValidStatusCodes) > 0 ||
+		probe.InsecureSkipVerify ||
+		probe.AuthSecretRef != "" ||
+		probe.Interval != ""
+}
+
+func customModuleName(service string) string {
+	return customModulePrefix + service
+}
+
+// getBlackboxProbes returns the probes of the blackbox targets, sorted by
+// service. The targets created before the probe settings were added are
+// read from their spec
+func (r *Reconciler) getBlackboxProbes(ctx context.Context, serverClient k8sclient.Client) ([]blackboxProbe, error) {
+	blackboxTargets := &monitoring.BlackboxTargetList{}
+	if err := serverClient.List(ctx, blackboxTargets, k8sclient.InNamespace(r.Config.GetOperatorNamespace())); err != nil {
+		return nil, fmt.Errorf("failed to list blackbox targets: %w", err)
+	}
+
+	var probes []blackboxProbe
+	for _, blackboxTarget := range blackboxTargets.Items {
+		annotation, ok := blackboxTarget.Annotations[probeAnnotation]
+		if !ok {
+			for _, target := range blackboxTarget.Spec.BlackboxTargets {
+				probes = append(probes, blackboxProbe{URL: target.Url, Service: target.Service, Module: target.Module})
+			}
+			continue
+		}
+
+		probe := blackboxProbe{}
+		if err := json.Unmarshal([]byte(annotation), &probe); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal probe of blackbox target %s: %w", blackboxTarget.Name, err)
+		}
+		probes = append(probes, probe)
+	}
+
+	sort.Slice(probes, func(i, j int) bool {
+		return probes[i].Service < probes[j].Service
+	})
+	return probes, nil
+}
+
+// getBlackboxJobs returns the scrape jobs of the custom probes
+func (r *Reconciler) getBlackboxJobs(probes []blackboxProbe) ([]byte, error) {
+	var jobs []byte
+	for _, probe := range probes {
+		if !isCustomProbe(probe.Probe) {
+			continue
+		}
+
+		templateHelper := NewTemplateHelper(map[string]string{
+			"job_name":          "blackbox-" + probe.Service,
+			"interval":          probe.Probe.Interval,
+			"module":            customModuleName(probe.Service),
+			"url":               probe.URL,
+			"service":           probe.Service,
+			"blackbox_exporter": r.Config.GetBlackboxExporterAddress(),
+		})
+		job, err := templateHelper.loadTemplate("blackbox/job.yaml")
+		if err != nil {
+			return nil, fmt.Errorf("error loading template: %w", err)
+		}
+		jobs = append(jobs, job...)
+		jobs = append(jobs, '\n')
+	}
+	return jobs, nil
+}
+
+// reconcileBlackboxModules adds the modules of the custom probes to the config
+// of the blackbox exporter, and removes the modules of the probes that
+// aren't custom anymore. The other modules are left as is
+func (r *Reconciler) reconcileBlackboxModules(ctx context.Context, serverClient k8sclient.Client, probes []blackboxProbe) (integreatlyv1alpha1.StatusPhase, error) {
+	modules := map[string]interface{}{}
+	credentials := map[string][]byte{}
+	for _, probe := range probes {
+		if !isCustomProbe(probe.Probe) {
+			continue
+		}
+		module, err := r.getBlackboxModule(ctx, serverClient, probe, credentials)
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+		modules[customModuleName(probe.Service)] = module
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: r.Config.GetBlackboxExporterConfigMapName(), Namespace: r.Config.GetOperatorNamespace()}, configMap)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			if len(modules) == 0 {
+				return integreatlyv1alpha1.PhaseCompleted, nil
+			}
+			return integreatlyv1alpha1.PhaseAwaitingComponents, nil
+		}
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get blackbox exporter config: %w", err)
+	}
+
+	// The credentials are mounted before the modules reference them
+	phase, err := r.reconcileBlackboxSecret(ctx, serverClient, credentials)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		return phase, err
+	}
+
+	blackboxConfig := struct {
+		Modules map[string]interface{} `yaml:"modules"`
+	}{}
+	if err := yaml.Unmarshal([]byte(configMap.Data[r.Config.GetBlackboxExporterConfigMapKey()]), &blackboxConfig); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to unmarshal blackbox exporter config: %w", err)
+	}
+	if blackboxConfig.Modules == nil {
+		blackboxConfig.Modules = map[string]interface{}{}
+	}
+	for name := range blackboxConfig.Modules {
+		if strings.HasPrefix(name, customModulePrefix) {
+			delete(blackboxConfig.Modules, name)
+		}
+	}
+	for name, module := range modules {
+		blackboxConfig.Modules[name] = module
+	}
+
+	data, err := yaml.Marshal(blackboxConfig)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to marshal blackbox exporter config: %w", err)
+	}
+	if configMap.Data[r.Config.GetBlackboxExporterConfigMapKey()] == string(data) {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[r.Config.GetBlackboxExporterConfigMapKey()] = string(data)
+	if err := serverClient.Update(ctx, configMap); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to update blackbox exporter config: %w", err)
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+// getBlackboxModule returns the http module of a custom probe. The credentials
+// of the probe are added to credentials, and the module references the files
+// they're mounted as, so they aren't stored in the blackbox exporter config
+func (r *Reconciler) getBlackboxModule(ctx context.Context, serverClient k8sclient.Client, probe blackboxProbe, credentials map[string][]byte) (map[string]interface{}, error) {
+	http := map[string]interface{}{}
+	if probe.Probe.Method != "" {
+		http["method"] = probe.Probe.Method
+	}
+	if len(probe.Probe.ValidStatusCodes) > 0 {
+		http["valid_status_codes"] = probe.Probe.ValidStatusCodes
+	}
+	if probe.Probe.InsecureSkipVerify {
+		http["tls_config"] = map[string]interface{}{"insecure_skip_verify": true}
+	}
+	if probe.Probe.AuthSecretRef != "" {
+		secret := &corev1.Secret{}
+		if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: probe.Probe.AuthSecretRef, Namespace: r.installation.Namespace}, secret); err != nil {
+			return nil, fmt.Errorf("failed to get probe secret %s: %w", probe.Probe.AuthSecretRef, err)
+		}
+
+		switch {
+		case len(secret.Data["username"]) > 0 && len(secret.Data["password"]) > 0:
+			key := probe.Service + "-password"
+			credentials[key] = secret.Data["password"]
+			http["basic_auth"] = map[string]string{
+				"username":      string(secret.Data["username"]),
+				"password_file": blackboxSecretPath + "/" + key,
+			}
+		case len(secret.Data["token"]) > 0:
+			key := probe.Service + "-token"
+			credentials[key] = secret.Data["token"]
+			http["bearer_token_file"] = blackboxSecretPath + "/" + key
+		default:
+			return nil, fmt.Errorf("probe secret %s has neither a username and password nor a token", probe.Probe.AuthSecretRef)
+		}
+	}
+
+	return map[string]interface{}{
+		"prober": "http",
+		"http":   http,
+	}, nil
+}
+
+// reconcileBlackboxSecret stores the credentials of the custom probes in the
+// namespace of the Prometheus, and mounts them in its blackbox exporter
+// container. The secret is removed when no probe has credentials
+func (r *Reconciler) reconcileBlackboxSecret(ctx context.Context, serverClient k8sclient.Client, credentials map[string][]byte) (integreatlyv1alpha1.StatusPhase, error) {
+	prom := &monitoringv1.Prometheus{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: middlewarePrometheusName, Namespace: r.Config.GetOperatorNamespace()}, prom); err != nil {
+		if k8serr.IsNotFound(err) {
+			if len(credentials) == 0 {
+				return integreatlyv1alpha1.PhaseCompleted, nil
+			}
+			return integreatlyv1alpha1.PhaseAwaitingComponents, nil
+		}
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get %s prometheus: %w", middlewarePrometheusName, err)
+	}
+
+	container := -1
+	for i := range prom.Spec.Containers {
+		if prom.Spec.Containers[i].Name == blackboxExporterContainerName {
+			container = i
+		}
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      blackboxSecretName,
+			Namespace: r.Config.GetOperatorNamespace(),
+		},
+	}
+	if len(credentials) == 0 {
+		if resources.Contains(prom.Spec.Secrets, blackboxSecretName) {
+			prom.Spec.Secrets = resources.Remove(prom.Spec.Secrets, blackboxSecretName)
+			if container != -1 {
+				var mounts []corev1.VolumeMount
+				for _, mount := range prom.Spec.Containers[container].VolumeMounts {
+					if mount.Name != blackboxSecretVolumeName {
+						mounts = append(mounts, mount)
+					}
+				}
+				prom.Spec.Containers[container].VolumeMounts = mounts
+			}
+			if err := serverClient.Update(ctx, prom); err != nil {
+				return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to unmount %s secret from %s prometheus: %w", blackboxSecretName, middlewarePrometheusName, err)
+			}
+		}
+		if err := serverClient.Delete(ctx, secret); err != nil && !k8serr.IsNotFound(err) {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to delete %s secret: %w", blackboxSecretName, err)
+		}
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	if container == -1 {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("container %s not found in %s prometheus", blackboxExporterContainerName, middlewarePrometheusName)
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, serverClient, secret, func() error {
+		owner.AddIntegreatlyOwnerAnnotations(secret, r.installation)
+		secret.Data = credentials
+		return nil
+	}); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create or update %s secret: %w", blackboxSecretName, err)
+	}
+
+	mounted := false
+	for _, mount := range prom.Spec.Containers[container].VolumeMounts {
+		if mount.Name == blackboxSecretVolumeName {
+			mounted = true
+		}
+	}
+	if mounted && resources.Contains(prom.Spec.Secrets, blackboxSecretName) {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+	if !resources.Contains(prom.Spec.Secrets, blackboxSecretName) {
+		prom.Spec.Secrets = append(prom.Spec.Secrets, blackboxSecretName)
+	}
+	if !mounted {
+		prom.Spec.Containers[container].VolumeMounts = append(prom.Spec.Containers[container].VolumeMounts, corev1.VolumeMount{
+			Name:      blackboxSecretVolumeName,
+			MountPath: blackboxSecretPath,
+			ReadOnly:  true,
+		})
+	}
+	if err := serverClient.Update(ctx, prom); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to mount %s secret in %s prometheus: %w", blackboxSecretName, middlewarePrometheusName, err)
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+// newEndpointSLOReconciler returns the recording rules of the probe success
+// ratios of the endpoints, and the burn rate alerts and error budget of their
+// availability SLOs
+func (r *Reconciler) newEndpointSLOReconciler(probes []blackboxProbe) resources.AlertReconciler {
+	var rules []monitoringv1.Rule
+	for _, window := range sloWindows {
+		rules = append(rules, monitoringv1.Rule{
+			Record: "service:probe_success:ratio_avg" + window,
+			Expr:   intstr.FromString(fmt.Sprintf("avg by(service) (avg_over_time(probe_success{job='blackbox'}[%s]))", window)),
+		})
+	}
+
+	services := map[string]bool{}
+	for _, probe := range probes {
+		if services[probe.Service] {
+			continue
+		}
+		services[probe.Service] = true
+
+		target := probe.Probe.AvailabilityTarget
+		if value, err := strconv.ParseFloat(target, 64); err != nil || value <= 0 || value >= 100 {
+			if target != "" {
+				logrus.Warnf("ignoring invalid availability target %q of %s", target, probe.Service)
+			}
+			target = defaultAvailabilityTarget
+		}
+		errorBudget := fmt.Sprintf("(1 - %s / 100)", target)
+		selector := fmt.Sprintf("{service='%s'}", probe.Service)
+
+		rules = append(rules,
+			monitoringv1.Rule{
+				Record: "service:probe_success:slo_target",
+				Expr:   intstr.FromString(fmt.Sprintf("vector(%s / 100)", target)),
+				Labels: map[string]string{"service": probe.Service},
+			},
+			monitoringv1.Rule{
+				Record: "service:probe_success:error_budget_remaining28d",
+				Expr:   intstr.FromString(fmt.Sprintf("1 - (1 - service:probe_success:ratio_avg28d%s) / %s", selector, errorBudget)),
+			},
+		)
+		for _, alert := range burnRateAlerts {
+			rules = append(rules, monitoringv1.Rule{
+				Alert: "EndpointAvailabilityErrorBudgetBurn",
+				Annotations: map[string]string{
+					"sop_url": resources.SopUrlAlertsAndTroubleshooting,
+					"message": fmt.Sprintf("The %s endpoint is burning its %s%% availability error budget %s times faster than allowed over the last %s", probe.Service, target, alert.burnRate, alert.longWindow),
+				},
+				Expr: intstr.FromString(fmt.Sprintf("(1 - service:probe_success:ratio_avg%s%s) > %s * %s and (1 - service:probe_success:ratio_avg%s%s) > %s * %s",
+					alert.longWindow, selector, alert.burnRate, errorBudget,
+					alert.shortWindow, selector, alert.burnRate, errorBudget)),
+				Labels: map[string]string{"severity": alert.severity, "service": probe.Service},
+			})
+		}
+	}
+
+	return &resources.AlertReconcilerImpl{
+		ProductName:  "monitoring",
+		Installation: r.installation,
+		Logger:       r.Logger,
+		Alerts: []resources.AlertConfiguration{
+			{
+				AlertName: endpointSLORuleName,
+				GroupName: "endpoint-slo.rules",
+				Namespace: r.Config.GetOperatorNamespace(),
+				Rules:     rules,
+			},
+		},
+	}
+}
+
+// reconcileBlackboxProbes configures the blackbox modules of the custom probes
+// and the SLO rules of all the probes
+func (r *Reconciler) reconcileBlackboxProbes(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	probes, err := r.getBlackboxProbes(ctx, serverClient)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
+	phase, err := r.reconcileBlackboxModules(ctx, serverClient, probes)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		return phase, err
+	}
+
+	return r.newEndpointSLOReconciler(probes).ReconcileAlerts(ctx, serverClient)
+}
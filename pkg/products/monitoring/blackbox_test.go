@@ -0,0 +1,225 @@
+package monitoring
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	prometheusmonitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoringv1 "github.com/integr8ly/application-monitoring-operator/pkg/apis/applicationmonitoring/v1alpha1"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCreateBlackboxProbe(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installation := basicInstallation()
+	installation.Spec.Monitoring = &integreatlyv1alpha1.MonitoringSpec{
+		Probes: map[string]integreatlyv1alpha1.ProbeSpec{
+			"3scale-admin-ui": {ValidStatusCodes: []int{200, 302}, Interval: "1m"},
+		},
+	}
+	cfg := config.NewMonitoring(config.ProductConfig{"OPERATOR_NAMESPACE": mockMonitoringOperatorNamespace})
+	serverClient := fakeclient.NewFakeClientWithScheme(scheme)
+
+	for name, service := range map[string]string{
+		"integreatly-3scale-admin-ui": "3scale-admin-ui",
+		"integreatly-rhsso":           "rhsso-ui",
+	} {
+		err := CreateBlackboxProbe(context.TODO(), name, monitoringv1.BlackboxtargetData{
+			Url:     "https://" + service + ".example.com",
+			Service: service,
+		}, integreatlyv1alpha1.ProbeSpec{Method: "GET"}, cfg, installation, serverClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reconciler := federationReconciler(installation)
+	probes, err := reconciler.getBlackboxProbes(context.TODO(), serverClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(probes) != 2 || probes[0].Service != "3scale-admin-ui" || probes[0].Probe.Interval != "1m" || probes[0].Probe.Method != "GET" {
+		t.Fatalf("unexpected probes %v", probes)
+	}
+
+	blackboxTarget := &monitoringv1.BlackboxTarget{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "integreatly-3scale-admin-ui", Namespace: mockMonitoringOperatorNamespace}, blackboxTarget); err != nil {
+		t.Fatal(err)
+	}
+	if len(blackboxTarget.Spec.BlackboxTargets) != 0 {
+		t.Errorf("expected custom probe not to be probed by the blackbox target, got %v", blackboxTarget.Spec.BlackboxTargets)
+	}
+
+	jobs, err := reconciler.getBlackboxJobs(probes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var scrapeConfigs []struct {
+		JobName        string              `yaml:"job_name"`
+		ScrapeInterval string              `yaml:"scrape_interval"`
+		Params         map[string][]string `yaml:"params"`
+	}
+	if err := yaml.Unmarshal(jobs, &scrapeConfigs); err != nil {
+		t.Fatalf("failed to unmarshal blackbox jobs: %v\n%s", err, jobs)
+	}
+	if len(scrapeConfigs) != 2 || scrapeConfigs[0].JobName != "blackbox-3scale-admin-ui" || scrapeConfigs[0].ScrapeInterval != "1m" ||
+		scrapeConfigs[1].Params["module"][0] != "rhmi_rhsso-ui" {
+		t.Errorf("unexpected blackbox jobs %+v", scrapeConfigs)
+	}
+}
+
+func TestReconciler_reconcileBlackboxProbes(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installation := basicInstallation()
+	installation.Spec.Monitoring = &integreatlyv1alpha1.MonitoringSpec{
+		Probes: map[string]integreatlyv1alpha1.ProbeSpec{
+			"3scale-admin-ui": {InsecureSkipVerify: true, AuthSecretRef: "probe-auth", AvailabilityTarget: "99.5"},
+		},
+	}
+	cfg := config.NewMonitoring(config.ProductConfig{"OPERATOR_NAMESPACE": mockMonitoringOperatorNamespace})
+	serverClient := fakeclient.NewFakeClientWithScheme(scheme,
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: cfg.GetBlackboxExporterConfigMapName(), Namespace: mockMonitoringOperatorNamespace},
+			Data: map[string]string{
+				cfg.GetBlackboxExporterConfigMapKey(): "modules:\n  http_2xx:\n    prober: http\n  rhmi_stale:\n    prober: http\n",
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "probe-auth", Namespace: installation.Namespace},
+			Data:       map[string][]byte{"token": []byte("test")},
+		},
+		&prometheusmonitoringv1.Prometheus{
+			ObjectMeta: metav1.ObjectMeta{Name: middlewarePrometheusName, Namespace: mockMonitoringOperatorNamespace},
+			Spec: prometheusmonitoringv1.PrometheusSpec{
+				Containers: []corev1.Container{{Name: blackboxExporterContainerName}},
+			},
+		},
+	)
+	for name, service := range map[string]string{
+		"integreatly-3scale-admin-ui": "3scale-admin-ui",
+		"integreatly-rhsso":           "rhsso-ui",
+	} {
+		err := CreateBlackboxTarget(context.TODO(), name, monitoringv1.BlackboxtargetData{
+			Url:     "https://" + service + ".example.com",
+			Service: service,
+		}, cfg, installation, serverClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reconciler := federationReconciler(installation)
+	reconciler.Logger = logrus.NewEntry(logrus.StandardLogger())
+	phase, err := reconciler.reconcileBlackboxProbes(context.TODO(), serverClient)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcileBlackboxProbes() = %s, %v", phase, err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: cfg.GetBlackboxExporterConfigMapName(), Namespace: mockMonitoringOperatorNamespace}, configMap); err != nil {
+		t.Fatal(err)
+	}
+	blackboxConfig := struct {
+		Modules map[string]struct {
+			HTTP struct {
+				BearerTokenFile string `yaml:"bearer_token_file"`
+				TLSConfig       struct {
+					InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
+				} `yaml:"tls_config"`
+			} `yaml:"http"`
+		} `yaml:"modules"`
+	}{}
+	if err := yaml.Unmarshal([]byte(configMap.Data[cfg.GetBlackboxExporterConfigMapKey()]), &blackboxConfig); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := blackboxConfig.Modules[defaultBlackboxModule]; !ok {
+		t.Errorf("expected the default module to be kept")
+	}
+	if _, ok := blackboxConfig.Modules["rhmi_stale"]; ok {
+		t.Errorf("expected the stale module to be removed")
+	}
+	module, ok := blackboxConfig.Modules["rhmi_3scale-admin-ui"]
+	if !ok || !module.HTTP.TLSConfig.InsecureSkipVerify || module.HTTP.BearerTokenFile != blackboxSecretPath+"/3scale-admin-ui-token" {
+		t.Errorf("unexpected 3scale-admin-ui module %+v", module)
+	}
+	if strings.Contains(configMap.Data[cfg.GetBlackboxExporterConfigMapKey()], "test") {
+		t.Errorf("expected the token not to be stored in the blackbox exporter config")
+	}
+
+	secret := &corev1.Secret{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: blackboxSecretName, Namespace: mockMonitoringOperatorNamespace}, secret); err != nil {
+		t.Fatalf("expected the probe credentials secret: %v", err)
+	}
+	if string(secret.Data["3scale-admin-ui-token"]) != "test" {
+		t.Errorf("unexpected probe credentials %v", secret.Data)
+	}
+	prom := &prometheusmonitoringv1.Prometheus{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: middlewarePrometheusName, Namespace: mockMonitoringOperatorNamespace}, prom); err != nil {
+		t.Fatal(err)
+	}
+	mounts := prom.Spec.Containers[0].VolumeMounts
+	if len(prom.Spec.Secrets) != 1 || len(mounts) != 1 || mounts[0].Name != blackboxSecretVolumeName || mounts[0].MountPath != blackboxSecretPath {
+		t.Errorf("expected the probe credentials to be mounted in the blackbox exporter, got secrets %v and mounts %v", prom.Spec.Secrets, mounts)
+	}
+
+	rule := &prometheusmonitoringv1.PrometheusRule{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: endpointSLORuleName, Namespace: mockMonitoringOperatorNamespace}, rule); err != nil {
+		t.Fatal(err)
+	}
+	alerts := map[string]int{}
+	for _, r := range rule.Spec.Groups[0].Rules {
+		if r.Alert == "" {
+			continue
+		}
+		alerts[r.Labels["service"]]++
+		if r.Labels["service"] == "3scale-admin-ui" && !strings.Contains(r.Expr.String(), "(1 - 99.5 / 100)") {
+			t.Errorf("expected alert to use the availability target, got %s", r.Expr.String())
+		}
+		if r.Labels["service"] == "rhsso-ui" && !strings.Contains(r.Expr.String(), "(1 - 99.9 / 100)") {
+			t.Errorf("expected alert to use the default availability target, got %s", r.Expr.String())
+		}
+	}
+	if alerts["3scale-admin-ui"] != len(burnRateAlerts) || alerts["rhsso-ui"] != len(burnRateAlerts) {
+		t.Errorf("expected burn rate alerts for each endpoint, got %v", alerts)
+	}
+
+	installation.Spec.Monitoring.Probes["3scale-admin-ui"] = integreatlyv1alpha1.ProbeSpec{InsecureSkipVerify: true}
+	if err := CreateBlackboxTarget(context.TODO(), "integreatly-3scale-admin-ui", monitoringv1.BlackboxtargetData{
+		Url:     "https://3scale-admin-ui.example.com",
+		Service: "3scale-admin-ui",
+	}, cfg, installation, serverClient); err != nil {
+		t.Fatal(err)
+	}
+	phase, err = reconciler.reconcileBlackboxProbes(context.TODO(), serverClient)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcileBlackboxProbes() = %s, %v", phase, err)
+	}
+	err = serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: blackboxSecretName, Namespace: mockMonitoringOperatorNamespace}, &corev1.Secret{})
+	if !k8serr.IsNotFound(err) {
+		t.Errorf("expected the probe credentials secret to be deleted, got %v", err)
+	}
+	prom = &prometheusmonitoringv1.Prometheus{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: middlewarePrometheusName, Namespace: mockMonitoringOperatorNamespace}, prom); err != nil {
+		t.Fatal(err)
+	}
+	if len(prom.Spec.Secrets) != 0 || len(prom.Spec.Containers[0].VolumeMounts) != 0 {
+		t.Errorf("expected the probe credentials to be unmounted, got secrets %v and mounts %v", prom.Spec.Secrets, prom.Spec.Containers[0].VolumeMounts)
+	}
+}
@@ -0,0 +1,221 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	prometheus "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/owner"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	defaultFederationMatch = `{__name__="ALERTS",alertstate="firing"}`
+	// recordingRulesRegex matches the names of the recording rules series,
+	// which contain a colon, and of the alerts
+	recordingRulesRegex = "ALERTS|.+:.+"
+
+	middlewarePrometheusName = "application-monitoring"
+	remoteWriteSecretName    = "rhmi-remote-write"
+	// remoteWriteSecretPath is where the secrets listed in the Prometheus
+	// spec are mounted
+	remoteWriteSecretPath = "/etc/prometheus/secrets/" + remoteWriteSecretName
+)
+
+func (r *Reconciler) getFederationSpec() *integreatlyv1alpha1.FederationSpec {
+	if r.installation.Spec.Monitoring == nil || r.installation.Spec.Monitoring.Federation == nil {
+		return &integreatlyv1alpha1.FederationSpec{}
+	}
+	return r.installation.Spec.Monitoring.Federation
+}
+
+func (r *Reconciler) getRemoteWriteSpec() *integreatlyv1alpha1.RemoteWriteSpec {
+	if r.installation.Spec.Monitoring == nil {
+		return nil
+	}
+	return r.installation.Spec.Monitoring.RemoteWrite
+}
+
+// federationMatches returns the series selectors of the metrics federated
+// into the cluster monitoring
+func federationMatches(federation *integreatlyv1alpha1.FederationSpec) []string {
+	if len(federation.Matches) == 0 {
+		return []string{defaultFederationMatch}
+	}
+	return federation.Matches
+}
+
+// relabelConfigs returns the relabel configs that keep the series selected
+// by series, followed by the label rewrites
+func relabelConfigs(series integreatlyv1alpha1.MetricsSeries, rewrites []integreatlyv1alpha1.LabelRewrite) []prometheus.RelabelConfig {
+	var result []prometheus.RelabelConfig
+	if series == integreatlyv1alpha1.MetricsSeriesRecordingRules {
+		result = append(result, prometheus.RelabelConfig{
+			SourceLabels: []string{"__name__"},
+			Regex:        recordingRulesRegex,
+			Action:       "keep",
+		})
+	}
+
+	for _, rewrite := range rewrites {
+		result = append(result, prometheus.RelabelConfig{
+			SourceLabels: rewrite.SourceLabels,
+			Regex:        rewrite.Regex,
+			TargetLabel:  rewrite.TargetLabel,
+			Replacement:  rewrite.Replacement,
+			Action:       rewrite.Action,
+		})
+	}
+
+	return result
+}
+
+func federationMetricRelabelConfigs(federation *integreatlyv1alpha1.FederationSpec) []*prometheus.RelabelConfig {
+	var result []*prometheus.RelabelConfig
+	for _, config := range relabelConfigs(federation.Series, federation.LabelRewrites) {
+		config := config
+		result = append(result, &config)
+	}
+	return result
+}
+
+// reconcileRemoteWrite sets the remote write endpoint of the middleware
+// monitoring Prometheus, or removes it when it isn't configured anymore
+func (r *Reconciler) reconcileRemoteWrite(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	remoteWrite := r.getRemoteWriteSpec()
+
+	prom := &prometheus.Prometheus{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: middlewarePrometheusName, Namespace: r.Config.GetOperatorNamespace()}, prom); err != nil {
+		if k8serr.IsNotFound(err) {
+			if remoteWrite == nil {
+				return integreatlyv1alpha1.PhaseCompleted, nil
+			}
+			return integreatlyv1alpha1.PhaseAwaitingComponents, nil
+		}
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get %s prometheus: %w", middlewarePrometheusName, err)
+	}
+
+	if remoteWrite == nil {
+		if len(prom.Spec.RemoteWrite) > 0 || resources.Contains(prom.Spec.Secrets, remoteWriteSecretName) {
+			prom.Spec.RemoteWrite = nil
+			prom.Spec.Secrets = resources.Remove(prom.Spec.Secrets, remoteWriteSecretName)
+			if err := serverClient.Update(ctx, prom); err != nil {
+				return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to remove remote write from %s prometheus: %w", middlewarePrometheusName, err)
+			}
+		}
+
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: remoteWriteSecretName, Namespace: r.Config.GetOperatorNamespace()}}
+		if err := serverClient.Delete(ctx, secret); err != nil && !k8serr.IsNotFound(err) {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to delete %s secret: %w", remoteWriteSecretName, err)
+		}
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	series := remoteWrite.Series
+	if series == "" {
+		series = integreatlyv1alpha1.MetricsSeriesRecordingRules
+	}
+	remoteWriteSpec := prometheus.RemoteWriteSpec{
+		URL:                 remoteWrite.URL,
+		WriteRelabelConfigs: relabelConfigs(series, remoteWrite.LabelRewrites),
+	}
+
+	if remoteWrite.SecretRef != "" {
+		secret, err := r.copyRemoteWriteSecret(ctx, serverClient, remoteWrite.SecretRef)
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+
+		switch {
+		case len(secret.Data["username"]) > 0 && len(secret.Data["password"]) > 0:
+			remoteWriteSpec.BasicAuth = &prometheus.BasicAuth{
+				Username: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: remoteWriteSecretName}, Key: "username"},
+				Password: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: remoteWriteSecretName}, Key: "password"},
+			}
+		case len(secret.Data["token"]) > 0:
+			remoteWriteSpec.BearerTokenFile = remoteWriteSecretPath + "/token"
+		default:
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("secret %s has neither a username and password nor a token", remoteWrite.SecretRef)
+		}
+		if len(secret.Data["ca.crt"]) > 0 {
+			remoteWriteSpec.TLSConfig = &prometheus.TLSConfig{CAFile: remoteWriteSecretPath + "/ca.crt"}
+		}
+	}
+
+	// The prometheus is only updated when its remote write changed, as every
+	// update is reconciled by the prometheus operator
+	secretMounted := resources.Contains(prom.Spec.Secrets, remoteWriteSecretName)
+	desiredRemoteWrite := []prometheus.RemoteWriteSpec{remoteWriteSpec}
+	if secretMounted == (remoteWrite.SecretRef != "") && reflect.DeepEqual(prom.Spec.RemoteWrite, desiredRemoteWrite) {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	if remoteWrite.SecretRef != "" && !secretMounted {
+		prom.Spec.Secrets = append(prom.Spec.Secrets, remoteWriteSecretName)
+	} else if remoteWrite.SecretRef == "" {
+		prom.Spec.Secrets = resources.Remove(prom.Spec.Secrets, remoteWriteSecretName)
+	}
+	prom.Spec.RemoteWrite = desiredRemoteWrite
+	if err := serverClient.Update(ctx, prom); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to set remote write of %s prometheus: %w", middlewarePrometheusName, err)
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+// reconcilePodMonitorSelector makes the middleware monitoring Prometheus select
+// the pod monitors the same way as the service monitors, as it's created
+// without a pod monitor selector and so ignores the cloned pod monitors
+func (r *Reconciler) reconcilePodMonitorSelector(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	prom := &prometheus.Prometheus{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: middlewarePrometheusName, Namespace: r.Config.GetOperatorNamespace()}, prom); err != nil {
+		if k8serr.IsNotFound(err) {
+			return integreatlyv1alpha1.PhaseAwaitingComponents, nil
+		}
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get %s prometheus: %w", middlewarePrometheusName, err)
+	}
+
+	if prom.Spec.PodMonitorSelector != nil || prom.Spec.ServiceMonitorSelector == nil {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+	prom.Spec.PodMonitorSelector = prom.Spec.ServiceMonitorSelector.DeepCopy()
+	prom.Spec.PodMonitorNamespaceSelector = prom.Spec.ServiceMonitorNamespaceSelector.DeepCopy()
+	if err := serverClient.Update(ctx, prom); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to set pod monitor selector of %s prometheus: %w", middlewarePrometheusName, err)
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+// copyRemoteWriteSecret copies the secret with the remote write credentials
+// from the installation namespace to the namespace of the Prometheus, which
+// can only mount the secrets of its namespace
+func (r *Reconciler) copyRemoteWriteSecret(ctx context.Context, serverClient k8sclient.Client, secretRef string) (*corev1.Secret, error) {
+	source := &corev1.Secret{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: secretRef, Namespace: r.installation.Namespace}, source); err != nil {
+		return nil, fmt.Errorf("failed to get remote write secret %s: %w", secretRef, err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      remoteWriteSecretName,
+			Namespace: r.Config.GetOperatorNamespace(),
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, serverClient, secret, func() error {
+		owner.AddIntegreatlyOwnerAnnotations(secret, r.installation)
+		secret.Data = source.Data
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to copy remote write secret %s: %w", secretRef, err)
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,212 @@
+package monitoring
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	prometheusmonitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	mockMonitoringOperatorNamespace = "redhat-rhmi-middleware-monitoring-operator"
+	mockFederationNamespace         = "redhat-rhmi-middleware-monitoring-federate"
+	mockRemoteWriteSecretName       = "test-remote-write"
+)
+
+func federationReconciler(installation *integreatlyv1alpha1.RHMI) *Reconciler {
+	return &Reconciler{
+		installation: installation,
+		Logger:       logrus.NewEntry(logrus.StandardLogger()),
+		Config: &config.Monitoring{
+			Config: map[string]string{
+				"OPERATOR_NAMESPACE":   mockMonitoringOperatorNamespace,
+				"FEDERATION_NAMESPACE": mockFederationNamespace,
+			},
+		},
+	}
+}
+
+func TestReconciler_reconcileFederation(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		Name                 string
+		Federation           *integreatlyv1alpha1.FederationSpec
+		ExpectedMatches      []string
+		ExpectedRelabelCount int
+	}{
+		{
+			Name:            "federates the firing alerts by default",
+			ExpectedMatches: []string{defaultFederationMatch},
+		},
+		{
+			Name: "federates the selected recording rules with label rewrites",
+			Federation: &integreatlyv1alpha1.FederationSpec{
+				Matches: []string{`{namespace=~"redhat-rhmi-.*"}`},
+				Series:  integreatlyv1alpha1.MetricsSeriesRecordingRules,
+				LabelRewrites: []integreatlyv1alpha1.LabelRewrite{
+					{Action: "labeldrop", Regex: "pod"},
+				},
+			},
+			ExpectedMatches:      []string{`{namespace=~"redhat-rhmi-.*"}`},
+			ExpectedRelabelCount: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			installation := basicInstallation()
+			if tc.Federation != nil {
+				installation.Spec.Monitoring = &integreatlyv1alpha1.MonitoringSpec{Federation: tc.Federation}
+			}
+			serverClient := fakeclient.NewFakeClientWithScheme(scheme)
+
+			phase, err := federationReconciler(installation).reconcileFederation(context.TODO(), serverClient)
+			if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+				t.Fatalf("reconcileFederation() = %s, %v", phase, err)
+			}
+
+			serviceMonitor := &prometheusmonitoringv1.ServiceMonitor{}
+			if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: federationServiceMonitorName, Namespace: mockFederationNamespace}, serviceMonitor); err != nil {
+				t.Fatal(err)
+			}
+			endpoint := serviceMonitor.Spec.Endpoints[0]
+			if !reflect.DeepEqual(endpoint.Params["match[]"], tc.ExpectedMatches) {
+				t.Errorf("expected matches %v, got %v", tc.ExpectedMatches, endpoint.Params["match[]"])
+			}
+			if len(endpoint.MetricRelabelConfigs) != tc.ExpectedRelabelCount {
+				t.Errorf("expected %d relabel configs, got %d", tc.ExpectedRelabelCount, len(endpoint.MetricRelabelConfigs))
+			}
+		})
+	}
+}
+
+func TestReconciler_reconcileRemoteWrite(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installation := basicInstallation()
+	installation.Spec.Monitoring = &integreatlyv1alpha1.MonitoringSpec{
+		RemoteWrite: &integreatlyv1alpha1.RemoteWriteSpec{
+			URL:       "https://thanos.example.com/api/v1/receive",
+			SecretRef: mockRemoteWriteSecretName,
+		},
+	}
+	serverClient := fakeclient.NewFakeClientWithScheme(scheme,
+		&prometheusmonitoringv1.Prometheus{
+			ObjectMeta: metav1.ObjectMeta{Name: middlewarePrometheusName, Namespace: mockMonitoringOperatorNamespace},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: mockRemoteWriteSecretName, Namespace: installation.Namespace},
+			Data: map[string][]byte{
+				"token":  []byte("test"),
+				"ca.crt": []byte("test"),
+			},
+		},
+	)
+	reconciler := federationReconciler(installation)
+
+	getPrometheus := func() *prometheusmonitoringv1.Prometheus {
+		prom := &prometheusmonitoringv1.Prometheus{}
+		if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: middlewarePrometheusName, Namespace: mockMonitoringOperatorNamespace}, prom); err != nil {
+			t.Fatal(err)
+		}
+		return prom
+	}
+
+	phase, err := reconciler.reconcileRemoteWrite(context.TODO(), serverClient)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcileRemoteWrite() = %s, %v", phase, err)
+	}
+
+	prom := getPrometheus()
+	if len(prom.Spec.RemoteWrite) != 1 {
+		t.Fatalf("expected a single remote write, got %v", prom.Spec.RemoteWrite)
+	}
+	remoteWrite := prom.Spec.RemoteWrite[0]
+	if remoteWrite.BearerTokenFile != remoteWriteSecretPath+"/token" || remoteWrite.BasicAuth != nil {
+		t.Errorf("expected remote write to use the bearer token, got %v", remoteWrite)
+	}
+	if remoteWrite.TLSConfig == nil || remoteWrite.TLSConfig.CAFile != remoteWriteSecretPath+"/ca.crt" {
+		t.Errorf("expected remote write to use the CA, got %v", remoteWrite.TLSConfig)
+	}
+	if len(remoteWrite.WriteRelabelConfigs) != 1 || remoteWrite.WriteRelabelConfigs[0].Regex != recordingRulesRegex {
+		t.Errorf("expected only the recording rules to be written, got %v", remoteWrite.WriteRelabelConfigs)
+	}
+	if !reflect.DeepEqual(prom.Spec.Secrets, []string{remoteWriteSecretName}) {
+		t.Errorf("expected the remote write secret to be mounted, got %v", prom.Spec.Secrets)
+	}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: remoteWriteSecretName, Namespace: mockMonitoringOperatorNamespace}, &corev1.Secret{}); err != nil {
+		t.Errorf("expected the remote write secret to be copied: %v", err)
+	}
+
+	phase, err = reconciler.reconcileRemoteWrite(context.TODO(), serverClient)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcileRemoteWrite() = %s, %v", phase, err)
+	}
+	if resourceVersion := getPrometheus().ResourceVersion; resourceVersion != prom.ResourceVersion {
+		t.Errorf("expected an unchanged remote write not to update the prometheus, got resource version %s instead of %s", resourceVersion, prom.ResourceVersion)
+	}
+
+	installation.Spec.Monitoring = nil
+	phase, err = reconciler.reconcileRemoteWrite(context.TODO(), serverClient)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcileRemoteWrite() = %s, %v", phase, err)
+	}
+
+	prom = getPrometheus()
+	if len(prom.Spec.RemoteWrite) != 0 || len(prom.Spec.Secrets) != 0 {
+		t.Errorf("expected remote write to be removed, got %v and secrets %v", prom.Spec.RemoteWrite, prom.Spec.Secrets)
+	}
+	err = serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: remoteWriteSecretName, Namespace: mockMonitoringOperatorNamespace}, &corev1.Secret{})
+	if !k8serr.IsNotFound(err) {
+		t.Errorf("expected the remote write secret to be deleted, got %v", err)
+	}
+}
+
+func TestReconciler_reconcilePodMonitorSelector(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"monitoring-key": "middleware"}}
+	namespaceSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"monitoring-key": "middleware"}}
+	serverClient := fakeclient.NewFakeClientWithScheme(scheme,
+		&prometheusmonitoringv1.Prometheus{
+			ObjectMeta: metav1.ObjectMeta{Name: middlewarePrometheusName, Namespace: mockMonitoringOperatorNamespace},
+			Spec: prometheusmonitoringv1.PrometheusSpec{
+				ServiceMonitorSelector:          selector,
+				ServiceMonitorNamespaceSelector: namespaceSelector,
+			},
+		},
+	)
+	reconciler := federationReconciler(basicInstallation())
+
+	phase, err := reconciler.reconcilePodMonitorSelector(context.TODO(), serverClient)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcilePodMonitorSelector() = %s, %v", phase, err)
+	}
+
+	prom := &prometheusmonitoringv1.Prometheus{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: middlewarePrometheusName, Namespace: mockMonitoringOperatorNamespace}, prom); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(prom.Spec.PodMonitorSelector, selector) || !reflect.DeepEqual(prom.Spec.PodMonitorNamespaceSelector, namespaceSelector) {
+		t.Errorf("expected the pod monitors to be selected as the service monitors, got %v and %v", prom.Spec.PodMonitorSelector, prom.Spec.PodMonitorNamespaceSelector)
+	}
+}
@@ -279,6 +279,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
+	phase, err = r.reconcileRemoteWrite(ctx, serverClient)
+	logrus.Infof("Phase: %s reconcileRemoteWrite", phase)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		events.HandleError(r.recorder, installation, phase, "Failed to reconcile remote write", err)
+		return phase, err
+	}
+
+	phase, err = r.reconcilePodMonitorSelector(ctx, serverClient)
+	logrus.Infof("Phase: %s reconcilePodMonitorSelector", phase)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		events.HandleError(r.recorder, installation, phase, "Failed to reconcile pod monitor selector", err)
+		return phase, err
+	}
+
 	phase, err = r.newAlertsReconciler().ReconcileAlerts(ctx, serverClient)
 	logrus.Infof("Phase: %s reconcilePrometheusRule", phase)
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
@@ -286,6 +300,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
+	phase, err = r.reconcileBlackboxProbes(ctx, serverClient)
+	logrus.Infof("Phase: %s reconcileBlackboxProbes", phase)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		events.HandleError(r.recorder, installation, phase, "Failed to reconcile blackbox probes", err)
+		return phase, err
+	}
+
 	// creates an alert to check for the presents of sendgrid smtp secret
 	phase, err = resources.CreateSmtpSecretExists(ctx, serverClient, installation)
 	logrus.Infof("Phase: %s CreateSmtpSecretExistsRule", phase)
@@ -367,9 +388,11 @@ func (r *Reconciler) reconcilePodPriority(ctx context.Context, serverClient k8sc
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 */
-// Creates a service monitor that federates metrics about alerts to the cluster
-// monitoring stack
+// Creates a service monitor that federates the metrics selected in the
+// installation, the firing alerts by default, to the cluster monitoring stack
 func (r *Reconciler) reconcileFederation(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	federation := r.getFederationSpec()
+
 	serviceMonitor := &prometheus.ServiceMonitor{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      federationServiceMonitorName,
@@ -389,11 +412,12 @@ func (r *Reconciler) reconcileFederation(ctx context.Context, serverClient k8scl
 					Path:   "/federate",
 					Scheme: "http",
 					Params: map[string][]string{
-						"match[]": []string{"{__name__=\"ALERTS\",alertstate=\"firing\"}"},
+						"match[]": federationMatches(federation),
 					},
-					Interval:      "30s",
-					ScrapeTimeout: "30s",
-					HonorLabels:   true,
+					Interval:             "30s",
+					ScrapeTimeout:        "30s",
+					HonorLabels:          true,
+					MetricRelabelConfigs: federationMetricRelabelConfigs(federation),
 				},
 			},
 			Selector: metav1.LabelSelector{
@@ -472,6 +496,16 @@ func (r *Reconciler) reconcileScrapeConfigs(ctx context.Context, serverClient k8
 		jobs.WriteByte('\n')
 	}
 
+	probes, err := r.getBlackboxProbes(ctx, serverClient)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+	blackboxJobs, err := r.getBlackboxJobs(probes)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+	jobs.Write(blackboxJobs)
+
 	scrapeConfigSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      r.Config.GetAdditionalScrapeConfigSecretName(),
@@ -753,54 +787,10 @@ func (r *Reconciler) reconcileAlertManagerConfigSecret(ctx context.Context, serv
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
+// CreateBlackboxTarget probes a product endpoint with the default probe
+// settings, unless they are overridden in the installation
 func CreateBlackboxTarget(ctx context.Context, name string, target monitoring.BlackboxtargetData, cfg *config.Monitoring, installation *integreatlyv1alpha1.RHMI, serverClient k8sclient.Client) error {
-	if cfg.GetOperatorNamespace() == "" {
-		// Retry later
-		return nil
-	}
-
-	if target.Url == "" {
-		// Retry later if the URL is not yet known
-		return nil
-	}
-
-	// default policy is to require a 2xx http return code
-	module := target.Module
-	if module == "" {
-		module = defaultBlackboxModule
-	}
-
-	// prepare the template
-	extraParams := map[string]string{
-		"Namespace":     cfg.GetOperatorNamespace(),
-		"MonitoringKey": cfg.GetLabelSelector(),
-		"name":          name,
-		"url":           target.Url,
-		"service":       target.Service,
-		"module":        module,
-	}
-
-	templateHelper := NewTemplateHelper(extraParams)
-	obj, err := templateHelper.CreateResource("blackbox/target.yaml")
-	if err != nil {
-		return fmt.Errorf("error creating resource from template: %w", err)
-	}
-
-	metaObj, err := meta.Accessor(obj)
-	if err == nil {
-		owner.AddIntegreatlyOwnerAnnotations(metaObj, installation)
-	}
-	// try to create the blackbox target. If if fails with already exist do nothing
-	err = serverClient.Create(ctx, obj)
-	if err != nil {
-		if k8serr.IsAlreadyExists(err) {
-			// The target already exists. Nothing else to do
-			return nil
-		}
-		return fmt.Errorf("error creating blackbox target: %w", err)
-	}
-
-	return nil
+	return CreateBlackboxProbe(ctx, name, target, integreatlyv1alpha1.ProbeSpec{}, cfg, installation, serverClient)
 }
 
 func (r *Reconciler) reconcileSubscription(ctx context.Context, serverClient k8sclient.Client, inst *integreatlyv1alpha1.RHMI, productNamespace string, operatorNamespace string) (integreatlyv1alpha1.StatusPhase, error) {
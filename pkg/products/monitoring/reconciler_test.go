@@ -365,6 +365,13 @@ func TestReconciler_fullReconcile(t *testing.T) {
 		},
 	}
 
+	prometheus := &prometheusmonitoringv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      middlewarePrometheusName,
+			Namespace: defaultInstallationNamespace,
+		},
+	}
+
 	cases := []struct {
 		Name           string
 		ExpectError    bool
@@ -382,7 +389,7 @@ func TestReconciler_fullReconcile(t *testing.T) {
 			ExpectedStatus: integreatlyv1alpha1.PhaseCompleted,
 			FakeClient: moqclient.NewSigsClientMoqWithScheme(scheme, ns, operatorNS, federationNs,
 				grafanadatasourcesecret, installation, smtpSecret, pagerdutySecret,
-				dmsSecret, alertmanagerRoute),
+				dmsSecret, alertmanagerRoute, prometheus),
 			FakeConfig: &config.ConfigReadWriterMock{
 				ReadMonitoringFunc: func() (ready *config.Monitoring, e error) {
 					return config.NewMonitoring(config.ProductConfig{
@@ -0,0 +1,93 @@
+package monitoringspec
+
+import (
+	"fmt"
+	"strconv"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/prometheus/common/model"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// cloneAnnotation excludes a namespace or a monitor from the cloning
+	// when set to "false"
+	cloneAnnotation = "monitoring.integreatly.org/clone"
+	// scrapeIntervalAnnotation and sampleLimitAnnotation override the scrape
+	// interval and sample limit of the clones of a monitor, or of all the
+	// monitors of a namespace
+	scrapeIntervalAnnotation = "monitoring.integreatly.org/scrape-interval"
+	sampleLimitAnnotation    = "monitoring.integreatly.org/sample-limit"
+)
+
+// cloningPolicy selects the namespaces and monitors that are cloned into the
+// monitoring namespace
+type cloningPolicy struct {
+	namespaceSelector labels.Selector
+	monitorSelector   labels.Selector
+}
+
+// scrapeOverrides are applied to the endpoints of a clone. The zero values
+// leave the source monitor as is
+type scrapeOverrides struct {
+	interval    string
+	sampleLimit uint64
+}
+
+func newCloningPolicy(installation *integreatlyv1alpha1.RHMI) (*cloningPolicy, error) {
+	policy := &cloningPolicy{
+		namespaceSelector: labels.Everything(),
+		monitorSelector:   labels.Everything(),
+	}
+	if installation.Spec.Monitoring == nil || installation.Spec.Monitoring.MonitorCloning == nil {
+		return policy, nil
+	}
+
+	cloning := installation.Spec.Monitoring.MonitorCloning
+	var err error
+	if cloning.NamespaceSelector != nil {
+		if policy.namespaceSelector, err = metav1.LabelSelectorAsSelector(cloning.NamespaceSelector); err != nil {
+			return nil, fmt.Errorf("invalid monitor cloning namespace selector: %w", err)
+		}
+	}
+	if cloning.MonitorSelector != nil {
+		if policy.monitorSelector, err = metav1.LabelSelectorAsSelector(cloning.MonitorSelector); err != nil {
+			return nil, fmt.Errorf("invalid monitor cloning monitor selector: %w", err)
+		}
+	}
+	return policy, nil
+}
+
+func (p *cloningPolicy) includesNamespace(namespace metav1.ObjectMeta) bool {
+	return namespace.Annotations[cloneAnnotation] != "false" && p.namespaceSelector.Matches(labels.Set(namespace.Labels))
+}
+
+func (p *cloningPolicy) includesMonitor(monitor metav1.ObjectMeta) bool {
+	return monitor.Annotations[cloneAnnotation] != "false" && p.monitorSelector.Matches(labels.Set(monitor.Labels))
+}
+
+// overrides returns the scrape overrides of a monitor, whose annotations take
+// precedence over the annotations of its namespace. Invalid annotations are
+// ignored so a typo doesn't stop the monitors from being cloned
+func (p *cloningPolicy) overrides(namespace, monitor metav1.ObjectMeta) scrapeOverrides {
+	result := scrapeOverrides{}
+	for _, meta := range []metav1.ObjectMeta{namespace, monitor} {
+		if interval, ok := meta.Annotations[scrapeIntervalAnnotation]; ok {
+			if _, err := model.ParseDuration(interval); err != nil {
+				logrus.Warnf("ignoring invalid %s annotation of %s: %v", scrapeIntervalAnnotation, meta.Name, err)
+			} else {
+				result.interval = interval
+			}
+		}
+		if sampleLimit, ok := meta.Annotations[sampleLimitAnnotation]; ok {
+			if limit, err := strconv.ParseUint(sampleLimit, 10, 64); err != nil {
+				logrus.Warnf("ignoring invalid %s annotation of %s: %v", sampleLimitAnnotation, meta.Name, err)
+			} else {
+				result.sampleLimit = limit
+			}
+		}
+	}
+	return result
+}
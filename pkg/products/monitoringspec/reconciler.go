@@ -3,6 +3,7 @@ package monitoringspec
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/events"
@@ -38,6 +39,7 @@ const (
 	labelSelector                             = "monitoring-key=middleware"
 	clonedServiceMonitorLabelKey              = "integreatly.org/cloned-servicemonitor"
 	clonedServiceMonitorLabelValue            = "true"
+	clonedPodMonitorLabelKey                  = "integreatly.org/cloned-podmonitor"
 )
 
 type Reconciler struct {
@@ -173,6 +175,11 @@ func (r *Reconciler) createNamespace(ctx context.Context, serverClient k8sclient
 func (r *Reconciler) reconcileMonitoring(ctx context.Context, serverClient k8sclient.Client,
 	installation *integreatlyv1alpha1.RHMI) (integreatlyv1alpha1.StatusPhase, error) {
 
+	policy, err := newCloningPolicy(installation)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
 	//Get list of service monitors in the namespace that has
 	//label "integreatly.org/cloned-servicemonitor" set to "true"
 	listOpts := []k8sclient.ListOption{
@@ -186,13 +193,30 @@ func (r *Reconciler) reconcileMonitoring(ctx context.Context, serverClient k8scl
 		return integreatlyv1alpha1.PhaseFailed, err
 	}
 
+	//Get list of pod monitors in the monitoring namespace
+	monPodmonMap, err := r.getPodMonitors(ctx, serverClient, []k8sclient.ListOption{
+		k8sclient.InNamespace(r.Config.GetNamespace()),
+		k8sclient.MatchingLabels(getClonedPodMonitorLabel()),
+	})
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
 	//Get the list of namespaces with the given label selector "monitoring-key=middleware"
 	namespaces, err := r.getMWMonitoredNamespaces(ctx, serverClient)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, err
 	}
 
+	clonedMonitors := []integreatlyv1alpha1.ClonedMonitor{}
+	//Namespaces scraped by the clones, that need the rolebindings
+	monitoredNamespaces := map[string]bool{}
+
 	for _, ns := range namespaces.Items {
+		if !policy.includesNamespace(ns.ObjectMeta) {
+			continue
+		}
+
 		//Get list of service monitors in each name space
 		listOpts := []k8sclient.ListOption{
 			k8sclient.InNamespace(ns.Name),
@@ -202,48 +226,107 @@ func (r *Reconciler) reconcileMonitoring(ctx context.Context, serverClient k8scl
 			return integreatlyv1alpha1.PhaseFailed, err
 		}
 		for _, sm := range serviceMonitorsMap {
+			if !policy.includesMonitor(sm.ObjectMeta) {
+				continue
+			}
 			//Create a copy of service monitors in the monitoring namespace
 			//Create the corresponding rolebindings at each of the service namespace
-			key := sm.Namespace + `-` + sm.Name
-			delete(monSermonMap, key) // Servicemonitor exists, remove it from the local map
-			err := r.reconcileServiceMonitor(ctx, serverClient, sm)
+			clone, err := r.reconcileServiceMonitor(ctx, serverClient, sm, policy.overrides(ns.ObjectMeta, sm.ObjectMeta))
 			if err != nil {
 				return integreatlyv1alpha1.PhaseFailed, err
 			}
-			err = r.reconcileRoleBindingsForServiceMonitor(ctx, serverClient, key)
+			if clone == nil {
+				continue
+			}
+			delete(monSermonMap, clone.Name) // Servicemonitor exists, remove it from the local map
+			err = r.reconcileRoleBindings(ctx, serverClient, clone.Spec.NamespaceSelector.MatchNames, monitoredNamespaces)
 			if err != nil {
 				return integreatlyv1alpha1.PhaseFailed, err
 			}
+			clonedMonitors = append(clonedMonitors, integreatlyv1alpha1.ClonedMonitor{
+				Kind:   monitoringv1.ServiceMonitorsKind,
+				Name:   clone.Name,
+				Source: sm.Namespace + "/" + sm.Name,
+			})
 		}
-	}
 
-	//Clean-up the stale service monitors and rolebindings if any
-	if len(monSermonMap) > 0 {
-		for _, sm := range monSermonMap {
-			//Remove servicemonitor
-			err = r.removeServiceMonitor(ctx, serverClient, sm.Namespace, sm.Name)
+		podMonitorsMap, err := r.getPodMonitors(ctx, serverClient, listOpts)
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+		for _, pm := range podMonitorsMap {
+			if !policy.includesMonitor(pm.ObjectMeta) {
+				continue
+			}
+			clone, err := r.reconcilePodMonitor(ctx, serverClient, pm, policy.overrides(ns.ObjectMeta, pm.ObjectMeta))
 			if err != nil {
 				return integreatlyv1alpha1.PhaseFailed, err
 			}
-			//Remove rolebindings
-			for _, namespace := range sm.Spec.NamespaceSelector.MatchNames {
-				err := r.removeRoleandRoleBinding(ctx, serverClient, namespace, roleRefName, roleBindingName)
-				if err != nil {
-					return integreatlyv1alpha1.PhaseFailed, err
-				}
+			if clone == nil {
+				continue
+			}
+			delete(monPodmonMap, clone.Name)
+			err = r.reconcileRoleBindings(ctx, serverClient, clone.Spec.NamespaceSelector.MatchNames, monitoredNamespaces)
+			if err != nil {
+				return integreatlyv1alpha1.PhaseFailed, err
 			}
+			clonedMonitors = append(clonedMonitors, integreatlyv1alpha1.ClonedMonitor{
+				Kind:   monitoringv1.PodMonitorsKind,
+				Name:   clone.Name,
+				Source: pm.Namespace + "/" + pm.Name,
+			})
 		}
 	}
+
+	//Clean-up the stale service monitors, pod monitors and rolebindings if any
+	var staleNamespaces []string
+	for _, sm := range monSermonMap {
+		//Remove servicemonitor
+		err = r.removeMonitor(ctx, serverClient, sm)
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+		staleNamespaces = append(staleNamespaces, sm.Spec.NamespaceSelector.MatchNames...)
+	}
+	for _, pm := range monPodmonMap {
+		//Remove podmonitor
+		err = r.removeMonitor(ctx, serverClient, pm)
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+		staleNamespaces = append(staleNamespaces, pm.Spec.NamespaceSelector.MatchNames...)
+	}
+	//Remove rolebindings of the namespaces that aren't scraped by any clone
+	for _, namespace := range staleNamespaces {
+		if monitoredNamespaces[namespace] {
+			continue
+		}
+		err := r.removeRoleandRoleBinding(ctx, serverClient, namespace, roleRefName, roleBindingName)
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+	}
+
+	sort.Slice(clonedMonitors, func(i, j int) bool {
+		if clonedMonitors[i].Kind != clonedMonitors[j].Kind {
+			return clonedMonitors[i].Kind < clonedMonitors[j].Kind
+		}
+		return clonedMonitors[i].Name < clonedMonitors[j].Name
+	})
+	installation.Status.Monitoring = &integreatlyv1alpha1.RHMIMonitoringStatus{ClonedMonitors: clonedMonitors}
+
 	return integreatlyv1alpha1.PhaseCompleted, err
 }
 
-func (r *Reconciler) reconcileServiceMonitor(ctx context.Context,
-	serverClient k8sclient.Client, serviceMonitor *monitoringv1.ServiceMonitor) (err error) {
+// reconcileServiceMonitor clones a service monitor into the monitoring
+// namespace. It returns nil if the service monitor can't be cloned
+func (r *Reconciler) reconcileServiceMonitor(ctx context.Context, serverClient k8sclient.Client,
+	serviceMonitor *monitoringv1.ServiceMonitor, overrides scrapeOverrides) (*monitoringv1.ServiceMonitor, error) {
 
 	if serviceMonitor.Spec.NamespaceSelector.Any {
 		logrus.Warnf("servicemonitor : %s cannot be copied to %s namespace. Namespace selector has been set to any",
 			serviceMonitor.Name, r.Config.GetNamespace())
-		return nil
+		return nil, nil
 	}
 	sm := &monitoringv1.ServiceMonitor{
 		ObjectMeta: metav1.ObjectMeta{
@@ -254,37 +337,87 @@ func (r *Reconciler) reconcileServiceMonitor(ctx context.Context,
 	opRes, err := controllerutil.CreateOrUpdate(ctx, serverClient, sm, func() error {
 		// Check if the servicemonitor has no  namespace selectors defined,
 		// if not add the namespace
-		sm.Spec = serviceMonitor.Spec
+		sm.Spec = *serviceMonitor.Spec.DeepCopy()
 		if len(sm.Spec.NamespaceSelector.MatchNames) == 0 {
 			sm.Spec.NamespaceSelector.MatchNames = []string{serviceMonitor.Namespace}
 		}
+		for i := range sm.Spec.Endpoints {
+			if overrides.interval != "" {
+				sm.Spec.Endpoints[i].Interval = overrides.interval
+			}
+		}
+		if overrides.sampleLimit != 0 {
+			sm.Spec.SampleLimit = overrides.sampleLimit
+		}
 		//Add all the original labels and append cloned servicemonitor label
-		sm.Labels = serviceMonitor.Labels
-		if len(sm.Labels) == 0 {
-			sm.Labels = make(map[string]string)
+		sm.Labels = make(map[string]string, len(serviceMonitor.Labels)+1)
+		for key, value := range serviceMonitor.Labels {
+			sm.Labels[key] = value
 		}
 		sm.Labels[clonedServiceMonitorLabelKey] = clonedServiceMonitorLabelValue
 		return nil
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if opRes != controllerutil.OperationResultNone {
 		r.Logger.Infof("operation result of creating servicemonitor %v was %v", sm.Name, opRes)
 	}
-	return err
+	return sm, nil
 }
 
-func (r *Reconciler) reconcileRoleBindingsForServiceMonitor(ctx context.Context,
-	serverClient k8sclient.Client, serviceMonitorName string) (err error) {
-	//Get the service monitor - that was created/updated
-	sermon := &monitoringv1.ServiceMonitor{}
-	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: serviceMonitorName, Namespace: r.Config.GetNamespace()}, sermon)
+// reconcilePodMonitor clones a pod monitor into the monitoring namespace. It
+// returns nil if the pod monitor can't be cloned
+func (r *Reconciler) reconcilePodMonitor(ctx context.Context, serverClient k8sclient.Client,
+	podMonitor *monitoringv1.PodMonitor, overrides scrapeOverrides) (*monitoringv1.PodMonitor, error) {
+
+	if podMonitor.Spec.NamespaceSelector.Any {
+		logrus.Warnf("podmonitor : %s cannot be copied to %s namespace. Namespace selector has been set to any",
+			podMonitor.Name, r.Config.GetNamespace())
+		return nil, nil
+	}
+	pm := &monitoringv1.PodMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podMonitor.Namespace + `-` + podMonitor.Name,
+			Namespace: r.Config.GetNamespace(),
+		},
+	}
+	opRes, err := controllerutil.CreateOrUpdate(ctx, serverClient, pm, func() error {
+		pm.Spec = *podMonitor.Spec.DeepCopy()
+		if len(pm.Spec.NamespaceSelector.MatchNames) == 0 {
+			pm.Spec.NamespaceSelector.MatchNames = []string{podMonitor.Namespace}
+		}
+		for i := range pm.Spec.PodMetricsEndpoints {
+			if overrides.interval != "" {
+				pm.Spec.PodMetricsEndpoints[i].Interval = overrides.interval
+			}
+		}
+		if overrides.sampleLimit != 0 {
+			pm.Spec.SampleLimit = overrides.sampleLimit
+		}
+		pm.Labels = make(map[string]string, len(podMonitor.Labels)+1)
+		for key, value := range podMonitor.Labels {
+			pm.Labels[key] = value
+		}
+		pm.Labels[clonedPodMonitorLabelKey] = clonedServiceMonitorLabelValue
+		return nil
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	if opRes != controllerutil.OperationResultNone {
+		r.Logger.Infof("operation result of creating podmonitor %v was %v", pm.Name, opRes)
+	}
+	return pm, nil
+}
+
+// reconcileRoleBindings lets the cluster monitoring scrape the namespaces of
+// a clone. The namespaces are added to monitoredNamespaces
+func (r *Reconciler) reconcileRoleBindings(ctx context.Context,
+	serverClient k8sclient.Client, namespaces []string, monitoredNamespaces map[string]bool) (err error) {
 	//Create role binding for each of the namespace label selectors
-	for _, namespace := range sermon.Spec.NamespaceSelector.MatchNames {
+	for _, namespace := range namespaces {
+		monitoredNamespaces[namespace] = true
 		err := r.reconcileRole(ctx, serverClient, namespace)
 		if err != nil {
 			return err
@@ -365,16 +498,10 @@ func (r *Reconciler) reconcileRoleBinding(ctx context.Context,
 	return err
 }
 
-func (r *Reconciler) removeServiceMonitor(ctx context.Context,
-	serverClient k8sclient.Client, namespace, name string) (err error) {
-	sm := &monitoringv1.ServiceMonitor{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: namespace,
-		},
-	}
-	//Delete the servicemonitor
-	err = serverClient.Delete(ctx, sm)
+func (r *Reconciler) removeMonitor(ctx context.Context,
+	serverClient k8sclient.Client, monitor runtime.Object) (err error) {
+	//Delete the servicemonitor or podmonitor
+	err = serverClient.Delete(ctx, monitor)
 	if err != nil && k8serr.IsNotFound(err) {
 		return nil
 	}
@@ -384,20 +511,6 @@ func (r *Reconciler) removeServiceMonitor(ctx context.Context,
 func (r *Reconciler) removeRoleandRoleBinding(ctx context.Context,
 	serverClient k8sclient.Client, namespace, roleName, rbName string) (err error) {
 
-	// Check if the namespace has service monitors
-	// if so do not delete the rolebinding
-	listOpts := []k8sclient.ListOption{
-		k8sclient.InNamespace(namespace),
-	}
-	serviceMonitorsMap, err := r.getServiceMonitors(ctx, serverClient, listOpts)
-	if err != nil {
-		return err
-	}
-
-	if len(serviceMonitorsMap) > 0 {
-		return nil
-	}
-
 	//Get the role
 	role := &rbac.Role{}
 	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: roleName, Namespace: namespace}, role)
@@ -445,6 +558,28 @@ func (r *Reconciler) getServiceMonitors(ctx context.Context,
 	return serviceMonitorsMap, err
 }
 
+func (r *Reconciler) getPodMonitors(ctx context.Context,
+	serverClient k8sclient.Client,
+	listOpts []k8sclient.ListOption) (podMonitorsMap map[string]*monitoringv1.PodMonitor, err error) {
+
+	podMonitors := &monitoringv1.PodMonitorList{}
+	err = serverClient.List(ctx, podMonitors, listOpts...)
+	if err != nil {
+		return podMonitorsMap, err
+	}
+	podMonitorsMap = make(map[string]*monitoringv1.PodMonitor)
+	for _, pm := range podMonitors.Items {
+		podMonitorsMap[pm.Name] = pm
+	}
+	return podMonitorsMap, err
+}
+
+func getClonedPodMonitorLabel() map[string]string {
+	return map[string]string{
+		clonedPodMonitorLabelKey: clonedServiceMonitorLabelValue,
+	}
+}
+
 func getClonedServiceMonitorLabel() map[string]string {
 	return map[string]string{
 		clonedServiceMonitorLabelKey: clonedServiceMonitorLabelValue,
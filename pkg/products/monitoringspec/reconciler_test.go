@@ -3,6 +3,7 @@ package monitoringspec
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/integr8ly/integreatly-operator/pkg/config"
@@ -533,3 +534,104 @@ func TestReconciler_fullReconcileWithCleanUp(t *testing.T) {
 		})
 	}
 }
+
+// Test case - clones the monitors of the namespaces that are selected
+// Verifies that the excluded namespaces and monitors are not cloned
+// Verifies that the scrape overrides are applied to the clones
+// Verifies that the cloned monitors are listed in the status
+func TestReconciler_reconcileMonitoringWithCloningPolicy(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createNamespace := func(name string, labels, annotations map[string]string) *corev1.Namespace {
+		labels["monitoring-key"] = "middleware"
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations},
+		}
+	}
+
+	fuseSM := createServicemonitor("fuse-servicemon", "fuse")
+	fuseSM.Annotations = map[string]string{scrapeIntervalAnnotation: "5m"}
+	noisySM := createServicemonitor("noisy-servicemon", "fuse")
+	noisySM.Annotations = map[string]string{cloneAnnotation: "false"}
+	fusePM := &prometheusmonitoringv1.PodMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "fuse-podmon", Namespace: "fuse"},
+		Spec: prometheusmonitoringv1.PodMonitorSpec{
+			PodMetricsEndpoints: []prometheusmonitoringv1.PodMetricsEndpoint{{Port: "metrics", Interval: "30s"}},
+		},
+	}
+	//Stale clone of a monitor of a namespace that isn't selected anymore
+	staleSM := createServicemonitor("ups-ups-servicemon", defaultInstallationNamespace)
+	staleSM.Labels = getClonedServiceMonitorLabel()
+	staleSM.Spec.NamespaceSelector.MatchNames = []string{"ups"}
+
+	installation := basicInstallation()
+	installation.Spec.Monitoring = &integreatlyv1alpha1.MonitoringSpec{
+		MonitorCloning: &integreatlyv1alpha1.MonitorCloningSpec{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"dev"}},
+				},
+			},
+		},
+	}
+
+	serverClient := fakeclient.NewFakeClientWithScheme(scheme,
+		createNamespace("fuse", map[string]string{}, map[string]string{sampleLimitAnnotation: "1000"}),
+		createNamespace("ups", map[string]string{"tier": "dev"}, nil),
+		createNamespace("apicurito", map[string]string{}, map[string]string{cloneAnnotation: "false"}),
+		fuseSM, noisySM, fusePM, staleSM,
+		createServicemonitor("ups-servicemon", "ups"),
+		createServicemonitor("apicurito-servicemon", "apicurito"),
+		createRole(roleRefName, "ups"),
+		createRoleBinding(roleBindingName, "ups"),
+	)
+
+	reconciler, err := NewReconciler(basicConfigMock(), installation, nil, setupRecorder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	reconciler.Config.SetNamespace(defaultInstallationNamespace)
+
+	phase, err := reconciler.reconcileMonitoring(context.TODO(), serverClient, installation)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcileMonitoring() = %s, %v", phase, err)
+	}
+
+	sm := &prometheusmonitoringv1.ServiceMonitor{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "fuse-fuse-servicemon", Namespace: defaultInstallationNamespace}, sm); err != nil {
+		t.Fatalf("expected fuse servicemonitor to be cloned: %v", err)
+	}
+	if sm.Spec.Endpoints[0].Interval != "5m" || sm.Spec.SampleLimit != 1000 {
+		t.Errorf("expected scrape overrides to be applied, got interval %s and sample limit %d", sm.Spec.Endpoints[0].Interval, sm.Spec.SampleLimit)
+	}
+
+	pm := &prometheusmonitoringv1.PodMonitor{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "fuse-fuse-podmon", Namespace: defaultInstallationNamespace}, pm); err != nil {
+		t.Fatalf("expected fuse podmonitor to be cloned: %v", err)
+	}
+	if pm.Spec.PodMetricsEndpoints[0].Interval != "30s" || pm.Labels[clonedPodMonitorLabelKey] != clonedServiceMonitorLabelValue {
+		t.Errorf("unexpected podmonitor clone %v", pm)
+	}
+
+	for _, name := range []string{"fuse-noisy-servicemon", "apicurito-apicurito-servicemon", "ups-ups-servicemon"} {
+		err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: name, Namespace: defaultInstallationNamespace}, &prometheusmonitoringv1.ServiceMonitor{})
+		if !k8serr.IsNotFound(err) {
+			t.Errorf("expected servicemonitor %s not to be cloned, got %v", name, err)
+		}
+	}
+	err = serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: roleBindingName, Namespace: "ups"}, &rbac.RoleBinding{})
+	if !k8serr.IsNotFound(err) {
+		t.Errorf("expected ups rolebinding to be removed, got %v", err)
+	}
+
+	expectedStatus := []integreatlyv1alpha1.ClonedMonitor{
+		{Kind: prometheusmonitoringv1.PodMonitorsKind, Name: "fuse-fuse-podmon", Source: "fuse/fuse-podmon"},
+		{Kind: prometheusmonitoringv1.ServiceMonitorsKind, Name: "fuse-fuse-servicemon", Source: "fuse/fuse-servicemon"},
+	}
+	if !reflect.DeepEqual(installation.Status.Monitoring.ClonedMonitors, expectedStatus) {
+		t.Errorf("expected cloned monitors %v, got %v", expectedStatus, installation.Status.Monitoring.ClonedMonitors)
+	}
+}
@@ -167,7 +167,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
-	phase, err = r.ReconcileStatefulSet(ctx, serverClient, r.Config.RHSSOCommon)
+	phase, err = r.ReconcileStatefulSet(ctx, serverClient, r.Config.RHSSOCommon, r.Config.GetProductName())
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.Recorder, installation, phase, "Failed to reconsile RHSSO pod priority", err)
 		return phase, err
@@ -0,0 +1,269 @@
+package rhssocommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	userStorageProviderType = "org.keycloak.storage.UserStorageProvider"
+	ldapStorageMapperType   = "org.keycloak.storage.ldap.mappers.LDAPStorageMapper"
+
+	defaultGroupsAttribute = "groups"
+)
+
+// ReconcileIdentityProviders federates the identity providers into the realm.
+// OIDC and SAML providers are added as identity providers, and LDAP providers
+// as user federation. The group mappings add their users to the groups of the
+// realm. Providers removed from the list since the last reconcile are removed
+// from the realm
+func (r *Reconciler) ReconcileIdentityProviders(ctx context.Context, serverClient k8sclient.Client, kc *keycloak.Keycloak, realmName string, providers []integreatlyv1alpha1.UserSSOIdentityProvider, config config.ConfigReadable, ssoCommon *config.RHSSOCommon) (integreatlyv1alpha1.StatusPhase, error) {
+	managed := ssoCommon.GetManagedIdentityProviders()
+	if len(providers) == 0 && len(managed) == 0 {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	kcClient, err := r.KeycloakClientFactory.AuthenticatedClient(*kc)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+	adminClient, err := r.getKeycloakAdminClientFactory().AuthenticatedAdminClient(ctx, serverClient, *kc)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
+	existingProviders, err := kcClient.ListIdentityProviders(realmName)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to list identity providers: %w", err)
+	}
+	existingFederation, err := adminClient.ListComponents(realmName, userStorageProviderType, "")
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to list user federation providers: %w", err)
+	}
+
+	var aliases []string
+	for _, provider := range providers {
+		providerConfig, err := r.getIdentityProviderConfig(ctx, serverClient, provider)
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+
+		if provider.Type == integreatlyv1alpha1.UserSSOIdentityProviderLDAP {
+			err = reconcileUserFederation(adminClient, realmName, provider, providerConfig, existingFederation)
+		} else {
+			err = reconcileIdentityProvider(kcClient, adminClient, realmName, provider, providerConfig, existingProviders)
+		}
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile identity provider %s: %w", provider.Alias, err)
+		}
+		aliases = append(aliases, provider.Alias)
+	}
+
+	for _, alias := range managed {
+		if contains(aliases, alias) {
+			continue
+		}
+		if err := removeIdentityProvider(kcClient, adminClient, realmName, alias, existingProviders, existingFederation); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to remove identity provider %s: %w", alias, err)
+		}
+		r.Logger.Infof("Removed identity provider %s from realm %s", alias, realmName)
+	}
+
+	ssoCommon.SetManagedIdentityProviders(aliases)
+	if err := r.ConfigManager.WriteConfig(config); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to write identity providers to config: %w", err)
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+func (r *Reconciler) getKeycloakAdminClientFactory() KeycloakAdminClientFactory {
+	if r.KeycloakAdminClientFactory != nil {
+		return r.KeycloakAdminClientFactory
+	}
+	return &LocalKeycloakAdminClientFactory{}
+}
+
+// getIdentityProviderConfig returns the config of the provider with the keys
+// of its secret added
+func (r *Reconciler) getIdentityProviderConfig(ctx context.Context, serverClient k8sclient.Client, provider integreatlyv1alpha1.UserSSOIdentityProvider) (map[string]string, error) {
+	providerConfig := map[string]string{}
+	for key, value := range provider.Config {
+		providerConfig[key] = value
+	}
+	if provider.SecretRef == "" {
+		return providerConfig, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: provider.SecretRef, Namespace: r.ConfigManager.GetOperatorNamespace()}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %s of identity provider %s: %w", provider.SecretRef, provider.Alias, err)
+	}
+	for key, value := range secret.Data {
+		providerConfig[key] = string(value)
+	}
+	return providerConfig, nil
+}
+
+func reconcileIdentityProvider(kcClient keycloakCommon.KeycloakInterface, adminClient KeycloakAdminInterface, realmName string, provider integreatlyv1alpha1.UserSSOIdentityProvider, providerConfig map[string]string, existing []*keycloak.KeycloakIdentityProvider) error {
+	idp := &keycloak.KeycloakIdentityProvider{
+		Alias:                     provider.Alias,
+		DisplayName:               provider.DisplayName,
+		ProviderID:                string(provider.Type),
+		Enabled:                   true,
+		FirstBrokerLoginFlowAlias: "first broker login",
+		Config:                    providerConfig,
+	}
+
+	if ContainsIdentityProvider(existing, provider.Alias) {
+		if err := kcClient.UpdateIdentityProvider(idp, realmName); err != nil {
+			return err
+		}
+	} else {
+		if _, err := kcClient.CreateIdentityProvider(idp, realmName); err != nil {
+			return err
+		}
+	}
+
+	existingMappers, err := adminClient.ListIdentityProviderMappers(provider.Alias, realmName)
+	if err != nil {
+		return fmt.Errorf("failed to list mappers: %w", err)
+	}
+
+	for _, mapping := range provider.GroupMappings {
+		mapper := identityProviderGroupMapper(provider, mapping)
+		for _, existingMapper := range existingMappers {
+			if existingMapper.Name == mapper.Name {
+				mapper.ID = existingMapper.ID
+				break
+			}
+		}
+
+		if mapper.ID != "" {
+			err = adminClient.UpdateIdentityProviderMapper(mapper, realmName)
+		} else {
+			err = adminClient.CreateIdentityProviderMapper(mapper, realmName)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to reconcile mapper %s: %w", mapper.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// identityProviderGroupMapper returns the mapper that adds the users with the
+// external group to the group of the realm, or all the users if the external
+// group is not set
+func identityProviderGroupMapper(provider integreatlyv1alpha1.UserSSOIdentityProvider, mapping integreatlyv1alpha1.UserSSOGroupMapping) *IdentityProviderMapper {
+	mapper := &IdentityProviderMapper{
+		IdentityProviderAlias: provider.Alias,
+		Config: map[string]string{
+			"group":    "/" + mapping.Group,
+			"syncMode": "FORCE",
+		},
+	}
+
+	if mapping.ExternalGroup == "" {
+		mapper.Name = fmt.Sprintf("%s-all", mapping.Group)
+		mapper.IdentityProviderMapper = "hardcoded-group-idp-mapper"
+		return mapper
+	}
+
+	groupsAttribute := provider.GroupsAttribute
+	if groupsAttribute == "" {
+		groupsAttribute = defaultGroupsAttribute
+	}
+	// Marshalling a slice of string maps can't fail
+	matchers, _ := json.Marshal([]map[string]string{{"key": groupsAttribute, "value": mapping.ExternalGroup}})
+
+	mapper.Name = fmt.Sprintf("%s-%s", mapping.Group, mapping.ExternalGroup)
+	if provider.Type == integreatlyv1alpha1.UserSSOIdentityProviderSAML {
+		mapper.IdentityProviderMapper = "saml-advanced-group-idp-mapper"
+		mapper.Config["attributes"] = string(matchers)
+	} else {
+		mapper.IdentityProviderMapper = "oidc-advanced-group-idp-mapper"
+		mapper.Config["claims"] = string(matchers)
+	}
+	return mapper
+}
+
+func reconcileUserFederation(adminClient KeycloakAdminInterface, realmName string, provider integreatlyv1alpha1.UserSSOIdentityProvider, providerConfig map[string]string, existing []*Component) error {
+	component := &Component{
+		Name:         provider.Alias,
+		ProviderID:   "ldap",
+		ProviderType: userStorageProviderType,
+		Config:       map[string][]string{},
+	}
+	for key, value := range providerConfig {
+		component.Config[key] = []string{value}
+	}
+
+	var err error
+	if existingComponent := findComponent(existing, provider.Alias); existingComponent != nil {
+		component.ID = existingComponent.ID
+		component.ParentID = existingComponent.ParentID
+		err = adminClient.UpdateComponent(component, realmName)
+	} else {
+		component.ID, err = adminClient.CreateComponent(component, realmName)
+	}
+	if err != nil {
+		return err
+	}
+
+	existingMappers, err := adminClient.ListComponents(realmName, ldapStorageMapperType, component.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list mappers: %w", err)
+	}
+
+	for _, mapping := range provider.GroupMappings {
+		mapper := &Component{
+			Name:         fmt.Sprintf("%s-all", mapping.Group),
+			ProviderID:   "hardcoded-ldap-group-mapper",
+			ProviderType: ldapStorageMapperType,
+			ParentID:     component.ID,
+			Config: map[string][]string{
+				"group": {"/" + mapping.Group},
+			},
+		}
+
+		if existingMapper := findComponent(existingMappers, mapper.Name); existingMapper != nil {
+			mapper.ID = existingMapper.ID
+			err = adminClient.UpdateComponent(mapper, realmName)
+		} else {
+			_, err = adminClient.CreateComponent(mapper, realmName)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to reconcile mapper %s: %w", mapper.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func removeIdentityProvider(kcClient keycloakCommon.KeycloakInterface, adminClient KeycloakAdminInterface, realmName, alias string, existingProviders []*keycloak.KeycloakIdentityProvider, existingFederation []*Component) error {
+	if ContainsIdentityProvider(existingProviders, alias) {
+		return kcClient.DeleteIdentityProvider(alias, realmName)
+	}
+	if component := findComponent(existingFederation, alias); component != nil {
+		return adminClient.DeleteComponent(component.ID, realmName)
+	}
+	return nil
+}
+
+func findComponent(components []*Component, name string) *Component {
+	for _, component := range components {
+		if component.Name == name {
+			return component
+		}
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package rhssocommon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeKeycloakAdminClient struct {
+	mappers       map[string][]*IdentityProviderMapper
+	components    []*Component
+	realmRoles    []*RealmRole
+	realmSettings map[string]interface{}
+}
+
+func (c *fakeKeycloakAdminClient) AuthenticatedAdminClient(_ context.Context, _ k8sclient.Client, _ keycloak.Keycloak) (KeycloakAdminInterface, error) {
+	return c, nil
+}
+
+func (c *fakeKeycloakAdminClient) ListIdentityProviderMappers(alias, realmName string) ([]*IdentityProviderMapper, error) {
+	return c.mappers[alias], nil
+}
+
+func (c *fakeKeycloakAdminClient) CreateIdentityProviderMapper(mapper *IdentityProviderMapper, realmName string) error {
+	mapper.ID = fmt.Sprintf("mapper-%d", len(c.mappers[mapper.IdentityProviderAlias]))
+	c.mappers[mapper.IdentityProviderAlias] = append(c.mappers[mapper.IdentityProviderAlias], mapper)
+	return nil
+}
+
+func (c *fakeKeycloakAdminClient) UpdateIdentityProviderMapper(mapper *IdentityProviderMapper, realmName string) error {
+	for i, existing := range c.mappers[mapper.IdentityProviderAlias] {
+		if existing.ID == mapper.ID {
+			c.mappers[mapper.IdentityProviderAlias][i] = mapper
+			return nil
+		}
+	}
+	return fmt.Errorf("mapper %s not found", mapper.ID)
+}
+
+func (c *fakeKeycloakAdminClient) ListComponents(realmName, providerType, parentID string) ([]*Component, error) {
+	var components []*Component
+	for _, component := range c.components {
+		if component.ProviderType == providerType && (parentID == "" || component.ParentID == parentID) {
+			components = append(components, component)
+		}
+	}
+	return components, nil
+}
+
+func (c *fakeKeycloakAdminClient) CreateComponent(component *Component, realmName string) (string, error) {
+	component.ID = fmt.Sprintf("component-%d", len(c.components))
+	c.components = append(c.components, component)
+	return component.ID, nil
+}
+
+func (c *fakeKeycloakAdminClient) UpdateComponent(component *Component, realmName string) error {
+	for i, existing := range c.components {
+		if existing.ID == component.ID {
+			c.components[i] = component
+			return nil
+		}
+	}
+	return fmt.Errorf("component %s not found", component.ID)
+}
+
+func (c *fakeKeycloakAdminClient) DeleteComponent(componentID, realmName string) error {
+	for i, existing := range c.components {
+		if existing.ID == componentID {
+			c.components = append(c.components[:i], c.components[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("component %s not found", componentID)
+}
+
+func (c *fakeKeycloakAdminClient) ListRealmRoles(realmName string) ([]*RealmRole, error) {
+	return c.realmRoles, nil
+}
+
+func (c *fakeKeycloakAdminClient) CreateRealmRole(role *RealmRole, realmName string) error {
+	c.realmRoles = append(c.realmRoles, role)
+	return nil
+}
+
+func (c *fakeKeycloakAdminClient) UpdateRealmRole(role *RealmRole, realmName string) error {
+	for i, existing := range c.realmRoles {
+		if existing.Name == role.Name {
+			c.realmRoles[i] = role
+			return nil
+		}
+	}
+	return fmt.Errorf("role %s not found", role.Name)
+}
+
+func (c *fakeKeycloakAdminClient) GetRealmSettings(realmName string) (map[string]interface{}, error) {
+	return c.realmSettings, nil
+}
+
+func (c *fakeKeycloakAdminClient) UpdateRealmSettings(settings map[string]interface{}, realmName string) error {
+	c.realmSettings = settings
+	return nil
+}
+
+func TestReconciler_ReconcileIdentityProviders(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "corporate-oidc",
+			Namespace: defaultOperatorNamespace,
+		},
+		Data: map[string][]byte{"clientSecret": []byte("secret")},
+	}
+	providers := []integreatlyv1alpha1.UserSSOIdentityProvider{
+		{
+			Alias:     "corporate",
+			Type:      integreatlyv1alpha1.UserSSOIdentityProviderOIDC,
+			Config:    map[string]string{"clientId": "rhmi"},
+			SecretRef: "corporate-oidc",
+			GroupMappings: []integreatlyv1alpha1.UserSSOGroupMapping{
+				{ExternalGroup: "admins", Group: "dedicated-admins"},
+				{Group: "rhmi-developers"},
+			},
+		},
+		{
+			Alias:  "directory",
+			Type:   integreatlyv1alpha1.UserSSOIdentityProviderLDAP,
+			Config: map[string]string{"connectionUrl": "ldaps://ldap.example.com"},
+			GroupMappings: []integreatlyv1alpha1.UserSSOGroupMapping{
+				{Group: "rhmi-developers"},
+			},
+		},
+	}
+
+	identityProviders := []*keycloak.KeycloakIdentityProvider{{Alias: "openshift-v4"}}
+	kcClient := &keycloakCommon.KeycloakInterfaceMock{
+		ListIdentityProvidersFunc: func(realmName string) ([]*keycloak.KeycloakIdentityProvider, error) {
+			return identityProviders, nil
+		},
+		CreateIdentityProviderFunc: func(identityProvider *keycloak.KeycloakIdentityProvider, realmName string) (string, error) {
+			identityProviders = append(identityProviders, identityProvider)
+			return identityProvider.Alias, nil
+		},
+		UpdateIdentityProviderFunc: func(identityProvider *keycloak.KeycloakIdentityProvider, realmName string) error {
+			return nil
+		},
+		DeleteIdentityProviderFunc: func(alias string, realmName string) error {
+			return nil
+		},
+	}
+	adminClient := &fakeKeycloakAdminClient{mappers: map[string][]*IdentityProviderMapper{}}
+
+	r := &Reconciler{
+		ConfigManager: basicConfigMock(),
+		Logger:        logrus.NewEntry(logrus.StandardLogger()),
+		KeycloakClientFactory: &keycloakCommon.KeycloakClientFactoryMock{AuthenticatedClientFunc: func(kc keycloak.Keycloak) (keycloakCommon.KeycloakInterface, error) {
+			return kcClient, nil
+		}},
+		KeycloakAdminClientFactory: adminClient,
+	}
+	serverClient := fake.NewFakeClientWithScheme(scheme, secret)
+	ssoConfig := config.NewRHSSOUser(config.ProductConfig{"NAMESPACE": defaultNamespace})
+	kc := &keycloak.Keycloak{}
+
+	// Reconciling twice doesn't duplicate the providers or their mappers
+	for i := 0; i < 2; i++ {
+		phase, err := r.ReconcileIdentityProviders(context.TODO(), serverClient, kc, masterRealmName, providers, ssoConfig, ssoConfig.RHSSOCommon)
+		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			t.Fatalf("unexpected phase %s and error %v", phase, err)
+		}
+	}
+
+	if len(kcClient.CreateIdentityProviderCalls()) != 1 || len(kcClient.UpdateIdentityProviderCalls()) != 1 {
+		t.Fatalf("expected identity provider to be created once and then updated")
+	}
+	idp := kcClient.CreateIdentityProviderCalls()[0].IdentityProvider
+	if idp.ProviderID != "oidc" || idp.Config["clientId"] != "rhmi" || idp.Config["clientSecret"] != "secret" {
+		t.Errorf("unexpected identity provider %v", idp)
+	}
+
+	mappers := adminClient.mappers["corporate"]
+	if len(mappers) != 2 {
+		t.Fatalf("expected 2 mappers, got %d", len(mappers))
+	}
+	if mappers[0].IdentityProviderMapper != "oidc-advanced-group-idp-mapper" || mappers[0].Config["group"] != "/dedicated-admins" || mappers[0].Config["claims"] != `[{"key":"groups","value":"admins"}]` {
+		t.Errorf("unexpected mapper of external group %v", mappers[0])
+	}
+	if mappers[1].IdentityProviderMapper != "hardcoded-group-idp-mapper" || mappers[1].Config["group"] != "/rhmi-developers" {
+		t.Errorf("unexpected mapper of all users %v", mappers[1])
+	}
+
+	if len(adminClient.components) != 2 {
+		t.Fatalf("expected user federation and its mapper, got %v", adminClient.components)
+	}
+	if federation := adminClient.components[0]; federation.ProviderID != "ldap" || federation.Config["connectionUrl"][0] != "ldaps://ldap.example.com" {
+		t.Errorf("unexpected user federation %v", federation)
+	}
+	if mapper := adminClient.components[1]; mapper.ParentID != adminClient.components[0].ID || mapper.ProviderID != "hardcoded-ldap-group-mapper" {
+		t.Errorf("unexpected user federation mapper %v", mapper)
+	}
+
+	// Providers removed from the list are removed from the realm
+	phase, err := r.ReconcileIdentityProviders(context.TODO(), serverClient, kc, masterRealmName, providers[:1], ssoConfig, ssoConfig.RHSSOCommon)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("unexpected phase %s and error %v", phase, err)
+	}
+	if findComponent(adminClient.components, "directory") != nil {
+		t.Errorf("expected user federation to be removed")
+	}
+
+	phase, err = r.ReconcileIdentityProviders(context.TODO(), serverClient, kc, masterRealmName, nil, ssoConfig, ssoConfig.RHSSOCommon)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("unexpected phase %s and error %v", phase, err)
+	}
+	if calls := kcClient.DeleteIdentityProviderCalls(); len(calls) != 1 || calls[0].Alias != "corporate" {
+		t.Errorf("expected identity provider to be removed, got %v", calls)
+	}
+	if len(ssoConfig.GetManagedIdentityProviders()) != 0 {
+		t.Errorf("unexpected managed identity providers %v", ssoConfig.GetManagedIdentityProviders())
+	}
+}
@@ -0,0 +1,285 @@
+package rhssocommon
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/keycloak/keycloak-operator/pkg/model"
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IdentityProviderMapper is a mapper of the users logging in through an
+// identity provider
+type IdentityProviderMapper struct {
+	ID                     string            `json:"id,omitempty"`
+	Name                   string            `json:"name"`
+	IdentityProviderAlias  string            `json:"identityProviderAlias"`
+	IdentityProviderMapper string            `json:"identityProviderMapper"`
+	Config                 map[string]string `json:"config,omitempty"`
+}
+
+// Component is a realm component, such as a user federation provider or one
+// of its mappers
+type Component struct {
+	ID           string              `json:"id,omitempty"`
+	Name         string              `json:"name"`
+	ProviderID   string              `json:"providerId"`
+	ProviderType string              `json:"providerType"`
+	ParentID     string              `json:"parentId,omitempty"`
+	Config       map[string][]string `json:"config,omitempty"`
+}
+
+// RealmRole is a role of the realm
+type RealmRole struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// KeycloakAdminInterface calls the Keycloak admin API endpoints that are not
+// supported by keycloakCommon.KeycloakInterface
+type KeycloakAdminInterface interface {
+	ListIdentityProviderMappers(alias, realmName string) ([]*IdentityProviderMapper, error)
+	CreateIdentityProviderMapper(mapper *IdentityProviderMapper, realmName string) error
+	UpdateIdentityProviderMapper(mapper *IdentityProviderMapper, realmName string) error
+
+	ListComponents(realmName, providerType, parentID string) ([]*Component, error)
+	CreateComponent(component *Component, realmName string) (string, error)
+	UpdateComponent(component *Component, realmName string) error
+	DeleteComponent(componentID, realmName string) error
+
+	ListRealmRoles(realmName string) ([]*RealmRole, error)
+	CreateRealmRole(role *RealmRole, realmName string) error
+	UpdateRealmRole(role *RealmRole, realmName string) error
+
+	// GetRealmSettings returns the realm representation as a map, so that
+	// updates only change the settings they set
+	GetRealmSettings(realmName string) (map[string]interface{}, error)
+	UpdateRealmSettings(settings map[string]interface{}, realmName string) error
+}
+
+// KeycloakAdminClientFactory creates authenticated admin clients
+type KeycloakAdminClientFactory interface {
+	AuthenticatedAdminClient(ctx context.Context, serverClient k8sclient.Client, kc keycloak.Keycloak) (KeycloakAdminInterface, error)
+}
+
+// serviceCAFile is the CA bundle of the cluster service CA, which signs the
+// serving certificate of the Keycloak service
+const serviceCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+
+// LocalKeycloakAdminClientFactory logs in to the Keycloak instance with the
+// admin credentials created by the Keycloak operator. The keycloak-client
+// doesn't support these endpoints, so the requests are sent through a client
+// that verifies the Keycloak certificate with the cluster service CA
+type LocalKeycloakAdminClientFactory struct{}
+
+var _ KeycloakAdminClientFactory = &LocalKeycloakAdminClientFactory{}
+
+func (f *LocalKeycloakAdminClientFactory) AuthenticatedAdminClient(ctx context.Context, serverClient k8sclient.Client, kc keycloak.Keycloak) (KeycloakAdminInterface, error) {
+	adminCreds := &corev1.Secret{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: kc.Status.CredentialSecret, Namespace: kc.Namespace}, adminCreds); err != nil {
+		return nil, fmt.Errorf("failed to get the admin credentials: %w", err)
+	}
+
+	tlsConfig, err := getServiceCATLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client := &keycloakAdminClient{
+		url: kc.Status.InternalURL,
+		httpClient: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+			Timeout:   10 * time.Second,
+		},
+	}
+	err = client.login(string(adminCreds.Data[model.AdminUsernameProperty]), string(adminCreds.Data[model.AdminPasswordProperty]))
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// getServiceCATLSConfig returns a TLS config trusting the cluster service CA
+func getServiceCATLSConfig() (*tls.Config, error) {
+	caCert, err := ioutil.ReadFile(serviceCAFile)
+	// if running locally, the service CA isn't available in the expected path
+	if os.IsNotExist(err) && os.Getenv(k8sutil.ForceRunModeEnv) == string(k8sutil.LocalRunMode) {
+		logrus.Warn("Keycloak admin client will skip certificate verification - this is acceptable only if operator is running locally")
+		return &tls.Config{InsecureSkipVerify: true}, nil // nolint
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service CA file: %w", err)
+	}
+
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("no certificates found in service CA file %s", serviceCAFile)
+	}
+	return &tls.Config{RootCAs: caCertPool}, nil
+}
+
+type keycloakAdminClient struct {
+	url        string
+	token      string
+	httpClient *http.Client
+}
+
+func (c *keycloakAdminClient) ListIdentityProviderMappers(alias, realmName string) ([]*IdentityProviderMapper, error) {
+	var mappers []*IdentityProviderMapper
+	err := c.do(http.MethodGet, fmt.Sprintf("realms/%s/identity-provider/instances/%s/mappers", realmName, alias), nil, &mappers)
+	return mappers, err
+}
+
+func (c *keycloakAdminClient) CreateIdentityProviderMapper(mapper *IdentityProviderMapper, realmName string) error {
+	return c.do(http.MethodPost, fmt.Sprintf("realms/%s/identity-provider/instances/%s/mappers", realmName, mapper.IdentityProviderAlias), mapper, nil)
+}
+
+func (c *keycloakAdminClient) UpdateIdentityProviderMapper(mapper *IdentityProviderMapper, realmName string) error {
+	return c.do(http.MethodPut, fmt.Sprintf("realms/%s/identity-provider/instances/%s/mappers/%s", realmName, mapper.IdentityProviderAlias, mapper.ID), mapper, nil)
+}
+
+func (c *keycloakAdminClient) ListComponents(realmName, providerType, parentID string) ([]*Component, error) {
+	query := url.Values{}
+	query.Set("type", providerType)
+	if parentID != "" {
+		query.Set("parent", parentID)
+	}
+
+	var components []*Component
+	err := c.do(http.MethodGet, fmt.Sprintf("realms/%s/components?%s", realmName, query.Encode()), nil, &components)
+	return components, err
+}
+
+func (c *keycloakAdminClient) CreateComponent(component *Component, realmName string) (string, error) {
+	res, err := c.request(http.MethodPost, fmt.Sprintf("realms/%s/components", realmName), component)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	location := strings.Split(res.Header.Get("Location"), "/")
+	return location[len(location)-1], nil
+}
+
+func (c *keycloakAdminClient) UpdateComponent(component *Component, realmName string) error {
+	return c.do(http.MethodPut, fmt.Sprintf("realms/%s/components/%s", realmName, component.ID), component, nil)
+}
+
+func (c *keycloakAdminClient) DeleteComponent(componentID, realmName string) error {
+	return c.do(http.MethodDelete, fmt.Sprintf("realms/%s/components/%s", realmName, componentID), nil, nil)
+}
+
+func (c *keycloakAdminClient) ListRealmRoles(realmName string) ([]*RealmRole, error) {
+	var roles []*RealmRole
+	err := c.do(http.MethodGet, fmt.Sprintf("realms/%s/roles", realmName), nil, &roles)
+	return roles, err
+}
+
+func (c *keycloakAdminClient) CreateRealmRole(role *RealmRole, realmName string) error {
+	return c.do(http.MethodPost, fmt.Sprintf("realms/%s/roles", realmName), role, nil)
+}
+
+func (c *keycloakAdminClient) UpdateRealmRole(role *RealmRole, realmName string) error {
+	return c.do(http.MethodPut, fmt.Sprintf("realms/%s/roles/%s", realmName, url.PathEscape(role.Name)), role, nil)
+}
+
+func (c *keycloakAdminClient) GetRealmSettings(realmName string) (map[string]interface{}, error) {
+	settings := map[string]interface{}{}
+	err := c.do(http.MethodGet, fmt.Sprintf("realms/%s", realmName), nil, &settings)
+	return settings, err
+}
+
+func (c *keycloakAdminClient) UpdateRealmSettings(settings map[string]interface{}, realmName string) error {
+	return c.do(http.MethodPut, fmt.Sprintf("realms/%s", realmName), settings, nil)
+}
+
+// do performs the request and decodes the response body into result, if set
+func (c *keycloakAdminClient) do(method, path string, body, result interface{}) error {
+	res, err := c.request(method, path, body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+func (c *keycloakAdminClient) request(method, path string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body of %s %s: %w", method, path, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/auth/admin/%s", c.url, path), reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request %s %s: %w", method, path, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform request %s %s: %w", method, path, err)
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		defer res.Body.Close()
+		return nil, fmt.Errorf("request %s %s failed: %s", method, path, res.Status)
+	}
+	return res, nil
+}
+
+func (c *keycloakAdminClient) login(user, pass string) error {
+	form := url.Values{}
+	form.Add("username", user)
+	form.Add("password", pass)
+	form.Add("client_id", "admin-cli")
+	form.Add("grant_type", "password")
+
+	res, err := c.httpClient.PostForm(fmt.Sprintf("%s/auth/realms/master/protocol/openid-connect/token", c.url), form)
+	if err != nil {
+		return fmt.Errorf("failed to perform token request: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	tokenRes := &keycloak.TokenResponse{}
+	if err := json.Unmarshal(body, tokenRes); err != nil {
+		return fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if tokenRes.Error != "" {
+		return fmt.Errorf("failed to log in to keycloak: %s", tokenRes.ErrorDescription)
+	}
+
+	c.token = tokenRes.AccessToken
+	return nil
+}
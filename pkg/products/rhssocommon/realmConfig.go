@@ -0,0 +1,554 @@
+package rhssocommon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// RealmConfigKey is the key of the realm config in its config map
+	RealmConfigKey = "realm.yaml"
+
+	// realmConfigLabel labels the Keycloak client CRs created from the realm
+	// config with the name of their realm
+	realmConfigLabel = "integreatly.org/realm-config"
+)
+
+// RealmConfig declares the configuration of a realm
+type RealmConfig struct {
+	PasswordPolicy string `json:"passwordPolicy,omitempty"`
+
+	// Token lifetimes, in seconds
+	AccessTokenLifespan   *int `json:"accessTokenLifespan,omitempty"`
+	SSOSessionIdleTimeout *int `json:"ssoSessionIdleTimeout,omitempty"`
+	SSOSessionMaxLifespan *int `json:"ssoSessionMaxLifespan,omitempty"`
+
+	Roles   []RealmConfigRole            `json:"roles,omitempty"`
+	Groups  []RealmConfigGroup           `json:"groups,omitempty"`
+	Clients []keycloak.KeycloakAPIClient `json:"clients,omitempty"`
+}
+
+type RealmConfigRole struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type RealmConfigGroup struct {
+	Name       string   `json:"name"`
+	RealmRoles []string `json:"realmRoles,omitempty"`
+	// ClientRoles are the names of the client roles mapped to the group,
+	// keyed by the client name (`clientId` field)
+	ClientRoles map[string][]string `json:"clientRoles,omitempty"`
+}
+
+// realmConfigError is an error in the realm config, that is reported in the
+// status rather than retried
+type realmConfigError struct {
+	message string
+}
+
+func (e *realmConfigError) Error() string {
+	return e.message
+}
+
+func newRealmConfigError(format string, args ...interface{}) error {
+	return &realmConfigError{message: fmt.Sprintf(format, args...)}
+}
+
+// ReconcileRealmConfig applies the realm config referenced by the RHMIConfig
+// to the realm. Clients are created through Keycloak client CRs selecting the
+// realm by its labels, and the rest through the admin API. Differences found
+// with a config that was already applied are reported as drift in the
+// RHMIConfig status. The reserved groups are managed by the operator and
+// can't be declared. If rhmiConfig is nil, the clients of a previous config
+// are removed
+func (r *Reconciler) ReconcileRealmConfig(ctx context.Context, serverClient k8sclient.Client, kc *keycloak.Keycloak, realmName string, realmLabels map[string]string, reservedGroups []string, rhmiConfig *integreatlyv1alpha1.RHMIConfig) (integreatlyv1alpha1.StatusPhase, error) {
+	if rhmiConfig == nil || rhmiConfig.Spec.UserSSO.RealmConfig == "" {
+		if _, err := r.reconcileRealmConfigClients(ctx, serverClient, kc.Namespace, realmName, realmLabels, nil); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+		if rhmiConfig == nil {
+			return integreatlyv1alpha1.PhaseCompleted, nil
+		}
+		return r.updateRealmConfigStatus(ctx, serverClient, rhmiConfig, nil)
+	}
+	configMapName := rhmiConfig.Spec.UserSSO.RealmConfig
+
+	status := &integreatlyv1alpha1.RHMIConfigStatusUserSSO{}
+	if rhmiConfig.Status.UserSSO != nil {
+		rhmiConfig.Status.UserSSO.DeepCopyInto(status)
+	}
+	status.Error = ""
+
+	configMap := &corev1.ConfigMap{}
+	err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: configMapName, Namespace: r.ConfigManager.GetOperatorNamespace()}, configMap)
+	if k8serr.IsNotFound(err) {
+		status.Error = fmt.Sprintf("realm config map %s not found", configMapName)
+		return r.updateRealmConfigStatus(ctx, serverClient, rhmiConfig, status)
+	}
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get realm config map %s: %w", configMapName, err)
+	}
+
+	realmConfig := &RealmConfig{}
+	if err := yaml.UnmarshalStrict([]byte(configMap.Data[RealmConfigKey]), realmConfig); err != nil {
+		status.Error = fmt.Sprintf("failed to parse %s of realm config map %s: %v", RealmConfigKey, configMapName, err)
+		return r.updateRealmConfigStatus(ctx, serverClient, rhmiConfig, status)
+	}
+	if err := validateRealmConfig(realmConfig, reservedGroups); err != nil {
+		status.Error = err.Error()
+		return r.updateRealmConfigStatus(ctx, serverClient, rhmiConfig, status)
+	}
+
+	readyClients, err := r.reconcileRealmConfigClients(ctx, serverClient, kc.Namespace, realmName, realmLabels, realmConfig.Clients)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
+	kcClient, err := r.KeycloakClientFactory.AuthenticatedClient(*kc)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+	adminClient, err := r.getKeycloakAdminClientFactory().AuthenticatedAdminClient(ctx, serverClient, *kc)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
+	drift, err := applyRealmConfig(kcClient, adminClient, realmName, realmConfig, readyClients)
+	var configErr *realmConfigError
+	if errors.As(err, &configErr) {
+		status.Error = configErr.Error()
+		return r.updateRealmConfigStatus(ctx, serverClient, rhmiConfig, status)
+	}
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to apply realm config %s: %w", configMapName, err)
+	}
+
+	if status.AppliedVersion != configMap.ResourceVersion {
+		// Differences with a new version of the config are not drift
+		status.AppliedVersion = configMap.ResourceVersion
+		status.Drift = nil
+		status.DriftDetectedAt = nil
+	} else if len(drift) > 0 {
+		r.Logger.Warnf("Realm %s drifted from realm config %s: %s", realmName, configMapName, strings.Join(drift, ", "))
+		now := metav1.Now()
+		status.Drift = drift
+		status.DriftDetectedAt = &now
+	}
+
+	return r.updateRealmConfigStatus(ctx, serverClient, rhmiConfig, status)
+}
+
+func (r *Reconciler) updateRealmConfigStatus(ctx context.Context, serverClient k8sclient.Client, rhmiConfig *integreatlyv1alpha1.RHMIConfig, status *integreatlyv1alpha1.RHMIConfigStatusUserSSO) (integreatlyv1alpha1.StatusPhase, error) {
+	if reflect.DeepEqual(status, rhmiConfig.Status.UserSSO) {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	if status != nil && status.Error != "" {
+		r.Logger.Errorf("Failed to apply realm config: %s", status.Error)
+	}
+	rhmiConfig.Status.UserSSO = status
+	if err := serverClient.Status().Update(ctx, rhmiConfig); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to update realm config status: %w", err)
+	}
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+func validateRealmConfig(realmConfig *RealmConfig, reservedGroups []string) error {
+	for name, lifespan := range map[string]*int{
+		"accessTokenLifespan":   realmConfig.AccessTokenLifespan,
+		"ssoSessionIdleTimeout": realmConfig.SSOSessionIdleTimeout,
+		"ssoSessionMaxLifespan": realmConfig.SSOSessionMaxLifespan,
+	} {
+		if lifespan != nil && *lifespan <= 0 {
+			return fmt.Errorf("%s must be greater than zero", name)
+		}
+	}
+
+	roles := map[string]bool{}
+	for _, role := range realmConfig.Roles {
+		if role.Name == "" || roles[role.Name] {
+			return fmt.Errorf("realm role names must be set and unique, found %q", role.Name)
+		}
+		roles[role.Name] = true
+	}
+
+	groups := map[string]bool{}
+	for _, group := range realmConfig.Groups {
+		if group.Name == "" || groups[group.Name] {
+			return fmt.Errorf("group names must be set and unique, found %q", group.Name)
+		}
+		if contains(reservedGroups, group.Name) {
+			return fmt.Errorf("group %s is managed by the operator and can't be declared", group.Name)
+		}
+		groups[group.Name] = true
+	}
+
+	clients := map[string]bool{}
+	for _, client := range realmConfig.Clients {
+		if errs := validation.IsDNS1123Subdomain(client.ClientID); len(errs) > 0 {
+			return fmt.Errorf("client ID %q is invalid: %s", client.ClientID, strings.Join(errs, ", "))
+		}
+		if clients[client.ClientID] {
+			return fmt.Errorf("client ID %s is declared more than once", client.ClientID)
+		}
+		clients[client.ClientID] = true
+	}
+
+	return nil
+}
+
+// reconcileRealmConfigClients creates a Keycloak client CR for each client,
+// and deletes the CRs of the clients that are no longer declared. It returns
+// the IDs of the clients whose CR has been applied by the Keycloak operator
+func (r *Reconciler) reconcileRealmConfigClients(ctx context.Context, serverClient k8sclient.Client, namespace, realmName string, realmLabels map[string]string, clients []keycloak.KeycloakAPIClient) ([]string, error) {
+	var names, readyClients []string
+	for i := range clients {
+		client := clients[i]
+		kcClient := &keycloak.KeycloakClient{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "realm-config-" + client.ClientID,
+				Namespace: namespace,
+			},
+		}
+		_, err := controllerutil.CreateOrUpdate(ctx, serverClient, kcClient, func() error {
+			if kcClient.Labels == nil {
+				kcClient.Labels = map[string]string{}
+			}
+			kcClient.Labels[realmConfigLabel] = realmName
+			kcClient.Spec.RealmSelector = &metav1.LabelSelector{MatchLabels: realmLabels}
+			kcClient.Spec.Client = &client
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create/update keycloak client %s: %w", client.ClientID, err)
+		}
+		names = append(names, kcClient.Name)
+		if kcClient.Status.Ready {
+			readyClients = append(readyClients, client.ClientID)
+		}
+	}
+
+	existing := &keycloak.KeycloakClientList{}
+	err := serverClient.List(ctx, existing, k8sclient.InNamespace(namespace), k8sclient.MatchingLabels{realmConfigLabel: realmName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keycloak clients of realm config: %w", err)
+	}
+	for i := range existing.Items {
+		kcClient := &existing.Items[i]
+		if contains(names, kcClient.Name) {
+			continue
+		}
+		if err := serverClient.Delete(ctx, kcClient); err != nil && !k8serr.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to delete keycloak client %s: %w", kcClient.Name, err)
+		}
+	}
+
+	return readyClients, nil
+}
+
+// applyRealmConfig applies the realm settings, roles and groups of the realm
+// config, and returns the differences found with the realm. The clients are
+// applied by the Keycloak operator, so differences are only reported for the
+// ready clients
+func applyRealmConfig(kcClient keycloakCommon.KeycloakInterface, adminClient KeycloakAdminInterface, realmName string, realmConfig *RealmConfig, readyClients []string) ([]string, error) {
+	var drift []string
+
+	settingsDrift, err := applyRealmSettings(adminClient, realmName, realmConfig)
+	if err != nil {
+		return nil, err
+	}
+	drift = append(drift, settingsDrift...)
+
+	existingRoles, err := adminClient.ListRealmRoles(realmName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list realm roles: %w", err)
+	}
+	roleNames := []string{}
+	for _, role := range existingRoles {
+		roleNames = append(roleNames, role.Name)
+	}
+	for _, declared := range realmConfig.Roles {
+		role := &RealmRole{Name: declared.Name, Description: declared.Description}
+		existing := findRealmRole(existingRoles, declared.Name)
+		if existing == nil {
+			drift = append(drift, fmt.Sprintf("realm role %s is missing", role.Name))
+			if err := adminClient.CreateRealmRole(role, realmName); err != nil {
+				return nil, fmt.Errorf("failed to create realm role %s: %w", role.Name, err)
+			}
+			roleNames = append(roleNames, role.Name)
+			continue
+		}
+		if existing.Description != role.Description {
+			drift = append(drift, fmt.Sprintf("realm role %s has description %q", role.Name, existing.Description))
+			if err := adminClient.UpdateRealmRole(role, realmName); err != nil {
+				return nil, fmt.Errorf("failed to update realm role %s: %w", role.Name, err)
+			}
+		}
+	}
+
+	clients, err := kcClient.ListClients(realmName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clients: %w", err)
+	}
+	for _, declared := range realmConfig.Clients {
+		if !contains(readyClients, declared.ClientID) {
+			continue
+		}
+		clientDrift, err := diffRealmConfigClient(declared, findClient(clients, declared.ClientID))
+		if err != nil {
+			return nil, err
+		}
+		drift = append(drift, clientDrift...)
+	}
+
+	for _, group := range realmConfig.Groups {
+		groupDrift, err := applyRealmConfigGroup(kcClient, realmName, group, roleNames, clients, realmConfig.Clients)
+		if err != nil {
+			return nil, err
+		}
+		drift = append(drift, groupDrift...)
+	}
+
+	return drift, nil
+}
+
+func applyRealmSettings(adminClient KeycloakAdminInterface, realmName string, realmConfig *RealmConfig) ([]string, error) {
+	declared := map[string]interface{}{}
+	if realmConfig.PasswordPolicy != "" {
+		declared["passwordPolicy"] = realmConfig.PasswordPolicy
+	}
+	if realmConfig.AccessTokenLifespan != nil {
+		declared["accessTokenLifespan"] = float64(*realmConfig.AccessTokenLifespan)
+	}
+	if realmConfig.SSOSessionIdleTimeout != nil {
+		declared["ssoSessionIdleTimeout"] = float64(*realmConfig.SSOSessionIdleTimeout)
+	}
+	if realmConfig.SSOSessionMaxLifespan != nil {
+		declared["ssoSessionMaxLifespan"] = float64(*realmConfig.SSOSessionMaxLifespan)
+	}
+	if len(declared) == 0 {
+		return nil, nil
+	}
+
+	settings, err := adminClient.GetRealmSettings(realmName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get realm settings: %w", err)
+	}
+
+	var drift []string
+	for name, value := range declared {
+		if settings[name] == value {
+			continue
+		}
+		drift = append(drift, fmt.Sprintf("%s is %v, expected %v", name, settings[name], value))
+		settings[name] = value
+	}
+	if len(drift) == 0 {
+		return nil, nil
+	}
+
+	if err := adminClient.UpdateRealmSettings(settings, realmName); err != nil {
+		return nil, fmt.Errorf("failed to update realm settings: %w", err)
+	}
+	return drift, nil
+}
+
+// applyRealmConfigGroup creates the group if it doesn't exist, and maps its
+// missing roles. Roles mapped to the group that are not declared are reported
+// as drift but kept. Client roles of declared clients that are not created
+// yet are mapped on a later reconcile
+func applyRealmConfigGroup(kcClient keycloakCommon.KeycloakInterface, realmName string, group RealmConfigGroup, roleNames []string, clients []*keycloak.KeycloakAPIClient, declaredClients []keycloak.KeycloakAPIClient) ([]string, error) {
+	var drift []string
+
+	existing, err := kcClient.FindGroupByName(group.Name, realmName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find group %s: %w", group.Name, err)
+	}
+	var groupID string
+	if existing != nil {
+		groupID = existing.ID
+	} else {
+		drift = append(drift, fmt.Sprintf("group %s is missing", group.Name))
+		if groupID, err = kcClient.CreateGroup(group.Name, realmName); err != nil {
+			return nil, fmt.Errorf("failed to create group %s: %w", group.Name, err)
+		}
+	}
+
+	for _, roleName := range group.RealmRoles {
+		if !contains(roleNames, roleName) {
+			return nil, newRealmConfigError("realm role %s of group %s doesn't exist", roleName, group.Name)
+		}
+	}
+	currentRoles, err := kcClient.ListGroupRealmRoles(realmName, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list realm roles of group %s: %w", group.Name, err)
+	}
+	availableRoles, err := kcClient.ListAvailableGroupRealmRoles(realmName, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list available realm roles of group %s: %w", group.Name, err)
+	}
+	roleDrift, err := diffGroupRoles(group.Name, "realm role", group.RealmRoles, currentRoles, availableRoles, func(role *keycloak.KeycloakUserRole) error {
+		_, err := kcClient.CreateGroupRealmRole(role, realmName, groupID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	drift = append(drift, roleDrift...)
+
+	for clientName, roles := range group.ClientRoles {
+		var clientID string
+		for _, client := range clients {
+			if client.ClientID == clientName {
+				clientID = client.ID
+				break
+			}
+		}
+		if clientID == "" {
+			if containsClient(declaredClients, clientName) {
+				continue
+			}
+			return nil, newRealmConfigError("client %s of group %s doesn't exist", clientName, group.Name)
+		}
+
+		currentRoles, err := kcClient.ListGroupClientRoles(realmName, clientID, groupID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s client roles of group %s: %w", clientName, group.Name, err)
+		}
+		availableRoles, err := kcClient.ListAvailableGroupClientRoles(realmName, clientID, groupID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list available %s client roles of group %s: %w", clientName, group.Name, err)
+		}
+		roleDrift, err := diffGroupRoles(group.Name, clientName+" client role", roles, currentRoles, availableRoles, func(role *keycloak.KeycloakUserRole) error {
+			_, err := kcClient.CreateGroupClientRole(role, realmName, clientID, groupID)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		drift = append(drift, roleDrift...)
+	}
+
+	return drift, nil
+}
+
+// diffGroupRoles maps the declared roles missing from the group, and returns
+// the differences between the declared and current roles
+func diffGroupRoles(groupName, kind string, declared []string, current, available []*keycloak.KeycloakUserRole, mapRole func(*keycloak.KeycloakUserRole) error) ([]string, error) {
+	var drift []string
+	currentNames := []string{}
+	for _, role := range current {
+		currentNames = append(currentNames, role.Name)
+		if !contains(declared, role.Name) {
+			drift = append(drift, fmt.Sprintf("group %s has %s %s", groupName, kind, role.Name))
+		}
+	}
+
+	for _, roleName := range declared {
+		if contains(currentNames, roleName) {
+			continue
+		}
+		var role *keycloak.KeycloakUserRole
+		for _, availableRole := range available {
+			if availableRole.Name == roleName {
+				role = availableRole
+				break
+			}
+		}
+		if role == nil {
+			return nil, newRealmConfigError("%s %s of group %s doesn't exist", kind, roleName, groupName)
+		}
+
+		drift = append(drift, fmt.Sprintf("group %s is missing %s %s", groupName, kind, roleName))
+		if err := mapRole(role); err != nil {
+			return nil, fmt.Errorf("failed to map %s %s to group %s: %w", kind, roleName, groupName, err)
+		}
+	}
+
+	return drift, nil
+}
+
+// diffRealmConfigClient compares the fields set in the declared client with
+// the client of the realm. The ID and secret are generated when not set, so
+// they're not compared
+func diffRealmConfigClient(declared keycloak.KeycloakAPIClient, existing *keycloak.KeycloakAPIClient) ([]string, error) {
+	if existing == nil {
+		return []string{fmt.Sprintf("client %s is missing", declared.ClientID)}, nil
+	}
+
+	declaredFields, err := toFields(declared)
+	if err != nil {
+		return nil, err
+	}
+	existingFields, err := toFields(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	var drift []string
+	for name, value := range declaredFields {
+		if name == "id" || name == "secret" || reflect.DeepEqual(existingFields[name], value) {
+			continue
+		}
+		drift = append(drift, fmt.Sprintf("client %s has %s %v, expected %v", declared.ClientID, name, existingFields[name], value))
+	}
+	sort.Strings(drift)
+	return drift, nil
+}
+
+// toFields converts the client to its JSON fields, omitting the empty ones
+func toFields(client interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal client: %w", err)
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal client: %w", err)
+	}
+	return fields, nil
+}
+
+func findClient(clients []*keycloak.KeycloakAPIClient, clientID string) *keycloak.KeycloakAPIClient {
+	for _, client := range clients {
+		if client.ClientID == clientID {
+			return client
+		}
+	}
+	return nil
+}
+
+func findRealmRole(roles []*RealmRole, name string) *RealmRole {
+	for _, role := range roles {
+		if role.Name == name {
+			return role
+		}
+	}
+	return nil
+}
+
+func containsClient(clients []keycloak.KeycloakAPIClient, clientID string) bool {
+	for _, client := range clients {
+		if client.ClientID == clientID {
+			return true
+		}
+	}
+	return false
+}
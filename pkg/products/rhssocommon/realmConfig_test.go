@@ -0,0 +1,195 @@
+package rhssocommon
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testRealmConfig = `
+passwordPolicy: length(12)
+accessTokenLifespan: 300
+roles:
+  - name: auditor
+    description: Read only access
+groups:
+  - name: operations
+    realmRoles:
+      - create-realm
+    clientRoles:
+      master-realm:
+        - manage-users
+clients:
+  - clientId: grafana
+    redirectUris:
+      - https://grafana.example.com/*
+`
+
+func TestReconciler_ReconcileRealmConfig(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rhmiConfig := &integreatlyv1alpha1.RHMIConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rhmi-config",
+			Namespace: defaultOperatorNamespace,
+		},
+		Spec: integreatlyv1alpha1.RHMIConfigSpec{
+			UserSSO: integreatlyv1alpha1.UserSSO{RealmConfig: "user-sso-realm"},
+		},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "user-sso-realm",
+			Namespace:       defaultOperatorNamespace,
+			ResourceVersion: "1",
+		},
+		Data: map[string]string{RealmConfigKey: testRealmConfig},
+	}
+	kc := &keycloak.Keycloak{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      keycloakName,
+			Namespace: defaultNamespace,
+		},
+	}
+
+	kcClient, mockContext := createKeycloakInterfaceMock()
+	adminClient := &fakeKeycloakAdminClient{
+		realmRoles:    []*RealmRole{{Name: "create-realm"}},
+		realmSettings: map[string]interface{}{"realm": masterRealmName, "passwordPolicy": "length(8)"},
+	}
+	r := &Reconciler{
+		ConfigManager: basicConfigMock(),
+		Logger:        logrus.NewEntry(logrus.StandardLogger()),
+		KeycloakClientFactory: &keycloakCommon.KeycloakClientFactoryMock{AuthenticatedClientFunc: func(kc keycloak.Keycloak) (keycloakCommon.KeycloakInterface, error) {
+			return kcClient, nil
+		}},
+		KeycloakAdminClientFactory: adminClient,
+	}
+	serverClient := fake.NewFakeClientWithScheme(scheme, rhmiConfig, configMap, kc)
+	realmLabels := map[string]string{"sso": "master"}
+
+	reconcile := func() *integreatlyv1alpha1.RHMIConfig {
+		config := &integreatlyv1alpha1.RHMIConfig{}
+		if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "rhmi-config", Namespace: defaultOperatorNamespace}, config); err != nil {
+			t.Fatalf("unexpected error getting rhmi config: %v", err)
+		}
+		phase, err := r.ReconcileRealmConfig(context.TODO(), serverClient, kc, masterRealmName, realmLabels, []string{"dedicated-admins"}, config)
+		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			t.Fatalf("unexpected phase %s and error %v", phase, err)
+		}
+		return config
+	}
+
+	// The first apply of the config is not drift
+	config := reconcile()
+	if status := config.Status.UserSSO; status == nil || status.Error != "" || len(status.Drift) != 0 || status.AppliedVersion == "" {
+		t.Fatalf("unexpected status %+v", config.Status.UserSSO)
+	}
+	if adminClient.realmSettings["passwordPolicy"] != "length(12)" || adminClient.realmSettings["accessTokenLifespan"] != float64(300) {
+		t.Errorf("unexpected realm settings %v", adminClient.realmSettings)
+	}
+	if findRealmRole(adminClient.realmRoles, "auditor") == nil {
+		t.Errorf("expected auditor realm role to be created")
+	}
+	if len(mockContext.Groups) != 1 || len(mockContext.RealmRoles[mockContext.Groups[0].ID]) != 1 || len(mockContext.ClientRoles[mockContext.Groups[0].ID]) != 1 {
+		t.Errorf("expected operations group to be created with its roles, got %v", mockContext.Groups)
+	}
+	client := &keycloak.KeycloakClient{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "realm-config-grafana", Namespace: defaultNamespace}, client); err != nil {
+		t.Fatalf("expected keycloak client to be created: %v", err)
+	}
+	if client.Spec.Client.ClientID != "grafana" || client.Spec.RealmSelector.MatchLabels["sso"] != "master" {
+		t.Errorf("unexpected keycloak client spec %+v", client.Spec)
+	}
+
+	// Changes made outside the config are reported and corrected
+	adminClient.realmSettings["passwordPolicy"] = "length(6)"
+	config = reconcile()
+	if status := config.Status.UserSSO; len(status.Drift) != 1 || !strings.HasPrefix(status.Drift[0], "passwordPolicy is length(6)") || status.DriftDetectedAt == nil {
+		t.Errorf("expected password policy drift, got %+v", status)
+	}
+	if adminClient.realmSettings["passwordPolicy"] != "length(12)" {
+		t.Errorf("expected password policy to be corrected, got %v", adminClient.realmSettings["passwordPolicy"])
+	}
+
+	// Clients are compared once the keycloak operator has applied them
+	client.Status.Ready = true
+	if err := serverClient.Update(context.TODO(), client); err != nil {
+		t.Fatal(err)
+	}
+	config = reconcile()
+	if status := config.Status.UserSSO; len(status.Drift) != 1 || status.Drift[0] != "client grafana is missing" {
+		t.Errorf("expected missing client drift, got %+v", status)
+	}
+
+	// Invalid configs are reported in the status
+	configMap.Data[RealmConfigKey] = "groups:\n  - name: dedicated-admins\n"
+	if err := serverClient.Update(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+	config = reconcile()
+	if !strings.Contains(config.Status.UserSSO.Error, "dedicated-admins") {
+		t.Errorf("expected reserved group error, got %+v", config.Status.UserSSO)
+	}
+
+	// Removing the config removes its clients and status
+	config.Spec.UserSSO.RealmConfig = ""
+	if err := serverClient.Update(context.TODO(), config); err != nil {
+		t.Fatal(err)
+	}
+	config = reconcile()
+	if config.Status.UserSSO != nil {
+		t.Errorf("expected status to be removed, got %+v", config.Status.UserSSO)
+	}
+	err = serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "realm-config-grafana", Namespace: defaultNamespace}, client)
+	if !k8serr.IsNotFound(err) {
+		t.Errorf("expected keycloak client to be deleted, got error %v", err)
+	}
+
+	// Without rhmi-config there's no status to update
+	phase, err := r.ReconcileRealmConfig(context.TODO(), serverClient, kc, masterRealmName, realmLabels, []string{"dedicated-admins"}, nil)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Errorf("unexpected phase %s and error %v without rhmi config", phase, err)
+	}
+}
+
+func TestDiffRealmConfigClient(t *testing.T) {
+	declared := keycloak.KeycloakAPIClient{
+		ClientID:     "grafana",
+		Secret:       "declared",
+		RedirectUris: []string{"https://grafana.example.com/*"},
+	}
+	existing := &keycloak.KeycloakAPIClient{
+		ID:           "1234",
+		ClientID:     "grafana",
+		Secret:       "generated",
+		Enabled:      true,
+		RedirectUris: []string{"*"},
+	}
+
+	drift, err := diffRealmConfigClient(declared, existing)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(drift) != 1 || !strings.HasPrefix(drift[0], "client grafana has redirectUris [*]") {
+		t.Errorf("expected redirect uris drift only, got %v", drift)
+	}
+
+	existing.RedirectUris = declared.RedirectUris
+	if drift, err := diffRealmConfigClient(declared, existing); err != nil || len(drift) != 0 {
+		t.Errorf("expected no drift, got %v and error %v", drift, err)
+	}
+}
@@ -0,0 +1,555 @@
+package rhssocommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultRealmExportInterval is the time between two exports of a realm
+	DefaultRealmExportInterval = 24 * time.Hour
+
+	// RealmExportRetention is the number of exports of a realm kept in the
+	// backup storage. The exports contain the client secrets of the realm, so
+	// the older ones are deleted
+	RealmExportRetention = 7
+
+	realmExportPrefix     = "rhsso-realm-exports"
+	realmExportTimeFormat = "20060102T150405Z"
+)
+
+// RealmExport holds the contents of a realm that are configured through the
+// Keycloak admin API, so they can be rebuilt in a new RHSSO instance. Users
+// are not exported, as they are synchronised from the cluster identity
+// provider
+type RealmExport struct {
+	ExportedAt          string                               `json:"exportedAt"`
+	Realm               *keycloak.KeycloakAPIRealm           `json:"realm"`
+	Clients             []*keycloak.KeycloakAPIClient        `json:"clients,omitempty"`
+	IdentityProviders   []*keycloak.KeycloakIdentityProvider `json:"identityProviders,omitempty"`
+	AuthenticationFlows []*RealmExportFlow                   `json:"authenticationFlows,omitempty"`
+	Groups              []*RealmExportGroup                  `json:"groups,omitempty"`
+}
+
+type RealmExportFlow struct {
+	Flow       keycloakCommon.AuthenticationFlow `json:"flow"`
+	Executions []*RealmExportExecution           `json:"executions,omitempty"`
+}
+
+type RealmExportExecution struct {
+	Execution keycloak.AuthenticationExecutionInfo `json:"execution"`
+	Config    *keycloak.AuthenticatorConfig        `json:"config,omitempty"`
+}
+
+type RealmExportGroup struct {
+	Name       string   `json:"name"`
+	Default    bool     `json:"default,omitempty"`
+	RealmRoles []string `json:"realmRoles,omitempty"`
+	// ClientRoles are the names of the client roles mapped to the group,
+	// keyed by the client name (`clientId` field)
+	ClientRoles map[string][]string `json:"clientRoles,omitempty"`
+	SubGroups   []*RealmExportGroup `json:"subGroups,omitempty"`
+}
+
+// ReconcileRealmExport exports the realm to the backup storage when the
+// export interval has passed since its last export. Installations without
+// backup storage are skipped
+func (r *Reconciler) ReconcileRealmExport(ctx context.Context, serverClient k8sclient.Client, keycloakName, realmName string, groupNames []string, config config.ConfigReadable, ssoCommon *config.RHSSOCommon) error {
+	now := time.Now().UTC()
+	if now.Sub(ssoCommon.GetRealmExportTime()) < DefaultRealmExportInterval {
+		return nil
+	}
+
+	store, err := r.getRealmExportStore(ctx, serverClient)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		r.Logger.Debugf("No backup storage found, skipping export of realm %s", realmName)
+		return nil
+	}
+
+	kcClient, err := r.getKeycloakClient(ctx, serverClient, keycloakName, config.GetNamespace())
+	if err != nil {
+		return err
+	}
+
+	export, err := ExportRealm(kcClient, realmName, groupNames)
+	if err != nil {
+		return fmt.Errorf("failed to export realm %s: %w", realmName, err)
+	}
+	export.ExportedAt = now.Format(time.RFC3339)
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		return fmt.Errorf("failed to marshal export of realm %s: %w", realmName, err)
+	}
+
+	keyPrefix := fmt.Sprintf("%s/%s/%s-", realmExportPrefix, config.GetProductName(), realmName)
+	key := fmt.Sprintf("%s%s.json", keyPrefix, now.Format(realmExportTimeFormat))
+	if err := store.Write(ctx, key, data); err != nil {
+		return err
+	}
+
+	ssoCommon.SetRealmExportTime(now)
+	ssoCommon.SetRealmExportKey(key)
+	if err := r.ConfigManager.WriteConfig(config); err != nil {
+		return fmt.Errorf("failed to write realm export to config: %w", err)
+	}
+
+	r.Logger.Infof("Exported realm %s to %s", realmName, key)
+	return pruneRealmExports(ctx, store, keyPrefix)
+}
+
+// pruneRealmExports deletes the exports with the key prefix beyond the
+// RealmExportRetention most recent ones. The keys end with the export time,
+// so they sort by age
+func pruneRealmExports(ctx context.Context, store RealmExportStore, keyPrefix string) error {
+	keys, err := store.List(ctx, keyPrefix)
+	if err != nil {
+		return err
+	}
+	if len(keys) <= RealmExportRetention {
+		return nil
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys[:len(keys)-RealmExportRetention] {
+		if err := store.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReconcileRealmImport imports the realm export with the given key from the
+// backup storage. Each export is only imported once
+func (r *Reconciler) ReconcileRealmImport(ctx context.Context, serverClient k8sclient.Client, keycloakName, key string, config config.ConfigReadable, ssoCommon *config.RHSSOCommon) (integreatlyv1alpha1.StatusPhase, error) {
+	if key == "" || key == ssoCommon.GetRealmImportedKey() {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	store, err := r.getRealmExportStore(ctx, serverClient)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+	if store == nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("no backup storage found to import realm export %s from", key)
+	}
+
+	data, err := store.Read(ctx, key)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+	export := &RealmExport{}
+	if err := json.Unmarshal(data, export); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to unmarshal realm export %s: %w", key, err)
+	}
+
+	kcClient, err := r.getKeycloakClient(ctx, serverClient, keycloakName, config.GetNamespace())
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
+	if err := ImportRealm(kcClient, export); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to import realm export %s: %w", key, err)
+	}
+
+	ssoCommon.SetRealmImportedKey(key)
+	if err := r.ConfigManager.WriteConfig(config); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to write realm import to config: %w", err)
+	}
+
+	r.Logger.Infof("Imported realm %s from %s", export.Realm.Realm, key)
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+func (r *Reconciler) getRealmExportStore(ctx context.Context, serverClient k8sclient.Client) (RealmExportStore, error) {
+	if r.RealmExportStore != nil {
+		return r.RealmExportStore, nil
+	}
+
+	store, err := NewS3RealmExportStore(ctx, serverClient, r.ConfigManager.GetBackupsSecretName(), r.Installation.Namespace)
+	if err != nil || store == nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (r *Reconciler) getKeycloakClient(ctx context.Context, serverClient k8sclient.Client, keycloakName, namespace string) (keycloakCommon.KeycloakInterface, error) {
+	kc := &keycloak.Keycloak{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: keycloakName, Namespace: namespace}, kc); err != nil {
+		return nil, fmt.Errorf("failed to get keycloak custom resource: %w", err)
+	}
+
+	return r.KeycloakClientFactory.AuthenticatedClient(*kc)
+}
+
+// ExportRealm reads the realm settings, clients, identity providers,
+// authentication flows, and the groups with the given names or default, with
+// their role mappings
+func ExportRealm(kcClient keycloakCommon.KeycloakInterface, realmName string, groupNames []string) (*RealmExport, error) {
+	realm, err := kcClient.GetRealm(realmName)
+	if err != nil {
+		return nil, err
+	}
+	if realm == nil {
+		return nil, fmt.Errorf("realm %s not found", realmName)
+	}
+
+	export := &RealmExport{Realm: realm.Spec.Realm}
+
+	if export.Clients, err = kcClient.ListClients(realmName); err != nil {
+		return nil, fmt.Errorf("failed to list clients: %w", err)
+	}
+	if export.IdentityProviders, err = kcClient.ListIdentityProviders(realmName); err != nil {
+		return nil, fmt.Errorf("failed to list identity providers: %w", err)
+	}
+
+	flows, err := kcClient.ListAuthenticationFlows(realmName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list authentication flows: %w", err)
+	}
+	for _, flow := range flows {
+		if !flow.TopLevel {
+			continue
+		}
+
+		exportFlow, err := exportAuthenticationFlow(kcClient, realmName, flow)
+		if err != nil {
+			return nil, err
+		}
+		export.AuthenticationFlows = append(export.AuthenticationFlows, exportFlow)
+	}
+
+	defaultGroups, err := kcClient.ListDefaultGroups(realmName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list default groups: %w", err)
+	}
+	defaultGroupIDs := map[string]bool{}
+	for _, group := range defaultGroups {
+		defaultGroupIDs[group.ID] = true
+		if !contains(groupNames, group.Name) {
+			groupNames = append(groupNames, group.Name)
+		}
+	}
+
+	for _, groupName := range groupNames {
+		group, err := kcClient.FindGroupByName(groupName, realmName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find group %s: %w", groupName, err)
+		}
+		if group == nil {
+			continue
+		}
+
+		exportGroup, err := exportGroup(kcClient, realmName, group, export.Clients, defaultGroupIDs)
+		if err != nil {
+			return nil, err
+		}
+		export.Groups = append(export.Groups, exportGroup)
+	}
+
+	return export, nil
+}
+
+func exportAuthenticationFlow(kcClient keycloakCommon.KeycloakInterface, realmName string, flow *keycloakCommon.AuthenticationFlow) (*RealmExportFlow, error) {
+	executions, err := kcClient.ListAuthenticationExecutionsForFlow(flow.Alias, realmName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list executions of authentication flow %s: %w", flow.Alias, err)
+	}
+
+	exportFlow := &RealmExportFlow{Flow: *flow}
+	for _, execution := range executions {
+		exportExecution := &RealmExportExecution{Execution: *execution}
+		if execution.AuthenticationConfig != "" {
+			exportExecution.Config, err = kcClient.GetAuthenticatorConfig(execution.AuthenticationConfig, realmName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get authenticator config of %s: %w", execution.DisplayName, err)
+			}
+		}
+		exportFlow.Executions = append(exportFlow.Executions, exportExecution)
+	}
+
+	return exportFlow, nil
+}
+
+func exportGroup(kcClient keycloakCommon.KeycloakInterface, realmName string, group *keycloakCommon.Group, clients []*keycloak.KeycloakAPIClient, defaultGroupIDs map[string]bool) (*RealmExportGroup, error) {
+	export := &RealmExportGroup{
+		Name:    group.Name,
+		Default: defaultGroupIDs[group.ID],
+	}
+
+	realmRoles, err := kcClient.ListGroupRealmRoles(realmName, group.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list realm roles of group %s: %w", group.Name, err)
+	}
+	for _, role := range realmRoles {
+		export.RealmRoles = append(export.RealmRoles, role.Name)
+	}
+
+	for _, client := range clients {
+		clientRoles, err := kcClient.ListGroupClientRoles(realmName, client.ID, group.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s client roles of group %s: %w", client.ClientID, group.Name, err)
+		}
+		for _, role := range clientRoles {
+			if export.ClientRoles == nil {
+				export.ClientRoles = map[string][]string{}
+			}
+			export.ClientRoles[client.ClientID] = append(export.ClientRoles[client.ClientID], role.Name)
+		}
+	}
+
+	for _, subGroup := range group.SubGroups {
+		exportSubGroup, err := exportGroup(kcClient, realmName, subGroup, clients, defaultGroupIDs)
+		if err != nil {
+			return nil, err
+		}
+		export.SubGroups = append(export.SubGroups, exportSubGroup)
+	}
+
+	return export, nil
+}
+
+// ImportRealm rebuilds the contents of the realm export. The realm is created
+// if it doesn't exist, and the existing clients, identity providers,
+// authentication flows and groups are updated to match the export
+func ImportRealm(kcClient keycloakCommon.KeycloakInterface, export *RealmExport) error {
+	if export.Realm == nil {
+		return fmt.Errorf("realm export has no realm")
+	}
+	realmName := export.Realm.Realm
+
+	realm, err := kcClient.GetRealm(realmName)
+	if err != nil {
+		return err
+	}
+	if realm == nil {
+		if _, err := kcClient.CreateRealm(&keycloak.KeycloakRealm{Spec: keycloak.KeycloakRealmSpec{Realm: export.Realm}}); err != nil {
+			return fmt.Errorf("failed to create realm %s: %w", realmName, err)
+		}
+	}
+
+	if err := importClients(kcClient, realmName, export.Clients); err != nil {
+		return err
+	}
+
+	for _, identityProvider := range export.IdentityProviders {
+		existing, err := kcClient.GetIdentityProvider(identityProvider.Alias, realmName)
+		if err != nil {
+			return fmt.Errorf("failed to get identity provider %s: %w", identityProvider.Alias, err)
+		}
+		if existing != nil {
+			err = kcClient.UpdateIdentityProvider(identityProvider, realmName)
+		} else {
+			_, err = kcClient.CreateIdentityProvider(identityProvider, realmName)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to import identity provider %s: %w", identityProvider.Alias, err)
+		}
+	}
+
+	for _, flow := range export.AuthenticationFlows {
+		if err := importAuthenticationFlow(kcClient, realmName, flow); err != nil {
+			return err
+		}
+	}
+
+	clients, err := kcClient.ListClients(realmName)
+	if err != nil {
+		return fmt.Errorf("failed to list clients: %w", err)
+	}
+	clientIDs := map[string]string{}
+	for _, client := range clients {
+		clientIDs[client.ClientID] = client.ID
+	}
+
+	for _, group := range export.Groups {
+		if _, err := importGroup(kcClient, realmName, group, clientIDs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func importClients(kcClient keycloakCommon.KeycloakInterface, realmName string, clients []*keycloak.KeycloakAPIClient) error {
+	existingClients, err := kcClient.ListClients(realmName)
+	if err != nil {
+		return fmt.Errorf("failed to list clients: %w", err)
+	}
+	existingIDs := map[string]string{}
+	for _, client := range existingClients {
+		existingIDs[client.ClientID] = client.ID
+	}
+
+	for _, exportClient := range clients {
+		client := *exportClient
+		if id, ok := existingIDs[client.ClientID]; ok {
+			client.ID = id
+			err = kcClient.UpdateClient(&client, realmName)
+		} else {
+			client.ID = ""
+			_, err = kcClient.CreateClient(&client, realmName)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to import client %s: %w", client.ClientID, err)
+		}
+	}
+
+	return nil
+}
+
+// importAuthenticationFlow creates the flow if it doesn't exist, and sets the
+// requirement and authenticator config of its executions. Only the top level
+// executions of new flows are created
+func importAuthenticationFlow(kcClient keycloakCommon.KeycloakInterface, realmName string, flow *RealmExportFlow) error {
+	alias := flow.Flow.Alias
+
+	existing, err := kcClient.FindAuthenticationFlowByAlias(alias, realmName)
+	if err != nil {
+		return fmt.Errorf("failed to find authentication flow %s: %w", alias, err)
+	}
+	if existing == nil {
+		if flow.Flow.BuiltIn {
+			return fmt.Errorf("built in authentication flow %s not found", alias)
+		}
+
+		newFlow := flow.Flow
+		newFlow.ID = ""
+		if _, err := kcClient.CreateAuthenticationFlow(newFlow, realmName); err != nil {
+			return fmt.Errorf("failed to create authentication flow %s: %w", alias, err)
+		}
+
+		for _, execution := range flow.Executions {
+			if execution.Execution.Level != 0 || execution.Execution.AuthenticationFlow {
+				continue
+			}
+			err := kcClient.AddExecutionToAuthenticatonFlow(alias, realmName, execution.Execution.ProviderID, keycloakCommon.Requirement(execution.Execution.Requirement))
+			if err != nil {
+				return fmt.Errorf("failed to add execution %s to authentication flow %s: %w", execution.Execution.DisplayName, alias, err)
+			}
+		}
+	}
+
+	for _, execution := range flow.Executions {
+		exportExecution := execution.Execution
+		current, err := kcClient.FindAuthenticationExecutionForFlow(alias, realmName, func(info *keycloak.AuthenticationExecutionInfo) bool {
+			return info.ProviderID == exportExecution.ProviderID &&
+				info.DisplayName == exportExecution.DisplayName &&
+				info.Level == exportExecution.Level
+		})
+		if err != nil {
+			return fmt.Errorf("failed to find execution %s of authentication flow %s: %w", exportExecution.DisplayName, alias, err)
+		}
+		if current == nil {
+			continue
+		}
+
+		if exportExecution.Requirement != "" && current.Requirement != exportExecution.Requirement {
+			current.Requirement = exportExecution.Requirement
+			if err := kcClient.UpdateAuthenticationExecutionForFlow(alias, realmName, current); err != nil {
+				return fmt.Errorf("failed to update execution %s of authentication flow %s: %w", current.DisplayName, alias, err)
+			}
+		}
+
+		if execution.Config != nil && current.AuthenticationConfig == "" {
+			config := *execution.Config
+			config.ID = ""
+			if _, err := kcClient.CreateAuthenticatorConfig(&config, realmName, current.ID); err != nil {
+				return fmt.Errorf("failed to create authenticator config of %s: %w", current.DisplayName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func importGroup(kcClient keycloakCommon.KeycloakInterface, realmName string, group *RealmExportGroup, clientIDs map[string]string) (string, error) {
+	existing, err := kcClient.FindGroupByName(group.Name, realmName)
+	if err != nil {
+		return "", fmt.Errorf("failed to find group %s: %w", group.Name, err)
+	}
+
+	var groupID string
+	if existing != nil {
+		groupID = existing.ID
+	} else {
+		groupID, err = kcClient.CreateGroup(group.Name, realmName)
+		if err != nil {
+			return "", fmt.Errorf("failed to create group %s: %w", group.Name, err)
+		}
+	}
+
+	if group.Default {
+		if err := kcClient.MakeGroupDefault(groupID, realmName); err != nil {
+			return "", fmt.Errorf("failed to make group %s default: %w", group.Name, err)
+		}
+	}
+
+	if len(group.RealmRoles) > 0 {
+		availableRoles, err := kcClient.ListAvailableGroupRealmRoles(realmName, groupID)
+		if err != nil {
+			return "", fmt.Errorf("failed to list available realm roles of group %s: %w", group.Name, err)
+		}
+		for _, role := range availableRoles {
+			if !contains(group.RealmRoles, role.Name) {
+				continue
+			}
+			if _, err := kcClient.CreateGroupRealmRole(role, realmName, groupID); err != nil {
+				return "", fmt.Errorf("failed to map realm role %s to group %s: %w", role.Name, group.Name, err)
+			}
+		}
+	}
+
+	for clientName, roleNames := range group.ClientRoles {
+		clientID, ok := clientIDs[clientName]
+		if !ok {
+			return "", fmt.Errorf("client %s of group %s not found", clientName, group.Name)
+		}
+
+		availableRoles, err := kcClient.ListAvailableGroupClientRoles(realmName, clientID, groupID)
+		if err != nil {
+			return "", fmt.Errorf("failed to list available %s client roles of group %s: %w", clientName, group.Name, err)
+		}
+		for _, role := range availableRoles {
+			if !contains(roleNames, role.Name) {
+				continue
+			}
+			if _, err := kcClient.CreateGroupClientRole(role, realmName, clientID, groupID); err != nil {
+				return "", fmt.Errorf("failed to map client role %s to group %s: %w", role.Name, group.Name, err)
+			}
+		}
+	}
+
+	for _, subGroup := range group.SubGroups {
+		subGroupID, err := importGroup(kcClient, realmName, subGroup, clientIDs)
+		if err != nil {
+			return "", err
+		}
+		if err := kcClient.SetGroupChild(groupID, realmName, &keycloakCommon.Group{ID: subGroupID}); err != nil {
+			return "", fmt.Errorf("failed to set group %s as child of %s: %w", subGroup.Name, group.Name, err)
+		}
+	}
+
+	return groupID, nil
+}
+
+func contains(items []string, find string) bool {
+	for _, item := range items {
+		if item == find {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package rhssocommon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RealmExportStore stores the realm exports
+type RealmExportStore interface {
+	Write(ctx context.Context, key string, data []byte) error
+	Read(ctx context.Context, key string) ([]byte, error)
+	// List returns the keys of the exports starting with the prefix
+	List(ctx context.Context, prefix string) ([]string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// S3RealmExportStore stores the realm exports in the S3 bucket of the backups
+type S3RealmExportStore struct {
+	bucket string
+	client s3iface.S3API
+}
+
+var _ RealmExportStore = &S3RealmExportStore{}
+
+// NewS3RealmExportStore creates a store for the bucket of the backups, from
+// the secret created by the cloud resource operator for the backups blob
+// storage. It returns nil if the secret doesn't exist, as installations
+// without backups storage don't have it
+func NewS3RealmExportStore(ctx context.Context, serverClient k8sclient.Client, secretName, namespace string) (*S3RealmExportStore, error) {
+	secret := &corev1.Secret{}
+	err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: secretName, Namespace: namespace}, secret)
+	if k8serr.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backups secret %s: %w", secretName, err)
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(string(secret.Data["bucketRegion"])),
+		Credentials: credentials.NewStaticCredentials(
+			string(secret.Data["credentialKeyID"]),
+			string(secret.Data["credentialSecretKey"]),
+			"",
+		),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aws session: %w", err)
+	}
+
+	return &S3RealmExportStore{
+		bucket: string(secret.Data["bucketName"]),
+		client: s3.New(sess),
+	}, nil
+}
+
+func (s *S3RealmExportStore) Write(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:               aws.String(s.bucket),
+		Key:                  aws.String(key),
+		Body:                 bytes.NewReader(data),
+		ContentType:          aws.String("application/json"),
+		ServerSideEncryption: aws.String(s3.ServerSideEncryptionAes256),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write %s to bucket %s: %w", key, s.bucket, err)
+	}
+	return nil
+}
+
+func (s *S3RealmExportStore) Read(ctx context.Context, key string) ([]byte, error) {
+	output, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from bucket %s: %w", key, s.bucket, err)
+	}
+	defer output.Body.Close()
+
+	return ioutil.ReadAll(output.Body)
+}
+
+func (s *S3RealmExportStore) List(ctx context.Context, prefix string) ([]string, error) {
+	keys := []string{}
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, object := range page.Contents {
+			keys = append(keys, aws.StringValue(object.Key))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s in bucket %s: %w", prefix, s.bucket, err)
+	}
+	return keys, nil
+}
+
+func (s *S3RealmExportStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete %s from bucket %s: %w", key, s.bucket, err)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package rhssocommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
+	keycloak "github.com/keycloak/keycloak-operator/pkg/apis/keycloak/v1alpha1"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeRealmExportStore struct {
+	objects map[string][]byte
+}
+
+func (s *fakeRealmExportStore) Write(_ context.Context, key string, data []byte) error {
+	s.objects[key] = data
+	return nil
+}
+
+func (s *fakeRealmExportStore) List(_ context.Context, prefix string) ([]string, error) {
+	keys := []string{}
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (s *fakeRealmExportStore) Delete(_ context.Context, key string) error {
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *fakeRealmExportStore) Read(_ context.Context, key string) ([]byte, error) {
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", key)
+	}
+	return data, nil
+}
+
+// createRealmMock returns a keycloak client mock for the master realm, with
+// the dedicated admins group and its realm managers subgroup
+func createRealmMock(t *testing.T) (*keycloakCommon.KeycloakInterfaceMock, *mockClientContext) {
+	kcMock, mockContext := createImportMock()
+
+	kcMock.GetRealmFunc = func(realmName string) (*keycloak.KeycloakRealm, error) {
+		return getKcr(keycloak.KeycloakRealmStatus{}), nil
+	}
+	kcMock.ListIdentityProvidersFunc = func(realmName string) ([]*keycloak.KeycloakIdentityProvider, error) {
+		return []*keycloak.KeycloakIdentityProvider{{Alias: "openshift-v4", ProviderID: "openshift-v4"}}, nil
+	}
+	kcMock.ListAuthenticationFlowsFunc = func(realmName string) ([]*keycloakCommon.AuthenticationFlow, error) {
+		return []*keycloakCommon.AuthenticationFlow{
+			{Alias: firstBrokerLoginFlowAlias, BuiltIn: true, TopLevel: true},
+			{Alias: "nested", TopLevel: false},
+		}, nil
+	}
+
+	// Only the master-realm client has roles mapped to the groups
+	listGroupClientRoles := kcMock.ListGroupClientRolesFunc
+	kcMock.ListGroupClientRolesFunc = func(realmName, clientID, groupID string) ([]*keycloak.KeycloakUserRole, error) {
+		if clientID != "master-realm" {
+			return nil, nil
+		}
+		return listGroupClientRoles(realmName, clientID, groupID)
+	}
+
+	dedicatedAdminsID, _ := kcMock.CreateGroup("dedicated-admins", masterRealmName)
+	realmManagersID, _ := kcMock.CreateGroup("realm-managers", masterRealmName)
+	if err := kcMock.SetGroupChild(dedicatedAdminsID, masterRealmName, &keycloakCommon.Group{ID: realmManagersID}); err != nil {
+		t.Fatalf("unexpected error creating groups: %v", err)
+	}
+	kcMock.MakeGroupDefault(dedicatedAdminsID, masterRealmName)
+	kcMock.CreateGroupRealmRole(&keycloak.KeycloakUserRole{Name: "create-realm"}, masterRealmName, dedicatedAdminsID)
+	kcMock.CreateGroupClientRole(&keycloak.KeycloakUserRole{Name: "manage-users"}, masterRealmName, "master-realm", realmManagersID)
+
+	return kcMock, mockContext
+}
+
+// createImportMock returns a keycloak client mock supporting the calls made
+// to import a realm
+func createImportMock() (*keycloakCommon.KeycloakInterfaceMock, *mockClientContext) {
+	kcClient, mockContext := createKeycloakInterfaceMock()
+	kcMock := kcClient.(*keycloakCommon.KeycloakInterfaceMock)
+
+	kcMock.UpdateClientFunc = func(client *keycloak.KeycloakAPIClient, realmName string) error {
+		return nil
+	}
+	kcMock.GetIdentityProviderFunc = func(alias string, realmName string) (*keycloak.KeycloakIdentityProvider, error) {
+		return nil, nil
+	}
+	kcMock.CreateIdentityProviderFunc = func(identityProvider *keycloak.KeycloakIdentityProvider, realmName string) (string, error) {
+		return identityProvider.Alias, nil
+	}
+	kcMock.FindAuthenticationFlowByAliasFunc = func(flowAlias string, realmName string) (*keycloakCommon.AuthenticationFlow, error) {
+		return &keycloakCommon.AuthenticationFlow{Alias: flowAlias, BuiltIn: true}, nil
+	}
+
+	return kcMock, mockContext
+}
+
+func TestExportImportRealm(t *testing.T) {
+	source, _ := createRealmMock(t)
+
+	export, err := ExportRealm(source, masterRealmName, []string{"dedicated-admins"})
+	if err != nil {
+		t.Fatalf("unexpected error exporting realm: %v", err)
+	}
+	if len(export.AuthenticationFlows) != 1 || len(export.AuthenticationFlows[0].Executions) != 2 {
+		t.Fatalf("expected top level flow with its executions to be exported, got %v", export.AuthenticationFlows)
+	}
+	if len(export.Groups) != 1 || !export.Groups[0].Default || len(export.Groups[0].SubGroups) != 1 {
+		t.Fatalf("unexpected exported groups %v", export.Groups)
+	}
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling export: %v", err)
+	}
+	imported := &RealmExport{}
+	if err := json.Unmarshal(data, imported); err != nil {
+		t.Fatalf("unexpected error unmarshalling export: %v", err)
+	}
+
+	// Import into an RHSSO without the realm
+	targetMock, target := createImportMock()
+	targetMock.GetRealmFunc = func(realmName string) (*keycloak.KeycloakRealm, error) {
+		return nil, nil
+	}
+	targetMock.CreateRealmFunc = func(realm *keycloak.KeycloakRealm) (string, error) {
+		return realm.Spec.Realm.Realm, nil
+	}
+	target.AuthenticationFlowsExecutions[firstBrokerLoginFlowAlias][0].Requirement = "DISABLED"
+
+	if err := ImportRealm(targetMock, imported); err != nil {
+		t.Fatalf("unexpected error importing realm: %v", err)
+	}
+
+	if len(targetMock.CreateRealmCalls()) != 1 {
+		t.Errorf("expected realm to be created")
+	}
+	if len(targetMock.CreateIdentityProviderCalls()) != 1 {
+		t.Errorf("expected identity provider to be created")
+	}
+	if target.AuthenticationFlowsExecutions[firstBrokerLoginFlowAlias][0].Requirement != "REQUIRED" {
+		t.Errorf("expected execution requirement to be restored")
+	}
+	if len(target.Groups) != 2 || len(target.DefaultGroups) != 1 || target.DefaultGroups[0].Name != "dedicated-admins" {
+		t.Fatalf("unexpected imported groups %v, default %v", target.Groups, target.DefaultGroups)
+	}
+	if len(target.Groups[0].SubGroups) != 1 || target.Groups[0].SubGroups[0].Name != "realm-managers" {
+		t.Errorf("expected realm managers to be a subgroup of dedicated admins, got %v", target.Groups[0].SubGroups)
+	}
+	if roles := target.RealmRoles[target.Groups[0].ID]; len(roles) != 1 || roles[0].Name != "create-realm" {
+		t.Errorf("unexpected realm roles of dedicated admins %v", roles)
+	}
+	if roles := target.ClientRoles[target.Groups[1].ID]; len(roles) != 1 || roles[0].Name != "manage-users" {
+		t.Errorf("unexpected client roles of realm managers %v", roles)
+	}
+}
+
+func TestReconciler_ReconcileRealmExport(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kc := &keycloak.Keycloak{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      keycloakName,
+			Namespace: defaultNamespace,
+		},
+	}
+	kcClient, _ := createRealmMock(t)
+	store := &fakeRealmExportStore{objects: map[string][]byte{}}
+	for day := 1; day <= RealmExportRetention; day++ {
+		store.objects[fmt.Sprintf("rhsso-realm-exports/rhssouser/master-202001%02dT000000Z.json", day)] = []byte("{}")
+	}
+	store.objects["rhsso-realm-exports/rhsso/master-20200101T000000Z.json"] = []byte("{}")
+
+	r := &Reconciler{
+		ConfigManager: basicConfigMock(),
+		Installation:  &integreatlyv1alpha1.RHMI{ObjectMeta: metav1.ObjectMeta{Namespace: defaultOperatorNamespace}},
+		Logger:        logrus.NewEntry(logrus.StandardLogger()),
+		KeycloakClientFactory: &keycloakCommon.KeycloakClientFactoryMock{AuthenticatedClientFunc: func(kc keycloak.Keycloak) (keycloakCommon.KeycloakInterface, error) {
+			return kcClient, nil
+		}},
+		RealmExportStore: store,
+	}
+	serverClient := fake.NewFakeClientWithScheme(scheme, kc)
+	ssoConfig := config.NewRHSSOUser(config.ProductConfig{"NAMESPACE": defaultNamespace})
+
+	err = r.ReconcileRealmExport(context.TODO(), serverClient, keycloakName, masterRealmName, []string{"dedicated-admins"}, ssoConfig, ssoConfig.RHSSOCommon)
+	if err != nil {
+		t.Fatalf("unexpected error exporting realm: %v", err)
+	}
+	key := ssoConfig.GetRealmExportKey()
+	if _, ok := store.objects[key]; !ok || !strings.HasPrefix(key, "rhsso-realm-exports/rhssouser/master-") {
+		t.Fatalf("expected export to be written to %s, got %v", key, store.objects)
+	}
+
+	// The oldest export is deleted, while the exports of other products are kept
+	if _, ok := store.objects["rhsso-realm-exports/rhssouser/master-20200101T000000Z.json"]; ok {
+		t.Errorf("expected the oldest export to be deleted, got %v", store.objects)
+	}
+	if len(store.objects) != RealmExportRetention+1 {
+		t.Fatalf("expected %d exports to be kept, got %v", RealmExportRetention+1, store.objects)
+	}
+
+	// A second export within the interval is skipped
+	err = r.ReconcileRealmExport(context.TODO(), serverClient, keycloakName, masterRealmName, []string{"dedicated-admins"}, ssoConfig, ssoConfig.RHSSOCommon)
+	if err != nil || len(store.objects) != RealmExportRetention+1 {
+		t.Fatalf("expected export to be skipped, got error %v and exports %v", err, store.objects)
+	}
+
+	// The export is imported once
+	phase, err := r.ReconcileRealmImport(context.TODO(), serverClient, keycloakName, key, ssoConfig, ssoConfig.RHSSOCommon)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("unexpected phase %s and error %v importing realm", phase, err)
+	}
+	if ssoConfig.GetRealmImportedKey() != key {
+		t.Errorf("expected imported key %s, got %s", key, ssoConfig.GetRealmImportedKey())
+	}
+	updates := len(kcClient.UpdateClientCalls())
+
+	phase, err = r.ReconcileRealmImport(context.TODO(), serverClient, keycloakName, key, ssoConfig, ssoConfig.RHSSOCommon)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted || len(kcClient.UpdateClientCalls()) != updates {
+		t.Errorf("expected import to be skipped, got phase %s and error %v", phase, err)
+	}
+
+	ssoConfig.SetRealmExportTime(time.Now().Add(-DefaultRealmExportInterval))
+	if err := r.ReconcileRealmExport(context.TODO(), serverClient, keycloakName, masterRealmName, nil, ssoConfig, ssoConfig.RHSSOCommon); err != nil {
+		t.Fatalf("unexpected error exporting realm: %v", err)
+	}
+	if time.Since(ssoConfig.GetRealmExportTime()) > time.Minute {
+		t.Errorf("expected a new export after the interval, last export at %v", ssoConfig.GetRealmExportTime())
+	}
+}
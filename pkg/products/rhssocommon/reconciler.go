@@ -48,6 +48,13 @@ type Reconciler struct {
 	*resources.Reconciler
 	Recorder              record.EventRecorder
 	KeycloakClientFactory keycloakCommon.KeycloakClientFactory
+	// RealmExportStore stores the realm exports. If not set, the bucket of
+	// the backups is used
+	RealmExportStore RealmExportStore
+	// KeycloakAdminClientFactory creates clients for the admin API endpoints
+	// not supported by the Keycloak client. If not set, the admin
+	// credentials of the Keycloak instance are used
+	KeycloakAdminClientFactory KeycloakAdminClientFactory
 }
 
 func NewReconciler(configManager config.ConfigReadWriter, mpm marketplace.MarketplaceInterface, installation *integreatlyv1alpha1.RHMI, logger *logrus.Entry, oauthv1Client oauthClient.OauthV1Interface, recorder record.EventRecorder, APIURL string, keycloakClientFactory keycloakCommon.KeycloakClientFactory) *Reconciler {
@@ -298,33 +305,25 @@ func ContainsIdentityProvider(providers []*keycloak.KeycloakIdentityProvider, al
 
 func (r *Reconciler) ReconcileCloudResources(dbPRefix string, defaultNamespace string, ssoType string, config *config.RHSSOCommon, ctx context.Context, installation *integreatlyv1alpha1.RHMI, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 	r.Logger.Info("Reconciling Keycloak external database instance")
-	postgresName := fmt.Sprintf("%s%s", dbPRefix, installation.Name)
-	postgres, err := resources.ReconcileRHSSOPostgresCredentials(ctx, installation, serverClient, postgresName, config.GetNamespace(), defaultNamespace)
-
+	postgres, err := resources.ReconcileRHSSOPostgresCredentials(ctx, installation, serverClient, dbPRefix, config.GetNamespace(), defaultNamespace)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile database credentials secret while provisioning %s: %w", ssoType, err)
 	}
 
-	// at this point it should be ok to create the failed alert.
-	if postgres != nil {
-		// reconcile postgres alerts
-		phase, err := resources.ReconcilePostgresAlerts(ctx, serverClient, installation, postgres)
-		productName := postgres.Labels["productName"]
-		if err != nil {
-			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile postgres alerts for %s: %w", productName, err)
-		}
-		if phase != integreatlyv1alpha1.PhaseCompleted {
-			return phase, nil
-		}
-		return integreatlyv1alpha1.PhaseCompleted, nil
+	// reconcile postgres alerts
+	phase, err := resources.NewDatastoreProvider(installation).ReconcileAlerts(ctx, serverClient, postgres)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile postgres alerts for %s: %w", postgres.ProductName, err)
 	}
-
-	// postgres provisioning is still in progress
-	return integreatlyv1alpha1.PhaseAwaitingCloudResources, nil
+	if !postgres.Ready {
+		// postgres provisioning is still in progress
+		return integreatlyv1alpha1.PhaseAwaitingCloudResources, nil
+	}
+	return phase, nil
 }
 
 func (r *Reconciler) PreUpgradeBackupsExecutor(resourceName string) backup.BackupExecutor {
-	if r.Installation.Spec.UseClusterStorage != "false" {
+	if r.Installation.Spec.UseClusterStorage != "false" || resources.IsBYODatastores(r.Installation) {
 		return backup.NewNoopBackupExecutor()
 	}
 
@@ -357,7 +356,7 @@ func (r *Reconciler) ReconcileSubscription(ctx context.Context, serverClient k8s
 	)
 }
 
-func (r *Reconciler) ReconcileStatefulSet(ctx context.Context, serverClient k8sclient.Client, config *config.RHSSOCommon) (integreatlyv1alpha1.StatusPhase, error) {
+func (r *Reconciler) ReconcileStatefulSet(ctx context.Context, serverClient k8sclient.Client, config *config.RHSSOCommon, productName integreatlyv1alpha1.ProductName) (integreatlyv1alpha1.StatusPhase, error) {
 	statefulSet := &k8sappsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "keycloak",
@@ -371,13 +370,16 @@ func (r *Reconciler) ReconcileStatefulSet(ctx context.Context, serverClient k8sc
 		mutatePodPriority = resources.MutatePodPriority(r.Installation.Spec.PriorityClassName)
 	}
 
+	topologyPolicy := resources.GetTopologyPolicy(r.Installation, productName)
+
 	return resources.UpdatePodTemplateIfExists(
 		ctx,
 		serverClient,
 		resources.SelectFromStatefulSet,
 		resources.AllMutationsOf(
-			resources.MutateMultiAZAntiAffinity(ctx, serverClient, "app"),
-			resources.MutateZoneTopologySpreadConstraints("app"),
+			resources.MutateMultiAZAntiAffinity(topologyPolicy, "app"),
+			resources.MutateZoneTopologySpreadConstraints(topologyPolicy, "app"),
+			resources.MutateNodePlacement(topologyPolicy),
 			mutatePodPriority,
 		),
 		statefulSet,
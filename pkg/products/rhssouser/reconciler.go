@@ -201,7 +201,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
-	phase, err = r.ReconcileStatefulSet(ctx, serverClient, r.Config.RHSSOCommon)
+	phase, err = r.ReconcileStatefulSet(ctx, serverClient, r.Config.RHSSOCommon, r.Config.GetProductName())
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.Recorder, installation, phase, "Failed to reconsile RHSSO pod priority", err)
 		return phase, err
@@ -213,6 +213,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
+	// The realm is imported and exported once keycloak is ready
+	if installation.Spec.RHSSOUser != nil {
+		phase, err = r.ReconcileRealmImport(ctx, serverClient, keycloakName, installation.Spec.RHSSOUser.RealmImport, r.Config, r.Config.RHSSOCommon)
+		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			events.HandleError(r.Recorder, installation, phase, "Failed to import realm", err)
+			return phase, err
+		}
+	}
+
+	// Failing to export the realm doesn't affect the product, it's retried
+	// on the next reconcile
+	if err := r.ReconcileRealmExport(ctx, serverClient, keycloakName, masterRealmName, []string{dedicatedAdminsGroupName, developersGroupName}, r.Config, r.Config.RHSSOCommon); err != nil {
+		r.Logger.Errorf("Failed to export realm %s: %v", masterRealmName, err)
+	}
+
 	err = r.ConfigManager.WriteConfig(r.Config)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("Error writing to config in rhssouser reconciler: %w", err)
@@ -312,7 +327,21 @@ func (r *Reconciler) reconcileComponents(ctx context.Context, installation *inte
 		}
 	}
 
-	phase, err := r.reconcileBrowserAuthFlow(ctx, kc, serverClient)
+	rhmiConfig := &integreatlyv1alpha1.RHMIConfig{}
+	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: "rhmi-config", Namespace: installation.Namespace}, rhmiConfig)
+	if k8serr.IsNotFound(err) {
+		// the realm config status is reported in rhmi-config, so there's
+		// nothing to update without it
+		rhmiConfig = nil
+	} else if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get rhmi config: %w", err)
+	}
+	var identityProviders []integreatlyv1alpha1.UserSSOIdentityProvider
+	if rhmiConfig != nil {
+		identityProviders = rhmiConfig.Spec.UserSSO.IdentityProviders
+	}
+
+	phase, err := r.reconcileBrowserAuthFlow(ctx, kc, serverClient, len(identityProviders) == 0)
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.Recorder, installation, phase, "Failed to reconcile browser authentication flow", err)
 		return phase, err
@@ -346,6 +375,20 @@ func (r *Reconciler) reconcileComponents(ctx context.Context, installation *inte
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile dedicated-admins group: %v", err)
 	}
 
+	// The identity providers are reconciled after the groups they map onto
+	phase, err = r.ReconcileIdentityProviders(ctx, serverClient, kc, masterRealmName, identityProviders, r.Config, r.Config.RHSSOCommon)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		events.HandleError(r.Recorder, installation, phase, "Failed to reconcile identity providers", err)
+		return phase, err
+	}
+
+	reservedGroups := []string{developersGroupName, dedicatedAdminsGroupName, realmManagersGroupName}
+	phase, err = r.ReconcileRealmConfig(ctx, serverClient, kc, masterRealmName, getMasterLabels(), reservedGroups, rhmiConfig)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		events.HandleError(r.Recorder, installation, phase, "Failed to reconcile realm config", err)
+		return phase, err
+	}
+
 	// Get all currently existing keycloak users
 	keycloakUsers, err := GetKeycloakUsers(ctx, serverClient, r.Config.GetNamespace())
 	if err != nil {
@@ -480,6 +523,12 @@ func GetKeycloakUsers(ctx context.Context, serverClient k8sclient.Client, ns str
 	return getUsers(ctx, serverClient, ns)
 }
 
+// GetInstanceLabels returns the labels of the user SSO realm, to select it
+// from the Keycloak CRs of other components
+func GetInstanceLabels() map[string]string {
+	return getMasterLabels()
+}
+
 func getMasterLabels() map[string]string {
 	return map[string]string{
 		masterRealmLabelKey: masterRealmLabelValue,
@@ -756,8 +805,9 @@ func OsUserInDedicatedAdmins(dedicatedAdmins []string, kcUser keycloak.KeycloakA
 
 // Add authenticator config to the master realm. Because it is the master realm we need to make direct calls
 // with the Keycloak client. This config allows for the automatic redirect to openshift-v4 as the IDP for Keycloak,
-// as apposed to presenting the user with multiple login options.
-func (r *Reconciler) reconcileBrowserAuthFlow(ctx context.Context, kc *keycloak.Keycloak, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+// as apposed to presenting the user with multiple login options. When other identity providers are configured
+// the redirect is removed, so users can choose the identity provider to log in with.
+func (r *Reconciler) reconcileBrowserAuthFlow(ctx context.Context, kc *keycloak.Keycloak, client k8sclient.Client, redirect bool) (integreatlyv1alpha1.StatusPhase, error) {
 
 	kcClient, err := r.KeycloakClientFactory.AuthenticatedClient(*kc)
 	if err != nil {
@@ -773,9 +823,19 @@ func (r *Reconciler) reconcileBrowserAuthFlow(ctx context.Context, kc *keycloak.
 	for _, execution := range executions {
 		if execution.ProviderID == "identity-provider-redirector" {
 			if execution.AuthenticationConfig != "" {
+				if !redirect {
+					if err := kcClient.DeleteAuthenticatorConfig(execution.AuthenticationConfig, masterRealmName); err != nil {
+						return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("Failed to delete Authenticator Config: %w", err)
+					}
+					r.Logger.Infof("Removed redirect to %s from browser flow of master realm, rhsso-user", idpAlias)
+					return integreatlyv1alpha1.PhaseCompleted, nil
+				}
 				r.Logger.Infof("Authenticator Config exists on master realm, rhsso-user")
 				return integreatlyv1alpha1.PhaseCompleted, nil
 			}
+			if !redirect {
+				return integreatlyv1alpha1.PhaseCompleted, nil
+			}
 			executionID = execution.ID
 			break
 		}
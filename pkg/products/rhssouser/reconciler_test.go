@@ -71,6 +71,9 @@ func basicConfigMock() *config.ConfigReadWriterMock {
 		GetOauthClientsSecretNameFunc: func() string {
 			return "oauth-client-secrets"
 		},
+		GetBackupsSecretNameFunc: func() string {
+			return "backups-s3-credentials"
+		},
 		ReadMonitoringFunc: func() (*config.Monitoring, error) {
 			return config.NewMonitoring(config.ProductConfig{
 				"NAMESPACE": "middleware-monitoring",
@@ -0,0 +1,369 @@
+package threescale
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/owner"
+	appsv1 "github.com/openshift/api/apps/v1"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	datastoreProbeImage       = "quay.io/integreatly/backup-container:1.0.15"
+	datastoreProbePrefix      = "datastore-probe-"
+	datastoreProbeInterval    = 5 * time.Minute
+	credentialsHashAnnotation = "integreatly.org/credentials-hash"
+
+	rotatedPostgresSecretName = "system-database-rotated"
+)
+
+// postgresDeploymentConfigs are the deployment configs rolled out when the
+// postgres credentials are rotated
+var postgresDeploymentConfigs = []string{
+	"system-app",
+	"system-sidekiq",
+	"system-sphinx",
+}
+
+type probeResult string
+
+const (
+	probePending   probeResult = "Pending"
+	probeSucceeded probeResult = "Succeeded"
+	probeFailed    probeResult = "Failed"
+)
+
+// datastoreProbe checks the connection URL in the key of a secret
+type datastoreProbe struct {
+	secretName string
+	urlKey     string
+	dsType     resources.DatastoreType
+}
+
+var (
+	postgresProbe   = datastoreProbe{secretName: externalPostgresSecretName, urlKey: "URL", dsType: resources.DatastorePostgres}
+	datastoreProbes = []datastoreProbe{
+		{secretName: externalBackendRedisSecretName, urlKey: "REDIS_STORAGE_URL", dsType: resources.DatastoreRedis},
+		{secretName: externalRedisSecretName, urlKey: "URL", dsType: resources.DatastoreRedis},
+		postgresProbe,
+	}
+)
+
+// reconcileDatastoreHealth probes the connection secrets of the external
+// datastores and exposes the results in the datastore health metric. An
+// unhealthy datastore doesn't block the reconcile, it's reported by the metric
+func (r *Reconciler) reconcileDatastoreHealth(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	for _, probe := range datastoreProbes {
+		result, err := r.probeDatastore(ctx, serverClient, probe)
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to probe datastore %s: %w", probe.secretName, err)
+		}
+
+		health := metrics.DatastoreHealth.WithLabelValues(probe.secretName, r.Config.GetNamespace(), string(probe.dsType))
+		switch result {
+		case probeSucceeded:
+			health.Set(1)
+		case probeFailed:
+			logrus.Warnf("Probe of datastore %s failed to connect or authenticate", probe.secretName)
+			health.Set(0)
+		}
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+// probeDatastore runs a short lived job that connects and authenticates to the
+// datastore. The job is run again when the credentials change, or once its
+// result is older than the probe interval. The result stays pending until the
+// job finishes
+func (r *Reconciler) probeDatastore(ctx context.Context, serverClient k8sclient.Client, probe datastoreProbe) (probeResult, error) {
+	ns := r.Config.GetNamespace()
+	secret := &corev1.Secret{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: probe.secretName, Namespace: ns}, secret); err != nil {
+		return probePending, fmt.Errorf("failed to get secret %s: %w", probe.secretName, err)
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256(secret.Data[probe.urlKey]))
+
+	job := &batchv1.Job{}
+	err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: datastoreProbePrefix + probe.secretName, Namespace: ns}, job)
+	if k8serr.IsNotFound(err) {
+		job = r.newDatastoreProbeJob(probe, hash)
+		if err := serverClient.Create(ctx, job); err != nil {
+			return probePending, fmt.Errorf("failed to create probe job %s: %w", job.Name, err)
+		}
+		return probePending, nil
+	}
+	if err != nil {
+		return probePending, fmt.Errorf("failed to get probe job: %w", err)
+	}
+	if job.DeletionTimestamp != nil {
+		return probePending, nil
+	}
+
+	result, finishedAt := getProbeJobResult(job)
+	if job.Annotations[credentialsHashAnnotation] != hash {
+		// The result is for the previous credentials
+		return probePending, deleteProbeJob(ctx, serverClient, job)
+	}
+	if result != probePending && finishedAt.Add(datastoreProbeInterval).Before(time.Now()) {
+		return result, deleteProbeJob(ctx, serverClient, job)
+	}
+	return result, nil
+}
+
+func (r *Reconciler) newDatastoreProbeJob(probe datastoreProbe, hash string) *batchv1.Job {
+	script := `psql "$DATASTORE_URL" -c 'SELECT 1'`
+	if probe.dsType == resources.DatastoreRedis {
+		script = `redis-cli -u "$DATASTORE_URL" ping | grep -q PONG`
+	}
+
+	backoffLimit := int32(1)
+	activeDeadlineSeconds := int64(120)
+	labels := map[string]string{"integreatly": "yes", "datastore-probe": probe.secretName}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        datastoreProbePrefix + probe.secretName,
+			Namespace:   r.Config.GetNamespace(),
+			Labels:      labels,
+			Annotations: map[string]string{credentialsHashAnnotation: hash},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &activeDeadlineSeconds,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "probe",
+							Image:   datastoreProbeImage,
+							Command: []string{"/bin/sh", "-c", script},
+							Env: []corev1.EnvVar{
+								{
+									Name: "DATASTORE_URL",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{Name: probe.secretName},
+											Key:                  probe.urlKey,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	owner.AddIntegreatlyOwnerAnnotations(job, r.installation)
+	return job
+}
+
+func getProbeJobResult(job *batchv1.Job) (probeResult, time.Time) {
+	if job.Status.Succeeded > 0 {
+		if job.Status.CompletionTime != nil {
+			return probeSucceeded, job.Status.CompletionTime.Time
+		}
+		return probeSucceeded, time.Now()
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return probeFailed, condition.LastTransitionTime.Time
+		}
+	}
+	return probePending, time.Time{}
+}
+
+func deleteProbeJob(ctx context.Context, serverClient k8sclient.Client, job *batchv1.Job) error {
+	err := serverClient.Delete(ctx, job, k8sclient.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !k8serr.IsNotFound(err) {
+		return fmt.Errorf("failed to delete probe job %s: %w", job.Name, err)
+	}
+	return nil
+}
+
+// reconcilePostgresSecret copies the postgres credentials to the connection
+// secret of 3scale. When the credentials are rotated, the new ones are probed
+// before replacing the current ones, so that 3scale keeps working if they are
+// wrong. Rotated credentials failing the probe are reported by the datastore
+// health metric and don't block the reconcile, they are probed again after
+// the probe interval. The system deployment configs are then rolled out to
+// use them, and the rotation is verified once the rollouts complete
+func (r *Reconciler) reconcilePostgresSecret(ctx context.Context, serverClient k8sclient.Client, credSec *corev1.Secret) (integreatlyv1alpha1.StatusPhase, error) {
+	username := credSec.Data["username"]
+	password := credSec.Data["password"]
+	data := map[string][]byte{
+		"URL":         []byte(fmt.Sprintf("postgresql://%s:%s@%s:%s/%s", username, password, credSec.Data["host"], credSec.Data["port"], credSec.Data["database"])),
+		"DB_USER":     username,
+		"DB_PASSWORD": password,
+	}
+
+	postgresSecret := &corev1.Secret{}
+	err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: externalPostgresSecretName, Namespace: r.Config.GetNamespace()}, postgresSecret)
+	if k8serr.IsNotFound(err) {
+		postgresSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      externalPostgresSecretName,
+				Namespace: r.Config.GetNamespace(),
+			},
+			Data: data,
+		}
+		if err := serverClient.Create(ctx, postgresSecret); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create 3scale %s connection secret: %w", externalPostgresSecretName, err)
+		}
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get 3scale %s connection secret: %w", externalPostgresSecretName, err)
+	}
+
+	if containsSecretData(postgresSecret.Data, data) {
+		return r.verifyPostgresRotation(ctx, serverClient)
+	}
+
+	logrus.Infof("Postgres credentials in secret %s were rotated, validating them", credSec.Name)
+	rotatedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rotatedPostgresSecretName,
+			Namespace: r.Config.GetNamespace(),
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, serverClient, rotatedSecret, func() error {
+		owner.AddIntegreatlyOwnerAnnotations(rotatedSecret, r.installation)
+		rotatedSecret.Data = data
+		return nil
+	})
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create or update secret %s: %w", rotatedPostgresSecretName, err)
+	}
+
+	rotatedProbe := datastoreProbe{secretName: rotatedPostgresSecretName, urlKey: "URL", dsType: resources.DatastorePostgres}
+	result, err := r.probeDatastore(ctx, serverClient, rotatedProbe)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to probe the rotated postgres credentials: %w", err)
+	}
+	rotatedHealth := []string{rotatedPostgresSecretName, r.Config.GetNamespace(), string(rotatedProbe.dsType)}
+	switch result {
+	case probePending:
+		return integreatlyv1alpha1.PhaseInProgress, nil
+	case probeFailed:
+		logrus.Warnf("The rotated postgres credentials in secret %s failed to connect, keeping the current credentials", credSec.Name)
+		metrics.DatastoreHealth.WithLabelValues(rotatedHealth...).Set(0)
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+	metrics.DatastoreHealth.DeleteLabelValues(rotatedHealth...)
+
+	// Record the versions of the deployment configs before rolling them
+	// out, so the rotation is only verified against the new rollouts
+	versions := map[string]int64{}
+	for _, name := range postgresDeploymentConfigs {
+		dc := &appsv1.DeploymentConfig{}
+		err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: name, Namespace: r.Config.GetNamespace()}, dc)
+		if k8serr.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get deployment config %s: %w", name, err)
+		}
+		versions[name] = dc.Status.LatestVersion
+	}
+
+	for key, value := range data {
+		postgresSecret.Data[key] = value
+	}
+	if err := serverClient.Update(ctx, postgresSecret); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to update 3scale %s connection secret: %w", externalPostgresSecretName, err)
+	}
+	r.Config.SetPostgresRotationVersions(versions)
+	if err := r.ConfigManager.WriteConfig(r.Config); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to write postgres rotation to config: %w", err)
+	}
+
+	for _, name := range postgresDeploymentConfigs {
+		if _, ok := versions[name]; !ok {
+			continue
+		}
+		if err := r.RolloutDeployment(name); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to roll out %s with the rotated postgres credentials: %w", name, err)
+		}
+	}
+
+	if err := serverClient.Delete(ctx, rotatedSecret); err != nil && !k8serr.IsNotFound(err) {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to delete secret %s: %w", rotatedPostgresSecretName, err)
+	}
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: datastoreProbePrefix + rotatedPostgresSecretName, Namespace: r.Config.GetNamespace()}}
+	if err := deleteProbeJob(ctx, serverClient, job); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
+	logrus.Info("Rolling out 3scale with the rotated postgres credentials")
+	return integreatlyv1alpha1.PhaseInProgress, nil
+}
+
+// verifyPostgresRotation waits for the deployment configs rolled out after a
+// rotation of the postgres credentials, and probes the credentials once more
+func (r *Reconciler) verifyPostgresRotation(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	versions := r.Config.GetPostgresRotationVersions()
+	if len(versions) == 0 {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	for _, name := range postgresDeploymentConfigs {
+		version, ok := versions[name]
+		if !ok {
+			continue
+		}
+		dc := &appsv1.DeploymentConfig{}
+		if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: name, Namespace: r.Config.GetNamespace()}, dc); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get deployment config %s: %w", name, err)
+		}
+		if dc.Status.LatestVersion <= version || dc.Status.ObservedGeneration < dc.Generation ||
+			dc.Status.UpdatedReplicas != dc.Status.Replicas || dc.Status.AvailableReplicas != dc.Status.Replicas {
+			logrus.Infof("Waiting for %s to roll out with the rotated postgres credentials", name)
+			return integreatlyv1alpha1.PhaseInProgress, nil
+		}
+	}
+
+	result, err := r.probeDatastore(ctx, serverClient, postgresProbe)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to probe the rotated postgres credentials: %w", err)
+	}
+	switch result {
+	case probePending:
+		return integreatlyv1alpha1.PhaseInProgress, nil
+	case probeFailed:
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to verify the rotated postgres credentials after rolling out %v", postgresDeploymentConfigs)
+	}
+
+	r.Config.SetPostgresRotationVersions(nil)
+	if err := r.ConfigManager.WriteConfig(r.Config); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to write postgres rotation to config: %w", err)
+	}
+	logrus.Info("Verified the rotated postgres credentials")
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+func containsSecretData(data, expected map[string][]byte) bool {
+	for key, value := range expected {
+		if !bytes.Equal(data[key], value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,295 @@
+package threescale
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	envoyapi "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoycore "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	v2route "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/ratelimit"
+	appsv1 "github.com/openshift/api/apps/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	marin3rStatusLabel      = "marin3r.3scale.net/status"
+	marin3rNodeIDAnnotation = "marin3r.3scale.net/node-id"
+	marin3rPortsAnnotation  = "marin3r.3scale.net/ports"
+
+	// envoySidecarContainer is the name of the sidecar container injected
+	// by marin3r
+	envoySidecarContainer = "envoy-sidecar"
+)
+
+// managementSidecar is an envoy sidecar that rate limits the requests to the
+// 3scale management APIs served by a deployment config
+type managementSidecar struct {
+	// nodeID is also the rate limit domain of the sidecar
+	nodeID           string
+	deploymentConfig string
+	apis             []managementAPI
+}
+
+// managementAPI is a 3scale management API whose service is pointed to a
+// listener of the sidecar when it is rate limited
+type managementAPI struct {
+	descriptor string
+	service    string
+	// appPort is the container port of the API
+	appPort uint32
+	// envoyPort is the port of the sidecar listener, named portName
+	envoyPort uint32
+	portName  string
+}
+
+var managementSidecars = []managementSidecar{
+	{
+		nodeID:           marin3rconfig.SystemRateLimitDomain,
+		deploymentConfig: "system-app",
+		apis: []managementAPI{
+			{descriptor: marin3rconfig.SystemProviderDescriptor, service: "system-provider", appPort: 3000, envoyPort: 8443, portName: "envoy-provider"},
+			{descriptor: marin3rconfig.SystemMasterDescriptor, service: "system-master", appPort: 3002, envoyPort: 8444, portName: "envoy-master"},
+		},
+	},
+	{
+		nodeID:           marin3rconfig.BackendListenerRateLimitDomain,
+		deploymentConfig: "backend-listener",
+		apis: []managementAPI{
+			{descriptor: marin3rconfig.BackendListenerDescriptor, service: "backend-listener", appPort: 3000, envoyPort: 8443, portName: "envoy-backend"},
+		},
+	},
+}
+
+// reconcileManagementRateLimiting adds an envoy sidecar to the deployment
+// configs of the 3scale management APIs whose limits are set, and points
+// their services to it once it's rolled out. The sidecars of the APIs that
+// aren't limited anymore are removed
+func (r *Reconciler) reconcileManagementRateLimiting(ctx context.Context, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	limits := map[string]*integreatlyv1alpha1.RateLimit{}
+	for _, limit := range marin3rconfig.GetManagementRateLimits(r.installation) {
+		limits[limit.Descriptor] = limit.Limit
+	}
+
+	for _, sidecar := range managementSidecars {
+		var limitedAPIs []managementAPI
+		for _, api := range sidecar.apis {
+			if limits[api.descriptor] != nil {
+				limitedAPIs = append(limitedAPIs, api)
+			}
+		}
+
+		deploymentConfig, phase, err := r.getDeploymentConfig(ctx, client, sidecar.deploymentConfig)
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get deployment config for %s: %w", sidecar.deploymentConfig, err)
+		}
+		if phase == integreatlyv1alpha1.PhaseAwaitingComponents {
+			return phase, nil
+		}
+
+		if len(limitedAPIs) > 0 {
+			err = r.enableManagementSidecar(ctx, client, sidecar, limitedAPIs, deploymentConfig)
+		} else {
+			err = r.disableManagementSidecar(ctx, client, sidecar, deploymentConfig)
+		}
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+func (r *Reconciler) enableManagementSidecar(ctx context.Context, client k8sclient.Client, sidecar managementSidecar, limitedAPIs []managementAPI, deploymentConfig *appsv1.DeploymentConfig) error {
+	rateLimitCluster, err := r.getRateLimitCluster(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	rateLimitFilter := newRateLimitFilter(sidecar.nodeID)
+	clusters := []*envoyapi.Cluster{rateLimitCluster}
+	var listeners []*envoyapi.Listener
+	var ports []string
+
+	for _, api := range limitedAPIs {
+		clusters = append(clusters, newEnvoyCluster(api.descriptor, &envoycore.Address{Address: &envoycore.Address_SocketAddress{
+			SocketAddress: &envoycore.SocketAddress{
+				Address:  "127.0.0.1",
+				Protocol: envoycore.SocketAddress_TCP,
+				PortSpecifier: &envoycore.SocketAddress_PortValue{
+					PortValue: api.appPort,
+				},
+			},
+		}}))
+
+		listener, err := newEnvoyListener(api.descriptor, "0.0.0.0", api.envoyPort, strings.ReplaceAll(api.descriptor, "-", "_"), &v2route.RouteAction{
+			ClusterSpecifier: &v2route.RouteAction_Cluster{
+				Cluster: api.descriptor,
+			},
+			RateLimits: newGenericKeyRateLimits(api.descriptor),
+		}, rateLimitFilter)
+		if err != nil {
+			return err
+		}
+		listeners = append(listeners, listener)
+		ports = append(ports, fmt.Sprintf("%s:%d", api.portName, api.envoyPort))
+	}
+
+	if err := r.reconcileEnvoyConfig(ctx, client, sidecar.nodeID, clusters, listeners); err != nil {
+		return err
+	}
+
+	if deploymentConfig.Spec.Template.Labels == nil {
+		deploymentConfig.Spec.Template.Labels = map[string]string{}
+	}
+	if deploymentConfig.Spec.Template.Annotations == nil {
+		deploymentConfig.Spec.Template.Annotations = map[string]string{}
+	}
+	sort.Strings(ports)
+	deploymentConfig.Spec.Template.Labels[marin3rStatusLabel] = "enabled"
+	deploymentConfig.Spec.Template.Annotations[marin3rNodeIDAnnotation] = sidecar.nodeID
+	deploymentConfig.Spec.Template.Annotations[marin3rPortsAnnotation] = strings.Join(ports, ",")
+	if err := client.Update(ctx, deploymentConfig); err != nil {
+		return fmt.Errorf("failed to update deployment config %s: %w", deploymentConfig.Name, err)
+	}
+
+	// The services are only pointed to the sidecar once the pods with its
+	// listeners are ready, otherwise the requests would be refused while the
+	// deployment config rolls out. The next reconcile points them
+	rolledOut, err := isSidecarRolledOut(ctx, client, deploymentConfig)
+	if err != nil {
+		return err
+	}
+	if !rolledOut {
+		logrus.Infof("Waiting for %s to roll out the rate limiting sidecar", deploymentConfig.Name)
+	}
+
+	for _, api := range sidecar.apis {
+		limited := false
+		for _, limitedAPI := range limitedAPIs {
+			limited = limited || limitedAPI.descriptor == api.descriptor
+		}
+		if limited && !rolledOut {
+			continue
+		}
+		if err := r.pointServiceToSidecar(ctx, client, api, limited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isSidecarRolledOut returns whether the latest template of the deployment
+// config is rolled out, and all its running pods are ready with the envoy
+// sidecar
+func isSidecarRolledOut(ctx context.Context, client k8sclient.Client, deploymentConfig *appsv1.DeploymentConfig) (bool, error) {
+	status := deploymentConfig.Status
+	if status.ObservedGeneration < deploymentConfig.Generation ||
+		status.UpdatedReplicas != status.Replicas || status.AvailableReplicas != status.Replicas {
+		return false, nil
+	}
+
+	pods := &corev1.PodList{}
+	err := client.List(ctx, pods, k8sclient.InNamespace(deploymentConfig.Namespace), k8sclient.MatchingLabels{"deploymentconfig": deploymentConfig.Name})
+	if err != nil {
+		return false, fmt.Errorf("failed to list pods of deployment config %s: %w", deploymentConfig.Name, err)
+	}
+
+	running := 0
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		running++
+		if !hasContainer(pod, envoySidecarContainer) || !isPodReady(pod) {
+			return false, nil
+		}
+	}
+	return running > 0, nil
+}
+
+func hasContainer(pod corev1.Pod, name string) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// disableManagementSidecar points the services back to the management APIs
+// before removing the sidecar, so the requests aren't sent to a sidecar that
+// is gone
+func (r *Reconciler) disableManagementSidecar(ctx context.Context, client k8sclient.Client, sidecar managementSidecar, deploymentConfig *appsv1.DeploymentConfig) error {
+	for _, api := range sidecar.apis {
+		if err := r.pointServiceToSidecar(ctx, client, api, false); err != nil {
+			return err
+		}
+	}
+
+	if deploymentConfig.Spec.Template.Annotations[marin3rNodeIDAnnotation] == sidecar.nodeID {
+		delete(deploymentConfig.Spec.Template.Labels, marin3rStatusLabel)
+		delete(deploymentConfig.Spec.Template.Annotations, marin3rNodeIDAnnotation)
+		delete(deploymentConfig.Spec.Template.Annotations, marin3rPortsAnnotation)
+		if err := client.Update(ctx, deploymentConfig); err != nil {
+			return fmt.Errorf("failed to update deployment config %s: %w", deploymentConfig.Name, err)
+		}
+	}
+
+	return ratelimit.DeleteEnvoyConfig(ctx, client, r.Config.GetNamespace(), sidecar.nodeID)
+}
+
+// pointServiceToSidecar sets the target port of the service of a management
+// API, which exposes a single port, to the sidecar listener. Otherwise the
+// target port is only set back to the API if it was the sidecar listener, so
+// the services of the APIs that were never rate limited are left as is
+func (r *Reconciler) pointServiceToSidecar(ctx context.Context, client k8sclient.Client, api managementAPI, toSidecar bool) error {
+	service, phase, err := r.getService(ctx, client, api.service)
+	if err != nil {
+		return fmt.Errorf("failed to get service for %s: %w", api.service, err)
+	}
+	if phase == integreatlyv1alpha1.PhaseAwaitingComponents {
+		if toSidecar {
+			return fmt.Errorf("service %s not found", api.service)
+		}
+		return nil
+	}
+
+	sidecarPort := intstr.FromInt(int(api.envoyPort))
+	updated := false
+	for i, port := range service.Spec.Ports {
+		if toSidecar && port.TargetPort != sidecarPort {
+			service.Spec.Ports[i].TargetPort = sidecarPort
+			updated = true
+		}
+		if !toSidecar && port.TargetPort == sidecarPort {
+			service.Spec.Ports[i].TargetPort = intstr.FromInt(int(api.appPort))
+			updated = true
+		}
+	}
+	if !updated {
+		return nil
+	}
+
+	if err := client.Update(ctx, service); err != nil {
+		return fmt.Errorf("failed to update service %s: %w", api.service, err)
+	}
+	return nil
+}
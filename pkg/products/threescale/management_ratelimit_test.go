@@ -0,0 +1,166 @@
+package threescale
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	marin3rv1alpha "github.com/3scale/marin3r/pkg/apis/marin3r/v1alpha1"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+	appsv1 "github.com/openshift/api/apps/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func getManagementAPIObjects() []runtime.Object {
+	objects := []runtime.Object{
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "ratelimit", Namespace: "marin3r"},
+			Spec:       corev1.ServiceSpec{ClusterIP: "172.30.0.10"},
+		},
+	}
+	for _, dc := range []string{"system-app", "backend-listener"} {
+		objects = append(objects, &appsv1.DeploymentConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: dc, Namespace: "test"},
+			Spec: appsv1.DeploymentConfigSpec{
+				Template: &corev1.PodTemplateSpec{},
+			},
+		})
+	}
+	for service, targetPort := range map[string]intstr.IntOrString{
+		"system-provider":  intstr.FromString("provider"),
+		"system-master":    intstr.FromString("master"),
+		"backend-listener": intstr.FromInt(3000),
+	} {
+		objects = append(objects, &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: service, Namespace: "test"},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Name: "http", Port: 3000, TargetPort: targetPort}},
+			},
+		})
+	}
+	return objects
+}
+
+func TestReconciler_reconcileManagementRateLimiting(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installation := getTestInstallation()
+	installation.Spec.Marin3r = &integreatlyv1alpha1.Marin3rSpec{
+		ManagementRateLimits: &integreatlyv1alpha1.ManagementRateLimits{
+			SystemProvider: &integreatlyv1alpha1.RateLimit{Unit: "minute", RequestsPerUnit: 600},
+		},
+	}
+	serverClient := fake.NewFakeClientWithScheme(scheme, getManagementAPIObjects()...)
+	r := &Reconciler{
+		ConfigManager: &config.ConfigReadWriterMock{
+			ReadMarin3rFunc: func() (*config.Marin3r, error) {
+				return config.NewMarin3r(config.ProductConfig{"NAMESPACE": "marin3r"}), nil
+			},
+		},
+		Config:       config.NewThreeScale(config.ProductConfig{"NAMESPACE": "test"}),
+		logger:       logrus.NewEntry(logrus.New()),
+		installation: installation,
+	}
+
+	getTargetPort := func(service string) intstr.IntOrString {
+		s := &corev1.Service{}
+		if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: service, Namespace: "test"}, s); err != nil {
+			t.Fatal(err)
+		}
+		return s.Spec.Ports[0].TargetPort
+	}
+	getDeploymentConfig := func(name string) *appsv1.DeploymentConfig {
+		dc := &appsv1.DeploymentConfig{}
+		if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: name, Namespace: "test"}, dc); err != nil {
+			t.Fatal(err)
+		}
+		return dc
+	}
+
+	phase, err := r.reconcileManagementRateLimiting(context.TODO(), serverClient)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcileManagementRateLimiting() = %s, %v", phase, err)
+	}
+
+	envoyConfig := &marin3rv1alpha.EnvoyConfig{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: marin3rconfig.SystemRateLimitDomain, Namespace: "test"}, envoyConfig); err != nil {
+		t.Fatalf("failed to get envoy config: %v", err)
+	}
+	var clusters, listeners []string
+	for _, cluster := range envoyConfig.Spec.EnvoyResources.Clusters {
+		clusters = append(clusters, cluster.Name)
+	}
+	for _, listener := range envoyConfig.Spec.EnvoyResources.Listeners {
+		listeners = append(listeners, listener.Name)
+	}
+	if !reflect.DeepEqual(clusters, []string{"ratelimit", "system-provider"}) || !reflect.DeepEqual(listeners, []string{"system-provider"}) {
+		t.Errorf("unexpected clusters %v and listeners %v", clusters, listeners)
+	}
+
+	systemApp := getDeploymentConfig("system-app")
+	if systemApp.Spec.Template.Annotations[marin3rNodeIDAnnotation] != marin3rconfig.SystemRateLimitDomain ||
+		systemApp.Spec.Template.Annotations[marin3rPortsAnnotation] != "envoy-provider:8443" {
+		t.Errorf("unexpected system-app annotations %v", systemApp.Spec.Template.Annotations)
+	}
+	if _, ok := getDeploymentConfig("backend-listener").Spec.Template.Labels[marin3rStatusLabel]; ok {
+		t.Errorf("expected backend-listener sidecar not to be enabled")
+	}
+	if port := getTargetPort("system-provider"); port != intstr.FromString("provider") {
+		t.Errorf("expected system-provider service to target the API until the sidecar is rolled out, got %v", port)
+	}
+
+	systemApp.Status = appsv1.DeploymentConfigStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	if err := serverClient.Update(context.TODO(), systemApp); err != nil {
+		t.Fatal(err)
+	}
+	err = serverClient.Create(context.TODO(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "system-app-2-abcde", Namespace: "test", Labels: map[string]string{"deploymentconfig": "system-app"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "system-provider"}, {Name: envoySidecarContainer}}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	phase, err = r.reconcileManagementRateLimiting(context.TODO(), serverClient)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcileManagementRateLimiting() = %s, %v", phase, err)
+	}
+	if port := getTargetPort("system-provider"); port != intstr.FromInt(8443) {
+		t.Errorf("expected system-provider service to target the sidecar, got %v", port)
+	}
+	if port := getTargetPort("system-master"); port != intstr.FromString("master") {
+		t.Errorf("expected system-master service to be left as is, got %v", port)
+	}
+
+	installation.Spec.Marin3r.ManagementRateLimits = nil
+	phase, err = r.reconcileManagementRateLimiting(context.TODO(), serverClient)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("reconcileManagementRateLimiting() = %s, %v", phase, err)
+	}
+
+	err = serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: marin3rconfig.SystemRateLimitDomain, Namespace: "test"}, &marin3rv1alpha.EnvoyConfig{})
+	if !k8serr.IsNotFound(err) {
+		t.Errorf("expected envoy config to be deleted, got %v", err)
+	}
+	if _, ok := getDeploymentConfig("system-app").Spec.Template.Annotations[marin3rNodeIDAnnotation]; ok {
+		t.Errorf("expected system-app sidecar to be removed")
+	}
+	if port := getTargetPort("system-provider"); port != intstr.FromInt(3000) {
+		t.Errorf("expected system-provider service to target the API, got %v", port)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/integr8ly/integreatly-operator/pkg/resources/events"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/ratelimit"
 
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
 	"github.com/integr8ly/integreatly-operator/pkg/products/monitoring"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/backup"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/owner"
@@ -58,6 +59,7 @@ import (
 	usersv1 "github.com/openshift/api/user/v1"
 	appsv1Client "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
 	oauthClient "github.com/openshift/client-go/oauth/clientset/versioned/typed/oauth/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -89,6 +91,12 @@ const (
 	apicastRatelimiting = "apicast-ratelimit"
 	registrySecretName  = "threescale-registry-auth"
 
+	// The listener and clusters of the envoy sidecar that call the rate
+	// limit service in shadow mode
+	rateLimitShadow            = "ratelimit-shadow"
+	rateLimitShadowSink        = "ratelimit-shadow-sink"
+	rateLimitShadowPort uint32 = 8444
+
 	threeScaleIcon = "data:image/svg+xml;base64,PHN2ZyB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciIHZpZXdCb3g9IjAgMCAxMDAgMTAwIj48ZGVmcz48c3R5bGU+LmNscy0xe2ZpbGw6I2Q3MWUwMDt9LmNscy0ye2ZpbGw6I2MyMWEwMDt9LmNscy0ze2ZpbGw6I2ZmZjt9PC9zdHlsZT48L2RlZnM+PHRpdGxlPnByb2R1Y3RpY29uc18xMDE3X1JHQl9BUEkgZmluYWwgY29sb3I8L3RpdGxlPjxnIGlkPSJMYXllcl8xIiBkYXRhLW5hbWU9IkxheWVyIDEiPjxjaXJjbGUgY2xhc3M9ImNscy0xIiBjeD0iNTAiIGN5PSI1MCIgcj0iNTAiIHRyYW5zZm9ybT0idHJhbnNsYXRlKC0yMC43MSA1MCkgcm90YXRlKC00NSkiLz48cGF0aCBjbGFzcz0iY2xzLTIiIGQ9Ik04NS4zNiwxNC42NEE1MCw1MCwwLDAsMSwxNC42NCw4NS4zNloiLz48cGF0aCBjbGFzcz0iY2xzLTMiIGQ9Ik01MC4yNSwzMC44M2EyLjY5LDIuNjksMCwxLDAtMi42OC0yLjY5QTIuNjUsMi42NSwwLDAsMCw1MC4yNSwzMC44M1pNNDMuMzYsMzkuNGEzLjM1LDMuMzUsMCwwLDAsMy4zMiwzLjM0LDMuMzQsMy4zNCwwLDAsMCwwLTYuNjdBMy4zNSwzLjM1LDAsMCwwLDQzLjM2LDM5LjRabTMuOTIsOS44OUEyLjY4LDIuNjgsMCwxLDAsNDQuNiw1MiwyLjcsMi43LDAsMCwwLDQ3LjI4LDQ5LjI5Wk0zMi42MywyOS42NWEzLjI2LDMuMjYsMCwxLDAtMy4yNC0zLjI2QTMuMjYsMy4yNiwwLDAsMCwzMi42MywyOS42NVpNNDAuNTMsMzRhMi43NywyLjc3LDAsMCwwLDAtNS41MywyLjc5LDIuNzksMCwwLDAtMi43NiwyLjc3QTIuODUsMi44NSwwLDAsMCw0MC41MywzNFptMS43Ni05LjMxYTQuNCw0LjQsMCwxLDAtNC4zOC00LjRBNC4zNyw0LjM3LDAsMCwwLDQyLjI5LDI0LjcxWk0zMi43OCw0OWE3LDcsMCwxLDAtNy03QTcsNywwLDAsMCwzMi43OCw0OVptMzIuMTMtNy43YTQuMjMsNC4yMywwLDAsMCw0LjMsNC4zMSw0LjMxLDQuMzEsMCwxLDAtNC4zLTQuMzFabTYuOSwxMC4wNmEzLjA4LDMuMDgsMCwxLDAsMy4wOC0zLjA5QTMuMDksMy4wOSwwLDAsMCw3MS44MSw1MS4zOFpNNzMuOSwzNC43N2E0LjMxLDQuMzEsMCwxLDAtNC4zLTQuMzFBNC4yOCw0LjI4LDAsMCwwLDczLjksMzQuNzdaTTUyLjE2LDQ1LjA2YTMuNjUsMy42NSwwLDEsMCwzLjY1LTMuNjZBMy42NCwzLjY0LDAsMCwwLDUyLjE2LDQ1LjA2Wk01NSwyMmEzLjE3LDMuMTcsMCwwLDAsMy4xNi0zLjE3QTMuMjMsMy4yMywwLDAsMCw1NSwxNS42MywzLjE3LDMuMTcsMCwwLDAsNTUsMjJabS0uNDcsMTAuMDlBNS4zNyw1LjM3LDAsMCwwLDYwLDM3LjU0YTUuNDgsNS40OCwwLDEsMC01LjQ1LTUuNDhaTTY2LjI1LDI1LjVhMi42OSwyLjY5LDAsMSwwLTIuNjgtMi42OUEyLjY1LDIuNjUsMCwwLDAsNjYuMjUsMjUuNVpNNDUuNyw2My4xYTMuNDIsMy40MiwwLDEsMC0zLjQxLTMuNDJBMy40MywzLjQzLDAsMCwwLDQ1LjcsNjMuMVptMTQsMTEuMTlhNC40LDQuNCwwLDEsMCw0LjM4LDQuNEE0LjM3LDQuMzcsMCwwLDAsNTkuNzMsNzQuMjlaTTYyLjMsNTAuNTFhOS4yLDkuMiwwLDEsMCw5LjE2LDkuMkE5LjIyLDkuMjIsMCwwLDAsNjIuMyw1MC41MVpNNTAuMSw2Ni43N2EyLjY5LDIuNjksMCwxLDAsMi42OCwyLjY5QTIuNywyLjcsMCwwLDAsNTAuMSw2Ni43N1pNODEuMjUsNDEuMTJhMi43LDIuNywwLDAsMC0yLjY4LDIuNjksMi42NSwyLjY1LDAsMCwwLDIuNjgsMi42OSwyLjY5LDIuNjksMCwwLDAsMC01LjM3Wk00NC40OSw3Ni40N2EzLjczLDMuNzMsMCwwLDAtMy43MywzLjc0LDMuNzcsMy43NywwLDEsMCwzLjczLTMuNzRaTTc5LjA2LDU2LjcyYTQsNCwwLDEsMCw0LDRBNCw0LDAsMCwwLDc5LjA2LDU2LjcyWm0tNiwxMS43OEEzLjA5LDMuMDksMCwwLDAsNzAsNzEuNmEzLDMsMCwwLDAsMy4wOCwzLjA5LDMuMDksMy4wOSwwLDAsMCwwLTYuMTlaTTI4LjMsNjhhNC4xNiw0LjE2LDAsMCwwLTQuMTQsNC4xNUE0LjIxLDQuMjEsMCwwLDAsMjguMyw3Ni4zYTQuMTUsNC4xNSwwLDAsMCwwLTguM1ptLTguMjItOWEzLDMsMCwxLDAsMywzQTMuMDUsMy4wNSwwLDAsMCwyMC4wOCw1OVptMS44NC05Ljc0YTMsMywwLDEsMCwzLDNBMy4wNSwzLjA1LDAsMCwwLDIxLjkxLDQ5LjIyWk0yMi4zNyw0MmEzLjI0LDMuMjQsMCwxLDAtMy4yNCwzLjI2QTMuMjYsMy4yNiwwLDAsMCwyMi4zNyw0MlpNNDMuMTEsNzAuMmEzLjgsMy44LDAsMCwwLTMuODEtMy43NCwzLjczLDMuNzMsMCwwLDAtMy43MywzLjc0QTMuOCwzLjgsMCwwLDAsMzkuMyw3NCwzLjg3LDMuODcsMCwwLDAsNDMuMTEsNzAuMlpNMzcuNTYsNTguNDNhNC42OCw0LjY4LDAsMCwwLTQuNjItNC42NCw0LjYzLDQuNjMsMCwwLDAtNC42Miw0LjY0LDQuNTgsNC41OCwwLDAsMCw0LjYyLDQuNjRBNC42Myw0LjYzLDAsMCwwLDM3LjU2LDU4LjQzWk0yMy4xMSwzMy44MmEyLjUyLDIuNTIsMCwxLDAtMi41MS0yLjUyQTIuNTMsMi41MywwLDAsMCwyMy4xMSwzMy44MloiLz48L2c+PC9zdmc+"
 )
 
@@ -259,6 +267,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 			return phase, err
 		}
 
+		phase, err = r.reconcileDatastoreHealth(ctx, serverClient)
+		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			events.HandleError(r.recorder, installation, phase, "Failed to reconcile datastore health", err)
+			return phase, err
+		}
+
 		phase, err = r.reconcileBlobStorage(ctx, serverClient)
 		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 			events.HandleError(r.recorder, installation, phase, "Failed to reconcile blob storage", err)
@@ -386,6 +400,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		if err != nil {
 			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create envoy config: %w", err)
 		}
+
+		phase, err = r.reconcileManagementRateLimiting(ctx, serverClient)
+		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			events.HandleError(r.recorder, installation, phase, "Failed to reconcile management API rate limiting", err)
+			return phase, err
+		}
 	}
 
 	phase, err = r.reconcileDeploymentConfigs(ctx, serverClient, productNamespace)
@@ -395,6 +415,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
+	phase, err = r.reconcileAutoscaling(ctx, serverClient, productNamespace)
+	logrus.Infof("Phase: %s reconcileAutoscaling", phase)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		events.HandleError(r.recorder, installation, phase, "Failed to reconcile autoscaling", err)
+		return phase, err
+	}
+
 	// Ensure deployment configs are ready before returning phase complete
 	phase, err = r.ensureDeploymentConfigsReady(ctx, serverClient, productNamespace)
 	logrus.Infof("Phase: %s ensureDeploymentConfigsReady", phase)
@@ -499,21 +526,12 @@ func (r *Reconciler) patchDeploymentConfig(ctx context.Context, client k8sclient
 // pods, how to proxy the traffic and what traffic to listen for. Our data needs to be converted to JSON in order for the envoyapi omit empty to filter through fields that we are only interested in,
 // then we need to convert it to yaml and finally push.
 func (r *Reconciler) createEnvoyRateLimitingConfig(ctx context.Context, client k8sclient.Client) error {
-	rateLimitService := &corev1.Service{}
-	marin3rConfig, err := r.ConfigManager.ReadMarin3r()
-	if err != nil {
-		return fmt.Errorf("failed to load marin3r config in 3scale reconciler: %v", err)
-	}
-	err = client.Get(ctx, k8sclient.ObjectKey{
-		Namespace: marin3rConfig.GetNamespace(),
-		Name:      "ratelimit",
-	}, rateLimitService)
-
+	rateLimitCluster, err := r.getRateLimitCluster(ctx, client)
 	if err != nil {
-		return fmt.Errorf("failed to rate limiting service: %v", err)
+		return err
 	}
 
-	// Setting up cluster endpoints for rate limit and apicast
+	// Setting up cluster endpoint for apicast
 	apicastEndpoint := &envoycore.Address{Address: &envoycore.Address_SocketAddress{
 		SocketAddress: &envoycore.SocketAddress{
 			Address:  "127.0.0.1",
@@ -524,16 +542,6 @@ func (r *Reconciler) createEnvoyRateLimitingConfig(ctx context.Context, client k
 		},
 	}}
 
-	rateLimitEndpoint := &envoycore.Address{Address: &envoycore.Address_SocketAddress{
-		SocketAddress: &envoycore.SocketAddress{
-			Address:  rateLimitService.Spec.ClusterIP,
-			Protocol: envoycore.SocketAddress_TCP,
-			PortSpecifier: &envoycore.SocketAddress_PortValue{
-				PortValue: uint32(8081),
-			},
-		},
-	}}
-
 	cluster := envoyapi.Cluster{
 		Name:                 apicastRatelimiting,
 		ConnectTimeout:       ptypes.DurationProto(2 * time.Second),
@@ -554,60 +562,127 @@ func (r *Reconciler) createEnvoyRateLimitingConfig(ctx context.Context, client k
 		},
 	}
 
-	rateLimitCluster := envoyapi.Cluster{
-		Name:                 "ratelimit",
-		ConnectTimeout:       ptypes.DurationProto(2 * time.Second),
-		ClusterDiscoveryType: &envoyapi.Cluster_Type{Type: envoyapi.Cluster_STRICT_DNS},
-		LbPolicy:             envoyapi.Cluster_ROUND_ROBIN,
-		Http2ProtocolOptions: &envoycore.Http2ProtocolOptions{},
-		LoadAssignment: &envoyapi.ClusterLoadAssignment{
-			ClusterName: "ratelimit",
-			Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
-				LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
-					{
-						HostIdentifier: &envoy_api_v2_endpoint.LbEndpoint_Endpoint{
-							Endpoint: &envoy_api_v2_endpoint.Endpoint{
-								Address: rateLimitEndpoint,
-							}},
-					},
-				},
-			}},
-		},
+	rateLimitMode, err := marin3rconfig.GetRateLimitMode(ctx, client, r.installation)
+	if err != nil {
+		return fmt.Errorf("failed to get rate limit mode: %w", err)
 	}
 
-	// Setting up envoyListener
-	virtualHost := v2route.VirtualHost{
-		Name:    apicastRatelimiting,
-		Domains: []string{"*"},
+	rateLimits := newGenericKeyRateLimits("slowpath")
+	rateLimitFilter := newRateLimitFilter(apicastRatelimiting)
 
-		Routes: []*v2route.Route{
-			{
-				Match: &v2route.RouteMatch{
-					PathSpecifier: &v2route.RouteMatch_Prefix{
-						Prefix: "/",
+	apicastRoute := &v2route.RouteAction{
+		ClusterSpecifier: &route.RouteAction_Cluster{
+			Cluster: apicastRatelimiting,
+		},
+	}
+	clusters := []*envoyapi.Cluster{&cluster, rateLimitCluster}
+	var apicastFilters []*hcm.HttpFilter
+	var shadowListener *envoyapi.Listener
+
+	if rateLimitMode == integreatlyv1alpha1.RateLimitModeShadow {
+		// The requests are mirrored to a listener that calls the rate limit
+		// service, so the requests over the limit are recorded in its metrics
+		// without being rejected. The responses to the mirrored requests are
+		// discarded, so the listener sends them to a cluster without endpoints
+		apicastRoute.RequestMirrorPolicies = []*route.RouteAction_RequestMirrorPolicy{{
+			Cluster: rateLimitShadow,
+		}}
+
+		shadowListener, err = newEnvoyListener(rateLimitShadow, "127.0.0.1", rateLimitShadowPort, "ratelimit_shadow", &v2route.RouteAction{
+			ClusterSpecifier: &route.RouteAction_Cluster{
+				Cluster: rateLimitShadowSink,
+			},
+			RateLimits: rateLimits,
+		}, rateLimitFilter)
+		if err != nil {
+			return err
+		}
+
+		clusters = append(clusters,
+			newEnvoyCluster(rateLimitShadow, &envoycore.Address{Address: &envoycore.Address_SocketAddress{
+				SocketAddress: &envoycore.SocketAddress{
+					Address:  "127.0.0.1",
+					Protocol: envoycore.SocketAddress_TCP,
+					PortSpecifier: &envoycore.SocketAddress_PortValue{
+						PortValue: rateLimitShadowPort,
 					},
 				},
-				Action: &v2route.Route_Route{
-					Route: &v2route.RouteAction{
-						ClusterSpecifier: &route.RouteAction_Cluster{
-							Cluster: apicastRatelimiting,
-						},
-						RateLimits: []*route.RateLimit{{
-							Stage: &wrappers.UInt32Value{Value: 0},
-							Actions: []*route.RateLimit_Action{{
-								ActionSpecifier: &route.RateLimit_Action_GenericKey_{
-									GenericKey: &route.RateLimit_Action_GenericKey{
-										DescriptorValue: "slowpath",
-									},
-								},
-							}},
-						}},
-					},
+			}}),
+			&envoyapi.Cluster{
+				Name:                 rateLimitShadowSink,
+				ConnectTimeout:       ptypes.DurationProto(2 * time.Second),
+				ClusterDiscoveryType: &envoyapi.Cluster_Type{Type: envoyapi.Cluster_STATIC},
+				LoadAssignment: &envoyapi.ClusterLoadAssignment{
+					ClusterName: rateLimitShadowSink,
 				},
 			},
-		},
+		)
+	} else {
+		apicastRoute.RateLimits = rateLimits
+		apicastFilters = append(apicastFilters, rateLimitFilter)
+	}
+
+	envoyListener, err := newEnvoyListener("http", "0.0.0.0", 8443, "ingress_http", apicastRoute, apicastFilters...)
+	if err != nil {
+		return err
+	}
+	listeners := []*envoyapi.Listener{envoyListener}
+	if shadowListener != nil {
+		listeners = append(listeners, shadowListener)
+	}
+
+	return r.reconcileEnvoyConfig(ctx, client, apicastRatelimiting, clusters, listeners)
+}
+
+// getRateLimitCluster returns the cluster of the envoy sidecars that
+// points to the rate limit service
+func (r *Reconciler) getRateLimitCluster(ctx context.Context, client k8sclient.Client) (*envoyapi.Cluster, error) {
+	rateLimitService := &corev1.Service{}
+	marin3rConfig, err := r.ConfigManager.ReadMarin3r()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load marin3r config in 3scale reconciler: %v", err)
+	}
+	err = client.Get(ctx, k8sclient.ObjectKey{
+		Namespace: marin3rConfig.GetNamespace(),
+		Name:      "ratelimit",
+	}, rateLimitService)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to rate limiting service: %v", err)
 	}
 
+	rateLimitCluster := newEnvoyCluster("ratelimit", &envoycore.Address{Address: &envoycore.Address_SocketAddress{
+		SocketAddress: &envoycore.SocketAddress{
+			Address:  rateLimitService.Spec.ClusterIP,
+			Protocol: envoycore.SocketAddress_TCP,
+			PortSpecifier: &envoycore.SocketAddress_PortValue{
+				PortValue: uint32(8081),
+			},
+		},
+	}})
+	rateLimitCluster.Http2ProtocolOptions = &envoycore.Http2ProtocolOptions{}
+
+	return rateLimitCluster, nil
+}
+
+// newGenericKeyRateLimits returns the rate limits of a route that count
+// all its requests with a generic key descriptor
+func newGenericKeyRateLimits(descriptorValue string) []*route.RateLimit {
+	return []*route.RateLimit{{
+		Stage: &wrappers.UInt32Value{Value: 0},
+		Actions: []*route.RateLimit_Action{{
+			ActionSpecifier: &route.RateLimit_Action_GenericKey_{
+				GenericKey: &route.RateLimit_Action_GenericKey{
+					DescriptorValue: descriptorValue,
+				},
+			},
+		}},
+	}}
+}
+
+// newRateLimitFilter returns the HTTP filter that calls the rate limit
+// service with the descriptors of the routes in domain
+func newRateLimitFilter(domain string) *hcm.HttpFilter {
 	// Setting GRPC
 	clusterName := &structpb.Struct{
 		Fields: map[string]*structpb.Value{
@@ -646,7 +721,7 @@ func (r *Reconciler) createEnvoyRateLimitingConfig(ctx context.Context, client k
 		Fields: map[string]*structpb.Value{
 			"domain": {
 				Kind: &structpb.Value_StringValue{
-					StringValue: apicastRatelimiting,
+					StringValue: domain,
 				},
 			},
 			"stage": {
@@ -661,42 +736,127 @@ func (r *Reconciler) createEnvoyRateLimitingConfig(ctx context.Context, client k
 			},
 		},
 	}
+	return &hcm.HttpFilter{
+		Name:       "envoy.rate_limit",
+		ConfigType: &hcm.HttpFilter_Config{Config: httpFilterGrpc},
+	}
+}
+
+// reconcileEnvoyConfig creates or updates the EnvoyConfig of the envoy
+// sidecars with nodeID
+func (r *Reconciler) reconcileEnvoyConfig(ctx context.Context, client k8sclient.Client, nodeID string, clusters []*envoyapi.Cluster, listeners []*envoyapi.Listener) error {
+	// Converting to Json and then to Yaml before creating the CR
+	envoyResources := &marin3rv1alpha.EnvoyResources{}
+	for _, cluster := range clusters {
+		resource, err := toEnvoyResource(cluster.Name, cluster)
+		if err != nil {
+			return fmt.Errorf("Failed to convert envoy cluster %s configuration %v", cluster.Name, err)
+		}
+		envoyResources.Clusters = append(envoyResources.Clusters, resource)
+	}
+	for _, listener := range listeners {
+		resource, err := toEnvoyResource(listener.Name, listener)
+		if err != nil {
+			return fmt.Errorf("Failed to convert envoy listener %s configuration %v", listener.Name, err)
+		}
+		envoyResources.Listeners = append(envoyResources.Listeners, resource)
+	}
+
+	envoyconfig := &marin3rv1alpha.EnvoyConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodeID,
+			Namespace: r.Config.GetNamespace(),
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, client, envoyconfig, func() error {
+		owner.AddIntegreatlyOwnerAnnotations(envoyconfig, r.installation)
+		envoyconfig.Spec.NodeID = nodeID
+		envoyconfig.Spec.Serialization = "yaml"
+		envoyconfig.Spec.EnvoyResources = envoyResources
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to create envoy config CR %v", err)
+	}
+
+	return nil
+}
+
+// newEnvoyCluster returns a cluster of the envoy sidecar with a single
+// endpoint
+func newEnvoyCluster(name string, address *envoycore.Address) *envoyapi.Cluster {
+	return &envoyapi.Cluster{
+		Name:                 name,
+		ConnectTimeout:       ptypes.DurationProto(2 * time.Second),
+		ClusterDiscoveryType: &envoyapi.Cluster_Type{Type: envoyapi.Cluster_STRICT_DNS},
+		LbPolicy:             envoyapi.Cluster_ROUND_ROBIN,
+		LoadAssignment: &envoyapi.ClusterLoadAssignment{
+			ClusterName: name,
+			Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
+				LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+					{
+						HostIdentifier: &envoy_api_v2_endpoint.LbEndpoint_Endpoint{
+							Endpoint: &envoy_api_v2_endpoint.Endpoint{
+								Address: address,
+							}},
+					},
+				},
+			}},
+		},
+	}
+}
+
+// newEnvoyListener returns a listener of the envoy sidecar that routes all
+// the requests with routeAction, through httpFilters and the router
+func newEnvoyListener(name, address string, port uint32, statPrefix string, routeAction *v2route.RouteAction, httpFilters ...*hcm.HttpFilter) (*envoyapi.Listener, error) {
+	virtualHost := v2route.VirtualHost{
+		Name:    name,
+		Domains: []string{"*"},
+
+		Routes: []*v2route.Route{
+			{
+				Match: &v2route.RouteMatch{
+					PathSpecifier: &v2route.RouteMatch_Prefix{
+						Prefix: "/",
+					},
+				},
+				Action: &v2route.Route_Route{
+					Route: routeAction,
+				},
+			},
+		},
+	}
 
 	// Setting up connection manager
 	manager := &hcm.HttpConnectionManager{
 		CodecType:  hcm.HttpConnectionManager_AUTO,
-		StatPrefix: "ingress_http",
+		StatPrefix: statPrefix,
 		RouteSpecifier: &hcm.HttpConnectionManager_RouteConfig{
 			RouteConfig: &envoyapi.RouteConfiguration{
 				Name:         "local_route",
 				VirtualHosts: []*v2route.VirtualHost{&virtualHost},
 			},
 		},
-		HttpFilters: []*hcm.HttpFilter{
-			{
-				Name:       "envoy.rate_limit",
-				ConfigType: &hcm.HttpFilter_Config{Config: httpFilterGrpc},
-			},
-			{
-				Name: "envoy.router",
-			},
-		},
+		HttpFilters: append(httpFilters, &hcm.HttpFilter{
+			Name: "envoy.router",
+		}),
 	}
 
 	pbst, err := ptypes.MarshalAny(manager)
 	if err != nil {
-		return fmt.Errorf("failed to convert HttpConnectionManager for rate limiting: %v", err)
+		return nil, fmt.Errorf("failed to convert HttpConnectionManager for rate limiting: %v", err)
 	}
 
-	envoyListener := &envoyapi.Listener{
-		Name: "http",
+	return &envoyapi.Listener{
+		Name: name,
 		Address: &envoycore.Address{
 			Address: &envoycore.Address_SocketAddress{
 				SocketAddress: &envoycore.SocketAddress{
 					Protocol: envoycore.SocketAddress_TCP,
-					Address:  "0.0.0.0",
+					Address:  address,
 					PortSpecifier: &envoycore.SocketAddress_PortValue{
-						PortValue: uint32(8443),
+						PortValue: port,
 					},
 				},
 			},
@@ -707,75 +867,23 @@ func (r *Reconciler) createEnvoyRateLimitingConfig(ctx context.Context, client k
 				ConfigType: &listener.Filter_TypedConfig{TypedConfig: pbst},
 			}},
 		}},
-	}
-
-	// Converting to Json and then to Yaml before creating the CR
-	rateLimitClusterJson, err := ResourcesToJSON(&rateLimitCluster)
-	if err != nil {
-		return fmt.Errorf("Failed to convert envoy rate limiting cluster configuration to JSON %v", err)
-	}
-
-	yamlRateLimitCluster, err := yaml.JSONToYAML(rateLimitClusterJson)
-	if err != nil {
-		return fmt.Errorf("Failed to convert envoy rate limiting cluster JSON configuration to YAML %v", err)
-	}
-
-	clusterJson, err := ResourcesToJSON(&cluster)
-	if err != nil {
-		return fmt.Errorf("Failed to convert envoy cluster configuration to JSON %v", err)
-	}
-
-	yamlCluster, err := yaml.JSONToYAML(clusterJson)
-	if err != nil {
-		return fmt.Errorf("Failed to convert envoy cluster JSON configuration to YAML %v", err)
-	}
+	}, nil
+}
 
-	listenerJson, err := ResourcesToJSON(envoyListener)
+// toEnvoyResource converts an envoy resource to the YAML of an EnvoyConfig
+// resource
+func toEnvoyResource(name string, pb proto.Message) (marin3rv1alpha.EnvoyResource, error) {
+	resourceJson, err := ResourcesToJSON(pb)
 	if err != nil {
-		return fmt.Errorf("Failed to convert envoy envoyListener configuration to JSON %v", err)
+		return marin3rv1alpha.EnvoyResource{}, fmt.Errorf("failed to convert to JSON: %w", err)
 	}
 
-	yamlListener, err := yaml.JSONToYAML(listenerJson)
+	resourceYaml, err := yaml.JSONToYAML(resourceJson)
 	if err != nil {
-		return fmt.Errorf("Failed to convert envoy envoyListener JSON configuration to YAML %v", err)
-	}
-
-	envoyconfig := &marin3rv1alpha.EnvoyConfig{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      apicastRatelimiting,
-			Namespace: r.Config.GetNamespace(),
-		},
+		return marin3rv1alpha.EnvoyResource{}, fmt.Errorf("failed to convert JSON to YAML: %w", err)
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, client, envoyconfig, func() error {
-		owner.AddIntegreatlyOwnerAnnotations(envoyconfig, r.installation)
-		envoyconfig.Spec.NodeID = apicastRatelimiting
-		envoyconfig.Spec.Serialization = "yaml"
-		envoyconfig.Spec.EnvoyResources = &marin3rv1alpha.EnvoyResources{
-			Clusters: []marin3rv1alpha.EnvoyResource{
-				{
-					Name:  apicastRatelimiting,
-					Value: string(yamlCluster),
-				},
-				{
-					Name:  "ratelimit",
-					Value: string(yamlRateLimitCluster),
-				},
-			},
-			Listeners: []marin3rv1alpha.EnvoyResource{
-				{
-					Name:  "http",
-					Value: string(yamlListener),
-				},
-			},
-		}
-		return nil
-	})
-	if err != nil {
-		return fmt.Errorf("Failed to create envoy config CR %v", err)
-	}
-
-	return nil
+	return marin3rv1alpha.EnvoyResource{Name: name, Value: string(resourceYaml)}, nil
 }
 
 func ResourcesToJSON(pb proto.Message) ([]byte, error) {
@@ -931,10 +1039,13 @@ func (r *Reconciler) reconcileComponents(ctx context.Context, serverClient k8scl
 		},
 	}
 
-	antiAffinityRequired, err := resources.IsAntiAffinityRequired(ctx, serverClient)
+	topologyPolicy := resources.GetTopologyPolicy(r.installation, r.Config.GetProductName())
+	antiAffinityRequired := resources.IsAntiAffinityRequired(topologyPolicy)
+	tolerations := resources.TolerationsForPolicy(topologyPolicy)
+
+	sizing, err := r.getSizing(ctx, serverClient)
 	if err != nil {
-		r.logger.Errorf("error when deciding if pod anti affinity is required. Defaulted to false: %v", err)
-		antiAffinityRequired = false
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get 3scale sizing: %w", err)
 	}
 
 	status, err := controllerutil.CreateOrUpdate(ctx, serverClient, apim, func() error {
@@ -946,33 +1057,7 @@ func (r *Reconciler) reconcileComponents(ctx context.Context, serverClient k8scl
 		apim.Spec.PodDisruptionBudget = &threescalev1.PodDisruptionBudgetSpec{Enabled: true}
 		apim.Spec.Monitoring = &threescalev1.MonitoringSpec{Enabled: false}
 
-		if *apim.Spec.System.AppSpec.Replicas < numberOfReplicas {
-			*apim.Spec.System.AppSpec.Replicas = numberOfReplicas
-		}
-		if *apim.Spec.System.SidekiqSpec.Replicas < numberOfReplicas {
-			*apim.Spec.System.SidekiqSpec.Replicas = numberOfReplicas
-		}
-		if *apim.Spec.Apicast.ProductionSpec.Replicas < numberOfReplicas {
-			*apim.Spec.Apicast.ProductionSpec.Replicas = numberOfReplicas
-		}
-		if *apim.Spec.Apicast.StagingSpec.Replicas < numberOfReplicas {
-			*apim.Spec.Apicast.StagingSpec.Replicas = numberOfReplicas
-		}
-		if *apim.Spec.Backend.ListenerSpec.Replicas < numberOfReplicas {
-			*apim.Spec.Backend.ListenerSpec.Replicas = numberOfReplicas
-		}
-		if *apim.Spec.Backend.WorkerSpec.Replicas < numberOfReplicas {
-			*apim.Spec.Backend.WorkerSpec.Replicas = numberOfReplicas
-		}
-		if *apim.Spec.Backend.CronSpec.Replicas < numberOfReplicas {
-			*apim.Spec.Backend.CronSpec.Replicas = numberOfReplicas
-		}
-		if *apim.Spec.Zync.AppSpec.Replicas < numberOfReplicas {
-			*apim.Spec.Zync.AppSpec.Replicas = numberOfReplicas
-		}
-		if *apim.Spec.Zync.QueSpec.Replicas < numberOfReplicas {
-			*apim.Spec.Zync.QueSpec.Replicas = numberOfReplicas
-		}
+		sizing.scaleComponents(apim)
 
 		apim.Spec.System.AppSpec.Affinity = resources.SelectAntiAffinityForCluster(antiAffinityRequired, map[string]string{
 			"threescale_component":         "system",
@@ -1012,6 +1097,19 @@ func (r *Reconciler) reconcileComponents(ctx context.Context, serverClient k8scl
 			"threescale_component_element": "zync-que",
 		})
 
+		// the tolerations set on the components are kept if no policy sets them
+		if tolerations != nil {
+			apim.Spec.System.AppSpec.Tolerations = tolerations
+			apim.Spec.System.SidekiqSpec.Tolerations = tolerations
+			apim.Spec.Apicast.ProductionSpec.Tolerations = tolerations
+			apim.Spec.Apicast.StagingSpec.Tolerations = tolerations
+			apim.Spec.Backend.ListenerSpec.Tolerations = tolerations
+			apim.Spec.Backend.WorkerSpec.Tolerations = tolerations
+			apim.Spec.Backend.CronSpec.Tolerations = tolerations
+			apim.Spec.Zync.AppSpec.Tolerations = tolerations
+			apim.Spec.Zync.QueSpec.Tolerations = tolerations
+		}
+
 		owner.AddIntegreatlyOwnerAnnotations(apim, r.installation)
 
 		return nil
@@ -1187,49 +1285,37 @@ func (r *Reconciler) getBlobStorageFileStorageSpec(ctx context.Context, serverCl
 	}, nil
 }
 
-// reconcileExternalDatasources provisions 2 redis caches and a postgres instance
+// reconcileExternalDatasources provides 2 redis caches and a postgres instance
 // which are used when 3scale HighAvailability mode is enabled
 func (r *Reconciler) reconcileExternalDatasources(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 	logrus.Info("Reconciling external datastores")
-	ns := r.installation.Namespace
+	datastores := resources.NewDatastoreProvider(r.installation)
 
-	// setup backend redis custom resource
-	// this will be used by the cloud resources operator to provision a redis instance
+	// setup backend redis
+	// this will be provisioned by the cloud resources operator unless it's provided by the user
 	logrus.Info("Creating backend redis instance")
-	backendRedisName := fmt.Sprintf("%s%s", constants.ThreeScaleBackendRedisPrefix, r.installation.Name)
-	backendRedis, err := croUtil.ReconcileRedis(ctx, serverClient, defaultInstallationNamespace, r.installation.Spec.Type, croUtil.TierProduction, backendRedisName, ns, backendRedisName, ns, func(cr metav1.Object) error {
-		owner.AddIntegreatlyOwnerAnnotations(cr, r.installation)
-		return nil
-	})
+	backendRedis, err := datastores.ReconcileRedis(ctx, serverClient, defaultInstallationNamespace, constants.ThreeScaleBackendRedisPrefix)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile backend redis request: %w", err)
 	}
 
-	// setup system redis custom resource
-	// this will be used by the cloud resources operator to provision a redis instance
+	// setup system redis
+	// this will be provisioned by the cloud resources operator unless it's provided by the user
 	logrus.Info("Creating system redis instance")
-	systemRedisName := fmt.Sprintf("%s%s", constants.ThreeScaleSystemRedisPrefix, r.installation.Name)
-	systemRedis, err := croUtil.ReconcileRedis(ctx, serverClient, defaultInstallationNamespace, r.installation.Spec.Type, croUtil.TierProduction, systemRedisName, ns, systemRedisName, ns, func(cr metav1.Object) error {
-		owner.AddIntegreatlyOwnerAnnotations(cr, r.installation)
-		return nil
-	})
+	systemRedis, err := datastores.ReconcileRedis(ctx, serverClient, defaultInstallationNamespace, constants.ThreeScaleSystemRedisPrefix)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile system redis request: %w", err)
 	}
 
-	// setup postgres cr for the cloud resource operator
-	// this will be used by the cloud resources operator to provision a postgres instance
+	// setup postgres
+	// this will be provisioned by the cloud resources operator unless it's provided by the user
 	logrus.Info("Creating postgres instance")
-	postgresName := fmt.Sprintf("%s%s", constants.ThreeScalePostgresPrefix, r.installation.Name)
-	postgres, err := croUtil.ReconcilePostgres(ctx, serverClient, defaultInstallationNamespace, r.installation.Spec.Type, croUtil.TierProduction, postgresName, ns, postgresName, ns, func(cr metav1.Object) error {
-		owner.AddIntegreatlyOwnerAnnotations(cr, r.installation)
-		return nil
-	})
+	postgres, err := datastores.ReconcilePostgres(ctx, serverClient, defaultInstallationNamespace, constants.ThreeScalePostgresPrefix)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile postgres request: %w", err)
 	}
 
-	phase, err := resources.ReconcileRedisAlerts(ctx, serverClient, r.installation, backendRedis)
+	phase, err := datastores.ReconcileAlerts(ctx, serverClient, backendRedis)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile redis alerts: %w", err)
 	}
@@ -1237,20 +1323,9 @@ func (r *Reconciler) reconcileExternalDatasources(ctx context.Context, serverCli
 		return phase, nil
 	}
 
-	// create Redis Cpu Usage High alert
-	err = resources.CreateRedisCpuUsageAlerts(ctx, serverClient, r.installation, backendRedis)
-	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create backend redis prometheus Cpu usage high alerts for threescale: %s", err)
-	}
-	// wait for the backend redis cr to reconcile
-	if backendRedis.Status.Phase != types.PhaseComplete {
-		return integreatlyv1alpha1.PhaseAwaitingComponents, nil
-	}
-
-	// get the secret created by the cloud resources operator
-	// containing backend redis connection details
+	// get the secret containing backend redis connection details
 	credSec := &corev1.Secret{}
-	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: backendRedis.Status.SecretRef.Name, Namespace: backendRedis.Status.SecretRef.Namespace}, credSec)
+	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: backendRedis.SecretRef.Name, Namespace: backendRedis.SecretRef.Namespace}, credSec)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get backend redis credential secret: %w", err)
 	}
@@ -1274,22 +1349,17 @@ func (r *Reconciler) reconcileExternalDatasources(ctx context.Context, serverCli
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create or update 3scale %s connection secret: %w", externalBackendRedisSecretName, err)
 	}
 
-	phase, err = resources.ReconcileRedisAlerts(ctx, serverClient, r.installation, systemRedis)
+	phase, err = datastores.ReconcileAlerts(ctx, serverClient, systemRedis)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile redis alerts: %w", err)
 	}
 	if phase != integreatlyv1alpha1.PhaseCompleted {
 		return phase, nil
 	}
-	// wait for the system redis cr to reconcile
-	if systemRedis.Status.Phase != types.PhaseComplete {
-		return integreatlyv1alpha1.PhaseAwaitingComponents, nil
-	}
 
-	// get the secret created by the cloud resources operator
-	// containing system redis connection details
+	// get the secret containing system redis connection details
 	systemCredSec := &corev1.Secret{}
-	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: systemRedis.Status.SecretRef.Name, Namespace: systemRedis.Status.SecretRef.Namespace}, systemCredSec)
+	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: systemRedis.SecretRef.Name, Namespace: systemRedis.SecretRef.Namespace}, systemCredSec)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get system redis credential secret: %w", err)
 	}
@@ -1315,45 +1385,24 @@ func (r *Reconciler) reconcileExternalDatasources(ctx context.Context, serverCli
 	}
 
 	// reconcile postgres alerts
-	phase, err = resources.ReconcilePostgresAlerts(ctx, serverClient, r.installation, postgres)
-	productName := postgres.Labels["productName"]
+	phase, err = datastores.ReconcileAlerts(ctx, serverClient, postgres)
 	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile postgres alerts for %s: %w", productName, err)
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to reconcile postgres alerts for %s: %w", postgres.ProductName, err)
 	}
 	if phase != integreatlyv1alpha1.PhaseCompleted {
 		return phase, nil
 	}
 
-	// get the secret containing redis credentials
+	// get the secret containing postgres credentials
 	postgresCredSec := &corev1.Secret{}
-	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: postgres.Status.SecretRef.Name, Namespace: postgres.Status.SecretRef.Namespace}, postgresCredSec)
+	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: postgres.SecretRef.Name, Namespace: postgres.SecretRef.Namespace}, postgresCredSec)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get postgres credential secret: %w", err)
 	}
 
-	// create postgres external connection secret
-	postgresSecret := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      externalPostgresSecretName,
-			Namespace: r.Config.GetNamespace(),
-		},
-		Data: map[string][]byte{},
-	}
-	_, err = controllerutil.CreateOrUpdate(ctx, serverClient, postgresSecret, func() error {
-		username := postgresCredSec.Data["username"]
-		password := postgresCredSec.Data["password"]
-		url := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s", username, password, postgresCredSec.Data["host"], postgresCredSec.Data["port"], postgresCredSec.Data["database"])
-
-		postgresSecret.Data["URL"] = []byte(url)
-		postgresSecret.Data["DB_USER"] = username
-		postgresSecret.Data["DB_PASSWORD"] = password
-		return nil
-	})
-	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create or update 3scale %s connection secret: %w", externalPostgresSecretName, err)
-	}
-
-	return integreatlyv1alpha1.PhaseCompleted, nil
+	// create postgres external connection secret, rolling out 3scale when
+	// the credentials are rotated
+	return r.reconcilePostgresSecret(ctx, serverClient, postgresCredSec)
 }
 
 func (r *Reconciler) reconcileRHSSOIntegration(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
@@ -1522,7 +1571,7 @@ func (r *Reconciler) reconcileOpenshiftUsers(ctx context.Context, installation *
 }
 
 func (r *Reconciler) preUpgradeBackupExecutor() backup.BackupExecutor {
-	if r.installation.Spec.UseClusterStorage != "false" {
+	if r.installation.Spec.UseClusterStorage != "false" || resources.IsBYODatastores(r.installation) {
 		return backup.NewNoopBackupExecutor()
 	}
 
@@ -1812,9 +1861,15 @@ func userIsOpenshiftAdmin(tsUser *User, adminGroup *usersv1.Group) bool {
 }
 
 func (r *Reconciler) getKeycloakClientSpec(clientSecret string) keycloak.KeycloakClientSpec {
+	return GetKeycloakClientSpec(clientID, clientSecret, fmt.Sprintf("https://3scale-admin.%s", r.installation.Spec.RoutingSubdomain), rhsso.GetInstanceLabels())
+}
+
+// GetKeycloakClientSpec returns the spec of the Keycloak client used by a 3scale
+// admin portal to authenticate its users against the realm with realmLabels
+func GetKeycloakClientSpec(clientID, clientSecret, adminURL string, realmLabels map[string]string) keycloak.KeycloakClientSpec {
 	return keycloak.KeycloakClientSpec{
 		RealmSelector: &metav1.LabelSelector{
-			MatchLabels: rhsso.GetInstanceLabels(),
+			MatchLabels: realmLabels,
 		},
 		Client: &keycloak.KeycloakAPIClient{
 			ID:                      clientID,
@@ -1823,10 +1878,10 @@ func (r *Reconciler) getKeycloakClientSpec(clientSecret string) keycloak.Keycloa
 			Secret:                  clientSecret,
 			ClientAuthenticatorType: "client-secret",
 			RedirectUris: []string{
-				fmt.Sprintf("https://3scale-admin.%s/*", r.installation.Spec.RoutingSubdomain),
+				adminURL + "/*",
 			},
 			StandardFlowEnabled: true,
-			RootURL:             fmt.Sprintf("https://3scale-admin.%s", r.installation.Spec.RoutingSubdomain),
+			RootURL:             adminURL,
 			FullScopeAllowed:    true,
 			Access: map[string]bool{
 				"view":      true,
@@ -2064,6 +2119,10 @@ func (r *Reconciler) deleteConsoleLink(ctx context.Context, serverClient k8sclie
 }
 
 func (r *Reconciler) reconcileDeploymentConfigs(ctx context.Context, serverClient k8sclient.Client, productNamespace string) (integreatlyv1alpha1.StatusPhase, error) {
+	sizing, err := r.getSizing(ctx, serverClient)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get 3scale sizing: %w", err)
+	}
 
 	for _, name := range threeScaleDeploymentConfigs {
 		deploymentConfig := &appsv1.DeploymentConfig{
@@ -2078,13 +2137,22 @@ func (r *Reconciler) reconcileDeploymentConfigs(ctx context.Context, serverClien
 			podPriorityMutation = resources.MutatePodPriority(r.installation.Spec.PriorityClassName)
 		}
 
+		topologyPolicy := resources.GetTopologyPolicy(r.installation, r.Config.GetProductName())
+
+		resourcesMutation := resources.NoopMutate
+		if requirements := sizing.resourceRequirements(name); len(requirements) > 0 {
+			resourcesMutation = resources.MutateContainerResources(requirements)
+		}
+
 		phase, err := resources.UpdatePodTemplateIfExists(
 			ctx,
 			serverClient,
 			resources.SelectFromDeploymentConfig,
 			resources.AllMutationsOf(
-				resources.MutateZoneTopologySpreadConstraints("app"),
+				resources.MutateZoneTopologySpreadConstraints(topologyPolicy, "app"),
+				resources.MutateNodePlacement(topologyPolicy),
 				podPriorityMutation,
+				resourcesMutation,
 			),
 			deploymentConfig,
 		)
@@ -2096,6 +2164,30 @@ func (r *Reconciler) reconcileDeploymentConfigs(ctx context.Context, serverClien
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
+// reconcileAutoscaling creates the HorizontalPodAutoscalers of the components
+// with autoscaling set, and removes them from the components without it
+func (r *Reconciler) reconcileAutoscaling(ctx context.Context, serverClient k8sclient.Client, productNamespace string) (integreatlyv1alpha1.StatusPhase, error) {
+	sizing, err := r.getSizing(ctx, serverClient)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get 3scale sizing: %w", err)
+	}
+
+	for _, name := range autoscalableComponents {
+		target := autoscalingv2beta2.CrossVersionObjectReference{
+			APIVersion: appsv1.GroupVersion.String(),
+			Kind:       "DeploymentConfig",
+			Name:       name,
+		}
+
+		phase, err := resources.ReconcileHorizontalPodAutoscaler(ctx, serverClient, productNamespace, target, sizing.autoscaling(name))
+		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			return phase, err
+		}
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
 // Deployment configs are rescaled when adding topologySpreadConstraints, PodTopology etc
 // Should check that these deployment configs are ready before returning phase complete in CR
 func (r *Reconciler) ensureDeploymentConfigsReady(ctx context.Context, serverClient k8sclient.Client, productNamespace string) (integreatlyv1alpha1.StatusPhase, error) {
@@ -3,8 +3,11 @@ package threescale
 import (
 	"context"
 	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 
+	marin3rv1alpha "github.com/3scale/marin3r/pkg/apis/marin3r/v1alpha1"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/constants"
 	"github.com/sirupsen/logrus"
 
@@ -37,6 +40,8 @@ import (
 	marketplacev2 "github.com/operator-framework/operator-marketplace/pkg/apis/operators/v2"
 
 	consolev1 "github.com/openshift/api/console/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -69,6 +74,9 @@ func getBuildScheme() (*runtime.Scheme, error) {
 	err = monitoringv1.AddToScheme(scheme)
 	err = consolev1.AddToScheme(scheme)
 	err = openshiftv1.AddToScheme(scheme)
+	err = autoscalingv2beta2.AddToScheme(scheme)
+	err = batchv1.AddToScheme(scheme)
+	err = marin3rv1alpha.SchemeBuilder.AddToScheme(scheme)
 	return scheme, err
 }
 
@@ -428,3 +436,81 @@ func TestReconciler_syncOpenshiftAdmimMembership(t *testing.T) {
 		t.Fatal("Expected user with ID 1 to be promoted as admin, but no promotion was invoked")
 	}
 }
+
+func TestReconciler_createEnvoyRateLimitingConfig(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name              string
+		rateLimitMode     integreatlyv1alpha1.RateLimitMode
+		expectedClusters  []string
+		expectedListeners []string
+	}{
+		{
+			name:              "requests over the limit are rejected in enforce mode",
+			rateLimitMode:     integreatlyv1alpha1.RateLimitModeEnforce,
+			expectedClusters:  []string{apicastRatelimiting, "ratelimit"},
+			expectedListeners: []string{"http"},
+		},
+		{
+			name:              "requests are mirrored to the rate limit service in shadow mode",
+			rateLimitMode:     integreatlyv1alpha1.RateLimitModeShadow,
+			expectedClusters:  []string{apicastRatelimiting, "ratelimit", rateLimitShadow, rateLimitShadowSink},
+			expectedListeners: []string{"http", rateLimitShadow},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installation := getTestInstallation()
+			installation.Spec.Marin3r = &integreatlyv1alpha1.Marin3rSpec{RateLimitMode: tt.rateLimitMode}
+			serverClient := fake.NewFakeClientWithScheme(scheme, &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ratelimit",
+					Namespace: "marin3r",
+				},
+				Spec: corev1.ServiceSpec{ClusterIP: "172.30.0.10"},
+			})
+			r := &Reconciler{
+				ConfigManager: &config.ConfigReadWriterMock{
+					ReadMarin3rFunc: func() (*config.Marin3r, error) {
+						return config.NewMarin3r(config.ProductConfig{"NAMESPACE": "marin3r"}), nil
+					},
+				},
+				Config:       config.NewThreeScale(config.ProductConfig{"NAMESPACE": "test"}),
+				logger:       logrus.NewEntry(logrus.New()),
+				installation: installation,
+			}
+
+			if err := r.createEnvoyRateLimitingConfig(context.TODO(), serverClient); err != nil {
+				t.Fatalf("createEnvoyRateLimitingConfig() error = %v", err)
+			}
+
+			envoyConfig := &marin3rv1alpha.EnvoyConfig{}
+			if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: apicastRatelimiting, Namespace: "test"}, envoyConfig); err != nil {
+				t.Fatalf("failed to get envoy config: %v", err)
+			}
+			var clusters, listeners []string
+			for _, cluster := range envoyConfig.Spec.EnvoyResources.Clusters {
+				clusters = append(clusters, cluster.Name)
+			}
+			for _, listener := range envoyConfig.Spec.EnvoyResources.Listeners {
+				listeners = append(listeners, listener.Name)
+			}
+			if !reflect.DeepEqual(clusters, tt.expectedClusters) || !reflect.DeepEqual(listeners, tt.expectedListeners) {
+				t.Errorf("unexpected clusters %v and listeners %v", clusters, listeners)
+			}
+
+			httpListener := envoyConfig.Spec.EnvoyResources.Listeners[0].Value
+			enforced := strings.Contains(httpListener, "envoy.rate_limit")
+			if enforced != (tt.rateLimitMode == integreatlyv1alpha1.RateLimitModeEnforce) {
+				t.Errorf("expected rate limit filter on the http listener only in enforce mode, got %s", httpListener)
+			}
+			if tt.rateLimitMode == integreatlyv1alpha1.RateLimitModeShadow && !strings.Contains(httpListener, rateLimitShadow) {
+				t.Errorf("expected requests to be mirrored to %s, got %s", rateLimitShadow, httpListener)
+			}
+		})
+	}
+}
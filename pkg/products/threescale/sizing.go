@@ -0,0 +1,184 @@
+package threescale
+
+import (
+	"context"
+	"fmt"
+
+	threescalev1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// sizingProfiles sets the minimum number of replicas of each scalable 3scale
+// component for each profile. Components not listed use numberOfReplicas
+var sizingProfiles = map[integreatlyv1alpha1.SizingProfile]map[string]int64{
+	integreatlyv1alpha1.SizingProfileSmall: {},
+	integreatlyv1alpha1.SizingProfileMedium: {
+		"apicast-production": 3,
+		"backend-listener":   3,
+		"backend-worker":     3,
+	},
+	integreatlyv1alpha1.SizingProfileLarge: {
+		"apicast-production": 6,
+		"backend-listener":   6,
+		"backend-worker":     6,
+		"system-app":         3,
+		"system-sidekiq":     3,
+	},
+}
+
+// scalableComponents are the components whose replicas are set in the APIManager
+var scalableComponents = []string{
+	"system-app",
+	"system-sidekiq",
+	"apicast-production",
+	"apicast-staging",
+	"backend-listener",
+	"backend-worker",
+	"backend-cron",
+	"zync",
+	"zync-que",
+}
+
+// autoscalableComponents are the components that can be scaled by a
+// HorizontalPodAutoscaler
+var autoscalableComponents = []string{
+	"apicast-production",
+	"backend-listener",
+}
+
+// sizing is the resolved sizing of the 3scale components
+type sizing struct {
+	profile    integreatlyv1alpha1.SizingProfile
+	components map[string]integreatlyv1alpha1.ComponentSizing
+
+	// autoscaledReplicas are the current replicas of the deployment configs
+	// scaled by a HorizontalPodAutoscaler
+	autoscaledReplicas map[string]int64
+}
+
+// getSizing resolves the sizing of the 3scale components from the RHMI spec.
+// If no profile is set, the profile of the SKU selected in the rate limit
+// configuration is used, so both stay in sync
+func (r *Reconciler) getSizing(ctx context.Context, serverClient k8sclient.Client) (*sizing, error) {
+	result := &sizing{
+		profile:            integreatlyv1alpha1.SizingProfileSmall,
+		components:         map[string]integreatlyv1alpha1.ComponentSizing{},
+		autoscaledReplicas: map[string]int64{},
+	}
+
+	spec := r.installation.Spec.ThreeScale
+	if spec != nil && spec.Profile != "" {
+		result.profile = spec.Profile
+	} else {
+		rateLimitConfig, err := marin3rconfig.GetRateLimitConfig(ctx, serverClient, r.installation)
+		if err != nil && !k8serr.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get rate limit config: %w", err)
+		}
+		if rateLimitConfig != nil && rateLimitConfig.ThreeScaleProfile != "" {
+			result.profile = integreatlyv1alpha1.SizingProfile(rateLimitConfig.ThreeScaleProfile)
+		}
+	}
+
+	if _, ok := sizingProfiles[result.profile]; !ok {
+		return nil, fmt.Errorf("unknown 3scale sizing profile %s", result.profile)
+	}
+
+	if spec == nil {
+		return result, nil
+	}
+
+	for component, componentSizing := range spec.Components {
+		if !contains(threeScaleDeploymentConfigs, component) {
+			return nil, fmt.Errorf("unknown 3scale component %s", component)
+		}
+		if !contains(scalableComponents, component) && componentSizing.Replicas != nil {
+			return nil, fmt.Errorf("replicas of 3scale component %s can't be set", component)
+		}
+		if componentSizing.Autoscaling != nil {
+			if !contains(autoscalableComponents, component) {
+				return nil, fmt.Errorf("autoscaling of 3scale component %s is not supported", component)
+			}
+			if componentSizing.Replicas != nil {
+				return nil, fmt.Errorf("replicas and autoscaling of 3scale component %s can't be set together", component)
+			}
+
+			deploymentConfig := &appsv1.DeploymentConfig{}
+			err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: component, Namespace: r.Config.GetNamespace()}, deploymentConfig)
+			if err != nil && !k8serr.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get deployment config %s: %w", component, err)
+			}
+			if err == nil {
+				result.autoscaledReplicas[component] = int64(deploymentConfig.Spec.Replicas)
+			}
+		}
+		result.components[component] = componentSizing
+	}
+
+	return result, nil
+}
+
+// replicas returns the number of replicas of the component. Replicas set for
+// the component are used as they are, while the profile only sets a minimum
+// so components scaled up manually are not scaled down. Components scaled by
+// a HorizontalPodAutoscaler keep the replicas it set on their deployment config
+func (s *sizing) replicas(component string, current *int64) *int64 {
+	componentSizing := s.components[component]
+	if componentSizing.Replicas != nil {
+		return &[]int64{*componentSizing.Replicas}[0]
+	}
+	if componentSizing.Autoscaling != nil {
+		if replicas, ok := s.autoscaledReplicas[component]; ok {
+			return &replicas
+		}
+		return &[]int64{int64(resources.GetAutoscalingMinReplicas(componentSizing.Autoscaling))}[0]
+	}
+
+	minimum, ok := sizingProfiles[s.profile][component]
+	if !ok {
+		minimum = numberOfReplicas
+	}
+	if current == nil || *current < minimum {
+		return &minimum
+	}
+	return current
+}
+
+// scaleComponents sets the replicas of each scalable component of the APIManager
+func (s *sizing) scaleComponents(apim *threescalev1.APIManager) {
+	apim.Spec.System.AppSpec.Replicas = s.replicas("system-app", apim.Spec.System.AppSpec.Replicas)
+	apim.Spec.System.SidekiqSpec.Replicas = s.replicas("system-sidekiq", apim.Spec.System.SidekiqSpec.Replicas)
+	apim.Spec.Apicast.ProductionSpec.Replicas = s.replicas("apicast-production", apim.Spec.Apicast.ProductionSpec.Replicas)
+	apim.Spec.Apicast.StagingSpec.Replicas = s.replicas("apicast-staging", apim.Spec.Apicast.StagingSpec.Replicas)
+	apim.Spec.Backend.ListenerSpec.Replicas = s.replicas("backend-listener", apim.Spec.Backend.ListenerSpec.Replicas)
+	apim.Spec.Backend.WorkerSpec.Replicas = s.replicas("backend-worker", apim.Spec.Backend.WorkerSpec.Replicas)
+	apim.Spec.Backend.CronSpec.Replicas = s.replicas("backend-cron", apim.Spec.Backend.CronSpec.Replicas)
+	apim.Spec.Zync.AppSpec.Replicas = s.replicas("zync", apim.Spec.Zync.AppSpec.Replicas)
+	apim.Spec.Zync.QueSpec.Replicas = s.replicas("zync-que", apim.Spec.Zync.QueSpec.Replicas)
+}
+
+// autoscaling returns the autoscaling set for the component, if any
+func (s *sizing) autoscaling(component string) *integreatlyv1alpha1.HorizontalAutoscaling {
+	return s.components[component].Autoscaling
+}
+
+// resourceRequirements returns the resources set for the containers of the
+// component, if any
+func (s *sizing) resourceRequirements(component string) map[string]corev1.ResourceRequirements {
+	return s.components[component].Resources
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package threescale
+
+import (
+	"context"
+	"testing"
+
+	threescalev1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+	appsv1 "github.com/openshift/api/apps/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func getTestRateLimitConfigMap(profile string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      marin3rconfig.RateLimitConfigMapName,
+			Namespace: "test",
+		},
+		Data: map[string]string{
+			"rate_limit": `{"` + marin3rconfig.ManagedApiServiceSKU + `": {"unit": "minute", "requests_per_unit": 20, "threescale_profile": "` + profile + `"}}`,
+		},
+	}
+}
+
+func getTestAPIManager(replicas int64) *threescalev1.APIManager {
+	return &threescalev1.APIManager{
+		Spec: threescalev1.APIManagerSpec{
+			System: &threescalev1.SystemSpec{
+				AppSpec:     &threescalev1.SystemAppSpec{Replicas: &[]int64{replicas}[0]},
+				SidekiqSpec: &threescalev1.SystemSidekiqSpec{Replicas: &[]int64{replicas}[0]},
+			},
+			Apicast: &threescalev1.ApicastSpec{
+				ProductionSpec: &threescalev1.ApicastProductionSpec{Replicas: &[]int64{replicas}[0]},
+				StagingSpec:    &threescalev1.ApicastStagingSpec{Replicas: &[]int64{replicas}[0]},
+			},
+			Backend: &threescalev1.BackendSpec{
+				ListenerSpec: &threescalev1.BackendListenerSpec{Replicas: &[]int64{replicas}[0]},
+				WorkerSpec:   &threescalev1.BackendWorkerSpec{Replicas: &[]int64{replicas}[0]},
+				CronSpec:     &threescalev1.BackendCronSpec{Replicas: &[]int64{replicas}[0]},
+			},
+			Zync: &threescalev1.ZyncSpec{
+				AppSpec: &threescalev1.ZyncAppSpec{Replicas: &[]int64{replicas}[0]},
+				QueSpec: &threescalev1.ZyncQueSpec{Replicas: &[]int64{replicas}[0]},
+			},
+		},
+	}
+}
+
+func TestReconciler_getSizing(t *testing.T) {
+	scheme, err := getBuildScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                  string
+		threeScale            *integreatlyv1alpha1.ThreeScaleSpec
+		objects               []runtime.Object
+		currentReplicas       int64
+		wantErr               bool
+		wantApicastProduction int64
+		wantBackendCron       int64
+	}{
+		{
+			name:                  "test default profile without rate limit config",
+			currentReplicas:       0,
+			wantApicastProduction: numberOfReplicas,
+			wantBackendCron:       numberOfReplicas,
+		},
+		{
+			name:                  "test profile follows the sku of the rate limit config",
+			objects:               []runtime.Object{getTestRateLimitConfigMap("large")},
+			currentReplicas:       0,
+			wantApicastProduction: 6,
+			wantBackendCron:       numberOfReplicas,
+		},
+		{
+			name:                  "test profile in spec takes precedence over the sku",
+			threeScale:            &integreatlyv1alpha1.ThreeScaleSpec{Profile: integreatlyv1alpha1.SizingProfileMedium},
+			objects:               []runtime.Object{getTestRateLimitConfigMap("large")},
+			currentReplicas:       0,
+			wantApicastProduction: 3,
+			wantBackendCron:       numberOfReplicas,
+		},
+		{
+			name:                  "test profile does not scale down components",
+			threeScale:            &integreatlyv1alpha1.ThreeScaleSpec{Profile: integreatlyv1alpha1.SizingProfileMedium},
+			currentReplicas:       4,
+			wantApicastProduction: 4,
+			wantBackendCron:       4,
+		},
+		{
+			name: "test explicit component sizing overrides the profile",
+			threeScale: &integreatlyv1alpha1.ThreeScaleSpec{
+				Profile: integreatlyv1alpha1.SizingProfileLarge,
+				Components: map[string]integreatlyv1alpha1.ComponentSizing{
+					"apicast-production": {Replicas: &[]int64{1}[0]},
+				},
+			},
+			currentReplicas:       4,
+			wantApicastProduction: 1,
+			wantBackendCron:       4,
+		},
+		{
+			name: "test autoscaled component keeps the replicas of its deployment config",
+			threeScale: &integreatlyv1alpha1.ThreeScaleSpec{
+				Profile: integreatlyv1alpha1.SizingProfileLarge,
+				Components: map[string]integreatlyv1alpha1.ComponentSizing{
+					"apicast-production": {Autoscaling: &integreatlyv1alpha1.HorizontalAutoscaling{MaxReplicas: 10}},
+				},
+			},
+			objects: []runtime.Object{&appsv1.DeploymentConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "apicast-production", Namespace: "3scale"},
+				Spec:       appsv1.DeploymentConfigSpec{Replicas: 8},
+			}},
+			currentReplicas:       4,
+			wantApicastProduction: 8,
+			wantBackendCron:       4,
+		},
+		{
+			name: "test autoscaled component without deployment config uses min replicas",
+			threeScale: &integreatlyv1alpha1.ThreeScaleSpec{
+				Components: map[string]integreatlyv1alpha1.ComponentSizing{
+					"apicast-production": {Autoscaling: &integreatlyv1alpha1.HorizontalAutoscaling{MaxReplicas: 10}},
+				},
+			},
+			currentReplicas:       4,
+			wantApicastProduction: 2,
+			wantBackendCron:       4,
+		},
+		{
+			name: "test autoscaling of unsupported component fails",
+			threeScale: &integreatlyv1alpha1.ThreeScaleSpec{
+				Components: map[string]integreatlyv1alpha1.ComponentSizing{
+					"system-app": {Autoscaling: &integreatlyv1alpha1.HorizontalAutoscaling{MaxReplicas: 10}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "test replicas and autoscaling together fail",
+			threeScale: &integreatlyv1alpha1.ThreeScaleSpec{
+				Components: map[string]integreatlyv1alpha1.ComponentSizing{
+					"backend-listener": {
+						Replicas:    &[]int64{2}[0],
+						Autoscaling: &integreatlyv1alpha1.HorizontalAutoscaling{MaxReplicas: 10},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "test unknown profile fails",
+			threeScale: &integreatlyv1alpha1.ThreeScaleSpec{Profile: "huge"},
+			wantErr:    true,
+		},
+		{
+			name: "test unknown component fails",
+			threeScale: &integreatlyv1alpha1.ThreeScaleSpec{
+				Components: map[string]integreatlyv1alpha1.ComponentSizing{
+					"apicast": {Replicas: &[]int64{1}[0]},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "test replicas of non scalable component fails",
+			threeScale: &integreatlyv1alpha1.ThreeScaleSpec{
+				Components: map[string]integreatlyv1alpha1.ComponentSizing{
+					"system-memcache": {Replicas: &[]int64{1}[0]},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installation := getTestInstallation()
+			installation.Spec.ThreeScale = tt.threeScale
+			r := &Reconciler{
+				installation: installation,
+				Config: config.NewThreeScale(config.ProductConfig{
+					"NAMESPACE": "3scale",
+				}),
+			}
+
+			sizing, err := r.getSizing(context.TODO(), fake.NewFakeClientWithScheme(scheme, tt.objects...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getSizing() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			apim := getTestAPIManager(tt.currentReplicas)
+			sizing.scaleComponents(apim)
+			if got := *apim.Spec.Apicast.ProductionSpec.Replicas; got != tt.wantApicastProduction {
+				t.Errorf("apicast-production replicas = %d, want %d", got, tt.wantApicastProduction)
+			}
+			if got := *apim.Spec.Backend.CronSpec.Replicas; got != tt.wantBackendCron {
+				t.Errorf("backend-cron replicas = %d, want %d", got, tt.wantBackendCron)
+			}
+		})
+	}
+}
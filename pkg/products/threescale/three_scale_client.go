@@ -2,10 +2,13 @@ package threescale
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
 )
 
 //go:generate moq -out three_scale_moq.go . ThreeScaleInterface
@@ -21,6 +24,44 @@ type ThreeScaleInterface interface {
 	SetUserAsAdmin(userID int, accessToken string) (*http.Response, error)
 	SetUserAsMember(userID int, accessToken string) (*http.Response, error)
 	UpdateUser(userID int, username string, email string, accessToken string) (*http.Response, error)
+
+	// Tenant calls are made to the master API, with the master access token
+	CreateTenant(orgName, username, email, password, masterAccessToken string) (*Tenant, error)
+	GetTenant(tenantID int, masterAccessToken string) (*TenantAccount, error)
+	GetTenantByOrgName(orgName, masterAccessToken string) (*TenantAccount, error)
+	DeleteTenant(tenantID int, masterAccessToken string) (*http.Response, error)
+	GetTenantUsers(tenantID int, masterAccessToken string) (*Users, error)
+	ActivateTenantUser(tenantID int, userID int, masterAccessToken string) (*http.Response, error)
+
+	// Backend and product calls are made to the account management API
+	GetBackendApis(accessToken string) (*BackendApis, error)
+	CreateBackendApi(data map[string]string, accessToken string) (*BackendApi, error)
+	UpdateBackendApi(backendID int, data map[string]string, accessToken string) (*BackendApi, error)
+	DeleteBackendApi(backendID int, accessToken string) (*http.Response, error)
+	GetBackendApiMetrics(backendID int, accessToken string) (*Metrics, error)
+	GetBackendApiMappingRules(backendID int, accessToken string) (*MappingRules, error)
+	CreateBackendApiMappingRule(backendID int, data map[string]string, accessToken string) (*MappingRule, error)
+	DeleteBackendApiMappingRule(backendID int, ruleID int, accessToken string) (*http.Response, error)
+	GetServices(accessToken string) (*Services, error)
+	CreateService(data map[string]string, accessToken string) (*Service, error)
+	UpdateService(serviceID int, data map[string]string, accessToken string) (*Service, error)
+	DeleteService(serviceID int, accessToken string) (*http.Response, error)
+	GetServiceMetrics(serviceID int, accessToken string) (*Metrics, error)
+	GetServiceMappingRules(serviceID int, accessToken string) (*MappingRules, error)
+	CreateServiceMappingRule(serviceID int, data map[string]string, accessToken string) (*MappingRule, error)
+	DeleteServiceMappingRule(serviceID int, ruleID int, accessToken string) (*http.Response, error)
+	GetBackendUsages(serviceID int, accessToken string) ([]*BackendUsage, error)
+	CreateBackendUsage(serviceID int, data map[string]string, accessToken string) (*BackendUsage, error)
+	DeleteBackendUsage(serviceID int, usageID int, accessToken string) (*http.Response, error)
+	GetApplicationPlans(serviceID int, accessToken string) (*ApplicationPlans, error)
+	CreateApplicationPlan(serviceID int, data map[string]string, accessToken string) (*ApplicationPlan, error)
+	UpdateApplicationPlan(serviceID int, planID int, data map[string]string, accessToken string) (*ApplicationPlan, error)
+	DeleteApplicationPlan(serviceID int, planID int, accessToken string) (*http.Response, error)
+	GetPolicies(serviceID int, accessToken string) (*PoliciesConfig, error)
+	UpdatePolicies(serviceID int, policies []PolicyConfig, accessToken string) (*http.Response, error)
+	DeployProxy(serviceID int, accessToken string) (*http.Response, error)
+	GetLatestProxyConfig(serviceID int, environment string, accessToken string) (*ProxyConfig, error)
+	PromoteProxyConfig(serviceID int, environment string, version int, toEnvironment string, accessToken string) (*http.Response, error)
 }
 
 const (
@@ -31,6 +72,7 @@ const (
 type threeScaleClient struct {
 	httpc          *http.Client
 	wildCardDomain string
+	adminDomain    string
 	ns             string
 }
 
@@ -39,9 +81,35 @@ func NewThreeScaleClient(httpc *http.Client, wildCardDomain string) *threeScaleC
 	return &threeScaleClient{
 		httpc:          httpc,
 		wildCardDomain: wildCardDomain,
+		adminDomain:    fmt.Sprintf("3scale-admin.%s", wildCardDomain),
 	}
 }
 
+// NewThreeScaleTenantClient returns a client whose admin calls are made to
+// the admin portal of a tenant
+func NewThreeScaleTenantClient(httpc *http.Client, wildCardDomain string, adminDomain string) *threeScaleClient {
+
+	return &threeScaleClient{
+		httpc:          httpc,
+		wildCardDomain: wildCardDomain,
+		adminDomain:    adminDomain,
+	}
+}
+
+// NewThreeScaleInstallationClient returns a client for the admin portal at
+// adminDomain of the 3scale of the installation
+func NewThreeScaleInstallationClient(installation *integreatlyv1alpha1.RHMI, adminDomain string) ThreeScaleInterface {
+	httpc := &http.Client{
+		Timeout: time.Second * 10,
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+			IdleConnTimeout:   time.Second * 10,
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: installation.Spec.SelfSignedCerts},
+		},
+	}
+	return NewThreeScaleTenantClient(httpc, installation.Spec.RoutingSubdomain, adminDomain)
+}
+
 func (tsc *threeScaleClient) SetNamespace(ns string) {
 	tsc.ns = ns
 }
@@ -54,7 +122,7 @@ func (tsc *threeScaleClient) AddAuthenticationProvider(data map[string]string, a
 	}
 	tsc.httpc.Timeout = time.Second * 10
 	res, err := tsc.httpc.Post(
-		fmt.Sprintf("https://3scale-admin.%s/admin/api/account/authentication_providers.json", tsc.wildCardDomain),
+		fmt.Sprintf("https://%s/admin/api/account/authentication_providers.json", tsc.adminDomain),
 		"application/json",
 		bytes.NewBuffer(reqData),
 	)
@@ -67,7 +135,7 @@ func (tsc *threeScaleClient) AddAuthenticationProvider(data map[string]string, a
 
 func (tsc *threeScaleClient) GetAuthenticationProviders(accessToken string) (*AuthProviders, error) {
 	res, err := tsc.httpc.Get(
-		fmt.Sprintf("https://3scale-admin.%s/admin/api/account/authentication_providers.json?access_token=%s", tsc.wildCardDomain, accessToken),
+		fmt.Sprintf("https://%s/admin/api/account/authentication_providers.json?access_token=%s", tsc.adminDomain, accessToken),
 	)
 	if err != nil {
 		return nil, err
@@ -114,7 +182,7 @@ func (tsc *threeScaleClient) GetUser(username, accessToken string) (*User, error
 
 func (tsc *threeScaleClient) GetUsers(accessToken string) (*Users, error) {
 	res, err := tsc.httpc.Get(
-		fmt.Sprintf("https://3scale-admin.%s/admin/api/users.json?access_token=%s", tsc.wildCardDomain, accessToken),
+		fmt.Sprintf("https://%s/admin/api/users.json?access_token=%s", tsc.adminDomain, accessToken),
 	)
 	if err != nil {
 		return nil, err
@@ -138,7 +206,7 @@ func (tsc *threeScaleClient) AddUser(username string, email string, password str
 	reqData, err := json.Marshal(data)
 
 	res, err := tsc.httpc.Post(
-		fmt.Sprintf("https://3scale-admin.%s/admin/api/users.json", tsc.wildCardDomain),
+		fmt.Sprintf("https://%s/admin/api/users.json", tsc.adminDomain),
 		"application/json",
 		bytes.NewBuffer(reqData),
 	)
@@ -156,7 +224,7 @@ func (tsc *threeScaleClient) DeleteUser(userID int, accessToken string) (*http.R
 
 	req, err := http.NewRequest(
 		http.MethodDelete,
-		fmt.Sprintf("https://3scale-admin.%s/admin/api/users/%d.json", tsc.wildCardDomain, userID),
+		fmt.Sprintf("https://%s/admin/api/users/%d.json", tsc.adminDomain, userID),
 		bytes.NewBuffer(reqData))
 	req.Header.Add("Content-type", "application/json")
 	tsc.httpc.Timeout = time.Second * 10
@@ -172,7 +240,7 @@ func (tsc *threeScaleClient) SetUserAsAdmin(userID int, accessToken string) (*ht
 	data, err := json.Marshal(map[string]string{
 		"access_token": accessToken,
 	})
-	url := fmt.Sprintf("https://3scale-admin.%s/admin/api/users/%d/admin.json", tsc.wildCardDomain, userID)
+	url := fmt.Sprintf("https://%s/admin/api/users/%d/admin.json", tsc.adminDomain, userID)
 	req, err := http.NewRequest(
 		"PUT",
 		url,
@@ -194,7 +262,7 @@ func (tsc *threeScaleClient) SetUserAsMember(userID int, accessToken string) (*h
 	data, err := json.Marshal(map[string]string{
 		"access_token": accessToken,
 	})
-	url := fmt.Sprintf("https://3scale-admin.%s/admin/api/users/%d/member.json", tsc.wildCardDomain, userID)
+	url := fmt.Sprintf("https://%s/admin/api/users/%d/member.json", tsc.adminDomain, userID)
 	req, err := http.NewRequest(
 		"PUT",
 		url,
@@ -217,7 +285,7 @@ func (tsc *threeScaleClient) UpdateUser(userID int, username string, email strin
 		"username":     username,
 		"email":        email,
 	})
-	url := fmt.Sprintf("https://3scale-admin.%s/admin/api/users/%d.json", tsc.wildCardDomain, userID)
+	url := fmt.Sprintf("https://%s/admin/api/users/%d.json", tsc.adminDomain, userID)
 	req, err := http.NewRequest(
 		"PUT",
 		url,
@@ -233,3 +301,149 @@ func (tsc *threeScaleClient) UpdateUser(userID int, username string, email strin
 
 	return res, err
 }
+
+func (tsc *threeScaleClient) CreateTenant(orgName, username, email, password, masterAccessToken string) (*Tenant, error) {
+	reqData, err := json.Marshal(map[string]string{
+		"access_token": masterAccessToken,
+		"org_name":     orgName,
+		"username":     username,
+		"email":        email,
+		"password":     password,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tsc.httpc.Timeout = time.Second * 10
+	res, err := tsc.httpc.Post(
+		fmt.Sprintf("https://master.%s/master/api/providers.json", tsc.wildCardDomain),
+		"application/json",
+		bytes.NewBuffer(reqData),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return nil, &tsError{message: fmt.Sprintf("Failed to create tenant %s: %s", orgName, res.Status), StatusCode: res.StatusCode}
+	}
+
+	tenant := &Tenant{}
+	err = json.NewDecoder(res.Body).Decode(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	return tenant, nil
+}
+
+func (tsc *threeScaleClient) GetTenant(tenantID int, masterAccessToken string) (*TenantAccount, error) {
+	res, err := tsc.httpc.Get(
+		fmt.Sprintf("https://master.%s/master/api/providers/%d.json?access_token=%s", tsc.wildCardDomain, tenantID, masterAccessToken),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, &tsError{message: fmt.Sprintf("Failed to get tenant %d: %s", tenantID, res.Status), StatusCode: res.StatusCode}
+	}
+
+	// The account is returned without the access token of the signup
+	signup := &Signup{}
+	err = json.NewDecoder(res.Body).Decode(signup)
+	if err != nil {
+		return nil, err
+	}
+
+	return &signup.Account, nil
+}
+
+// GetTenantByOrgName pages through the accounts of the master, which are the
+// tenants, for the one with the organization name
+func (tsc *threeScaleClient) GetTenantByOrgName(orgName, masterAccessToken string) (*TenantAccount, error) {
+	for page := 1; ; page++ {
+		res, err := tsc.httpc.Get(
+			fmt.Sprintf("https://master.%s/admin/api/accounts.json?access_token=%s&page=%d&per_page=%d", tsc.wildCardDomain, masterAccessToken, page, tenantAccountsPageSize),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode != http.StatusOK {
+			res.Body.Close()
+			return nil, &tsError{message: fmt.Sprintf("Failed to get tenants: %s", res.Status), StatusCode: res.StatusCode}
+		}
+
+		accounts := &TenantAccounts{}
+		err = json.NewDecoder(res.Body).Decode(accounts)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range accounts.Accounts {
+			if a.Account.OrgName == orgName {
+				return &a.Account, nil
+			}
+		}
+		if len(accounts.Accounts) < tenantAccountsPageSize {
+			return nil, &tsError{message: fmt.Sprintf("Tenant %s not found", orgName), StatusCode: http.StatusNotFound}
+		}
+	}
+}
+
+func (tsc *threeScaleClient) DeleteTenant(tenantID int, masterAccessToken string) (*http.Response, error) {
+	req, err := http.NewRequest(
+		http.MethodDelete,
+		fmt.Sprintf("https://master.%s/master/api/providers/%d.json?access_token=%s", tsc.wildCardDomain, tenantID, masterAccessToken),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	tsc.httpc.Timeout = time.Second * 10
+
+	return tsc.httpc.Do(req)
+}
+
+func (tsc *threeScaleClient) GetTenantUsers(tenantID int, masterAccessToken string) (*Users, error) {
+	res, err := tsc.httpc.Get(
+		fmt.Sprintf("https://master.%s/admin/api/accounts/%d/users.json?access_token=%s", tsc.wildCardDomain, tenantID, masterAccessToken),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, &tsError{message: fmt.Sprintf("Failed to get users of tenant %d: %s", tenantID, res.Status), StatusCode: res.StatusCode}
+	}
+
+	users := &Users{}
+	err = json.NewDecoder(res.Body).Decode(users)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (tsc *threeScaleClient) ActivateTenantUser(tenantID int, userID int, masterAccessToken string) (*http.Response, error) {
+	data, err := json.Marshal(map[string]string{
+		"access_token": masterAccessToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(
+		"PUT",
+		fmt.Sprintf("https://master.%s/admin/api/accounts/%d/users/%d/activate.json", tsc.wildCardDomain, tenantID, userID),
+		bytes.NewBuffer(data),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	tsc.httpc.Timeout = time.Second * 10
+
+	return tsc.httpc.Do(req)
+}
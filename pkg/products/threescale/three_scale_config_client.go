@@ -0,0 +1,273 @@
+package threescale
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// adminAPIRequest makes a request to the account management API of the admin
+// portal. The access token is sent as query parameter of GET requests, and in
+// the JSON body of the other requests. The response is decoded in out when
+// it's not nil, and an error is returned when its status isn't expectedStatus
+func (tsc *threeScaleClient) adminAPIRequest(method, path string, data map[string]string, accessToken string, expectedStatus int, out interface{}) (*http.Response, error) {
+	endpoint := fmt.Sprintf("https://%s%s", tsc.adminDomain, path)
+	var body *bytes.Buffer
+	if method == http.MethodGet {
+		endpoint = fmt.Sprintf("%s?access_token=%s", endpoint, url.QueryEscape(accessToken))
+		body = &bytes.Buffer{}
+	} else {
+		reqData := map[string]string{"access_token": accessToken}
+		for key, value := range data {
+			reqData[key] = value
+		}
+		encoded, err := json.Marshal(reqData)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewBuffer(encoded)
+	}
+
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	tsc.httpc.Timeout = time.Second * 10
+	res, err := tsc.httpc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != expectedStatus {
+		return res, &tsError{message: fmt.Sprintf("%s %s failed: %s", method, path, res.Status), StatusCode: res.StatusCode}
+	}
+	if out != nil {
+		if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+func (tsc *threeScaleClient) GetBackendApis(accessToken string) (*BackendApis, error) {
+	backends := &BackendApis{}
+	_, err := tsc.adminAPIRequest(http.MethodGet, "/admin/api/backend_apis.json", nil, accessToken, http.StatusOK, backends)
+	if err != nil {
+		return nil, err
+	}
+	return backends, nil
+}
+
+func (tsc *threeScaleClient) CreateBackendApi(data map[string]string, accessToken string) (*BackendApi, error) {
+	backend := &BackendApi{}
+	_, err := tsc.adminAPIRequest(http.MethodPost, "/admin/api/backend_apis.json", data, accessToken, http.StatusCreated, backend)
+	if err != nil {
+		return nil, err
+	}
+	return backend, nil
+}
+
+func (tsc *threeScaleClient) UpdateBackendApi(backendID int, data map[string]string, accessToken string) (*BackendApi, error) {
+	backend := &BackendApi{}
+	_, err := tsc.adminAPIRequest(http.MethodPut, fmt.Sprintf("/admin/api/backend_apis/%d.json", backendID), data, accessToken, http.StatusOK, backend)
+	if err != nil {
+		return nil, err
+	}
+	return backend, nil
+}
+
+func (tsc *threeScaleClient) DeleteBackendApi(backendID int, accessToken string) (*http.Response, error) {
+	return tsc.adminAPIRequest(http.MethodDelete, fmt.Sprintf("/admin/api/backend_apis/%d.json", backendID), nil, accessToken, http.StatusOK, nil)
+}
+
+func (tsc *threeScaleClient) GetBackendApiMetrics(backendID int, accessToken string) (*Metrics, error) {
+	metrics := &Metrics{}
+	_, err := tsc.adminAPIRequest(http.MethodGet, fmt.Sprintf("/admin/api/backend_apis/%d/metrics.json", backendID), nil, accessToken, http.StatusOK, metrics)
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+func (tsc *threeScaleClient) GetBackendApiMappingRules(backendID int, accessToken string) (*MappingRules, error) {
+	rules := &MappingRules{}
+	_, err := tsc.adminAPIRequest(http.MethodGet, fmt.Sprintf("/admin/api/backend_apis/%d/mapping_rules.json", backendID), nil, accessToken, http.StatusOK, rules)
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (tsc *threeScaleClient) CreateBackendApiMappingRule(backendID int, data map[string]string, accessToken string) (*MappingRule, error) {
+	rule := &MappingRule{}
+	_, err := tsc.adminAPIRequest(http.MethodPost, fmt.Sprintf("/admin/api/backend_apis/%d/mapping_rules.json", backendID), data, accessToken, http.StatusCreated, rule)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (tsc *threeScaleClient) DeleteBackendApiMappingRule(backendID int, ruleID int, accessToken string) (*http.Response, error) {
+	return tsc.adminAPIRequest(http.MethodDelete, fmt.Sprintf("/admin/api/backend_apis/%d/mapping_rules/%d.json", backendID, ruleID), nil, accessToken, http.StatusOK, nil)
+}
+
+func (tsc *threeScaleClient) GetServices(accessToken string) (*Services, error) {
+	services := &Services{}
+	_, err := tsc.adminAPIRequest(http.MethodGet, "/admin/api/services.json", nil, accessToken, http.StatusOK, services)
+	if err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+func (tsc *threeScaleClient) CreateService(data map[string]string, accessToken string) (*Service, error) {
+	service := &Service{}
+	_, err := tsc.adminAPIRequest(http.MethodPost, "/admin/api/services.json", data, accessToken, http.StatusCreated, service)
+	if err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+func (tsc *threeScaleClient) UpdateService(serviceID int, data map[string]string, accessToken string) (*Service, error) {
+	service := &Service{}
+	_, err := tsc.adminAPIRequest(http.MethodPut, fmt.Sprintf("/admin/api/services/%d.json", serviceID), data, accessToken, http.StatusOK, service)
+	if err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+func (tsc *threeScaleClient) DeleteService(serviceID int, accessToken string) (*http.Response, error) {
+	return tsc.adminAPIRequest(http.MethodDelete, fmt.Sprintf("/admin/api/services/%d.json", serviceID), nil, accessToken, http.StatusOK, nil)
+}
+
+func (tsc *threeScaleClient) GetServiceMetrics(serviceID int, accessToken string) (*Metrics, error) {
+	metrics := &Metrics{}
+	_, err := tsc.adminAPIRequest(http.MethodGet, fmt.Sprintf("/admin/api/services/%d/metrics.json", serviceID), nil, accessToken, http.StatusOK, metrics)
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+func (tsc *threeScaleClient) GetServiceMappingRules(serviceID int, accessToken string) (*MappingRules, error) {
+	rules := &MappingRules{}
+	_, err := tsc.adminAPIRequest(http.MethodGet, fmt.Sprintf("/admin/api/services/%d/proxy/mapping_rules.json", serviceID), nil, accessToken, http.StatusOK, rules)
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (tsc *threeScaleClient) CreateServiceMappingRule(serviceID int, data map[string]string, accessToken string) (*MappingRule, error) {
+	rule := &MappingRule{}
+	_, err := tsc.adminAPIRequest(http.MethodPost, fmt.Sprintf("/admin/api/services/%d/proxy/mapping_rules.json", serviceID), data, accessToken, http.StatusCreated, rule)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (tsc *threeScaleClient) DeleteServiceMappingRule(serviceID int, ruleID int, accessToken string) (*http.Response, error) {
+	return tsc.adminAPIRequest(http.MethodDelete, fmt.Sprintf("/admin/api/services/%d/proxy/mapping_rules/%d.json", serviceID, ruleID), nil, accessToken, http.StatusOK, nil)
+}
+
+func (tsc *threeScaleClient) GetBackendUsages(serviceID int, accessToken string) ([]*BackendUsage, error) {
+	var usages []*BackendUsage
+	_, err := tsc.adminAPIRequest(http.MethodGet, fmt.Sprintf("/admin/api/services/%d/backend_usages.json", serviceID), nil, accessToken, http.StatusOK, &usages)
+	if err != nil {
+		return nil, err
+	}
+	return usages, nil
+}
+
+func (tsc *threeScaleClient) CreateBackendUsage(serviceID int, data map[string]string, accessToken string) (*BackendUsage, error) {
+	usage := &BackendUsage{}
+	_, err := tsc.adminAPIRequest(http.MethodPost, fmt.Sprintf("/admin/api/services/%d/backend_usages.json", serviceID), data, accessToken, http.StatusCreated, usage)
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+func (tsc *threeScaleClient) DeleteBackendUsage(serviceID int, usageID int, accessToken string) (*http.Response, error) {
+	return tsc.adminAPIRequest(http.MethodDelete, fmt.Sprintf("/admin/api/services/%d/backend_usages/%d.json", serviceID, usageID), nil, accessToken, http.StatusOK, nil)
+}
+
+func (tsc *threeScaleClient) GetApplicationPlans(serviceID int, accessToken string) (*ApplicationPlans, error) {
+	plans := &ApplicationPlans{}
+	_, err := tsc.adminAPIRequest(http.MethodGet, fmt.Sprintf("/admin/api/services/%d/application_plans.json", serviceID), nil, accessToken, http.StatusOK, plans)
+	if err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+func (tsc *threeScaleClient) CreateApplicationPlan(serviceID int, data map[string]string, accessToken string) (*ApplicationPlan, error) {
+	plan := &ApplicationPlan{}
+	_, err := tsc.adminAPIRequest(http.MethodPost, fmt.Sprintf("/admin/api/services/%d/application_plans.json", serviceID), data, accessToken, http.StatusCreated, plan)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (tsc *threeScaleClient) UpdateApplicationPlan(serviceID int, planID int, data map[string]string, accessToken string) (*ApplicationPlan, error) {
+	plan := &ApplicationPlan{}
+	_, err := tsc.adminAPIRequest(http.MethodPut, fmt.Sprintf("/admin/api/services/%d/application_plans/%d.json", serviceID, planID), data, accessToken, http.StatusOK, plan)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (tsc *threeScaleClient) DeleteApplicationPlan(serviceID int, planID int, accessToken string) (*http.Response, error) {
+	return tsc.adminAPIRequest(http.MethodDelete, fmt.Sprintf("/admin/api/services/%d/application_plans/%d.json", serviceID, planID), nil, accessToken, http.StatusOK, nil)
+}
+
+func (tsc *threeScaleClient) GetPolicies(serviceID int, accessToken string) (*PoliciesConfig, error) {
+	policies := &PoliciesConfig{}
+	_, err := tsc.adminAPIRequest(http.MethodGet, fmt.Sprintf("/admin/api/services/%d/proxy/policies.json", serviceID), nil, accessToken, http.StatusOK, policies)
+	if err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (tsc *threeScaleClient) UpdatePolicies(serviceID int, policies []PolicyConfig, accessToken string) (*http.Response, error) {
+	policiesConfig, err := json.Marshal(policies)
+	if err != nil {
+		return nil, err
+	}
+	return tsc.adminAPIRequest(http.MethodPut, fmt.Sprintf("/admin/api/services/%d/proxy/policies.json", serviceID), map[string]string{
+		"policies_config": string(policiesConfig),
+	}, accessToken, http.StatusOK, nil)
+}
+
+// DeployProxy deploys the latest configuration of the service to the staging
+// APIcast
+func (tsc *threeScaleClient) DeployProxy(serviceID int, accessToken string) (*http.Response, error) {
+	return tsc.adminAPIRequest(http.MethodPost, fmt.Sprintf("/admin/api/services/%d/proxy/deploy.json", serviceID), nil, accessToken, http.StatusCreated, nil)
+}
+
+func (tsc *threeScaleClient) GetLatestProxyConfig(serviceID int, environment string, accessToken string) (*ProxyConfig, error) {
+	config := &ProxyConfigElement{}
+	_, err := tsc.adminAPIRequest(http.MethodGet, fmt.Sprintf("/admin/api/services/%d/proxy/configs/%s/latest.json", serviceID, environment), nil, accessToken, http.StatusOK, config)
+	if err != nil {
+		return nil, err
+	}
+	return &config.ProxyConfig, nil
+}
+
+func (tsc *threeScaleClient) PromoteProxyConfig(serviceID int, environment string, version int, toEnvironment string, accessToken string) (*http.Response, error) {
+	return tsc.adminAPIRequest(http.MethodPost, fmt.Sprintf("/admin/api/services/%d/proxy/configs/%s/%d/promote.json", serviceID, environment, version), map[string]string{
+		"to": toEnvironment,
+	}, accessToken, http.StatusCreated, nil)
+}
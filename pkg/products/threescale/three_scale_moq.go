@@ -18,27 +18,117 @@ var _ ThreeScaleInterface = &ThreeScaleInterfaceMock{}
 //
 //         // make and configure a mocked ThreeScaleInterface
 //         mockedThreeScaleInterface := &ThreeScaleInterfaceMock{
+//             ActivateTenantUserFunc: func(tenantID int, userID int, masterAccessToken string) (*http.Response, error) {
+// 	               panic("mock out the ActivateTenantUser method")
+//             },
 //             AddAuthenticationProviderFunc: func(data map[string]string, accessToken string) (*http.Response, error) {
 // 	               panic("mock out the AddAuthenticationProvider method")
 //             },
 //             AddUserFunc: func(username string, email string, password string, accessToken string) (*http.Response, error) {
 // 	               panic("mock out the AddUser method")
 //             },
+//             CreateApplicationPlanFunc: func(serviceID int, data map[string]string, accessToken string) (*ApplicationPlan, error) {
+// 	               panic("mock out the CreateApplicationPlan method")
+//             },
+//             CreateBackendApiFunc: func(data map[string]string, accessToken string) (*BackendApi, error) {
+// 	               panic("mock out the CreateBackendApi method")
+//             },
+//             CreateBackendApiMappingRuleFunc: func(backendID int, data map[string]string, accessToken string) (*MappingRule, error) {
+// 	               panic("mock out the CreateBackendApiMappingRule method")
+//             },
+//             CreateBackendUsageFunc: func(serviceID int, data map[string]string, accessToken string) (*BackendUsage, error) {
+// 	               panic("mock out the CreateBackendUsage method")
+//             },
+//             CreateServiceFunc: func(data map[string]string, accessToken string) (*Service, error) {
+// 	               panic("mock out the CreateService method")
+//             },
+//             CreateServiceMappingRuleFunc: func(serviceID int, data map[string]string, accessToken string) (*MappingRule, error) {
+// 	               panic("mock out the CreateServiceMappingRule method")
+//             },
+//             CreateTenantFunc: func(orgName string, username string, email string, password string, masterAccessToken string) (*Tenant, error) {
+// 	               panic("mock out the CreateTenant method")
+//             },
+//             DeleteApplicationPlanFunc: func(serviceID int, planID int, accessToken string) (*http.Response, error) {
+// 	               panic("mock out the DeleteApplicationPlan method")
+//             },
+//             DeleteBackendApiFunc: func(backendID int, accessToken string) (*http.Response, error) {
+// 	               panic("mock out the DeleteBackendApi method")
+//             },
+//             DeleteBackendApiMappingRuleFunc: func(backendID int, ruleID int, accessToken string) (*http.Response, error) {
+// 	               panic("mock out the DeleteBackendApiMappingRule method")
+//             },
+//             DeleteBackendUsageFunc: func(serviceID int, usageID int, accessToken string) (*http.Response, error) {
+// 	               panic("mock out the DeleteBackendUsage method")
+//             },
+//             DeleteServiceFunc: func(serviceID int, accessToken string) (*http.Response, error) {
+// 	               panic("mock out the DeleteService method")
+//             },
+//             DeleteServiceMappingRuleFunc: func(serviceID int, ruleID int, accessToken string) (*http.Response, error) {
+// 	               panic("mock out the DeleteServiceMappingRule method")
+//             },
+//             DeleteTenantFunc: func(tenantID int, masterAccessToken string) (*http.Response, error) {
+// 	               panic("mock out the DeleteTenant method")
+//             },
 //             DeleteUserFunc: func(userID int, accessToken string) (*http.Response, error) {
 // 	               panic("mock out the DeleteUser method")
 //             },
+//             DeployProxyFunc: func(serviceID int, accessToken string) (*http.Response, error) {
+// 	               panic("mock out the DeployProxy method")
+//             },
+//             GetApplicationPlansFunc: func(serviceID int, accessToken string) (*ApplicationPlans, error) {
+// 	               panic("mock out the GetApplicationPlans method")
+//             },
 //             GetAuthenticationProviderByNameFunc: func(name string, accessToken string) (*AuthProvider, error) {
 // 	               panic("mock out the GetAuthenticationProviderByName method")
 //             },
 //             GetAuthenticationProvidersFunc: func(accessToken string) (*AuthProviders, error) {
 // 	               panic("mock out the GetAuthenticationProviders method")
 //             },
+//             GetBackendApiMappingRulesFunc: func(backendID int, accessToken string) (*MappingRules, error) {
+// 	               panic("mock out the GetBackendApiMappingRules method")
+//             },
+//             GetBackendApiMetricsFunc: func(backendID int, accessToken string) (*Metrics, error) {
+// 	               panic("mock out the GetBackendApiMetrics method")
+//             },
+//             GetBackendApisFunc: func(accessToken string) (*BackendApis, error) {
+// 	               panic("mock out the GetBackendApis method")
+//             },
+//             GetBackendUsagesFunc: func(serviceID int, accessToken string) ([]*BackendUsage, error) {
+// 	               panic("mock out the GetBackendUsages method")
+//             },
+//             GetLatestProxyConfigFunc: func(serviceID int, environment string, accessToken string) (*ProxyConfig, error) {
+// 	               panic("mock out the GetLatestProxyConfig method")
+//             },
+//             GetPoliciesFunc: func(serviceID int, accessToken string) (*PoliciesConfig, error) {
+// 	               panic("mock out the GetPolicies method")
+//             },
+//             GetServiceMappingRulesFunc: func(serviceID int, accessToken string) (*MappingRules, error) {
+// 	               panic("mock out the GetServiceMappingRules method")
+//             },
+//             GetServiceMetricsFunc: func(serviceID int, accessToken string) (*Metrics, error) {
+// 	               panic("mock out the GetServiceMetrics method")
+//             },
+//             GetServicesFunc: func(accessToken string) (*Services, error) {
+// 	               panic("mock out the GetServices method")
+//             },
+//             GetTenantFunc: func(tenantID int, masterAccessToken string) (*TenantAccount, error) {
+// 	               panic("mock out the GetTenant method")
+//             },
+//             GetTenantByOrgNameFunc: func(orgName string, masterAccessToken string) (*TenantAccount, error) {
+// 	               panic("mock out the GetTenantByOrgName method")
+//             },
+//             GetTenantUsersFunc: func(tenantID int, masterAccessToken string) (*Users, error) {
+// 	               panic("mock out the GetTenantUsers method")
+//             },
 //             GetUserFunc: func(username string, accessToken string) (*User, error) {
 // 	               panic("mock out the GetUser method")
 //             },
 //             GetUsersFunc: func(accessToken string) (*Users, error) {
 // 	               panic("mock out the GetUsers method")
 //             },
+//             PromoteProxyConfigFunc: func(serviceID int, environment string, version int, toEnvironment string, accessToken string) (*http.Response, error) {
+// 	               panic("mock out the PromoteProxyConfig method")
+//             },
 //             SetNamespaceFunc: func(ns string)  {
 // 	               panic("mock out the SetNamespace method")
 //             },
@@ -48,6 +138,18 @@ var _ ThreeScaleInterface = &ThreeScaleInterfaceMock{}
 //             SetUserAsMemberFunc: func(userID int, accessToken string) (*http.Response, error) {
 // 	               panic("mock out the SetUserAsMember method")
 //             },
+//             UpdateApplicationPlanFunc: func(serviceID int, planID int, data map[string]string, accessToken string) (*ApplicationPlan, error) {
+// 	               panic("mock out the UpdateApplicationPlan method")
+//             },
+//             UpdateBackendApiFunc: func(backendID int, data map[string]string, accessToken string) (*BackendApi, error) {
+// 	               panic("mock out the UpdateBackendApi method")
+//             },
+//             UpdatePoliciesFunc: func(serviceID int, policies []PolicyConfig, accessToken string) (*http.Response, error) {
+// 	               panic("mock out the UpdatePolicies method")
+//             },
+//             UpdateServiceFunc: func(serviceID int, data map[string]string, accessToken string) (*Service, error) {
+// 	               panic("mock out the UpdateService method")
+//             },
 //             UpdateUserFunc: func(userID int, username string, email string, accessToken string) (*http.Response, error) {
 // 	               panic("mock out the UpdateUser method")
 //             },
@@ -58,27 +160,117 @@ var _ ThreeScaleInterface = &ThreeScaleInterfaceMock{}
 //
 //     }
 type ThreeScaleInterfaceMock struct {
+	// ActivateTenantUserFunc mocks the ActivateTenantUser method.
+	ActivateTenantUserFunc func(tenantID int, userID int, masterAccessToken string) (*http.Response, error)
+
 	// AddAuthenticationProviderFunc mocks the AddAuthenticationProvider method.
 	AddAuthenticationProviderFunc func(data map[string]string, accessToken string) (*http.Response, error)
 
 	// AddUserFunc mocks the AddUser method.
 	AddUserFunc func(username string, email string, password string, accessToken string) (*http.Response, error)
 
+	// CreateApplicationPlanFunc mocks the CreateApplicationPlan method.
+	CreateApplicationPlanFunc func(serviceID int, data map[string]string, accessToken string) (*ApplicationPlan, error)
+
+	// CreateBackendApiFunc mocks the CreateBackendApi method.
+	CreateBackendApiFunc func(data map[string]string, accessToken string) (*BackendApi, error)
+
+	// CreateBackendApiMappingRuleFunc mocks the CreateBackendApiMappingRule method.
+	CreateBackendApiMappingRuleFunc func(backendID int, data map[string]string, accessToken string) (*MappingRule, error)
+
+	// CreateBackendUsageFunc mocks the CreateBackendUsage method.
+	CreateBackendUsageFunc func(serviceID int, data map[string]string, accessToken string) (*BackendUsage, error)
+
+	// CreateServiceFunc mocks the CreateService method.
+	CreateServiceFunc func(data map[string]string, accessToken string) (*Service, error)
+
+	// CreateServiceMappingRuleFunc mocks the CreateServiceMappingRule method.
+	CreateServiceMappingRuleFunc func(serviceID int, data map[string]string, accessToken string) (*MappingRule, error)
+
+	// CreateTenantFunc mocks the CreateTenant method.
+	CreateTenantFunc func(orgName string, username string, email string, password string, masterAccessToken string) (*Tenant, error)
+
+	// DeleteApplicationPlanFunc mocks the DeleteApplicationPlan method.
+	DeleteApplicationPlanFunc func(serviceID int, planID int, accessToken string) (*http.Response, error)
+
+	// DeleteBackendApiFunc mocks the DeleteBackendApi method.
+	DeleteBackendApiFunc func(backendID int, accessToken string) (*http.Response, error)
+
+	// DeleteBackendApiMappingRuleFunc mocks the DeleteBackendApiMappingRule method.
+	DeleteBackendApiMappingRuleFunc func(backendID int, ruleID int, accessToken string) (*http.Response, error)
+
+	// DeleteBackendUsageFunc mocks the DeleteBackendUsage method.
+	DeleteBackendUsageFunc func(serviceID int, usageID int, accessToken string) (*http.Response, error)
+
+	// DeleteServiceFunc mocks the DeleteService method.
+	DeleteServiceFunc func(serviceID int, accessToken string) (*http.Response, error)
+
+	// DeleteServiceMappingRuleFunc mocks the DeleteServiceMappingRule method.
+	DeleteServiceMappingRuleFunc func(serviceID int, ruleID int, accessToken string) (*http.Response, error)
+
+	// DeleteTenantFunc mocks the DeleteTenant method.
+	DeleteTenantFunc func(tenantID int, masterAccessToken string) (*http.Response, error)
+
 	// DeleteUserFunc mocks the DeleteUser method.
 	DeleteUserFunc func(userID int, accessToken string) (*http.Response, error)
 
+	// DeployProxyFunc mocks the DeployProxy method.
+	DeployProxyFunc func(serviceID int, accessToken string) (*http.Response, error)
+
+	// GetApplicationPlansFunc mocks the GetApplicationPlans method.
+	GetApplicationPlansFunc func(serviceID int, accessToken string) (*ApplicationPlans, error)
+
 	// GetAuthenticationProviderByNameFunc mocks the GetAuthenticationProviderByName method.
 	GetAuthenticationProviderByNameFunc func(name string, accessToken string) (*AuthProvider, error)
 
 	// GetAuthenticationProvidersFunc mocks the GetAuthenticationProviders method.
 	GetAuthenticationProvidersFunc func(accessToken string) (*AuthProviders, error)
 
+	// GetBackendApiMappingRulesFunc mocks the GetBackendApiMappingRules method.
+	GetBackendApiMappingRulesFunc func(backendID int, accessToken string) (*MappingRules, error)
+
+	// GetBackendApiMetricsFunc mocks the GetBackendApiMetrics method.
+	GetBackendApiMetricsFunc func(backendID int, accessToken string) (*Metrics, error)
+
+	// GetBackendApisFunc mocks the GetBackendApis method.
+	GetBackendApisFunc func(accessToken string) (*BackendApis, error)
+
+	// GetBackendUsagesFunc mocks the GetBackendUsages method.
+	GetBackendUsagesFunc func(serviceID int, accessToken string) ([]*BackendUsage, error)
+
+	// GetLatestProxyConfigFunc mocks the GetLatestProxyConfig method.
+	GetLatestProxyConfigFunc func(serviceID int, environment string, accessToken string) (*ProxyConfig, error)
+
+	// GetPoliciesFunc mocks the GetPolicies method.
+	GetPoliciesFunc func(serviceID int, accessToken string) (*PoliciesConfig, error)
+
+	// GetServiceMappingRulesFunc mocks the GetServiceMappingRules method.
+	GetServiceMappingRulesFunc func(serviceID int, accessToken string) (*MappingRules, error)
+
+	// GetServiceMetricsFunc mocks the GetServiceMetrics method.
+	GetServiceMetricsFunc func(serviceID int, accessToken string) (*Metrics, error)
+
+	// GetServicesFunc mocks the GetServices method.
+	GetServicesFunc func(accessToken string) (*Services, error)
+
+	// GetTenantFunc mocks the GetTenant method.
+	GetTenantFunc func(tenantID int, masterAccessToken string) (*TenantAccount, error)
+
+	// GetTenantByOrgNameFunc mocks the GetTenantByOrgName method.
+	GetTenantByOrgNameFunc func(orgName string, masterAccessToken string) (*TenantAccount, error)
+
+	// GetTenantUsersFunc mocks the GetTenantUsers method.
+	GetTenantUsersFunc func(tenantID int, masterAccessToken string) (*Users, error)
+
 	// GetUserFunc mocks the GetUser method.
 	GetUserFunc func(username string, accessToken string) (*User, error)
 
 	// GetUsersFunc mocks the GetUsers method.
 	GetUsersFunc func(accessToken string) (*Users, error)
 
+	// PromoteProxyConfigFunc mocks the PromoteProxyConfig method.
+	PromoteProxyConfigFunc func(serviceID int, environment string, version int, toEnvironment string, accessToken string) (*http.Response, error)
+
 	// SetNamespaceFunc mocks the SetNamespace method.
 	SetNamespaceFunc func(ns string)
 
@@ -88,11 +280,32 @@ type ThreeScaleInterfaceMock struct {
 	// SetUserAsMemberFunc mocks the SetUserAsMember method.
 	SetUserAsMemberFunc func(userID int, accessToken string) (*http.Response, error)
 
+	// UpdateApplicationPlanFunc mocks the UpdateApplicationPlan method.
+	UpdateApplicationPlanFunc func(serviceID int, planID int, data map[string]string, accessToken string) (*ApplicationPlan, error)
+
+	// UpdateBackendApiFunc mocks the UpdateBackendApi method.
+	UpdateBackendApiFunc func(backendID int, data map[string]string, accessToken string) (*BackendApi, error)
+
+	// UpdatePoliciesFunc mocks the UpdatePolicies method.
+	UpdatePoliciesFunc func(serviceID int, policies []PolicyConfig, accessToken string) (*http.Response, error)
+
+	// UpdateServiceFunc mocks the UpdateService method.
+	UpdateServiceFunc func(serviceID int, data map[string]string, accessToken string) (*Service, error)
+
 	// UpdateUserFunc mocks the UpdateUser method.
 	UpdateUserFunc func(userID int, username string, email string, accessToken string) (*http.Response, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// ActivateTenantUser holds details about calls to the ActivateTenantUser method.
+		ActivateTenantUser []struct {
+			// TenantID is the tenantID argument value.
+			TenantID int
+			// UserID is the userID argument value.
+			UserID int
+			// MasterAccessToken is the masterAccessToken argument value.
+			MasterAccessToken string
+		}
 		// AddAuthenticationProvider holds details about calls to the AddAuthenticationProvider method.
 		AddAuthenticationProvider []struct {
 			// Data is the data argument value.
@@ -111,6 +324,126 @@ type ThreeScaleInterfaceMock struct {
 			// AccessToken is the accessToken argument value.
 			AccessToken string
 		}
+		// CreateApplicationPlan holds details about calls to the CreateApplicationPlan method.
+		CreateApplicationPlan []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// Data is the data argument value.
+			Data map[string]string
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// CreateBackendApi holds details about calls to the CreateBackendApi method.
+		CreateBackendApi []struct {
+			// Data is the data argument value.
+			Data map[string]string
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// CreateBackendApiMappingRule holds details about calls to the CreateBackendApiMappingRule method.
+		CreateBackendApiMappingRule []struct {
+			// BackendID is the backendID argument value.
+			BackendID int
+			// Data is the data argument value.
+			Data map[string]string
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// CreateBackendUsage holds details about calls to the CreateBackendUsage method.
+		CreateBackendUsage []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// Data is the data argument value.
+			Data map[string]string
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// CreateService holds details about calls to the CreateService method.
+		CreateService []struct {
+			// Data is the data argument value.
+			Data map[string]string
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// CreateServiceMappingRule holds details about calls to the CreateServiceMappingRule method.
+		CreateServiceMappingRule []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// Data is the data argument value.
+			Data map[string]string
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// CreateTenant holds details about calls to the CreateTenant method.
+		CreateTenant []struct {
+			// OrgName is the orgName argument value.
+			OrgName string
+			// Username is the username argument value.
+			Username string
+			// Email is the email argument value.
+			Email string
+			// Password is the password argument value.
+			Password string
+			// MasterAccessToken is the masterAccessToken argument value.
+			MasterAccessToken string
+		}
+		// DeleteApplicationPlan holds details about calls to the DeleteApplicationPlan method.
+		DeleteApplicationPlan []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// PlanID is the planID argument value.
+			PlanID int
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// DeleteBackendApi holds details about calls to the DeleteBackendApi method.
+		DeleteBackendApi []struct {
+			// BackendID is the backendID argument value.
+			BackendID int
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// DeleteBackendApiMappingRule holds details about calls to the DeleteBackendApiMappingRule method.
+		DeleteBackendApiMappingRule []struct {
+			// BackendID is the backendID argument value.
+			BackendID int
+			// RuleID is the ruleID argument value.
+			RuleID int
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// DeleteBackendUsage holds details about calls to the DeleteBackendUsage method.
+		DeleteBackendUsage []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// UsageID is the usageID argument value.
+			UsageID int
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// DeleteService holds details about calls to the DeleteService method.
+		DeleteService []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// DeleteServiceMappingRule holds details about calls to the DeleteServiceMappingRule method.
+		DeleteServiceMappingRule []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// RuleID is the ruleID argument value.
+			RuleID int
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// DeleteTenant holds details about calls to the DeleteTenant method.
+		DeleteTenant []struct {
+			// TenantID is the tenantID argument value.
+			TenantID int
+			// MasterAccessToken is the masterAccessToken argument value.
+			MasterAccessToken string
+		}
 		// DeleteUser holds details about calls to the DeleteUser method.
 		DeleteUser []struct {
 			// UserID is the userID argument value.
@@ -118,6 +451,20 @@ type ThreeScaleInterfaceMock struct {
 			// AccessToken is the accessToken argument value.
 			AccessToken string
 		}
+		// DeployProxy holds details about calls to the DeployProxy method.
+		DeployProxy []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// GetApplicationPlans holds details about calls to the GetApplicationPlans method.
+		GetApplicationPlans []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
 		// GetAuthenticationProviderByName holds details about calls to the GetAuthenticationProviderByName method.
 		GetAuthenticationProviderByName []struct {
 			// Name is the name argument value.
@@ -130,6 +477,88 @@ type ThreeScaleInterfaceMock struct {
 			// AccessToken is the accessToken argument value.
 			AccessToken string
 		}
+		// GetBackendApiMappingRules holds details about calls to the GetBackendApiMappingRules method.
+		GetBackendApiMappingRules []struct {
+			// BackendID is the backendID argument value.
+			BackendID int
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// GetBackendApiMetrics holds details about calls to the GetBackendApiMetrics method.
+		GetBackendApiMetrics []struct {
+			// BackendID is the backendID argument value.
+			BackendID int
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// GetBackendApis holds details about calls to the GetBackendApis method.
+		GetBackendApis []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// GetBackendUsages holds details about calls to the GetBackendUsages method.
+		GetBackendUsages []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// GetLatestProxyConfig holds details about calls to the GetLatestProxyConfig method.
+		GetLatestProxyConfig []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// Environment is the environment argument value.
+			Environment string
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// GetPolicies holds details about calls to the GetPolicies method.
+		GetPolicies []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// GetServiceMappingRules holds details about calls to the GetServiceMappingRules method.
+		GetServiceMappingRules []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// GetServiceMetrics holds details about calls to the GetServiceMetrics method.
+		GetServiceMetrics []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// GetServices holds details about calls to the GetServices method.
+		GetServices []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// GetTenant holds details about calls to the GetTenant method.
+		GetTenant []struct {
+			// TenantID is the tenantID argument value.
+			TenantID int
+			// MasterAccessToken is the masterAccessToken argument value.
+			MasterAccessToken string
+		}
+		// GetTenantByOrgName holds details about calls to the GetTenantByOrgName method.
+		GetTenantByOrgName []struct {
+			// OrgName is the orgName argument value.
+			OrgName string
+			// MasterAccessToken is the masterAccessToken argument value.
+			MasterAccessToken string
+		}
+		// GetTenantUsers holds details about calls to the GetTenantUsers method.
+		GetTenantUsers []struct {
+			// TenantID is the tenantID argument value.
+			TenantID int
+			// MasterAccessToken is the masterAccessToken argument value.
+			MasterAccessToken string
+		}
 		// GetUser holds details about calls to the GetUser method.
 		GetUser []struct {
 			// Username is the username argument value.
@@ -142,6 +571,19 @@ type ThreeScaleInterfaceMock struct {
 			// AccessToken is the accessToken argument value.
 			AccessToken string
 		}
+		// PromoteProxyConfig holds details about calls to the PromoteProxyConfig method.
+		PromoteProxyConfig []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// Environment is the environment argument value.
+			Environment string
+			// Version is the version argument value.
+			Version int
+			// ToEnvironment is the toEnvironment argument value.
+			ToEnvironment string
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
 		// SetNamespace holds details about calls to the SetNamespace method.
 		SetNamespace []struct {
 			// Ns is the ns argument value.
@@ -161,6 +603,44 @@ type ThreeScaleInterfaceMock struct {
 			// AccessToken is the accessToken argument value.
 			AccessToken string
 		}
+		// UpdateApplicationPlan holds details about calls to the UpdateApplicationPlan method.
+		UpdateApplicationPlan []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// PlanID is the planID argument value.
+			PlanID int
+			// Data is the data argument value.
+			Data map[string]string
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// UpdateBackendApi holds details about calls to the UpdateBackendApi method.
+		UpdateBackendApi []struct {
+			// BackendID is the backendID argument value.
+			BackendID int
+			// Data is the data argument value.
+			Data map[string]string
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// UpdatePolicies holds details about calls to the UpdatePolicies method.
+		UpdatePolicies []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// Policies is the policies argument value.
+			Policies []PolicyConfig
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
+		// UpdateService holds details about calls to the UpdateService method.
+		UpdateService []struct {
+			// ServiceID is the serviceID argument value.
+			ServiceID int
+			// Data is the data argument value.
+			Data map[string]string
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+		}
 		// UpdateUser holds details about calls to the UpdateUser method.
 		UpdateUser []struct {
 			// UserID is the userID argument value.
@@ -173,19 +653,93 @@ type ThreeScaleInterfaceMock struct {
 			AccessToken string
 		}
 	}
+	lockActivateTenantUser              sync.RWMutex
 	lockAddAuthenticationProvider       sync.RWMutex
 	lockAddUser                         sync.RWMutex
+	lockCreateApplicationPlan           sync.RWMutex
+	lockCreateBackendApi                sync.RWMutex
+	lockCreateBackendApiMappingRule     sync.RWMutex
+	lockCreateBackendUsage              sync.RWMutex
+	lockCreateService                   sync.RWMutex
+	lockCreateServiceMappingRule        sync.RWMutex
+	lockCreateTenant                    sync.RWMutex
+	lockDeleteApplicationPlan           sync.RWMutex
+	lockDeleteBackendApi                sync.RWMutex
+	lockDeleteBackendApiMappingRule     sync.RWMutex
+	lockDeleteBackendUsage              sync.RWMutex
+	lockDeleteService                   sync.RWMutex
+	lockDeleteServiceMappingRule        sync.RWMutex
+	lockDeleteTenant                    sync.RWMutex
 	lockDeleteUser                      sync.RWMutex
+	lockDeployProxy                     sync.RWMutex
+	lockGetApplicationPlans             sync.RWMutex
 	lockGetAuthenticationProviderByName sync.RWMutex
 	lockGetAuthenticationProviders      sync.RWMutex
+	lockGetBackendApiMappingRules       sync.RWMutex
+	lockGetBackendApiMetrics            sync.RWMutex
+	lockGetBackendApis                  sync.RWMutex
+	lockGetBackendUsages                sync.RWMutex
+	lockGetLatestProxyConfig            sync.RWMutex
+	lockGetPolicies                     sync.RWMutex
+	lockGetServiceMappingRules          sync.RWMutex
+	lockGetServiceMetrics               sync.RWMutex
+	lockGetServices                     sync.RWMutex
+	lockGetTenant                       sync.RWMutex
+	lockGetTenantByOrgName              sync.RWMutex
+	lockGetTenantUsers                  sync.RWMutex
 	lockGetUser                         sync.RWMutex
 	lockGetUsers                        sync.RWMutex
+	lockPromoteProxyConfig              sync.RWMutex
 	lockSetNamespace                    sync.RWMutex
 	lockSetUserAsAdmin                  sync.RWMutex
 	lockSetUserAsMember                 sync.RWMutex
+	lockUpdateApplicationPlan           sync.RWMutex
+	lockUpdateBackendApi                sync.RWMutex
+	lockUpdatePolicies                  sync.RWMutex
+	lockUpdateService                   sync.RWMutex
 	lockUpdateUser                      sync.RWMutex
 }
 
+// ActivateTenantUser calls ActivateTenantUserFunc.
+func (mock *ThreeScaleInterfaceMock) ActivateTenantUser(tenantID int, userID int, masterAccessToken string) (*http.Response, error) {
+	if mock.ActivateTenantUserFunc == nil {
+		panic("ThreeScaleInterfaceMock.ActivateTenantUserFunc: method is nil but ThreeScaleInterface.ActivateTenantUser was just called")
+	}
+	callInfo := struct {
+		TenantID          int
+		UserID            int
+		MasterAccessToken string
+	}{
+		TenantID:          tenantID,
+		UserID:            userID,
+		MasterAccessToken: masterAccessToken,
+	}
+	mock.lockActivateTenantUser.Lock()
+	mock.calls.ActivateTenantUser = append(mock.calls.ActivateTenantUser, callInfo)
+	mock.lockActivateTenantUser.Unlock()
+	return mock.ActivateTenantUserFunc(tenantID, userID, masterAccessToken)
+}
+
+// ActivateTenantUserCalls gets all the calls that were made to ActivateTenantUser.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.ActivateTenantUserCalls())
+func (mock *ThreeScaleInterfaceMock) ActivateTenantUserCalls() []struct {
+	TenantID          int
+	UserID            int
+	MasterAccessToken string
+} {
+	var calls []struct {
+		TenantID          int
+		UserID            int
+		MasterAccessToken string
+	}
+	mock.lockActivateTenantUser.RLock()
+	calls = mock.calls.ActivateTenantUser
+	mock.lockActivateTenantUser.RUnlock()
+	return calls
+}
+
 // AddAuthenticationProvider calls AddAuthenticationProviderFunc.
 func (mock *ThreeScaleInterfaceMock) AddAuthenticationProvider(data map[string]string, accessToken string) (*http.Response, error) {
 	if mock.AddAuthenticationProviderFunc == nil {
@@ -206,6 +760,7 @@ func (mock *ThreeScaleInterfaceMock) AddAuthenticationProvider(data map[string]s
 
 // AddAuthenticationProviderCalls gets all the calls that were made to AddAuthenticationProvider.
 // Check the length with:
+//
 //     len(mockedThreeScaleInterface.AddAuthenticationProviderCalls())
 func (mock *ThreeScaleInterfaceMock) AddAuthenticationProviderCalls() []struct {
 	Data        map[string]string
@@ -245,6 +800,7 @@ func (mock *ThreeScaleInterfaceMock) AddUser(username string, email string, pass
 
 // AddUserCalls gets all the calls that were made to AddUser.
 // Check the length with:
+//
 //     len(mockedThreeScaleInterface.AddUserCalls())
 func (mock *ThreeScaleInterfaceMock) AddUserCalls() []struct {
 	Username    string
@@ -264,104 +820,1155 @@ func (mock *ThreeScaleInterfaceMock) AddUserCalls() []struct {
 	return calls
 }
 
-// DeleteUser calls DeleteUserFunc.
-func (mock *ThreeScaleInterfaceMock) DeleteUser(userID int, accessToken string) (*http.Response, error) {
-	if mock.DeleteUserFunc == nil {
-		panic("ThreeScaleInterfaceMock.DeleteUserFunc: method is nil but ThreeScaleInterface.DeleteUser was just called")
+// CreateApplicationPlan calls CreateApplicationPlanFunc.
+func (mock *ThreeScaleInterfaceMock) CreateApplicationPlan(serviceID int, data map[string]string, accessToken string) (*ApplicationPlan, error) {
+	if mock.CreateApplicationPlanFunc == nil {
+		panic("ThreeScaleInterfaceMock.CreateApplicationPlanFunc: method is nil but ThreeScaleInterface.CreateApplicationPlan was just called")
 	}
 	callInfo := struct {
-		UserID      int
+		ServiceID   int
+		Data        map[string]string
 		AccessToken string
 	}{
-		UserID:      userID,
+		ServiceID:   serviceID,
+		Data:        data,
 		AccessToken: accessToken,
 	}
-	mock.lockDeleteUser.Lock()
-	mock.calls.DeleteUser = append(mock.calls.DeleteUser, callInfo)
-	mock.lockDeleteUser.Unlock()
-	return mock.DeleteUserFunc(userID, accessToken)
+	mock.lockCreateApplicationPlan.Lock()
+	mock.calls.CreateApplicationPlan = append(mock.calls.CreateApplicationPlan, callInfo)
+	mock.lockCreateApplicationPlan.Unlock()
+	return mock.CreateApplicationPlanFunc(serviceID, data, accessToken)
 }
 
-// DeleteUserCalls gets all the calls that were made to DeleteUser.
+// CreateApplicationPlanCalls gets all the calls that were made to CreateApplicationPlan.
 // Check the length with:
-//     len(mockedThreeScaleInterface.DeleteUserCalls())
-func (mock *ThreeScaleInterfaceMock) DeleteUserCalls() []struct {
-	UserID      int
+//
+//     len(mockedThreeScaleInterface.CreateApplicationPlanCalls())
+func (mock *ThreeScaleInterfaceMock) CreateApplicationPlanCalls() []struct {
+	ServiceID   int
+	Data        map[string]string
 	AccessToken string
 } {
 	var calls []struct {
-		UserID      int
+		ServiceID   int
+		Data        map[string]string
 		AccessToken string
 	}
-	mock.lockDeleteUser.RLock()
-	calls = mock.calls.DeleteUser
-	mock.lockDeleteUser.RUnlock()
+	mock.lockCreateApplicationPlan.RLock()
+	calls = mock.calls.CreateApplicationPlan
+	mock.lockCreateApplicationPlan.RUnlock()
 	return calls
 }
 
-// GetAuthenticationProviderByName calls GetAuthenticationProviderByNameFunc.
-func (mock *ThreeScaleInterfaceMock) GetAuthenticationProviderByName(name string, accessToken string) (*AuthProvider, error) {
-	if mock.GetAuthenticationProviderByNameFunc == nil {
-		panic("ThreeScaleInterfaceMock.GetAuthenticationProviderByNameFunc: method is nil but ThreeScaleInterface.GetAuthenticationProviderByName was just called")
+// CreateBackendApi calls CreateBackendApiFunc.
+func (mock *ThreeScaleInterfaceMock) CreateBackendApi(data map[string]string, accessToken string) (*BackendApi, error) {
+	if mock.CreateBackendApiFunc == nil {
+		panic("ThreeScaleInterfaceMock.CreateBackendApiFunc: method is nil but ThreeScaleInterface.CreateBackendApi was just called")
 	}
 	callInfo := struct {
-		Name        string
+		Data        map[string]string
 		AccessToken string
 	}{
-		Name:        name,
+		Data:        data,
 		AccessToken: accessToken,
 	}
-	mock.lockGetAuthenticationProviderByName.Lock()
-	mock.calls.GetAuthenticationProviderByName = append(mock.calls.GetAuthenticationProviderByName, callInfo)
-	mock.lockGetAuthenticationProviderByName.Unlock()
-	return mock.GetAuthenticationProviderByNameFunc(name, accessToken)
+	mock.lockCreateBackendApi.Lock()
+	mock.calls.CreateBackendApi = append(mock.calls.CreateBackendApi, callInfo)
+	mock.lockCreateBackendApi.Unlock()
+	return mock.CreateBackendApiFunc(data, accessToken)
 }
 
-// GetAuthenticationProviderByNameCalls gets all the calls that were made to GetAuthenticationProviderByName.
+// CreateBackendApiCalls gets all the calls that were made to CreateBackendApi.
 // Check the length with:
-//     len(mockedThreeScaleInterface.GetAuthenticationProviderByNameCalls())
-func (mock *ThreeScaleInterfaceMock) GetAuthenticationProviderByNameCalls() []struct {
-	Name        string
+//
+//     len(mockedThreeScaleInterface.CreateBackendApiCalls())
+func (mock *ThreeScaleInterfaceMock) CreateBackendApiCalls() []struct {
+	Data        map[string]string
 	AccessToken string
 } {
 	var calls []struct {
-		Name        string
+		Data        map[string]string
 		AccessToken string
 	}
-	mock.lockGetAuthenticationProviderByName.RLock()
-	calls = mock.calls.GetAuthenticationProviderByName
-	mock.lockGetAuthenticationProviderByName.RUnlock()
+	mock.lockCreateBackendApi.RLock()
+	calls = mock.calls.CreateBackendApi
+	mock.lockCreateBackendApi.RUnlock()
 	return calls
 }
 
-// GetAuthenticationProviders calls GetAuthenticationProvidersFunc.
-func (mock *ThreeScaleInterfaceMock) GetAuthenticationProviders(accessToken string) (*AuthProviders, error) {
-	if mock.GetAuthenticationProvidersFunc == nil {
-		panic("ThreeScaleInterfaceMock.GetAuthenticationProvidersFunc: method is nil but ThreeScaleInterface.GetAuthenticationProviders was just called")
+// CreateBackendApiMappingRule calls CreateBackendApiMappingRuleFunc.
+func (mock *ThreeScaleInterfaceMock) CreateBackendApiMappingRule(backendID int, data map[string]string, accessToken string) (*MappingRule, error) {
+	if mock.CreateBackendApiMappingRuleFunc == nil {
+		panic("ThreeScaleInterfaceMock.CreateBackendApiMappingRuleFunc: method is nil but ThreeScaleInterface.CreateBackendApiMappingRule was just called")
 	}
 	callInfo := struct {
+		BackendID   int
+		Data        map[string]string
 		AccessToken string
 	}{
+		BackendID:   backendID,
+		Data:        data,
 		AccessToken: accessToken,
 	}
-	mock.lockGetAuthenticationProviders.Lock()
-	mock.calls.GetAuthenticationProviders = append(mock.calls.GetAuthenticationProviders, callInfo)
-	mock.lockGetAuthenticationProviders.Unlock()
-	return mock.GetAuthenticationProvidersFunc(accessToken)
+	mock.lockCreateBackendApiMappingRule.Lock()
+	mock.calls.CreateBackendApiMappingRule = append(mock.calls.CreateBackendApiMappingRule, callInfo)
+	mock.lockCreateBackendApiMappingRule.Unlock()
+	return mock.CreateBackendApiMappingRuleFunc(backendID, data, accessToken)
+}
+
+// CreateBackendApiMappingRuleCalls gets all the calls that were made to CreateBackendApiMappingRule.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.CreateBackendApiMappingRuleCalls())
+func (mock *ThreeScaleInterfaceMock) CreateBackendApiMappingRuleCalls() []struct {
+	BackendID   int
+	Data        map[string]string
+	AccessToken string
+} {
+	var calls []struct {
+		BackendID   int
+		Data        map[string]string
+		AccessToken string
+	}
+	mock.lockCreateBackendApiMappingRule.RLock()
+	calls = mock.calls.CreateBackendApiMappingRule
+	mock.lockCreateBackendApiMappingRule.RUnlock()
+	return calls
+}
+
+// CreateBackendUsage calls CreateBackendUsageFunc.
+func (mock *ThreeScaleInterfaceMock) CreateBackendUsage(serviceID int, data map[string]string, accessToken string) (*BackendUsage, error) {
+	if mock.CreateBackendUsageFunc == nil {
+		panic("ThreeScaleInterfaceMock.CreateBackendUsageFunc: method is nil but ThreeScaleInterface.CreateBackendUsage was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		Data        map[string]string
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		Data:        data,
+		AccessToken: accessToken,
+	}
+	mock.lockCreateBackendUsage.Lock()
+	mock.calls.CreateBackendUsage = append(mock.calls.CreateBackendUsage, callInfo)
+	mock.lockCreateBackendUsage.Unlock()
+	return mock.CreateBackendUsageFunc(serviceID, data, accessToken)
+}
+
+// CreateBackendUsageCalls gets all the calls that were made to CreateBackendUsage.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.CreateBackendUsageCalls())
+func (mock *ThreeScaleInterfaceMock) CreateBackendUsageCalls() []struct {
+	ServiceID   int
+	Data        map[string]string
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		Data        map[string]string
+		AccessToken string
+	}
+	mock.lockCreateBackendUsage.RLock()
+	calls = mock.calls.CreateBackendUsage
+	mock.lockCreateBackendUsage.RUnlock()
+	return calls
+}
+
+// CreateService calls CreateServiceFunc.
+func (mock *ThreeScaleInterfaceMock) CreateService(data map[string]string, accessToken string) (*Service, error) {
+	if mock.CreateServiceFunc == nil {
+		panic("ThreeScaleInterfaceMock.CreateServiceFunc: method is nil but ThreeScaleInterface.CreateService was just called")
+	}
+	callInfo := struct {
+		Data        map[string]string
+		AccessToken string
+	}{
+		Data:        data,
+		AccessToken: accessToken,
+	}
+	mock.lockCreateService.Lock()
+	mock.calls.CreateService = append(mock.calls.CreateService, callInfo)
+	mock.lockCreateService.Unlock()
+	return mock.CreateServiceFunc(data, accessToken)
+}
+
+// CreateServiceCalls gets all the calls that were made to CreateService.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.CreateServiceCalls())
+func (mock *ThreeScaleInterfaceMock) CreateServiceCalls() []struct {
+	Data        map[string]string
+	AccessToken string
+} {
+	var calls []struct {
+		Data        map[string]string
+		AccessToken string
+	}
+	mock.lockCreateService.RLock()
+	calls = mock.calls.CreateService
+	mock.lockCreateService.RUnlock()
+	return calls
+}
+
+// CreateServiceMappingRule calls CreateServiceMappingRuleFunc.
+func (mock *ThreeScaleInterfaceMock) CreateServiceMappingRule(serviceID int, data map[string]string, accessToken string) (*MappingRule, error) {
+	if mock.CreateServiceMappingRuleFunc == nil {
+		panic("ThreeScaleInterfaceMock.CreateServiceMappingRuleFunc: method is nil but ThreeScaleInterface.CreateServiceMappingRule was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		Data        map[string]string
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		Data:        data,
+		AccessToken: accessToken,
+	}
+	mock.lockCreateServiceMappingRule.Lock()
+	mock.calls.CreateServiceMappingRule = append(mock.calls.CreateServiceMappingRule, callInfo)
+	mock.lockCreateServiceMappingRule.Unlock()
+	return mock.CreateServiceMappingRuleFunc(serviceID, data, accessToken)
+}
+
+// CreateServiceMappingRuleCalls gets all the calls that were made to CreateServiceMappingRule.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.CreateServiceMappingRuleCalls())
+func (mock *ThreeScaleInterfaceMock) CreateServiceMappingRuleCalls() []struct {
+	ServiceID   int
+	Data        map[string]string
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		Data        map[string]string
+		AccessToken string
+	}
+	mock.lockCreateServiceMappingRule.RLock()
+	calls = mock.calls.CreateServiceMappingRule
+	mock.lockCreateServiceMappingRule.RUnlock()
+	return calls
+}
+
+// CreateTenant calls CreateTenantFunc.
+func (mock *ThreeScaleInterfaceMock) CreateTenant(orgName string, username string, email string, password string, masterAccessToken string) (*Tenant, error) {
+	if mock.CreateTenantFunc == nil {
+		panic("ThreeScaleInterfaceMock.CreateTenantFunc: method is nil but ThreeScaleInterface.CreateTenant was just called")
+	}
+	callInfo := struct {
+		OrgName           string
+		Username          string
+		Email             string
+		Password          string
+		MasterAccessToken string
+	}{
+		OrgName:           orgName,
+		Username:          username,
+		Email:             email,
+		Password:          password,
+		MasterAccessToken: masterAccessToken,
+	}
+	mock.lockCreateTenant.Lock()
+	mock.calls.CreateTenant = append(mock.calls.CreateTenant, callInfo)
+	mock.lockCreateTenant.Unlock()
+	return mock.CreateTenantFunc(orgName, username, email, password, masterAccessToken)
+}
+
+// CreateTenantCalls gets all the calls that were made to CreateTenant.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.CreateTenantCalls())
+func (mock *ThreeScaleInterfaceMock) CreateTenantCalls() []struct {
+	OrgName           string
+	Username          string
+	Email             string
+	Password          string
+	MasterAccessToken string
+} {
+	var calls []struct {
+		OrgName           string
+		Username          string
+		Email             string
+		Password          string
+		MasterAccessToken string
+	}
+	mock.lockCreateTenant.RLock()
+	calls = mock.calls.CreateTenant
+	mock.lockCreateTenant.RUnlock()
+	return calls
+}
+
+// DeleteApplicationPlan calls DeleteApplicationPlanFunc.
+func (mock *ThreeScaleInterfaceMock) DeleteApplicationPlan(serviceID int, planID int, accessToken string) (*http.Response, error) {
+	if mock.DeleteApplicationPlanFunc == nil {
+		panic("ThreeScaleInterfaceMock.DeleteApplicationPlanFunc: method is nil but ThreeScaleInterface.DeleteApplicationPlan was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		PlanID      int
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		PlanID:      planID,
+		AccessToken: accessToken,
+	}
+	mock.lockDeleteApplicationPlan.Lock()
+	mock.calls.DeleteApplicationPlan = append(mock.calls.DeleteApplicationPlan, callInfo)
+	mock.lockDeleteApplicationPlan.Unlock()
+	return mock.DeleteApplicationPlanFunc(serviceID, planID, accessToken)
+}
+
+// DeleteApplicationPlanCalls gets all the calls that were made to DeleteApplicationPlan.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.DeleteApplicationPlanCalls())
+func (mock *ThreeScaleInterfaceMock) DeleteApplicationPlanCalls() []struct {
+	ServiceID   int
+	PlanID      int
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		PlanID      int
+		AccessToken string
+	}
+	mock.lockDeleteApplicationPlan.RLock()
+	calls = mock.calls.DeleteApplicationPlan
+	mock.lockDeleteApplicationPlan.RUnlock()
+	return calls
+}
+
+// DeleteBackendApi calls DeleteBackendApiFunc.
+func (mock *ThreeScaleInterfaceMock) DeleteBackendApi(backendID int, accessToken string) (*http.Response, error) {
+	if mock.DeleteBackendApiFunc == nil {
+		panic("ThreeScaleInterfaceMock.DeleteBackendApiFunc: method is nil but ThreeScaleInterface.DeleteBackendApi was just called")
+	}
+	callInfo := struct {
+		BackendID   int
+		AccessToken string
+	}{
+		BackendID:   backendID,
+		AccessToken: accessToken,
+	}
+	mock.lockDeleteBackendApi.Lock()
+	mock.calls.DeleteBackendApi = append(mock.calls.DeleteBackendApi, callInfo)
+	mock.lockDeleteBackendApi.Unlock()
+	return mock.DeleteBackendApiFunc(backendID, accessToken)
+}
+
+// DeleteBackendApiCalls gets all the calls that were made to DeleteBackendApi.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.DeleteBackendApiCalls())
+func (mock *ThreeScaleInterfaceMock) DeleteBackendApiCalls() []struct {
+	BackendID   int
+	AccessToken string
+} {
+	var calls []struct {
+		BackendID   int
+		AccessToken string
+	}
+	mock.lockDeleteBackendApi.RLock()
+	calls = mock.calls.DeleteBackendApi
+	mock.lockDeleteBackendApi.RUnlock()
+	return calls
+}
+
+// DeleteBackendApiMappingRule calls DeleteBackendApiMappingRuleFunc.
+func (mock *ThreeScaleInterfaceMock) DeleteBackendApiMappingRule(backendID int, ruleID int, accessToken string) (*http.Response, error) {
+	if mock.DeleteBackendApiMappingRuleFunc == nil {
+		panic("ThreeScaleInterfaceMock.DeleteBackendApiMappingRuleFunc: method is nil but ThreeScaleInterface.DeleteBackendApiMappingRule was just called")
+	}
+	callInfo := struct {
+		BackendID   int
+		RuleID      int
+		AccessToken string
+	}{
+		BackendID:   backendID,
+		RuleID:      ruleID,
+		AccessToken: accessToken,
+	}
+	mock.lockDeleteBackendApiMappingRule.Lock()
+	mock.calls.DeleteBackendApiMappingRule = append(mock.calls.DeleteBackendApiMappingRule, callInfo)
+	mock.lockDeleteBackendApiMappingRule.Unlock()
+	return mock.DeleteBackendApiMappingRuleFunc(backendID, ruleID, accessToken)
+}
+
+// DeleteBackendApiMappingRuleCalls gets all the calls that were made to DeleteBackendApiMappingRule.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.DeleteBackendApiMappingRuleCalls())
+func (mock *ThreeScaleInterfaceMock) DeleteBackendApiMappingRuleCalls() []struct {
+	BackendID   int
+	RuleID      int
+	AccessToken string
+} {
+	var calls []struct {
+		BackendID   int
+		RuleID      int
+		AccessToken string
+	}
+	mock.lockDeleteBackendApiMappingRule.RLock()
+	calls = mock.calls.DeleteBackendApiMappingRule
+	mock.lockDeleteBackendApiMappingRule.RUnlock()
+	return calls
+}
+
+// DeleteBackendUsage calls DeleteBackendUsageFunc.
+func (mock *ThreeScaleInterfaceMock) DeleteBackendUsage(serviceID int, usageID int, accessToken string) (*http.Response, error) {
+	if mock.DeleteBackendUsageFunc == nil {
+		panic("ThreeScaleInterfaceMock.DeleteBackendUsageFunc: method is nil but ThreeScaleInterface.DeleteBackendUsage was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		UsageID     int
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		UsageID:     usageID,
+		AccessToken: accessToken,
+	}
+	mock.lockDeleteBackendUsage.Lock()
+	mock.calls.DeleteBackendUsage = append(mock.calls.DeleteBackendUsage, callInfo)
+	mock.lockDeleteBackendUsage.Unlock()
+	return mock.DeleteBackendUsageFunc(serviceID, usageID, accessToken)
+}
+
+// DeleteBackendUsageCalls gets all the calls that were made to DeleteBackendUsage.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.DeleteBackendUsageCalls())
+func (mock *ThreeScaleInterfaceMock) DeleteBackendUsageCalls() []struct {
+	ServiceID   int
+	UsageID     int
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		UsageID     int
+		AccessToken string
+	}
+	mock.lockDeleteBackendUsage.RLock()
+	calls = mock.calls.DeleteBackendUsage
+	mock.lockDeleteBackendUsage.RUnlock()
+	return calls
+}
+
+// DeleteService calls DeleteServiceFunc.
+func (mock *ThreeScaleInterfaceMock) DeleteService(serviceID int, accessToken string) (*http.Response, error) {
+	if mock.DeleteServiceFunc == nil {
+		panic("ThreeScaleInterfaceMock.DeleteServiceFunc: method is nil but ThreeScaleInterface.DeleteService was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		AccessToken: accessToken,
+	}
+	mock.lockDeleteService.Lock()
+	mock.calls.DeleteService = append(mock.calls.DeleteService, callInfo)
+	mock.lockDeleteService.Unlock()
+	return mock.DeleteServiceFunc(serviceID, accessToken)
+}
+
+// DeleteServiceCalls gets all the calls that were made to DeleteService.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.DeleteServiceCalls())
+func (mock *ThreeScaleInterfaceMock) DeleteServiceCalls() []struct {
+	ServiceID   int
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		AccessToken string
+	}
+	mock.lockDeleteService.RLock()
+	calls = mock.calls.DeleteService
+	mock.lockDeleteService.RUnlock()
+	return calls
+}
+
+// DeleteServiceMappingRule calls DeleteServiceMappingRuleFunc.
+func (mock *ThreeScaleInterfaceMock) DeleteServiceMappingRule(serviceID int, ruleID int, accessToken string) (*http.Response, error) {
+	if mock.DeleteServiceMappingRuleFunc == nil {
+		panic("ThreeScaleInterfaceMock.DeleteServiceMappingRuleFunc: method is nil but ThreeScaleInterface.DeleteServiceMappingRule was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		RuleID      int
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		RuleID:      ruleID,
+		AccessToken: accessToken,
+	}
+	mock.lockDeleteServiceMappingRule.Lock()
+	mock.calls.DeleteServiceMappingRule = append(mock.calls.DeleteServiceMappingRule, callInfo)
+	mock.lockDeleteServiceMappingRule.Unlock()
+	return mock.DeleteServiceMappingRuleFunc(serviceID, ruleID, accessToken)
+}
+
+// DeleteServiceMappingRuleCalls gets all the calls that were made to DeleteServiceMappingRule.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.DeleteServiceMappingRuleCalls())
+func (mock *ThreeScaleInterfaceMock) DeleteServiceMappingRuleCalls() []struct {
+	ServiceID   int
+	RuleID      int
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		RuleID      int
+		AccessToken string
+	}
+	mock.lockDeleteServiceMappingRule.RLock()
+	calls = mock.calls.DeleteServiceMappingRule
+	mock.lockDeleteServiceMappingRule.RUnlock()
+	return calls
+}
+
+// DeleteTenant calls DeleteTenantFunc.
+func (mock *ThreeScaleInterfaceMock) DeleteTenant(tenantID int, masterAccessToken string) (*http.Response, error) {
+	if mock.DeleteTenantFunc == nil {
+		panic("ThreeScaleInterfaceMock.DeleteTenantFunc: method is nil but ThreeScaleInterface.DeleteTenant was just called")
+	}
+	callInfo := struct {
+		TenantID          int
+		MasterAccessToken string
+	}{
+		TenantID:          tenantID,
+		MasterAccessToken: masterAccessToken,
+	}
+	mock.lockDeleteTenant.Lock()
+	mock.calls.DeleteTenant = append(mock.calls.DeleteTenant, callInfo)
+	mock.lockDeleteTenant.Unlock()
+	return mock.DeleteTenantFunc(tenantID, masterAccessToken)
+}
+
+// DeleteTenantCalls gets all the calls that were made to DeleteTenant.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.DeleteTenantCalls())
+func (mock *ThreeScaleInterfaceMock) DeleteTenantCalls() []struct {
+	TenantID          int
+	MasterAccessToken string
+} {
+	var calls []struct {
+		TenantID          int
+		MasterAccessToken string
+	}
+	mock.lockDeleteTenant.RLock()
+	calls = mock.calls.DeleteTenant
+	mock.lockDeleteTenant.RUnlock()
+	return calls
+}
+
+// DeleteUser calls DeleteUserFunc.
+func (mock *ThreeScaleInterfaceMock) DeleteUser(userID int, accessToken string) (*http.Response, error) {
+	if mock.DeleteUserFunc == nil {
+		panic("ThreeScaleInterfaceMock.DeleteUserFunc: method is nil but ThreeScaleInterface.DeleteUser was just called")
+	}
+	callInfo := struct {
+		UserID      int
+		AccessToken string
+	}{
+		UserID:      userID,
+		AccessToken: accessToken,
+	}
+	mock.lockDeleteUser.Lock()
+	mock.calls.DeleteUser = append(mock.calls.DeleteUser, callInfo)
+	mock.lockDeleteUser.Unlock()
+	return mock.DeleteUserFunc(userID, accessToken)
+}
+
+// DeleteUserCalls gets all the calls that were made to DeleteUser.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.DeleteUserCalls())
+func (mock *ThreeScaleInterfaceMock) DeleteUserCalls() []struct {
+	UserID      int
+	AccessToken string
+} {
+	var calls []struct {
+		UserID      int
+		AccessToken string
+	}
+	mock.lockDeleteUser.RLock()
+	calls = mock.calls.DeleteUser
+	mock.lockDeleteUser.RUnlock()
+	return calls
+}
+
+// DeployProxy calls DeployProxyFunc.
+func (mock *ThreeScaleInterfaceMock) DeployProxy(serviceID int, accessToken string) (*http.Response, error) {
+	if mock.DeployProxyFunc == nil {
+		panic("ThreeScaleInterfaceMock.DeployProxyFunc: method is nil but ThreeScaleInterface.DeployProxy was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		AccessToken: accessToken,
+	}
+	mock.lockDeployProxy.Lock()
+	mock.calls.DeployProxy = append(mock.calls.DeployProxy, callInfo)
+	mock.lockDeployProxy.Unlock()
+	return mock.DeployProxyFunc(serviceID, accessToken)
+}
+
+// DeployProxyCalls gets all the calls that were made to DeployProxy.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.DeployProxyCalls())
+func (mock *ThreeScaleInterfaceMock) DeployProxyCalls() []struct {
+	ServiceID   int
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		AccessToken string
+	}
+	mock.lockDeployProxy.RLock()
+	calls = mock.calls.DeployProxy
+	mock.lockDeployProxy.RUnlock()
+	return calls
+}
+
+// GetApplicationPlans calls GetApplicationPlansFunc.
+func (mock *ThreeScaleInterfaceMock) GetApplicationPlans(serviceID int, accessToken string) (*ApplicationPlans, error) {
+	if mock.GetApplicationPlansFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetApplicationPlansFunc: method is nil but ThreeScaleInterface.GetApplicationPlans was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		AccessToken: accessToken,
+	}
+	mock.lockGetApplicationPlans.Lock()
+	mock.calls.GetApplicationPlans = append(mock.calls.GetApplicationPlans, callInfo)
+	mock.lockGetApplicationPlans.Unlock()
+	return mock.GetApplicationPlansFunc(serviceID, accessToken)
+}
+
+// GetApplicationPlansCalls gets all the calls that were made to GetApplicationPlans.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.GetApplicationPlansCalls())
+func (mock *ThreeScaleInterfaceMock) GetApplicationPlansCalls() []struct {
+	ServiceID   int
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		AccessToken string
+	}
+	mock.lockGetApplicationPlans.RLock()
+	calls = mock.calls.GetApplicationPlans
+	mock.lockGetApplicationPlans.RUnlock()
+	return calls
+}
+
+// GetAuthenticationProviderByName calls GetAuthenticationProviderByNameFunc.
+func (mock *ThreeScaleInterfaceMock) GetAuthenticationProviderByName(name string, accessToken string) (*AuthProvider, error) {
+	if mock.GetAuthenticationProviderByNameFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetAuthenticationProviderByNameFunc: method is nil but ThreeScaleInterface.GetAuthenticationProviderByName was just called")
+	}
+	callInfo := struct {
+		Name        string
+		AccessToken string
+	}{
+		Name:        name,
+		AccessToken: accessToken,
+	}
+	mock.lockGetAuthenticationProviderByName.Lock()
+	mock.calls.GetAuthenticationProviderByName = append(mock.calls.GetAuthenticationProviderByName, callInfo)
+	mock.lockGetAuthenticationProviderByName.Unlock()
+	return mock.GetAuthenticationProviderByNameFunc(name, accessToken)
+}
+
+// GetAuthenticationProviderByNameCalls gets all the calls that were made to GetAuthenticationProviderByName.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.GetAuthenticationProviderByNameCalls())
+func (mock *ThreeScaleInterfaceMock) GetAuthenticationProviderByNameCalls() []struct {
+	Name        string
+	AccessToken string
+} {
+	var calls []struct {
+		Name        string
+		AccessToken string
+	}
+	mock.lockGetAuthenticationProviderByName.RLock()
+	calls = mock.calls.GetAuthenticationProviderByName
+	mock.lockGetAuthenticationProviderByName.RUnlock()
+	return calls
+}
+
+// GetAuthenticationProviders calls GetAuthenticationProvidersFunc.
+func (mock *ThreeScaleInterfaceMock) GetAuthenticationProviders(accessToken string) (*AuthProviders, error) {
+	if mock.GetAuthenticationProvidersFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetAuthenticationProvidersFunc: method is nil but ThreeScaleInterface.GetAuthenticationProviders was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+	}{
+		AccessToken: accessToken,
+	}
+	mock.lockGetAuthenticationProviders.Lock()
+	mock.calls.GetAuthenticationProviders = append(mock.calls.GetAuthenticationProviders, callInfo)
+	mock.lockGetAuthenticationProviders.Unlock()
+	return mock.GetAuthenticationProvidersFunc(accessToken)
 }
 
 // GetAuthenticationProvidersCalls gets all the calls that were made to GetAuthenticationProviders.
 // Check the length with:
-//     len(mockedThreeScaleInterface.GetAuthenticationProvidersCalls())
-func (mock *ThreeScaleInterfaceMock) GetAuthenticationProvidersCalls() []struct {
+//
+//     len(mockedThreeScaleInterface.GetAuthenticationProvidersCalls())
+func (mock *ThreeScaleInterfaceMock) GetAuthenticationProvidersCalls() []struct {
+	AccessToken string
+} {
+	var calls []struct {
+		AccessToken string
+	}
+	mock.lockGetAuthenticationProviders.RLock()
+	calls = mock.calls.GetAuthenticationProviders
+	mock.lockGetAuthenticationProviders.RUnlock()
+	return calls
+}
+
+// GetBackendApiMappingRules calls GetBackendApiMappingRulesFunc.
+func (mock *ThreeScaleInterfaceMock) GetBackendApiMappingRules(backendID int, accessToken string) (*MappingRules, error) {
+	if mock.GetBackendApiMappingRulesFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetBackendApiMappingRulesFunc: method is nil but ThreeScaleInterface.GetBackendApiMappingRules was just called")
+	}
+	callInfo := struct {
+		BackendID   int
+		AccessToken string
+	}{
+		BackendID:   backendID,
+		AccessToken: accessToken,
+	}
+	mock.lockGetBackendApiMappingRules.Lock()
+	mock.calls.GetBackendApiMappingRules = append(mock.calls.GetBackendApiMappingRules, callInfo)
+	mock.lockGetBackendApiMappingRules.Unlock()
+	return mock.GetBackendApiMappingRulesFunc(backendID, accessToken)
+}
+
+// GetBackendApiMappingRulesCalls gets all the calls that were made to GetBackendApiMappingRules.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.GetBackendApiMappingRulesCalls())
+func (mock *ThreeScaleInterfaceMock) GetBackendApiMappingRulesCalls() []struct {
+	BackendID   int
+	AccessToken string
+} {
+	var calls []struct {
+		BackendID   int
+		AccessToken string
+	}
+	mock.lockGetBackendApiMappingRules.RLock()
+	calls = mock.calls.GetBackendApiMappingRules
+	mock.lockGetBackendApiMappingRules.RUnlock()
+	return calls
+}
+
+// GetBackendApiMetrics calls GetBackendApiMetricsFunc.
+func (mock *ThreeScaleInterfaceMock) GetBackendApiMetrics(backendID int, accessToken string) (*Metrics, error) {
+	if mock.GetBackendApiMetricsFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetBackendApiMetricsFunc: method is nil but ThreeScaleInterface.GetBackendApiMetrics was just called")
+	}
+	callInfo := struct {
+		BackendID   int
+		AccessToken string
+	}{
+		BackendID:   backendID,
+		AccessToken: accessToken,
+	}
+	mock.lockGetBackendApiMetrics.Lock()
+	mock.calls.GetBackendApiMetrics = append(mock.calls.GetBackendApiMetrics, callInfo)
+	mock.lockGetBackendApiMetrics.Unlock()
+	return mock.GetBackendApiMetricsFunc(backendID, accessToken)
+}
+
+// GetBackendApiMetricsCalls gets all the calls that were made to GetBackendApiMetrics.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.GetBackendApiMetricsCalls())
+func (mock *ThreeScaleInterfaceMock) GetBackendApiMetricsCalls() []struct {
+	BackendID   int
+	AccessToken string
+} {
+	var calls []struct {
+		BackendID   int
+		AccessToken string
+	}
+	mock.lockGetBackendApiMetrics.RLock()
+	calls = mock.calls.GetBackendApiMetrics
+	mock.lockGetBackendApiMetrics.RUnlock()
+	return calls
+}
+
+// GetBackendApis calls GetBackendApisFunc.
+func (mock *ThreeScaleInterfaceMock) GetBackendApis(accessToken string) (*BackendApis, error) {
+	if mock.GetBackendApisFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetBackendApisFunc: method is nil but ThreeScaleInterface.GetBackendApis was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+	}{
+		AccessToken: accessToken,
+	}
+	mock.lockGetBackendApis.Lock()
+	mock.calls.GetBackendApis = append(mock.calls.GetBackendApis, callInfo)
+	mock.lockGetBackendApis.Unlock()
+	return mock.GetBackendApisFunc(accessToken)
+}
+
+// GetBackendApisCalls gets all the calls that were made to GetBackendApis.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.GetBackendApisCalls())
+func (mock *ThreeScaleInterfaceMock) GetBackendApisCalls() []struct {
+	AccessToken string
+} {
+	var calls []struct {
+		AccessToken string
+	}
+	mock.lockGetBackendApis.RLock()
+	calls = mock.calls.GetBackendApis
+	mock.lockGetBackendApis.RUnlock()
+	return calls
+}
+
+// GetBackendUsages calls GetBackendUsagesFunc.
+func (mock *ThreeScaleInterfaceMock) GetBackendUsages(serviceID int, accessToken string) ([]*BackendUsage, error) {
+	if mock.GetBackendUsagesFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetBackendUsagesFunc: method is nil but ThreeScaleInterface.GetBackendUsages was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		AccessToken: accessToken,
+	}
+	mock.lockGetBackendUsages.Lock()
+	mock.calls.GetBackendUsages = append(mock.calls.GetBackendUsages, callInfo)
+	mock.lockGetBackendUsages.Unlock()
+	return mock.GetBackendUsagesFunc(serviceID, accessToken)
+}
+
+// GetBackendUsagesCalls gets all the calls that were made to GetBackendUsages.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.GetBackendUsagesCalls())
+func (mock *ThreeScaleInterfaceMock) GetBackendUsagesCalls() []struct {
+	ServiceID   int
 	AccessToken string
 } {
 	var calls []struct {
+		ServiceID   int
 		AccessToken string
 	}
-	mock.lockGetAuthenticationProviders.RLock()
-	calls = mock.calls.GetAuthenticationProviders
-	mock.lockGetAuthenticationProviders.RUnlock()
+	mock.lockGetBackendUsages.RLock()
+	calls = mock.calls.GetBackendUsages
+	mock.lockGetBackendUsages.RUnlock()
+	return calls
+}
+
+// GetLatestProxyConfig calls GetLatestProxyConfigFunc.
+func (mock *ThreeScaleInterfaceMock) GetLatestProxyConfig(serviceID int, environment string, accessToken string) (*ProxyConfig, error) {
+	if mock.GetLatestProxyConfigFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetLatestProxyConfigFunc: method is nil but ThreeScaleInterface.GetLatestProxyConfig was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		Environment string
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		Environment: environment,
+		AccessToken: accessToken,
+	}
+	mock.lockGetLatestProxyConfig.Lock()
+	mock.calls.GetLatestProxyConfig = append(mock.calls.GetLatestProxyConfig, callInfo)
+	mock.lockGetLatestProxyConfig.Unlock()
+	return mock.GetLatestProxyConfigFunc(serviceID, environment, accessToken)
+}
+
+// GetLatestProxyConfigCalls gets all the calls that were made to GetLatestProxyConfig.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.GetLatestProxyConfigCalls())
+func (mock *ThreeScaleInterfaceMock) GetLatestProxyConfigCalls() []struct {
+	ServiceID   int
+	Environment string
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		Environment string
+		AccessToken string
+	}
+	mock.lockGetLatestProxyConfig.RLock()
+	calls = mock.calls.GetLatestProxyConfig
+	mock.lockGetLatestProxyConfig.RUnlock()
+	return calls
+}
+
+// GetPolicies calls GetPoliciesFunc.
+func (mock *ThreeScaleInterfaceMock) GetPolicies(serviceID int, accessToken string) (*PoliciesConfig, error) {
+	if mock.GetPoliciesFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetPoliciesFunc: method is nil but ThreeScaleInterface.GetPolicies was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		AccessToken: accessToken,
+	}
+	mock.lockGetPolicies.Lock()
+	mock.calls.GetPolicies = append(mock.calls.GetPolicies, callInfo)
+	mock.lockGetPolicies.Unlock()
+	return mock.GetPoliciesFunc(serviceID, accessToken)
+}
+
+// GetPoliciesCalls gets all the calls that were made to GetPolicies.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.GetPoliciesCalls())
+func (mock *ThreeScaleInterfaceMock) GetPoliciesCalls() []struct {
+	ServiceID   int
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		AccessToken string
+	}
+	mock.lockGetPolicies.RLock()
+	calls = mock.calls.GetPolicies
+	mock.lockGetPolicies.RUnlock()
+	return calls
+}
+
+// GetServiceMappingRules calls GetServiceMappingRulesFunc.
+func (mock *ThreeScaleInterfaceMock) GetServiceMappingRules(serviceID int, accessToken string) (*MappingRules, error) {
+	if mock.GetServiceMappingRulesFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetServiceMappingRulesFunc: method is nil but ThreeScaleInterface.GetServiceMappingRules was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		AccessToken: accessToken,
+	}
+	mock.lockGetServiceMappingRules.Lock()
+	mock.calls.GetServiceMappingRules = append(mock.calls.GetServiceMappingRules, callInfo)
+	mock.lockGetServiceMappingRules.Unlock()
+	return mock.GetServiceMappingRulesFunc(serviceID, accessToken)
+}
+
+// GetServiceMappingRulesCalls gets all the calls that were made to GetServiceMappingRules.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.GetServiceMappingRulesCalls())
+func (mock *ThreeScaleInterfaceMock) GetServiceMappingRulesCalls() []struct {
+	ServiceID   int
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		AccessToken string
+	}
+	mock.lockGetServiceMappingRules.RLock()
+	calls = mock.calls.GetServiceMappingRules
+	mock.lockGetServiceMappingRules.RUnlock()
+	return calls
+}
+
+// GetServiceMetrics calls GetServiceMetricsFunc.
+func (mock *ThreeScaleInterfaceMock) GetServiceMetrics(serviceID int, accessToken string) (*Metrics, error) {
+	if mock.GetServiceMetricsFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetServiceMetricsFunc: method is nil but ThreeScaleInterface.GetServiceMetrics was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		AccessToken: accessToken,
+	}
+	mock.lockGetServiceMetrics.Lock()
+	mock.calls.GetServiceMetrics = append(mock.calls.GetServiceMetrics, callInfo)
+	mock.lockGetServiceMetrics.Unlock()
+	return mock.GetServiceMetricsFunc(serviceID, accessToken)
+}
+
+// GetServiceMetricsCalls gets all the calls that were made to GetServiceMetrics.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.GetServiceMetricsCalls())
+func (mock *ThreeScaleInterfaceMock) GetServiceMetricsCalls() []struct {
+	ServiceID   int
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		AccessToken string
+	}
+	mock.lockGetServiceMetrics.RLock()
+	calls = mock.calls.GetServiceMetrics
+	mock.lockGetServiceMetrics.RUnlock()
+	return calls
+}
+
+// GetServices calls GetServicesFunc.
+func (mock *ThreeScaleInterfaceMock) GetServices(accessToken string) (*Services, error) {
+	if mock.GetServicesFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetServicesFunc: method is nil but ThreeScaleInterface.GetServices was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+	}{
+		AccessToken: accessToken,
+	}
+	mock.lockGetServices.Lock()
+	mock.calls.GetServices = append(mock.calls.GetServices, callInfo)
+	mock.lockGetServices.Unlock()
+	return mock.GetServicesFunc(accessToken)
+}
+
+// GetServicesCalls gets all the calls that were made to GetServices.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.GetServicesCalls())
+func (mock *ThreeScaleInterfaceMock) GetServicesCalls() []struct {
+	AccessToken string
+} {
+	var calls []struct {
+		AccessToken string
+	}
+	mock.lockGetServices.RLock()
+	calls = mock.calls.GetServices
+	mock.lockGetServices.RUnlock()
+	return calls
+}
+
+// GetTenant calls GetTenantFunc.
+func (mock *ThreeScaleInterfaceMock) GetTenant(tenantID int, masterAccessToken string) (*TenantAccount, error) {
+	if mock.GetTenantFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetTenantFunc: method is nil but ThreeScaleInterface.GetTenant was just called")
+	}
+	callInfo := struct {
+		TenantID          int
+		MasterAccessToken string
+	}{
+		TenantID:          tenantID,
+		MasterAccessToken: masterAccessToken,
+	}
+	mock.lockGetTenant.Lock()
+	mock.calls.GetTenant = append(mock.calls.GetTenant, callInfo)
+	mock.lockGetTenant.Unlock()
+	return mock.GetTenantFunc(tenantID, masterAccessToken)
+}
+
+// GetTenantCalls gets all the calls that were made to GetTenant.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.GetTenantCalls())
+func (mock *ThreeScaleInterfaceMock) GetTenantCalls() []struct {
+	TenantID          int
+	MasterAccessToken string
+} {
+	var calls []struct {
+		TenantID          int
+		MasterAccessToken string
+	}
+	mock.lockGetTenant.RLock()
+	calls = mock.calls.GetTenant
+	mock.lockGetTenant.RUnlock()
+	return calls
+}
+
+// GetTenantByOrgName calls GetTenantByOrgNameFunc.
+func (mock *ThreeScaleInterfaceMock) GetTenantByOrgName(orgName string, masterAccessToken string) (*TenantAccount, error) {
+	if mock.GetTenantByOrgNameFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetTenantByOrgNameFunc: method is nil but ThreeScaleInterface.GetTenantByOrgName was just called")
+	}
+	callInfo := struct {
+		OrgName           string
+		MasterAccessToken string
+	}{
+		OrgName:           orgName,
+		MasterAccessToken: masterAccessToken,
+	}
+	mock.lockGetTenantByOrgName.Lock()
+	mock.calls.GetTenantByOrgName = append(mock.calls.GetTenantByOrgName, callInfo)
+	mock.lockGetTenantByOrgName.Unlock()
+	return mock.GetTenantByOrgNameFunc(orgName, masterAccessToken)
+}
+
+// GetTenantByOrgNameCalls gets all the calls that were made to GetTenantByOrgName.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.GetTenantByOrgNameCalls())
+func (mock *ThreeScaleInterfaceMock) GetTenantByOrgNameCalls() []struct {
+	OrgName           string
+	MasterAccessToken string
+} {
+	var calls []struct {
+		OrgName           string
+		MasterAccessToken string
+	}
+	mock.lockGetTenantByOrgName.RLock()
+	calls = mock.calls.GetTenantByOrgName
+	mock.lockGetTenantByOrgName.RUnlock()
+	return calls
+}
+
+// GetTenantUsers calls GetTenantUsersFunc.
+func (mock *ThreeScaleInterfaceMock) GetTenantUsers(tenantID int, masterAccessToken string) (*Users, error) {
+	if mock.GetTenantUsersFunc == nil {
+		panic("ThreeScaleInterfaceMock.GetTenantUsersFunc: method is nil but ThreeScaleInterface.GetTenantUsers was just called")
+	}
+	callInfo := struct {
+		TenantID          int
+		MasterAccessToken string
+	}{
+		TenantID:          tenantID,
+		MasterAccessToken: masterAccessToken,
+	}
+	mock.lockGetTenantUsers.Lock()
+	mock.calls.GetTenantUsers = append(mock.calls.GetTenantUsers, callInfo)
+	mock.lockGetTenantUsers.Unlock()
+	return mock.GetTenantUsersFunc(tenantID, masterAccessToken)
+}
+
+// GetTenantUsersCalls gets all the calls that were made to GetTenantUsers.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.GetTenantUsersCalls())
+func (mock *ThreeScaleInterfaceMock) GetTenantUsersCalls() []struct {
+	TenantID          int
+	MasterAccessToken string
+} {
+	var calls []struct {
+		TenantID          int
+		MasterAccessToken string
+	}
+	mock.lockGetTenantUsers.RLock()
+	calls = mock.calls.GetTenantUsers
+	mock.lockGetTenantUsers.RUnlock()
 	return calls
 }
 
@@ -385,6 +1992,7 @@ func (mock *ThreeScaleInterfaceMock) GetUser(username string, accessToken string
 
 // GetUserCalls gets all the calls that were made to GetUser.
 // Check the length with:
+//
 //     len(mockedThreeScaleInterface.GetUserCalls())
 func (mock *ThreeScaleInterfaceMock) GetUserCalls() []struct {
 	Username    string
@@ -418,6 +2026,7 @@ func (mock *ThreeScaleInterfaceMock) GetUsers(accessToken string) (*Users, error
 
 // GetUsersCalls gets all the calls that were made to GetUsers.
 // Check the length with:
+//
 //     len(mockedThreeScaleInterface.GetUsersCalls())
 func (mock *ThreeScaleInterfaceMock) GetUsersCalls() []struct {
 	AccessToken string
@@ -431,6 +2040,54 @@ func (mock *ThreeScaleInterfaceMock) GetUsersCalls() []struct {
 	return calls
 }
 
+// PromoteProxyConfig calls PromoteProxyConfigFunc.
+func (mock *ThreeScaleInterfaceMock) PromoteProxyConfig(serviceID int, environment string, version int, toEnvironment string, accessToken string) (*http.Response, error) {
+	if mock.PromoteProxyConfigFunc == nil {
+		panic("ThreeScaleInterfaceMock.PromoteProxyConfigFunc: method is nil but ThreeScaleInterface.PromoteProxyConfig was just called")
+	}
+	callInfo := struct {
+		ServiceID     int
+		Environment   string
+		Version       int
+		ToEnvironment string
+		AccessToken   string
+	}{
+		ServiceID:     serviceID,
+		Environment:   environment,
+		Version:       version,
+		ToEnvironment: toEnvironment,
+		AccessToken:   accessToken,
+	}
+	mock.lockPromoteProxyConfig.Lock()
+	mock.calls.PromoteProxyConfig = append(mock.calls.PromoteProxyConfig, callInfo)
+	mock.lockPromoteProxyConfig.Unlock()
+	return mock.PromoteProxyConfigFunc(serviceID, environment, version, toEnvironment, accessToken)
+}
+
+// PromoteProxyConfigCalls gets all the calls that were made to PromoteProxyConfig.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.PromoteProxyConfigCalls())
+func (mock *ThreeScaleInterfaceMock) PromoteProxyConfigCalls() []struct {
+	ServiceID     int
+	Environment   string
+	Version       int
+	ToEnvironment string
+	AccessToken   string
+} {
+	var calls []struct {
+		ServiceID     int
+		Environment   string
+		Version       int
+		ToEnvironment string
+		AccessToken   string
+	}
+	mock.lockPromoteProxyConfig.RLock()
+	calls = mock.calls.PromoteProxyConfig
+	mock.lockPromoteProxyConfig.RUnlock()
+	return calls
+}
+
 // SetNamespace calls SetNamespaceFunc.
 func (mock *ThreeScaleInterfaceMock) SetNamespace(ns string) {
 	if mock.SetNamespaceFunc == nil {
@@ -449,6 +2106,7 @@ func (mock *ThreeScaleInterfaceMock) SetNamespace(ns string) {
 
 // SetNamespaceCalls gets all the calls that were made to SetNamespace.
 // Check the length with:
+//
 //     len(mockedThreeScaleInterface.SetNamespaceCalls())
 func (mock *ThreeScaleInterfaceMock) SetNamespaceCalls() []struct {
 	Ns string
@@ -482,6 +2140,7 @@ func (mock *ThreeScaleInterfaceMock) SetUserAsAdmin(userID int, accessToken stri
 
 // SetUserAsAdminCalls gets all the calls that were made to SetUserAsAdmin.
 // Check the length with:
+//
 //     len(mockedThreeScaleInterface.SetUserAsAdminCalls())
 func (mock *ThreeScaleInterfaceMock) SetUserAsAdminCalls() []struct {
 	UserID      int
@@ -517,6 +2176,7 @@ func (mock *ThreeScaleInterfaceMock) SetUserAsMember(userID int, accessToken str
 
 // SetUserAsMemberCalls gets all the calls that were made to SetUserAsMember.
 // Check the length with:
+//
 //     len(mockedThreeScaleInterface.SetUserAsMemberCalls())
 func (mock *ThreeScaleInterfaceMock) SetUserAsMemberCalls() []struct {
 	UserID      int
@@ -532,6 +2192,170 @@ func (mock *ThreeScaleInterfaceMock) SetUserAsMemberCalls() []struct {
 	return calls
 }
 
+// UpdateApplicationPlan calls UpdateApplicationPlanFunc.
+func (mock *ThreeScaleInterfaceMock) UpdateApplicationPlan(serviceID int, planID int, data map[string]string, accessToken string) (*ApplicationPlan, error) {
+	if mock.UpdateApplicationPlanFunc == nil {
+		panic("ThreeScaleInterfaceMock.UpdateApplicationPlanFunc: method is nil but ThreeScaleInterface.UpdateApplicationPlan was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		PlanID      int
+		Data        map[string]string
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		PlanID:      planID,
+		Data:        data,
+		AccessToken: accessToken,
+	}
+	mock.lockUpdateApplicationPlan.Lock()
+	mock.calls.UpdateApplicationPlan = append(mock.calls.UpdateApplicationPlan, callInfo)
+	mock.lockUpdateApplicationPlan.Unlock()
+	return mock.UpdateApplicationPlanFunc(serviceID, planID, data, accessToken)
+}
+
+// UpdateApplicationPlanCalls gets all the calls that were made to UpdateApplicationPlan.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.UpdateApplicationPlanCalls())
+func (mock *ThreeScaleInterfaceMock) UpdateApplicationPlanCalls() []struct {
+	ServiceID   int
+	PlanID      int
+	Data        map[string]string
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		PlanID      int
+		Data        map[string]string
+		AccessToken string
+	}
+	mock.lockUpdateApplicationPlan.RLock()
+	calls = mock.calls.UpdateApplicationPlan
+	mock.lockUpdateApplicationPlan.RUnlock()
+	return calls
+}
+
+// UpdateBackendApi calls UpdateBackendApiFunc.
+func (mock *ThreeScaleInterfaceMock) UpdateBackendApi(backendID int, data map[string]string, accessToken string) (*BackendApi, error) {
+	if mock.UpdateBackendApiFunc == nil {
+		panic("ThreeScaleInterfaceMock.UpdateBackendApiFunc: method is nil but ThreeScaleInterface.UpdateBackendApi was just called")
+	}
+	callInfo := struct {
+		BackendID   int
+		Data        map[string]string
+		AccessToken string
+	}{
+		BackendID:   backendID,
+		Data:        data,
+		AccessToken: accessToken,
+	}
+	mock.lockUpdateBackendApi.Lock()
+	mock.calls.UpdateBackendApi = append(mock.calls.UpdateBackendApi, callInfo)
+	mock.lockUpdateBackendApi.Unlock()
+	return mock.UpdateBackendApiFunc(backendID, data, accessToken)
+}
+
+// UpdateBackendApiCalls gets all the calls that were made to UpdateBackendApi.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.UpdateBackendApiCalls())
+func (mock *ThreeScaleInterfaceMock) UpdateBackendApiCalls() []struct {
+	BackendID   int
+	Data        map[string]string
+	AccessToken string
+} {
+	var calls []struct {
+		BackendID   int
+		Data        map[string]string
+		AccessToken string
+	}
+	mock.lockUpdateBackendApi.RLock()
+	calls = mock.calls.UpdateBackendApi
+	mock.lockUpdateBackendApi.RUnlock()
+	return calls
+}
+
+// UpdatePolicies calls UpdatePoliciesFunc.
+func (mock *ThreeScaleInterfaceMock) UpdatePolicies(serviceID int, policies []PolicyConfig, accessToken string) (*http.Response, error) {
+	if mock.UpdatePoliciesFunc == nil {
+		panic("ThreeScaleInterfaceMock.UpdatePoliciesFunc: method is nil but ThreeScaleInterface.UpdatePolicies was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		Policies    []PolicyConfig
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		Policies:    policies,
+		AccessToken: accessToken,
+	}
+	mock.lockUpdatePolicies.Lock()
+	mock.calls.UpdatePolicies = append(mock.calls.UpdatePolicies, callInfo)
+	mock.lockUpdatePolicies.Unlock()
+	return mock.UpdatePoliciesFunc(serviceID, policies, accessToken)
+}
+
+// UpdatePoliciesCalls gets all the calls that were made to UpdatePolicies.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.UpdatePoliciesCalls())
+func (mock *ThreeScaleInterfaceMock) UpdatePoliciesCalls() []struct {
+	ServiceID   int
+	Policies    []PolicyConfig
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		Policies    []PolicyConfig
+		AccessToken string
+	}
+	mock.lockUpdatePolicies.RLock()
+	calls = mock.calls.UpdatePolicies
+	mock.lockUpdatePolicies.RUnlock()
+	return calls
+}
+
+// UpdateService calls UpdateServiceFunc.
+func (mock *ThreeScaleInterfaceMock) UpdateService(serviceID int, data map[string]string, accessToken string) (*Service, error) {
+	if mock.UpdateServiceFunc == nil {
+		panic("ThreeScaleInterfaceMock.UpdateServiceFunc: method is nil but ThreeScaleInterface.UpdateService was just called")
+	}
+	callInfo := struct {
+		ServiceID   int
+		Data        map[string]string
+		AccessToken string
+	}{
+		ServiceID:   serviceID,
+		Data:        data,
+		AccessToken: accessToken,
+	}
+	mock.lockUpdateService.Lock()
+	mock.calls.UpdateService = append(mock.calls.UpdateService, callInfo)
+	mock.lockUpdateService.Unlock()
+	return mock.UpdateServiceFunc(serviceID, data, accessToken)
+}
+
+// UpdateServiceCalls gets all the calls that were made to UpdateService.
+// Check the length with:
+//
+//     len(mockedThreeScaleInterface.UpdateServiceCalls())
+func (mock *ThreeScaleInterfaceMock) UpdateServiceCalls() []struct {
+	ServiceID   int
+	Data        map[string]string
+	AccessToken string
+} {
+	var calls []struct {
+		ServiceID   int
+		Data        map[string]string
+		AccessToken string
+	}
+	mock.lockUpdateService.RLock()
+	calls = mock.calls.UpdateService
+	mock.lockUpdateService.RUnlock()
+	return calls
+}
+
 // UpdateUser calls UpdateUserFunc.
 func (mock *ThreeScaleInterfaceMock) UpdateUser(userID int, username string, email string, accessToken string) (*http.Response, error) {
 	if mock.UpdateUserFunc == nil {
@@ -556,6 +2380,7 @@ func (mock *ThreeScaleInterfaceMock) UpdateUser(userID int, username string, ema
 
 // UpdateUserCalls gets all the calls that were made to UpdateUser.
 // Check the length with:
+//
 //     len(mockedThreeScaleInterface.UpdateUserCalls())
 func (mock *ThreeScaleInterfaceMock) UpdateUserCalls() []struct {
 	UserID      int
@@ -1,6 +1,9 @@
 package threescale
 
-import "net/http"
+import (
+	"encoding/json"
+	"net/http"
+)
 
 type Users struct {
 	Users []*User `json:"users"`
@@ -48,6 +51,152 @@ type AuthProviderDetails struct {
 	CallbackUrl                    string `json:"callback_url"`
 }
 
+type Tenant struct {
+	Signup Signup `json:"signup"`
+}
+
+type Signup struct {
+	Account     TenantAccount `json:"account"`
+	AccessToken AccessToken   `json:"access_token"`
+}
+
+type TenantAccount struct {
+	Id          int    `json:"id"`
+	State       string `json:"state"`
+	OrgName     string `json:"org_name"`
+	AdminDomain string `json:"admin_domain"`
+	Domain      string `json:"domain"`
+}
+
+// tenantAccountsPageSize is the maximum number of accounts the 3scale API
+// returns per page
+const tenantAccountsPageSize = 500
+
+type TenantAccounts struct {
+	Accounts []struct {
+		Account TenantAccount `json:"account"`
+	} `json:"accounts"`
+}
+
+type AccessToken struct {
+	Id         int      `json:"id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	Permission string   `json:"permission"`
+	Value      string   `json:"value"`
+}
+
+type BackendApis struct {
+	BackendApis []*BackendApi `json:"backend_apis"`
+}
+
+type BackendApi struct {
+	BackendApiDetails BackendApiDetails `json:"backend_api"`
+}
+
+type BackendApiDetails struct {
+	Id              int    `json:"id"`
+	Name            string `json:"name"`
+	SystemName      string `json:"system_name"`
+	Description     string `json:"description"`
+	PrivateEndpoint string `json:"private_endpoint"`
+}
+
+type Services struct {
+	Services []*Service `json:"services"`
+}
+
+type Service struct {
+	ServiceDetails ServiceDetails `json:"service"`
+}
+
+type ServiceDetails struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	SystemName  string `json:"system_name"`
+	Description string `json:"description"`
+}
+
+type BackendUsage struct {
+	BackendUsageDetails BackendUsageDetails `json:"backend_usage"`
+}
+
+type BackendUsageDetails struct {
+	Id        int    `json:"id"`
+	Path      string `json:"path"`
+	ServiceId int    `json:"service_id"`
+	BackendId int    `json:"backend_id"`
+}
+
+type Metrics struct {
+	Metrics []*Metric `json:"metrics"`
+}
+
+type Metric struct {
+	MetricDetails MetricDetails `json:"metric"`
+}
+
+type MetricDetails struct {
+	Id           int    `json:"id"`
+	Name         string `json:"name"`
+	SystemName   string `json:"system_name"`
+	FriendlyName string `json:"friendly_name"`
+}
+
+type MappingRules struct {
+	MappingRules []*MappingRule `json:"mapping_rules"`
+}
+
+type MappingRule struct {
+	MappingRuleDetails MappingRuleDetails `json:"mapping_rule"`
+}
+
+type MappingRuleDetails struct {
+	Id         int    `json:"id"`
+	MetricId   int    `json:"metric_id"`
+	Pattern    string `json:"pattern"`
+	HTTPMethod string `json:"http_method"`
+	Delta      int    `json:"delta"`
+	Position   int    `json:"position"`
+	Last       bool   `json:"last"`
+}
+
+type ApplicationPlans struct {
+	Plans []*ApplicationPlan `json:"plans"`
+}
+
+type ApplicationPlan struct {
+	ApplicationPlanDetails ApplicationPlanDetails `json:"application_plan"`
+}
+
+type ApplicationPlanDetails struct {
+	Id         int    `json:"id"`
+	Name       string `json:"name"`
+	SystemName string `json:"system_name"`
+	State      string `json:"state"`
+}
+
+type PoliciesConfig struct {
+	Policies []PolicyConfig `json:"policies_config"`
+}
+
+type PolicyConfig struct {
+	Name          string          `json:"name"`
+	Version       string          `json:"version"`
+	Configuration json.RawMessage `json:"configuration"`
+	Enabled       bool            `json:"enabled"`
+}
+
+type ProxyConfigElement struct {
+	ProxyConfig ProxyConfig `json:"proxy_config"`
+}
+
+type ProxyConfig struct {
+	Id          int    `json:"id"`
+	Version     int    `json:"version"`
+	Environment string `json:"environment"`
+}
+
 type tsError struct {
 	message    string
 	StatusCode int
@@ -57,6 +206,18 @@ func (tse *tsError) Error() string {
 	return tse.message
 }
 
+// IsNotFoundError returns true when the 3scale API responded that the
+// requested object doesn't exist
+func IsNotFoundError(e error) bool {
+	return tsIsNotFoundError(e)
+}
+
+// NewNotFoundError returns an error for which IsNotFoundError is true, to
+// mock the 3scale API responding that an object doesn't exist
+func NewNotFoundError(message string) error {
+	return &tsError{message: message, StatusCode: http.StatusNotFound}
+}
+
 func tsIsNotFoundError(e error) bool {
 	switch e := e.(type) {
 	case *tsError:
@@ -0,0 +1,151 @@
+package assets
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	// AssetsDirEnvVarKey overrides the directory of the bundles
+	AssetsDirEnvVarKey = "PRODUCT_ASSETS_DIR"
+	defaultAssetsDir   = "templates/products"
+	// ChecksumsFileName lists the sha256 checksums of the files of a bundle,
+	// in the format of sha256sum
+	ChecksumsFileName = "SHA256SUMS"
+)
+
+// Bundle is a set of product files, such as OpenShift templates and image
+// streams, vendored in the operator image by scripts/vendor-product-assets.sh
+// so the reconcilers don't depend on the availability of their upstream
+// repositories. The files keep the layout of their upstream repository
+type Bundle struct {
+	name        string
+	remoteURL   string
+	upstreamURL string
+	httpClient  *http.Client
+
+	loadChecksums sync.Once
+	checksums     map[string]string
+	checksumsErr  error
+}
+
+// GetAssetsDir returns the directory of the bundles
+func GetAssetsDir() string {
+	if dir, ok := os.LookupEnv(AssetsDirEnvVarKey); ok && dir != "" {
+		return dir
+	}
+	return defaultAssetsDir
+}
+
+// NewBundle returns the bundle called name. When remoteURL is set, the files
+// are downloaded from it instead of being read from the operator image, and
+// are verified against the checksums of the bundle so that only the files
+// the operator was released with are used. Until the bundle is vendored in
+// the image, the files are downloaded unverified from remoteURL, or from
+// upstreamURL when it isn't set
+func NewBundle(name, remoteURL, upstreamURL string, httpClient *http.Client) *Bundle {
+	return &Bundle{
+		name:        name,
+		remoteURL:   withTrailingSlash(remoteURL),
+		upstreamURL: withTrailingSlash(upstreamURL),
+		httpClient:  httpClient,
+	}
+}
+
+func withTrailingSlash(url string) string {
+	if url != "" && !strings.HasSuffix(url, "/") {
+		return url + "/"
+	}
+	return url
+}
+
+func (b *Bundle) dir() string {
+	return filepath.Join(GetAssetsDir(), b.name)
+}
+
+// ReadFile returns the content of the file at path, relative to the root of
+// the bundle
+func (b *Bundle) ReadFile(path string) ([]byte, error) {
+	b.loadChecksums.Do(func() {
+		b.checksums, b.checksumsErr = readChecksums(filepath.Join(b.dir(), ChecksumsFileName))
+	})
+	if os.IsNotExist(b.checksumsErr) {
+		// the bundle isn't vendored in the image yet
+		url := b.remoteURL
+		if url == "" {
+			url = b.upstreamURL
+		}
+		return b.download(url + path)
+	}
+	if b.checksumsErr != nil {
+		return nil, fmt.Errorf("failed to read checksums of %s bundle: %w", b.name, b.checksumsErr)
+	}
+
+	if b.remoteURL == "" {
+		content, err := ioutil.ReadFile(filepath.Join(b.dir(), path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from %s bundle: %w", path, b.name, err)
+		}
+		return content, nil
+	}
+
+	checksum, ok := b.checksums[path]
+	if !ok {
+		return nil, fmt.Errorf("%s is not part of %s bundle", path, b.name)
+	}
+
+	content, err := b.download(b.remoteURL + path)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+	if actual := hex.EncodeToString(sum[:]); actual != checksum {
+		return nil, fmt.Errorf("checksum of %s%s is %s, expected %s", b.remoteURL, path, actual, checksum)
+	}
+	return content, nil
+}
+
+func (b *Bundle) download(url string) ([]byte, error) {
+	resp, err := b.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file content from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get file content from %s. Status: %d", url, resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// readChecksums parses a file in the format of sha256sum, returning the
+// checksums keyed by path
+func readChecksums(path string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	checksums := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid checksum line %q", scanner.Text())
+		}
+		checksums[strings.TrimPrefix(fields[1], "*")] = fields[0]
+	}
+	return checksums, scanner.Err()
+}
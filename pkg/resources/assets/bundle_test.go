@@ -0,0 +1,166 @@
+package assets
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupBundle(t *testing.T, files map[string]string) func() {
+	dir, err := ioutil.TempDir("", "assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var checksums strings.Builder
+	for path, content := range files {
+		fullPath := filepath.Join(dir, "test", path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&checksums, "%x  %s\n", sha256.Sum256([]byte(content)), path)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "test", ChecksumsFileName), []byte(checksums.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Setenv(AssetsDirEnvVarKey, dir); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		os.Unsetenv(AssetsDirEnvVarKey)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestBundle_ReadFile(t *testing.T) {
+	defer setupBundle(t, map[string]string{
+		"1.0/openshift/template.yml": "kind: Template",
+		"1.0/openshift/other.yml":    "kind: Template",
+	})()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/1.0/openshift/template.yml":
+			fmt.Fprint(rw, "kind: Template")
+		case "/1.0/openshift/other.yml":
+			fmt.Fprint(rw, "kind: ImageStream")
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		Name          string
+		RemoteURL     string
+		Path          string
+		ExpectError   bool
+		ExpectContent string
+	}{
+		{
+			Name:          "test file is read from the bundle",
+			Path:          "1.0/openshift/template.yml",
+			ExpectContent: "kind: Template",
+		},
+		{
+			Name:        "test error on file missing from the bundle",
+			Path:        "1.0/openshift/missing.yml",
+			ExpectError: true,
+		},
+		{
+			Name:          "test downloaded file with a valid checksum",
+			RemoteURL:     server.URL,
+			Path:          "1.0/openshift/template.yml",
+			ExpectContent: "kind: Template",
+		},
+		{
+			Name:        "test error on downloaded file with an invalid checksum",
+			RemoteURL:   server.URL,
+			Path:        "1.0/openshift/other.yml",
+			ExpectError: true,
+		},
+		{
+			Name:        "test error on downloaded file without a checksum",
+			RemoteURL:   server.URL,
+			Path:        "1.0/openshift/missing.yml",
+			ExpectError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			content, err := NewBundle("test", tc.RemoteURL, "", server.Client()).ReadFile(tc.Path)
+			if tc.ExpectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(content) != tc.ExpectContent {
+				t.Errorf("expected content %q, got %q", tc.ExpectContent, content)
+			}
+		})
+	}
+}
+
+func TestBundle_ReadFileNotVendored(t *testing.T) {
+	dir, err := ioutil.TempDir("", "assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Setenv(AssetsDirEnvVarKey, dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv(AssetsDirEnvVarKey)
+
+	remote := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, "remote")
+	}))
+	defer remote.Close()
+	upstream := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, "upstream")
+	}))
+	defer upstream.Close()
+
+	cases := []struct {
+		Name          string
+		RemoteURL     string
+		ExpectContent string
+	}{
+		{
+			Name:          "test file is downloaded from upstream",
+			ExpectContent: "upstream",
+		},
+		{
+			Name:          "test file is downloaded from the remote url",
+			RemoteURL:     remote.URL,
+			ExpectContent: "remote",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			content, err := NewBundle("test", tc.RemoteURL, upstream.URL, http.DefaultClient).ReadFile("1.0/openshift/template.yml")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(content) != tc.ExpectContent {
+				t.Errorf("expected content %q, got %q", tc.ExpectContent, content)
+			}
+		})
+	}
+}
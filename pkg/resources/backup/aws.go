@@ -38,6 +38,10 @@ const (
 	PostgresSnapshotType AWSSnapshotType = "PostgresSnapshot"
 	// RedisSnapshotType creates RedisSnapshot CRs
 	RedisSnapshotType AWSSnapshotType = "RedisSnapshot"
+
+	// PreUpgradeSnapshotInfix is part of the name of every snapshot CR
+	// created before a product upgrade
+	PreUpgradeSnapshotInfix = "-preupgrade-snapshot-"
 )
 
 // PerformBackup creates a snapshot CR and waits until the status of the CR
@@ -45,7 +49,7 @@ const (
 func (e *AWSBackupExecutor) PerformBackup(client k8sclient.Client, timeout time.Duration) error {
 	logrus.Infof("Performing backup by creating %s for AWS resource %s", e.SnapshotType, e.ResourceName)
 
-	snapshotName := fmt.Sprintf("%s-preupgrade-snapshot-%s", e.ResourceName, time.Now().Format("2006-01-02-150405"))
+	snapshotName := fmt.Sprintf("%s%s%s", e.ResourceName, PreUpgradeSnapshotInfix, time.Now().Format("2006-01-02-150405"))
 
 	// Initialize the snapshot CR based on the snapshot type
 	var snapshotCR runtime.Object
@@ -1,16 +1,17 @@
 package constants
 
 const (
-	CodeReadyPostgresPrefix      = "codeready-postgres-"
-	ThreeScaleBackendRedisPrefix = "threescale-backend-redis-"
-	ThreeScaleSystemRedisPrefix  = "threescale-redis-"
-	ThreeScalePostgresPrefix     = "threescale-postgres-"
-	RateLimitRedisPrefix         = "ratelimit-service-redis-"
-	RHSSOPostgresPrefix          = "rhsso-postgres-"
-	RHSSOUserProstgresPrefix     = "rhssouser-postgres-"
-	UPSPostgresPrefix            = "ups-postgres-"
-	FusePostgresPrefix           = "fuse-postgres-"
-	AMQAuthServicePostgres       = "standard-authservice-postgresql"
-	ThreeScaleBlobStoragePrefix  = "threescale-blobstorage-"
-	BackupsBlobStoragePrefix     = "backups-blobstorage-"
+	CodeReadyPostgresPrefix        = "codeready-postgres-"
+	ThreeScaleBackendRedisPrefix   = "threescale-backend-redis-"
+	ThreeScaleSystemRedisPrefix    = "threescale-redis-"
+	ThreeScalePostgresPrefix       = "threescale-postgres-"
+	RateLimitRedisPrefix           = "ratelimit-service-redis-"
+	RHSSOPostgresPrefix            = "rhsso-postgres-"
+	RHSSOUserProstgresPrefix       = "rhssouser-postgres-"
+	UPSPostgresPrefix              = "ups-postgres-"
+	FusePostgresPrefix             = "fuse-postgres-"
+	AMQAuthServicePostgres         = "standard-authservice-postgresql"
+	ThreeScaleBlobStoragePrefix    = "threescale-blobstorage-"
+	BackupsBlobStoragePrefix       = "backups-blobstorage-"
+	ApicurioRegistryPostgresPrefix = "apicurio-registry-postgres-"
 )
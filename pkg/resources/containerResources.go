@@ -0,0 +1,33 @@
+package resources
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MutateContainerResources creates a PodTemplateMutation that sets the
+// resource requirements of the containers of the pod, keyed by container
+// name. Containers not listed are left untouched, and an error is returned
+// when a listed container is not found in the pod
+func MutateContainerResources(requirements map[string]corev1.ResourceRequirements) PodTemplateMutation {
+	return func(obj metav1.Object, podTemplate *corev1.PodTemplateSpec) error {
+		found := map[string]bool{}
+		for i, container := range podTemplate.Spec.Containers {
+			containerRequirements, ok := requirements[container.Name]
+			if !ok {
+				continue
+			}
+			podTemplate.Spec.Containers[i].Resources = *containerRequirements.DeepCopy()
+			found[container.Name] = true
+		}
+
+		for name := range requirements {
+			if !found[name] {
+				return fmt.Errorf("container %s not found in %s", name, obj.GetName())
+			}
+		}
+		return nil
+	}
+}
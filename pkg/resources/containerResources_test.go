@@ -0,0 +1,69 @@
+package resources
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	openshiftappsv1 "github.com/openshift/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMutateContainerResources(t *testing.T) {
+	requirements := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	sidecarRequirements := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+	}
+
+	scenarios := []struct {
+		Name          string
+		Requirements  map[string]corev1.ResourceRequirements
+		ExpectedError string
+		Expected      []corev1.ResourceRequirements
+	}{
+		{
+			Name:         "Test only the listed containers are updated",
+			Requirements: map[string]corev1.ResourceRequirements{"system-master": requirements},
+			Expected:     []corev1.ResourceRequirements{requirements, sidecarRequirements},
+		},
+		{
+			Name:          "Test error on unknown container",
+			Requirements:  map[string]corev1.ResourceRequirements{"system-app": requirements},
+			ExpectedError: "container system-app not found in system-app",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			obj := &openshiftappsv1.DeploymentConfig{ObjectMeta: v1.ObjectMeta{Name: "system-app"}}
+			podTemplate := &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "system-master"},
+						{Name: "system-provider", Resources: sidecarRequirements},
+					},
+				},
+			}
+
+			err := MutateContainerResources(scenario.Requirements)(obj, podTemplate)
+			if scenario.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), scenario.ExpectedError) {
+					t.Fatalf("expected error containing %q, got %v", scenario.ExpectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for i, container := range podTemplate.Spec.Containers {
+				if !reflect.DeepEqual(container.Resources, scenario.Expected[i]) {
+					t.Errorf("unexpected resources of container %s: %+v", container.Name, container.Resources)
+				}
+			}
+		})
+	}
+}
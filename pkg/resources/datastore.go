@@ -0,0 +1,243 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	crov1 "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croTypes "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	croUtil "github.com/integr8ly/cloud-resource-operator/pkg/client"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/owner"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+type DatastoreType string
+
+const (
+	DatastorePostgres DatastoreType = "postgres"
+	DatastoreRedis    DatastoreType = "redis"
+
+	datastoreDialTimeout = 5 * time.Second
+)
+
+// Datastore is a Postgres database or Redis cache used by a product,
+// regardless of how it is provided
+type Datastore struct {
+	Name        string
+	Namespace   string
+	ProductName string
+	Type        DatastoreType
+
+	// Ready is true when the connection details can be used
+	Ready bool
+	// SecretRef is the secret with the connection details of the datastore,
+	// in the layout of the secrets created by the cloud resource operator:
+	// host, port, username, password and database for Postgres, and uri and
+	// port for Redis. It's only set when the datastore is ready
+	SecretRef *croTypes.SecretRef
+
+	// Postgres and Redis are the custom resources of the datastores
+	// provisioned by the cloud resource operator
+	Postgres *crov1.Postgres
+	Redis    *crov1.Redis
+}
+
+// DatastoreProvider provides the datastores of the products. The datastores
+// are named after their prefix and the installation name, and are created in
+// the installation namespace
+type DatastoreProvider interface {
+	ReconcilePostgres(ctx context.Context, serverClient k8sclient.Client, productName, prefix string) (*Datastore, error)
+	ReconcileRedis(ctx context.Context, serverClient k8sclient.Client, productName, prefix string) (*Datastore, error)
+	// ReconcileAlerts creates the alerts of the datastore. It returns
+	// PhaseCompleted once the datastore is ready
+	ReconcileAlerts(ctx context.Context, serverClient k8sclient.Client, ds *Datastore) (integreatlyv1alpha1.StatusPhase, error)
+}
+
+// NewDatastoreProvider returns the provider of the datastores configured in
+// the installation
+func NewDatastoreProvider(installation *integreatlyv1alpha1.RHMI) DatastoreProvider {
+	if IsBYODatastores(installation) {
+		return &byoDatastoreProvider{installation: installation, dial: net.DialTimeout}
+	}
+	return &croDatastoreProvider{installation: installation}
+}
+
+// IsBYODatastores returns true if the installation uses existing datastores
+// rather than datastores provisioned by the cloud resource operator, which
+// can't be snapshotted before upgrades
+func IsBYODatastores(installation *integreatlyv1alpha1.RHMI) bool {
+	spec := installation.Spec.Datastores
+	return spec != nil && spec.Provider == integreatlyv1alpha1.DatastoreProviderBYO
+}
+
+// croDatastoreProvider provisions the datastores with the cloud resource
+// operator, using the strategies of the installation type
+type croDatastoreProvider struct {
+	installation *integreatlyv1alpha1.RHMI
+}
+
+func (p *croDatastoreProvider) ReconcilePostgres(ctx context.Context, serverClient k8sclient.Client, productName, prefix string) (*Datastore, error) {
+	name := fmt.Sprintf("%s%s", prefix, p.installation.Name)
+	ns := p.installation.Namespace
+	postgres, err := croUtil.ReconcilePostgres(ctx, serverClient, productName, p.installation.Spec.Type, croUtil.TierProduction, name, ns, name, ns, func(cr metav1.Object) error {
+		owner.AddIntegreatlyOwnerAnnotations(cr, p.installation)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ds := &Datastore{
+		Name:        name,
+		Namespace:   ns,
+		ProductName: productName,
+		Type:        DatastorePostgres,
+		Ready:       postgres.Status.Phase == croTypes.PhaseComplete,
+		Postgres:    postgres,
+	}
+	if ds.Ready {
+		ds.SecretRef = postgres.Status.SecretRef
+	}
+	return ds, nil
+}
+
+func (p *croDatastoreProvider) ReconcileRedis(ctx context.Context, serverClient k8sclient.Client, productName, prefix string) (*Datastore, error) {
+	name := fmt.Sprintf("%s%s", prefix, p.installation.Name)
+	ns := p.installation.Namespace
+	redis, err := croUtil.ReconcileRedis(ctx, serverClient, productName, p.installation.Spec.Type, croUtil.TierProduction, name, ns, name, ns, func(cr metav1.Object) error {
+		owner.AddIntegreatlyOwnerAnnotations(cr, p.installation)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ds := &Datastore{
+		Name:        name,
+		Namespace:   ns,
+		ProductName: productName,
+		Type:        DatastoreRedis,
+		Ready:       redis.Status.Phase == croTypes.PhaseComplete,
+		Redis:       redis,
+	}
+	if ds.Ready {
+		ds.SecretRef = redis.Status.SecretRef
+	}
+	return ds, nil
+}
+
+func (p *croDatastoreProvider) ReconcileAlerts(ctx context.Context, serverClient k8sclient.Client, ds *Datastore) (integreatlyv1alpha1.StatusPhase, error) {
+	if ds.Redis != nil {
+		return ReconcileRedisAlerts(ctx, serverClient, p.installation, ds.Redis)
+	}
+	return ReconcilePostgresAlerts(ctx, serverClient, p.installation, ds.Postgres)
+}
+
+// byoDatastoreProvider uses existing datastores. Their connection details are
+// read from the datastores secret of the installation, copied to a secret in
+// the layout of the cloud resource operator, and validated by connecting to
+// the datastore
+type byoDatastoreProvider struct {
+	installation *integreatlyv1alpha1.RHMI
+	dial         func(network, address string, timeout time.Duration) (net.Conn, error)
+}
+
+func (p *byoDatastoreProvider) ReconcilePostgres(ctx context.Context, serverClient k8sclient.Client, productName, prefix string) (*Datastore, error) {
+	return p.reconcileDatastore(ctx, serverClient, DatastorePostgres, productName, prefix, map[string]string{
+		"host":     "host",
+		"port":     "port",
+		"username": "username",
+		"password": "password",
+		"database": "database",
+	})
+}
+
+func (p *byoDatastoreProvider) ReconcileRedis(ctx context.Context, serverClient k8sclient.Client, productName, prefix string) (*Datastore, error) {
+	return p.reconcileDatastore(ctx, serverClient, DatastoreRedis, productName, prefix, map[string]string{
+		"host": "uri",
+		"port": "port",
+	})
+}
+
+func (p *byoDatastoreProvider) ReconcileAlerts(ctx context.Context, serverClient k8sclient.Client, ds *Datastore) (integreatlyv1alpha1.StatusPhase, error) {
+	return ReconcileBYODatastoreAlerts(ctx, serverClient, ds)
+}
+
+// reconcileDatastore copies the keys of the datastore from the datastores
+// secret to the connection secret of the datastore, renamed as set in keys
+func (p *byoDatastoreProvider) reconcileDatastore(ctx context.Context, serverClient k8sclient.Client, dsType DatastoreType, productName, prefix string, keys map[string]string) (*Datastore, error) {
+	ds := &Datastore{
+		Name:        fmt.Sprintf("%s%s", prefix, p.installation.Name),
+		Namespace:   p.installation.Namespace,
+		ProductName: productName,
+		Type:        dsType,
+	}
+	available := metrics.BYODatastoreAvailable.WithLabelValues(ds.Name, productName, string(dsType))
+	connection := metrics.BYODatastoreConnection.WithLabelValues(ds.Name, productName, string(dsType))
+
+	secretName := p.installation.Spec.Datastores.SecretRef
+	if secretName == "" {
+		return nil, fmt.Errorf("the datastores secret must be set when the datastores provider is %s", integreatlyv1alpha1.DatastoreProviderBYO)
+	}
+	secret := &corev1.Secret{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: secretName, Namespace: p.installation.Namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get datastores secret %s: %w", secretName, err)
+	}
+
+	keyPrefix := strings.TrimSuffix(prefix, "-") + "."
+	data := map[string][]byte{}
+	var missing []string
+	for key, croKey := range keys {
+		value := secret.Data[keyPrefix+key]
+		if len(value) == 0 {
+			missing = append(missing, keyPrefix+key)
+			continue
+		}
+		data[croKey] = value
+	}
+	if len(missing) > 0 {
+		logrus.Errorf("The datastores secret %s is missing the keys %s of datastore %s", secretName, strings.Join(missing, ", "), ds.Name)
+		available.Set(0)
+		connection.Set(0)
+		return ds, nil
+	}
+	available.Set(1)
+
+	address := net.JoinHostPort(string(secret.Data[keyPrefix+"host"]), string(secret.Data[keyPrefix+"port"]))
+	conn, err := p.dial("tcp", address, datastoreDialTimeout)
+	if err != nil {
+		logrus.Errorf("Failed to connect to datastore %s at %s: %v", ds.Name, address, err)
+		connection.Set(0)
+		return ds, nil
+	}
+	conn.Close()
+	connection.Set(1)
+
+	connSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ds.Name,
+			Namespace: ds.Namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, serverClient, connSecret, func() error {
+		owner.AddIntegreatlyOwnerAnnotations(connSecret, p.installation)
+		connSecret.Data = data
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create or update connection secret of datastore %s: %w", ds.Name, err)
+	}
+
+	ds.Ready = true
+	ds.SecretRef = &croTypes.SecretRef{Name: connSecret.Name, Namespace: connSecret.Namespace}
+	return ds, nil
+}
@@ -0,0 +1,139 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBYODatastoreProvider(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := monitoringv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rhmi",
+			Namespace: "redhat-rhmi-operator",
+		},
+		Spec: integreatlyv1alpha1.RHMISpec{
+			Datastores: &integreatlyv1alpha1.DatastoresSpec{
+				Provider:  integreatlyv1alpha1.DatastoreProviderBYO,
+				SecretRef: "datastores",
+			},
+		},
+	}
+	datastoresSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "datastores",
+			Namespace: "redhat-rhmi-operator",
+		},
+		Data: map[string][]byte{
+			"threescale-postgres.host":      []byte("postgres.example.com"),
+			"threescale-postgres.port":      []byte("5432"),
+			"threescale-postgres.username":  []byte("user"),
+			"threescale-postgres.password":  []byte("password"),
+			"threescale-postgres.database":  []byte("system"),
+			"threescale-backend-redis.host": []byte("redis.example.com"),
+		},
+	}
+
+	scenarios := []struct {
+		Name          string
+		Reconcile     func(DatastoreProvider, k8sclient.Client) (*Datastore, error)
+		Dial          func(network, address string, timeout time.Duration) (net.Conn, error)
+		ExpectedPhase integreatlyv1alpha1.StatusPhase
+		Assertion     func(*testing.T, k8sclient.Client, *Datastore)
+	}{
+		{
+			Name: "Connection secret is created for reachable datastore",
+			Reconcile: func(p DatastoreProvider, client k8sclient.Client) (*Datastore, error) {
+				return p.ReconcilePostgres(context.TODO(), client, "3scale", "threescale-postgres-")
+			},
+			Dial: func(network, address string, timeout time.Duration) (net.Conn, error) {
+				if address != "postgres.example.com:5432" {
+					t.Errorf("unexpected address %s", address)
+				}
+				client, server := net.Pipe()
+				server.Close()
+				return client, nil
+			},
+			ExpectedPhase: integreatlyv1alpha1.PhaseCompleted,
+			Assertion: func(t *testing.T, client k8sclient.Client, ds *Datastore) {
+				secret := &corev1.Secret{}
+				if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: ds.SecretRef.Name, Namespace: ds.SecretRef.Namespace}, secret); err != nil {
+					t.Fatalf("expected connection secret to be created: %v", err)
+				}
+				if ds.SecretRef.Name != "threescale-postgres-rhmi" || string(secret.Data["host"]) != "postgres.example.com" || string(secret.Data["database"]) != "system" {
+					t.Errorf("unexpected connection secret %s with data %v", ds.SecretRef.Name, secret.Data)
+				}
+			},
+		},
+		{
+			Name: "Datastore is not ready when it can't be reached",
+			Reconcile: func(p DatastoreProvider, client k8sclient.Client) (*Datastore, error) {
+				return p.ReconcilePostgres(context.TODO(), client, "3scale", "threescale-postgres-")
+			},
+			Dial: func(network, address string, timeout time.Duration) (net.Conn, error) {
+				return nil, errors.New("connection refused")
+			},
+			ExpectedPhase: integreatlyv1alpha1.PhaseAwaitingCloudResources,
+		},
+		{
+			Name: "Datastore is not ready when its connection details are missing",
+			Reconcile: func(p DatastoreProvider, client k8sclient.Client) (*Datastore, error) {
+				return p.ReconcileRedis(context.TODO(), client, "3scale", "threescale-backend-redis-")
+			},
+			Dial: func(network, address string, timeout time.Duration) (net.Conn, error) {
+				t.Errorf("unexpected connection to %s", address)
+				return nil, errors.New("unexpected connection")
+			},
+			ExpectedPhase: integreatlyv1alpha1.PhaseAwaitingCloudResources,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			client := fake.NewFakeClientWithScheme(scheme, datastoresSecret.DeepCopy())
+			provider := &byoDatastoreProvider{installation: installation, dial: scenario.Dial}
+
+			ds, err := scenario.Reconcile(provider, client)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			phase, err := provider.ReconcileAlerts(context.TODO(), client, ds)
+			if err != nil {
+				t.Fatalf("unexpected error reconciling alerts: %v", err)
+			}
+			if phase != scenario.ExpectedPhase {
+				t.Errorf("expected phase %s, got %s", scenario.ExpectedPhase, phase)
+			}
+
+			// The alerts are created whether or not the datastore is ready
+			for _, ruleName := range []string{"availability-rule-" + ds.Name, "connectivity-rule-" + ds.Name} {
+				rule := &monitoringv1.PrometheusRule{}
+				if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: ruleName, Namespace: installation.Namespace}, rule); err != nil {
+					t.Errorf("expected rule %s to be created: %v", ruleName, err)
+				}
+			}
+
+			if scenario.Assertion != nil {
+				scenario.Assertion(t, client, ds)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	defaultAutoscalingMinReplicas    int32 = 2
+	defaultAutoscalingCPUUtilization int32 = 80
+)
+
+// GetAutoscalingMinReplicas returns the minimum number of replicas of the
+// autoscaling spec, or its default if not set
+func GetAutoscalingMinReplicas(spec *integreatlyv1alpha1.HorizontalAutoscaling) int32 {
+	if spec.MinReplicas != nil {
+		return *spec.MinReplicas
+	}
+	return defaultAutoscalingMinReplicas
+}
+
+// ReconcileHorizontalPodAutoscaler creates or updates the
+// HorizontalPodAutoscaler of the target from the autoscaling spec. If spec is
+// nil, the HorizontalPodAutoscaler is removed if it exists
+func ReconcileHorizontalPodAutoscaler(ctx context.Context, client k8sclient.Client, namespace string, target autoscalingv2beta2.CrossVersionObjectReference, spec *integreatlyv1alpha1.HorizontalAutoscaling) (integreatlyv1alpha1.StatusPhase, error) {
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      target.Name,
+			Namespace: namespace,
+		},
+	}
+
+	if spec == nil {
+		if err := client.Delete(ctx, hpa); err != nil && !k8serr.IsNotFound(err) {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to delete horizontal pod autoscaler %s: %w", target.Name, err)
+		}
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	minReplicas := GetAutoscalingMinReplicas(spec)
+	if spec.MaxReplicas < minReplicas {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("max replicas %d of %s autoscaling is lower than min replicas %d", spec.MaxReplicas, target.Name, minReplicas)
+	}
+
+	if _, err := controllerutil.CreateOrUpdate(ctx, client, hpa, func() error {
+		hpa.Spec.ScaleTargetRef = target
+		hpa.Spec.MinReplicas = &minReplicas
+		hpa.Spec.MaxReplicas = spec.MaxReplicas
+		hpa.Spec.Metrics = []autoscalingv2beta2.MetricSpec{getCPUUtilizationMetric(spec)}
+		return nil
+	}); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create or update horizontal pod autoscaler %s: %w", target.Name, err)
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+func getCPUUtilizationMetric(spec *integreatlyv1alpha1.HorizontalAutoscaling) autoscalingv2beta2.MetricSpec {
+	cpuUtilization := defaultAutoscalingCPUUtilization
+	if spec.TargetCPUUtilization != nil {
+		cpuUtilization = *spec.TargetCPUUtilization
+	}
+
+	return autoscalingv2beta2.MetricSpec{
+		Type: autoscalingv2beta2.ResourceMetricSourceType,
+		Resource: &autoscalingv2beta2.ResourceMetricSource{
+			Name: corev1.ResourceCPU,
+			Target: autoscalingv2beta2.MetricTarget{
+				Type:               autoscalingv2beta2.UtilizationMetricType,
+				AverageUtilization: &cpuUtilization,
+			},
+		},
+	}
+}
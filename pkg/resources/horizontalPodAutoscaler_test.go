@@ -0,0 +1,121 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileHorizontalPodAutoscaler(t *testing.T) {
+	scheme := testScheme()
+	autoscalingv2beta2.AddToScheme(scheme)
+
+	target := autoscalingv2beta2.CrossVersionObjectReference{
+		APIVersion: "apps.openshift.io/v1",
+		Kind:       "DeploymentConfig",
+		Name:       "apicast-production",
+	}
+	existing := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "apicast-production",
+			Namespace: "redhat-test-3scale",
+		},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: target,
+			MaxReplicas:    4,
+		},
+	}
+
+	scenarios := []struct {
+		Name        string
+		InitObjs    []runtime.Object
+		Spec        *integreatlyv1alpha1.HorizontalAutoscaling
+		ExpectedErr bool
+		ExpectedHPA bool
+		MinReplicas int32
+		MaxReplicas int32
+		Metrics     []autoscalingv2beta2.MetricSourceType
+	}{
+		{
+			Name:        "Defaults to CPU utilization",
+			Spec:        &integreatlyv1alpha1.HorizontalAutoscaling{MaxReplicas: 4},
+			ExpectedHPA: true,
+			MinReplicas: 2,
+			MaxReplicas: 4,
+			Metrics:     []autoscalingv2beta2.MetricSourceType{autoscalingv2beta2.ResourceMetricSourceType},
+		},
+		{
+			Name:     "Updates existing autoscaler",
+			InitObjs: []runtime.Object{existing.DeepCopy()},
+			Spec: &integreatlyv1alpha1.HorizontalAutoscaling{
+				MinReplicas:          &[]int32{3}[0],
+				MaxReplicas:          8,
+				TargetCPUUtilization: &[]int32{60}[0],
+			},
+			ExpectedHPA: true,
+			MinReplicas: 3,
+			MaxReplicas: 8,
+			Metrics:     []autoscalingv2beta2.MetricSourceType{autoscalingv2beta2.ResourceMetricSourceType},
+		},
+		{
+			Name:        "Fails when max replicas is lower than min replicas",
+			Spec:        &integreatlyv1alpha1.HorizontalAutoscaling{MaxReplicas: 1},
+			ExpectedErr: true,
+		},
+		{
+			Name:     "Removes autoscaler when not set",
+			InitObjs: []runtime.Object{existing.DeepCopy()},
+		},
+		{
+			Name: "Nothing to remove when not set",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			client := fake.NewFakeClientWithScheme(scheme, scenario.InitObjs...)
+
+			phase, err := ReconcileHorizontalPodAutoscaler(context.TODO(), client, "redhat-test-3scale", target, scenario.Spec)
+			if scenario.ExpectedErr {
+				if err == nil || phase != integreatlyv1alpha1.PhaseFailed {
+					t.Fatalf("expected failure, got phase %s and error %v", phase, err)
+				}
+				return
+			}
+			if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+				t.Fatalf("unexpected phase %s and error %v", phase, err)
+			}
+
+			hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+			err = client.Get(context.TODO(), k8sclient.ObjectKey{Name: target.Name, Namespace: "redhat-test-3scale"}, hpa)
+			if !scenario.ExpectedHPA {
+				if !k8serr.IsNotFound(err) {
+					t.Fatalf("expected horizontal pod autoscaler to be removed, got error %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error getting horizontal pod autoscaler: %v", err)
+			}
+
+			if *hpa.Spec.MinReplicas != scenario.MinReplicas || hpa.Spec.MaxReplicas != scenario.MaxReplicas {
+				t.Errorf("unexpected replicas %d-%d, expected %d-%d", *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas, scenario.MinReplicas, scenario.MaxReplicas)
+			}
+			if len(hpa.Spec.Metrics) != len(scenario.Metrics) {
+				t.Fatalf("unexpected metrics %v", hpa.Spec.Metrics)
+			}
+			for i, metric := range hpa.Spec.Metrics {
+				if metric.Type != scenario.Metrics[i] {
+					t.Errorf("unexpected metric type %s, expected %s", metric.Type, scenario.Metrics[i])
+				}
+			}
+		})
+	}
+}
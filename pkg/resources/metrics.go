@@ -14,6 +14,8 @@
 //  * Redis high memory usage for the last hour (per 3scale redis)
 //  * Postgres will run out of space in 4 days (per product)
 //  * Postgres will run out of space in 4 hours (per product)
+//  * BYO Datastore Availability Alerts (per BYO datastore)
+//  * BYO Datastore Connectivity Alerts (per BYO datastore)
 
 package resources
 
@@ -153,6 +155,45 @@ func ReconcileRedisAlerts(ctx context.Context, client k8sclient.Client, inst *v1
 	return v1alpha1.PhaseCompleted, nil
 }
 
+// ReconcileBYODatastoreAlerts creates the availability and connectivity alerts
+// of a datastore that isn't provisioned by the cloud resource operator. The
+// alerts watch the metrics exposed by the operator when it validates the
+// datastore
+func ReconcileBYODatastoreAlerts(ctx context.Context, client k8sclient.Client, ds *Datastore) (v1alpha1.StatusPhase, error) {
+	engine := "Postgres"
+	availabilitySOP, connectivitySOP := sopUrlPostgresInstanceUnavailable, sopUrlPostgresConnectionFailed
+	if ds.Type == DatastoreRedis {
+		engine = "Redis"
+		availabilitySOP, connectivitySOP = sopUrlRedisCacheUnavailable, sopUrlRedisConnectionFailed
+	}
+
+	selector := fmt.Sprintf("resourceID='%s',productName='%s',type='%s'", ds.Name, ds.ProductName, ds.Type)
+	labels := map[string]string{
+		"severity":    "critical",
+		"productName": ds.ProductName,
+	}
+	alertPrefix := strings.Title(strings.Replace(ds.Name, "-", "", -1))
+
+	alertExp := intstr.FromString(fmt.Sprintf("absent(rhmi_byo_datastore_available{%s} == 1)", selector))
+	alertDescription := fmt.Sprintf("The connection details of the BYO %s datastore: '%s' for product: %s are missing from the datastores secret", ds.Type, ds.Name, ds.ProductName)
+	_, err := reconcilePrometheusRule(ctx, client, fmt.Sprintf("availability-rule-%s", ds.Name), ds.Namespace, alertPrefix+"BYO"+engine+"Unavailable", alertDescription, availabilitySOP, alertFor5Mins, alertExp, labels)
+	if err != nil {
+		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create availability alert for %s: %w", ds.Name, err)
+	}
+
+	alertExp = intstr.FromString(fmt.Sprintf("absent(rhmi_byo_datastore_connection{%s} == 1)", selector))
+	alertDescription = fmt.Sprintf("Unable to connect to the BYO %s datastore: '%s' for product: %s", ds.Type, ds.Name, ds.ProductName)
+	_, err = reconcilePrometheusRule(ctx, client, fmt.Sprintf("connectivity-rule-%s", ds.Name), ds.Namespace, alertPrefix+"BYO"+engine+"ConnectionFailed", alertDescription, connectivitySOP, alertFor5Mins, alertExp, labels)
+	if err != nil {
+		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create connectivity alert for %s: %w", ds.Name, err)
+	}
+
+	if !ds.Ready {
+		return v1alpha1.PhaseAwaitingCloudResources, nil
+	}
+	return v1alpha1.PhaseCompleted, nil
+}
+
 // CreateSmtpSecretExists creates a PrometheusRule to alert if the rhmi-smtp-secret is present
 // the ocm sendgrid service creates a secret automatically this is a check for when that service fails
 func CreateSmtpSecretExists(ctx context.Context, client k8sclient.Client, cr *v1alpha1.RHMI) (v1alpha1.StatusPhase, error) {
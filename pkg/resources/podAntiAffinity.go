@@ -3,9 +3,8 @@ package resources
 import (
 	"context"
 	"fmt"
-	"os"
-	"strconv"
 
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,20 +14,13 @@ import (
 const (
 	// ZoneLabel is the label that specifies the zone where a node is
 	ZoneLabel = "topology.kubernetes.io/zone"
-	// AntiAffinityRequiredEnvVar is an environment variable that, when set to
-	// true, makes the product pod replicas use "required" anti affinity rules
-	// by AZ
-	AntiAffinityRequiredEnvVar = "FORCE_ZONE_DISTRIBUTION"
 )
 
 // MutateMultiAZAntiAffinity returns a PodTemplateMutation that sets the anti
-// affinity by AZ on the label labelMatch. It checks if the affinity rule is
-// required or not, and sets the required or preferred affinity based on it
-func MutateMultiAZAntiAffinity(ctx context.Context, client k8sclient.Client, labelMatch string) PodTemplateMutation {
-	isRequired, err := IsAntiAffinityRequired(ctx, client)
-	if err != nil {
-		isRequired = false
-	}
+// affinity by AZ on the label labelMatch. The affinity is required or
+// preferred based on the zone spread of the topology policy
+func MutateMultiAZAntiAffinity(policy integreatlyv1alpha1.TopologyPolicy, labelMatch string) PodTemplateMutation {
+	isRequired := IsAntiAffinityRequired(policy)
 
 	return func(obj metav1.Object, podTemplate *corev1.PodTemplateSpec) error {
 		labels := obj.GetLabels()
@@ -94,16 +86,9 @@ func SelectAntiAffinityForCluster(required bool, matchLabels map[string]string)
 }
 
 // IsAntiAffinityRequired checks whether the anti affinity rule must be set
-// to required or preferred.
-//
-// It currently checks the value of the FORCE_ZONE_DISTRIBUTION bool env var
-func IsAntiAffinityRequired(_ context.Context, _ k8sclient.Client) (bool, error) {
-	envValue, ok := os.LookupEnv(AntiAffinityRequiredEnvVar)
-	if !ok {
-		return false, nil
-	}
-
-	return strconv.ParseBool(envValue)
+// to required or preferred for the topology policy
+func IsAntiAffinityRequired(policy integreatlyv1alpha1.TopologyPolicy) bool {
+	return policy.ZoneSpread == integreatlyv1alpha1.ZoneSpreadRequired
 }
 
 // IsMultiAZCluster checks if the cluster runs in multiple AZs, by retrieving
@@ -9,6 +9,9 @@ import (
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// DeleteEnvoyConfigsInNamespaces deletes the EnvoyConfigs of all the envoy
+// sidecars in namespaces, those of the APIcasts as well as those of the
+// 3scale management APIs
 func DeleteEnvoyConfigsInNamespaces(ctx context.Context, client k8sclient.Client, namespaces ...string) (integreatlyv1alpha1.StatusPhase, error) {
 	phase := integreatlyv1alpha1.PhaseCompleted
 
@@ -50,3 +53,17 @@ func DeleteEnvoyConfigsInNamespace(ctx context.Context, client k8sclient.Client,
 
 	return integreatlyv1alpha1.PhaseInProgress, nil
 }
+
+// DeleteEnvoyConfig deletes the EnvoyConfig of a single envoy sidecar, when
+// its rate limiting is disabled
+func DeleteEnvoyConfig(ctx context.Context, client k8sclient.Client, namespace, name string) error {
+	envoyConfig := &marin3rv1alpha1.EnvoyConfig{}
+	envoyConfig.Name = name
+	envoyConfig.Namespace = namespace
+
+	if err := k8sclient.IgnoreNotFound(client.Delete(ctx, envoyConfig)); err != nil {
+		return fmt.Errorf("failed to delete envoyconfig %s in namespace %s: %v", name, namespace, err)
+	}
+
+	return nil
+}
@@ -3,18 +3,13 @@ package resources
 import (
 	"context"
 	"fmt"
-	crov1 "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
-	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/owner"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 
-	croUtil "github.com/integr8ly/cloud-resource-operator/pkg/client"
-
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -28,23 +23,19 @@ const (
 	databaseSecretKeySuperuser = "POSTGRES_SUPERUSER"
 )
 
-//ReconcileRHSSOPostgresCredentials Provisions postgres and creates external database secret based on Installation CR, secret will be nil while the postgres instance is provisioning
-func ReconcileRHSSOPostgresCredentials(ctx context.Context, installation *integreatlyv1alpha1.RHMI, serverClient k8sclient.Client, name, ns, nsPostfix string) (*crov1.Postgres, error) {
-	postgresNS := installation.Namespace
-	postgres, err := croUtil.ReconcilePostgres(ctx, serverClient, nsPostfix, installation.Spec.Type, croUtil.TierProduction, name, postgresNS, name, postgresNS, func(cr metav1.Object) error {
-		owner.AddIntegreatlyOwnerAnnotations(cr, installation)
-		return nil
-	})
+//ReconcileRHSSOPostgresCredentials Provides postgres and creates external database secret based on Installation CR, the datastore is not ready while the postgres instance is provisioning
+func ReconcileRHSSOPostgresCredentials(ctx context.Context, installation *integreatlyv1alpha1.RHMI, serverClient k8sclient.Client, prefix, ns, nsPostfix string) (*Datastore, error) {
+	postgres, err := NewDatastoreProvider(installation).ReconcilePostgres(ctx, serverClient, nsPostfix, prefix)
 	if err != nil {
-		return nil, fmt.Errorf("failed to provision postgres instance while reconciling rhsso postgres credentials, %s: %w", name, err)
+		return nil, fmt.Errorf("failed to provision postgres instance while reconciling rhsso postgres credentials, %s%s: %w", prefix, installation.Name, err)
 	}
-	if postgres.Status.Phase != types.PhaseComplete {
-		return nil, nil
+	if !postgres.Ready {
+		return postgres, nil
 	}
 	postgresSec := &corev1.Secret{}
-	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: postgres.Status.SecretRef.Name, Namespace: postgres.Status.SecretRef.Namespace}, postgresSec)
+	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: postgres.SecretRef.Name, Namespace: postgres.SecretRef.Namespace}, postgresSec)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get postgres credential secret while reconciling rhsso postgres credentials, %s: %w", name, err)
+		return nil, fmt.Errorf("failed to get postgres credential secret while reconciling rhsso postgres credentials, %s: %w", postgres.Name, err)
 	}
 	// create secret using the default name which the keycloak operator expects
 	keycloakSec := &corev1.Secret{
@@ -68,7 +59,7 @@ func ReconcileRHSSOPostgresCredentials(ctx context.Context, installation *integr
 		return nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create keycloak external database secret, %s: %w", name, err)
+		return nil, fmt.Errorf("failed to create keycloak external database secret, %s: %w", postgres.Name, err)
 	}
 	return postgres, nil
 }
@@ -44,7 +44,7 @@ func TestReconcileRHSSOPostgresCredentials(t *testing.T) {
 	//completed postgres that points at the secret croPostgresSecret
 	croPostgres := &crov1.Postgres{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "test",
+			Name:      "rhsso-postgres-test",
 			Namespace: defaultOperatorNamespace,
 		},
 		Status: crov1.PostgresStatus{
@@ -76,15 +76,15 @@ func TestReconcileRHSSOPostgresCredentials(t *testing.T) {
 
 	tests := []struct {
 		name         string
-		postgresName string
+		prefix       string
 		installation *integreatlyv1alpha1.RHMI
 		fakeClient   func() k8sclient.Client
-		want         *crov1.Postgres
+		want         *Datastore
 		wantErr      bool
 	}{
 		{
 			name:         "error returned when postgres cannot be provisioned",
-			postgresName: "test",
+			prefix:       "rhsso-postgres-",
 			installation: installation,
 			fakeClient: func() k8sclient.Client {
 				mockClient := moqclient.NewSigsClientMoqWithScheme(scheme, croPostgres)
@@ -96,19 +96,22 @@ func TestReconcileRHSSOPostgresCredentials(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:         "nil returned when postgres phase is not complete",
-			postgresName: "test",
+			name:         "datastore not ready returned when postgres phase is not complete",
+			prefix:       "rhsso-postgres-",
 			installation: installation,
 			fakeClient: func() k8sclient.Client {
 				pendingPostgres := croPostgres.DeepCopy()
 				pendingPostgres.Status.Phase = croTypes.PhaseInProgress
 				return moqclient.NewSigsClientMoqWithScheme(scheme, pendingPostgres)
 			},
-			want: nil,
+			want: &Datastore{
+				Name:  "rhsso-postgres-test",
+				Ready: false,
+			},
 		},
 		{
 			name:         "error returned when postgres credential secret cannot be found",
-			postgresName: "test",
+			prefix:       "rhsso-postgres-",
 			installation: installation,
 			fakeClient: func() k8sclient.Client {
 				mockClient := moqclient.NewSigsClientMoqWithScheme(scheme, croPostgres)
@@ -121,31 +124,22 @@ func TestReconcileRHSSOPostgresCredentials(t *testing.T) {
 		},
 		{
 			name:         "postgres with expected config returned on successful reconcile",
-			postgresName: "test",
+			prefix:       "rhsso-postgres-",
 			installation: installation,
 			fakeClient: func() k8sclient.Client {
 				mockClient := moqclient.NewSigsClientMoqWithScheme(scheme, croPostgres, croPostgresSecret)
 				return mockClient
 			},
-			want: &crov1.Postgres{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "test",
-					Namespace: defaultOperatorNamespace,
-				},
-				Spec: crov1.PostgresSpec{
-					Tier:      "production",
-					SecretRef: secretRef,
-				},
-				Status: crov1.PostgresStatus{
-					Phase:     croTypes.PhaseComplete,
-					SecretRef: secretRef,
-				},
+			want: &Datastore{
+				Name:      "rhsso-postgres-test",
+				Ready:     true,
+				SecretRef: secretRef,
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ReconcileRHSSOPostgresCredentials(context.TODO(), tt.installation, tt.fakeClient(), tt.postgresName, defaultOperatorNamespace, defaultRHSSONamespace)
+			got, err := ReconcileRHSSOPostgresCredentials(context.TODO(), tt.installation, tt.fakeClient(), tt.prefix, defaultOperatorNamespace, defaultRHSSONamespace)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ReconcileRHSSOPostgresCredentials() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -155,8 +149,8 @@ func TestReconcileRHSSOPostgresCredentials(t *testing.T) {
 				return
 			}
 
-			if tt.want != nil && got.Name != tt.want.Name && got.Spec.Tier != "production" {
-				t.Errorf("reconcileCloudResources() got = %v, want = %v", got.Name, tt.want.Name)
+			if tt.want != nil && (got.Name != tt.want.Name || got.Ready != tt.want.Ready || got.Ready && got.Postgres.Spec.Tier != "production") {
+				t.Errorf("reconcileCloudResources() got = %v, want = %v", got, tt.want)
 			}
 		})
 	}
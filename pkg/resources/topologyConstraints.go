@@ -4,13 +4,20 @@ import (
 	"errors"
 	"fmt"
 
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // MutateZoneTopologySpreadConstraints creates a PodTemplateMutation that
-// sets the TopologySpreadConstraints for Multi AZ
-func MutateZoneTopologySpreadConstraints(labelMatch string) PodTemplateMutation {
+// sets the TopologySpreadConstraints for Multi AZ. Pods are not scheduled when
+// they can't be spread if the zone spread of the topology policy is required
+func MutateZoneTopologySpreadConstraints(policy integreatlyv1alpha1.TopologyPolicy, labelMatch string) PodTemplateMutation {
+	whenUnsatisfiable := corev1.ScheduleAnyway
+	if IsAntiAffinityRequired(policy) {
+		whenUnsatisfiable = corev1.DoNotSchedule
+	}
+
 	return func(obj metav1.Object, podTemplate *corev1.PodTemplateSpec) error {
 		labels := obj.GetLabels()
 		if labels == nil {
@@ -26,7 +33,7 @@ func MutateZoneTopologySpreadConstraints(labelMatch string) PodTemplateMutation
 			{
 				MaxSkew:           1,
 				TopologyKey:       ZoneLabel,
-				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				WhenUnsatisfiable: whenUnsatisfiable,
 				LabelSelector: &metav1.LabelSelector{
 					MatchLabels: map[string]string{
 						labelMatch: labelValue,
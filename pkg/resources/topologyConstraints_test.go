@@ -197,7 +197,7 @@ func TestReconcileTopologySpreadConstraints(t *testing.T) {
 				context.TODO(),
 				client,
 				scenario.TemplateSelector,
-				MutateZoneTopologySpreadConstraints(scenario.LabelMatch),
+				MutateZoneTopologySpreadConstraints(integreatlyv1alpha1.TopologyPolicy{}, scenario.LabelMatch),
 				scenario.TargetObj,
 			)
 
@@ -0,0 +1,185 @@
+package resources
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// NodePoolLabel is the node label, and taint key, that identifies the
+	// nodes of a dedicated node pool
+	NodePoolLabel = "integreatly.org/node-pool"
+
+	// ForceZoneDistributionEnvVar is an environment variable that, when set
+	// to true, requires the zone spread of the products without a zone
+	// spread in the topology of the installation.
+	//
+	// Deprecated: set the zone spread of the topology to Required instead
+	ForceZoneDistributionEnvVar = "FORCE_ZONE_DISTRIBUTION"
+)
+
+// ResolveTopology returns the effective placement policy of the products from
+// the topology in the installation spec. Required zone spreading is only
+// applied if the cluster is multi AZ, as the pods couldn't be scheduled
+// otherwise. The default zone spread is required if it's not set and the
+// deprecated FORCE_ZONE_DISTRIBUTION env var is true
+func ResolveTopology(installation *integreatlyv1alpha1.RHMI, multiAZ bool) *integreatlyv1alpha1.RHMITopologyStatus {
+	status := &integreatlyv1alpha1.RHMITopologyStatus{
+		MultiAZ: multiAZ,
+	}
+
+	spec := installation.Spec.Topology
+	if spec == nil {
+		spec = &integreatlyv1alpha1.TopologySpec{}
+	}
+	defaultPolicy := spec.Default
+	if defaultPolicy.ZoneSpread == "" && isZoneDistributionForced() {
+		defaultPolicy.ZoneSpread = integreatlyv1alpha1.ZoneSpreadRequired
+	}
+
+	status.Default = resolveTopologyPolicy(defaultPolicy, multiAZ)
+	for product, policy := range spec.Products {
+		if status.Products == nil {
+			status.Products = map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.TopologyPolicy{}
+		}
+		status.Products[product] = resolveTopologyPolicy(mergeTopologyPolicy(defaultPolicy, policy), multiAZ)
+	}
+
+	return status
+}
+
+// ValidateTopology checks the zone spread of each policy of the topology,
+// and the value of the deprecated FORCE_ZONE_DISTRIBUTION env var
+func ValidateTopology(spec *integreatlyv1alpha1.TopologySpec) error {
+	if value, ok := os.LookupEnv(ForceZoneDistributionEnvVar); ok {
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid value of env var %s: %w", ForceZoneDistributionEnvVar, err)
+		}
+	}
+
+	if spec == nil {
+		return nil
+	}
+
+	if err := validateTopologyPolicy(spec.Default); err != nil {
+		return fmt.Errorf("default policy: %w", err)
+	}
+	for product, policy := range spec.Products {
+		if err := validateTopologyPolicy(policy); err != nil {
+			return fmt.Errorf("policy of product %s: %w", product, err)
+		}
+	}
+	return nil
+}
+
+// GetTopologyPolicy returns the effective placement policy of the product.
+// The policy is read from the installation status, or resolved from the spec
+// for a single AZ cluster if the status is not set yet
+func GetTopologyPolicy(installation *integreatlyv1alpha1.RHMI, product integreatlyv1alpha1.ProductName) integreatlyv1alpha1.TopologyPolicy {
+	status := installation.Status.Topology
+	if status == nil {
+		status = ResolveTopology(installation, false)
+	}
+
+	if policy, ok := status.Products[product]; ok {
+		return policy
+	}
+	return status.Default
+}
+
+// MutateNodePlacement returns a PodTemplateMutation that sets the node
+// selector and tolerations of the topology policy, including those of its
+// node pool. The node selector and tolerations of the pod are left untouched
+// if the policy doesn't set them
+func MutateNodePlacement(policy integreatlyv1alpha1.TopologyPolicy) PodTemplateMutation {
+	return func(_ metav1.Object, podTemplate *corev1.PodTemplateSpec) error {
+		if nodeSelector := NodeSelectorForPolicy(policy); nodeSelector != nil {
+			podTemplate.Spec.NodeSelector = nodeSelector
+		}
+		if tolerations := TolerationsForPolicy(policy); tolerations != nil {
+			podTemplate.Spec.Tolerations = tolerations
+		}
+		return nil
+	}
+}
+
+// NodeSelectorForPolicy returns the node selector of the topology policy,
+// selecting the nodes of its node pool if set
+func NodeSelectorForPolicy(policy integreatlyv1alpha1.TopologyPolicy) map[string]string {
+	if len(policy.NodeSelector) == 0 && policy.NodePool == "" {
+		return nil
+	}
+
+	nodeSelector := map[string]string{}
+	for key, value := range policy.NodeSelector {
+		nodeSelector[key] = value
+	}
+	if policy.NodePool != "" {
+		nodeSelector[NodePoolLabel] = policy.NodePool
+	}
+	return nodeSelector
+}
+
+// TolerationsForPolicy returns the tolerations of the topology policy,
+// tolerating the taint of its node pool if set
+func TolerationsForPolicy(policy integreatlyv1alpha1.TopologyPolicy) []corev1.Toleration {
+	if len(policy.Tolerations) == 0 && policy.NodePool == "" {
+		return nil
+	}
+
+	tolerations := append([]corev1.Toleration{}, policy.Tolerations...)
+	if policy.NodePool != "" {
+		tolerations = append(tolerations, corev1.Toleration{
+			Key:      NodePoolLabel,
+			Operator: corev1.TolerationOpEqual,
+			Value:    policy.NodePool,
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+	}
+	return tolerations
+}
+
+func isZoneDistributionForced() bool {
+	forced, err := strconv.ParseBool(os.Getenv(ForceZoneDistributionEnvVar))
+	return err == nil && forced
+}
+
+func validateTopologyPolicy(policy integreatlyv1alpha1.TopologyPolicy) error {
+	switch policy.ZoneSpread {
+	case "", integreatlyv1alpha1.ZoneSpreadRequired, integreatlyv1alpha1.ZoneSpreadPreferred:
+		return nil
+	default:
+		return fmt.Errorf("unknown zone spread %s, expected %s or %s", policy.ZoneSpread, integreatlyv1alpha1.ZoneSpreadRequired, integreatlyv1alpha1.ZoneSpreadPreferred)
+	}
+}
+
+func resolveTopologyPolicy(policy integreatlyv1alpha1.TopologyPolicy, multiAZ bool) integreatlyv1alpha1.TopologyPolicy {
+	if policy.ZoneSpread != integreatlyv1alpha1.ZoneSpreadRequired || !multiAZ {
+		policy.ZoneSpread = integreatlyv1alpha1.ZoneSpreadPreferred
+	}
+	return policy
+}
+
+// mergeTopologyPolicy returns the default policy with the fields set in the
+// product policy replaced
+func mergeTopologyPolicy(defaultPolicy, productPolicy integreatlyv1alpha1.TopologyPolicy) integreatlyv1alpha1.TopologyPolicy {
+	result := defaultPolicy
+	if productPolicy.ZoneSpread != "" {
+		result.ZoneSpread = productPolicy.ZoneSpread
+	}
+	if productPolicy.NodeSelector != nil {
+		result.NodeSelector = productPolicy.NodeSelector
+	}
+	if productPolicy.Tolerations != nil {
+		result.Tolerations = productPolicy.Tolerations
+	}
+	if productPolicy.NodePool != "" {
+		result.NodePool = productPolicy.NodePool
+	}
+	return result
+}
@@ -0,0 +1,168 @@
+package resources
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/pkg/apis/integreatly/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetTopologyPolicy(t *testing.T) {
+	topology := &integreatlyv1alpha1.TopologySpec{
+		Default: integreatlyv1alpha1.TopologyPolicy{
+			ZoneSpread:   integreatlyv1alpha1.ZoneSpreadRequired,
+			NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""},
+		},
+		Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.TopologyPolicy{
+			integreatlyv1alpha1.Product3Scale: {
+				NodePool: "3scale",
+			},
+		},
+	}
+
+	scenarios := []struct {
+		Name                  string
+		Topology              *integreatlyv1alpha1.TopologySpec
+		ForceZoneDistribution string
+		MultiAZ               bool
+		Product               integreatlyv1alpha1.ProductName
+		Expected              integreatlyv1alpha1.TopologyPolicy
+	}{
+		{
+			Name:     "Defaults to preferred zone spread",
+			MultiAZ:  true,
+			Product:  integreatlyv1alpha1.ProductRHSSO,
+			Expected: integreatlyv1alpha1.TopologyPolicy{ZoneSpread: integreatlyv1alpha1.ZoneSpreadPreferred},
+		},
+		{
+			Name:                  "Deprecated env var requires zone spread",
+			ForceZoneDistribution: "true",
+			MultiAZ:               true,
+			Product:               integreatlyv1alpha1.ProductRHSSO,
+			Expected:              integreatlyv1alpha1.TopologyPolicy{ZoneSpread: integreatlyv1alpha1.ZoneSpreadRequired},
+		},
+		{
+			Name: "Topology zone spread takes precedence over the deprecated env var",
+			Topology: &integreatlyv1alpha1.TopologySpec{
+				Default: integreatlyv1alpha1.TopologyPolicy{ZoneSpread: integreatlyv1alpha1.ZoneSpreadPreferred},
+			},
+			ForceZoneDistribution: "true",
+			MultiAZ:               true,
+			Product:               integreatlyv1alpha1.ProductRHSSO,
+			Expected:              integreatlyv1alpha1.TopologyPolicy{ZoneSpread: integreatlyv1alpha1.ZoneSpreadPreferred},
+		},
+		{
+			Name:     "Required zone spread in multi AZ cluster",
+			Topology: topology,
+			MultiAZ:  true,
+			Product:  integreatlyv1alpha1.ProductRHSSO,
+			Expected: topology.Default,
+		},
+		{
+			Name:     "Required zone spread ignored in single AZ cluster",
+			Topology: topology,
+			MultiAZ:  false,
+			Product:  integreatlyv1alpha1.ProductRHSSO,
+			Expected: integreatlyv1alpha1.TopologyPolicy{
+				ZoneSpread:   integreatlyv1alpha1.ZoneSpreadPreferred,
+				NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""},
+			},
+		},
+		{
+			Name:     "Product policy overrides the default",
+			Topology: topology,
+			MultiAZ:  true,
+			Product:  integreatlyv1alpha1.Product3Scale,
+			Expected: integreatlyv1alpha1.TopologyPolicy{
+				ZoneSpread:   integreatlyv1alpha1.ZoneSpreadRequired,
+				NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""},
+				NodePool:     "3scale",
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			if scenario.ForceZoneDistribution != "" {
+				os.Setenv(ForceZoneDistributionEnvVar, scenario.ForceZoneDistribution)
+				defer os.Unsetenv(ForceZoneDistributionEnvVar)
+			}
+			installation := &integreatlyv1alpha1.RHMI{
+				Spec: integreatlyv1alpha1.RHMISpec{Topology: scenario.Topology},
+			}
+			installation.Status.Topology = ResolveTopology(installation, scenario.MultiAZ)
+
+			policy := GetTopologyPolicy(installation, scenario.Product)
+			if !reflect.DeepEqual(policy, scenario.Expected) {
+				t.Errorf("unexpected policy %v, expected %v", policy, scenario.Expected)
+			}
+		})
+	}
+}
+
+func TestNodePlacementForPolicy(t *testing.T) {
+	policy := integreatlyv1alpha1.TopologyPolicy{
+		NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""},
+		Tolerations: []corev1.Toleration{
+			{Key: "dedicated", Operator: corev1.TolerationOpExists},
+		},
+		NodePool: "3scale",
+	}
+
+	podTemplate := &corev1.PodTemplateSpec{}
+	if err := MutateNodePlacement(policy)(nil, podTemplate); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedNodeSelector := map[string]string{
+		"node-role.kubernetes.io/worker": "",
+		NodePoolLabel:                    "3scale",
+	}
+	if !reflect.DeepEqual(podTemplate.Spec.NodeSelector, expectedNodeSelector) {
+		t.Errorf("unexpected node selector %v", podTemplate.Spec.NodeSelector)
+	}
+	if len(podTemplate.Spec.Tolerations) != 2 || podTemplate.Spec.Tolerations[1].Value != "3scale" {
+		t.Errorf("unexpected tolerations %v", podTemplate.Spec.Tolerations)
+	}
+	if len(policy.NodeSelector) != 1 {
+		t.Errorf("expected node selector of the policy not to be modified, got %v", policy.NodeSelector)
+	}
+}
+
+func TestNodePlacementWithoutPolicy(t *testing.T) {
+	podTemplate := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+			Tolerations: []corev1.Toleration{
+				{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists},
+			},
+		},
+	}
+	if err := MutateNodePlacement(integreatlyv1alpha1.TopologyPolicy{})(nil, podTemplate); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(podTemplate.Spec.NodeSelector) != 1 || len(podTemplate.Spec.Tolerations) != 1 {
+		t.Errorf("expected node selector and tolerations to be kept, got %v %v", podTemplate.Spec.NodeSelector, podTemplate.Spec.Tolerations)
+	}
+}
+
+func TestValidateTopology(t *testing.T) {
+	valid := &integreatlyv1alpha1.TopologySpec{
+		Default: integreatlyv1alpha1.TopologyPolicy{ZoneSpread: integreatlyv1alpha1.ZoneSpreadRequired},
+	}
+	if err := ValidateTopology(valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	invalid := &integreatlyv1alpha1.TopologySpec{
+		Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.TopologyPolicy{
+			integreatlyv1alpha1.ProductMarin3r: {ZoneSpread: "Always"},
+		},
+	}
+	if err := ValidateTopology(invalid); err == nil {
+		t.Error("expected error for unknown zone spread")
+	}
+}
@@ -0,0 +1,56 @@
+#!/usr/bin/env bash
+# Refreshes the OpenShift templates and image streams installed by the
+# datasync and fuse-on-openshift reconcilers in templates/products, and the
+# SHA256SUMS files the downloaded files are verified against when the
+# templates URL of a product is overridden. The versions and the Fuse file
+# lists are read from the Go sources. The refreshed bundles must be committed
+set -e
+set -o pipefail
+
+ASSETS_DIR=${ASSETS_DIR:-templates/products}
+TYPES=pkg/apis/integreatly/v1alpha1/rhmi_types.go
+FUSE_RECONCILER=pkg/products/fuseonopenshift/reconciler.go
+
+constant() {
+  grep -E "^\s*$1\s" $TYPES | awk -F '"' '{print $2}'
+}
+
+# string_list <name> prints the items of the []string variable of the fuse
+# reconciler
+string_list() {
+  awk -v name="$1" '$1 == name && $2 == "=" {found=1; next} found && /^[ \t]*}/ {exit} found' $FUSE_RECONCILER |
+    awk -F '"' 'NF > 1 {print $2}'
+}
+
+# vendor <bundle> <base url> <path>...
+vendor() {
+  local bundle=$1 base_url=$2
+  shift 2
+
+  rm -rf "${ASSETS_DIR:?}/$bundle"
+  for path in "$@"; do
+    mkdir -p "$(dirname "$ASSETS_DIR/$bundle/$path")"
+    curl -sSfL -o "$ASSETS_DIR/$bundle/$path" "$base_url$path"
+  done
+  (cd "$ASSETS_DIR/$bundle" && sha256sum "$@" > SHA256SUMS)
+  echo "vendored $# files into $ASSETS_DIR/$bundle"
+}
+
+DATASYNC_VERSION=$(constant VersionDataSync)
+vendor datasync https://raw.githubusercontent.com/aerogear/datasync-deployment/ \
+  "$DATASYNC_VERSION/openshift/datasync-http.yml" \
+  "$DATASYNC_VERSION/openshift/datasync-showcase.yml"
+
+FUSE_CORE=$(constant TagFuseOnOpenShiftCore)
+FUSE_SB2=$(constant TagFuseOnOpenShiftSpringBoot2)
+FUSE_FILES=("$FUSE_CORE/$(grep -E '^\s*imageStreamFileName\s' $FUSE_RECONCILER | awk -F '"' '{print $2}')")
+for template in $(string_list consoleTemplates); do
+  FUSE_FILES+=("$FUSE_CORE/$template")
+done
+for template in $(string_list quickstartCoreTemplates); do
+  FUSE_FILES+=("$FUSE_CORE/quickstarts/$template")
+done
+for template in $(string_list quickstartSpringBoot2Templates); do
+  FUSE_FILES+=("$FUSE_SB2/quickstarts/$template")
+done
+vendor fuse-on-openshift https://raw.githubusercontent.com/jboss-fuse/application-templates/ "${FUSE_FILES[@]}"